@@ -54,6 +54,8 @@ type BotsService interface {
 	SendUserAction(ctx context.Context, in *SendUserActionRequest, opts ...client.CallOption) (*chat.SendUserActionResponse, error)
 	// Broadcast message `from` given bot profile to `peer` recipient(s)
 	BroadcastMessage(ctx context.Context, in *BroadcastMessageRequest, opts ...client.CallOption) (*BroadcastMessageResponse, error)
+	// GetBotStatus returns the last health check result of the bot gateway
+	GetBotStatus(ctx context.Context, in *GetBotStatusRequest, opts ...client.CallOption) (*BotStatus, error)
 }
 
 type botsService struct {
@@ -148,6 +150,16 @@ func (c *botsService) BroadcastMessage(ctx context.Context, in *BroadcastMessage
 	return out, nil
 }
 
+func (c *botsService) GetBotStatus(ctx context.Context, in *GetBotStatusRequest, opts ...client.CallOption) (*BotStatus, error) {
+	req := c.c.NewRequest(c.name, "Bots.GetBotStatus", in)
+	out := new(BotStatus)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Bots service
 
 type BotsHandler interface {
@@ -167,6 +179,8 @@ type BotsHandler interface {
 	SendUserAction(context.Context, *SendUserActionRequest, *chat.SendUserActionResponse) error
 	// Broadcast message `from` given bot profile to `peer` recipient(s)
 	BroadcastMessage(context.Context, *BroadcastMessageRequest, *BroadcastMessageResponse) error
+	// GetBotStatus returns the last health check result of the bot gateway
+	GetBotStatus(context.Context, *GetBotStatusRequest, *BotStatus) error
 }
 
 func RegisterBotsHandler(s server.Server, hdlr BotsHandler, opts ...server.HandlerOption) error {
@@ -179,6 +193,7 @@ func RegisterBotsHandler(s server.Server, hdlr BotsHandler, opts ...server.Handl
 		SearchBot(ctx context.Context, in *SearchBotRequest, out *SearchBotResponse) error
 		SendUserAction(ctx context.Context, in *SendUserActionRequest, out *chat.SendUserActionResponse) error
 		BroadcastMessage(ctx context.Context, in *BroadcastMessageRequest, out *BroadcastMessageResponse) error
+		GetBotStatus(ctx context.Context, in *GetBotStatusRequest, out *BotStatus) error
 	}
 	type Bots struct {
 		bots
@@ -222,3 +237,7 @@ func (h *botsHandler) SendUserAction(ctx context.Context, in *SendUserActionRequ
 func (h *botsHandler) BroadcastMessage(ctx context.Context, in *BroadcastMessageRequest, out *BroadcastMessageResponse) error {
 	return h.BotsHandler.BroadcastMessage(ctx, in, out)
 }
+
+func (h *botsHandler) GetBotStatus(ctx context.Context, in *GetBotStatusRequest, out *BotStatus) error {
+	return h.BotsHandler.GetBotStatus(ctx, in, out)
+}
@@ -48,6 +48,8 @@ type ChatService interface {
 	StartConversation(ctx context.Context, in *StartConversationRequest, opts ...client.CallOption) (*StartConversationResponse, error)
 	// CloseConversation stops and close chat-bot's schema routine with all it's recipient(s)
 	CloseConversation(ctx context.Context, in *CloseConversationRequest, opts ...client.CallOption) (*CloseConversationResponse, error)
+	// RateConversation records the post-chat survey answer; even if closed
+	RateConversation(ctx context.Context, in *RateConversationRequest, opts ...client.CallOption) (*RateConversationResponse, error)
 	// JoinConversation accepts user's invitation to chat conversation
 	JoinConversation(ctx context.Context, in *JoinConversationRequest, opts ...client.CallOption) (*JoinConversationResponse, error)
 	// LeaveConversation kicks requested user from chat conversation
@@ -116,6 +118,16 @@ func (c *chatService) CloseConversation(ctx context.Context, in *CloseConversati
 	return out, nil
 }
 
+func (c *chatService) RateConversation(ctx context.Context, in *RateConversationRequest, opts ...client.CallOption) (*RateConversationResponse, error) {
+	req := c.c.NewRequest(c.name, "ChatService.RateConversation", in)
+	out := new(RateConversationResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatService) JoinConversation(ctx context.Context, in *JoinConversationRequest, opts ...client.CallOption) (*JoinConversationResponse, error) {
 	req := c.c.NewRequest(c.name, "ChatService.JoinConversation", in)
 	out := new(JoinConversationResponse)
@@ -275,6 +287,8 @@ type ChatServiceHandler interface {
 	StartConversation(context.Context, *StartConversationRequest, *StartConversationResponse) error
 	// CloseConversation stops and close chat-bot's schema routine with all it's recipient(s)
 	CloseConversation(context.Context, *CloseConversationRequest, *CloseConversationResponse) error
+	// RateConversation records the post-chat survey answer; even if closed
+	RateConversation(context.Context, *RateConversationRequest, *RateConversationResponse) error
 	// JoinConversation accepts user's invitation to chat conversation
 	JoinConversation(context.Context, *JoinConversationRequest, *JoinConversationResponse) error
 	// LeaveConversation kicks requested user from chat conversation
@@ -306,6 +320,7 @@ func RegisterChatServiceHandler(s server.Server, hdlr ChatServiceHandler, opts .
 		SendMessage(ctx context.Context, in *SendMessageRequest, out *SendMessageResponse) error
 		StartConversation(ctx context.Context, in *StartConversationRequest, out *StartConversationResponse) error
 		CloseConversation(ctx context.Context, in *CloseConversationRequest, out *CloseConversationResponse) error
+		RateConversation(ctx context.Context, in *RateConversationRequest, out *RateConversationResponse) error
 		JoinConversation(ctx context.Context, in *JoinConversationRequest, out *JoinConversationResponse) error
 		LeaveConversation(ctx context.Context, in *LeaveConversationRequest, out *LeaveConversationResponse) error
 		InviteToConversation(ctx context.Context, in *InviteToConversationRequest, out *InviteToConversationResponse) error
@@ -345,6 +360,10 @@ func (h *chatServiceHandler) CloseConversation(ctx context.Context, in *CloseCon
 	return h.ChatServiceHandler.CloseConversation(ctx, in, out)
 }
 
+func (h *chatServiceHandler) RateConversation(ctx context.Context, in *RateConversationRequest, out *RateConversationResponse) error {
+	return h.ChatServiceHandler.RateConversation(ctx, in, out)
+}
+
 func (h *chatServiceHandler) JoinConversation(ctx context.Context, in *JoinConversationRequest, out *JoinConversationResponse) error {
 	return h.ChatServiceHandler.JoinConversation(ctx, in, out)
 }
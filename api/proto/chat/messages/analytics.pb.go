@@ -0,0 +1,437 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.3
+// 	protoc        v6.33.0
+// source: chat/messages/analytics.proto
+
+package messages
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ChatMetric aggregate.
+// All durations are average values in milliseconds.
+// The [agent] group metrics are of the agent's own channel(s):
+// messages while joined, times from the agent's channel start.
+type ChatMetric struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// [GROUP] Text gateway -or- Agent.
+	Peer *Peer `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// [GROUP] Hour start timestamp (milli).
+	Time int64 `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	// Count of the conversations started.
+	Conversations int32 `protobuf:"varint,3,opt,name=conversations,proto3" json:"conversations,omitempty"`
+	// Count of the conversations closed before any agent joined.
+	Abandoned int32 `protobuf:"varint,4,opt,name=abandoned,proto3" json:"abandoned,omitempty"`
+	// Count of all the messages.
+	Messages int32 `protobuf:"varint,5,opt,name=messages,proto3" json:"messages,omitempty"`
+	// Count of the client's messages.
+	Inbound int32 `protobuf:"varint,6,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	// Count of the agents messages.
+	Outbound int32 `protobuf:"varint,7,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	// Time from conversation start to the first agent message.
+	FirstResponseTime int64 `protobuf:"varint,8,opt,name=first_response_time,json=firstResponseTime,proto3" json:"first_response_time,omitempty"`
+	// Time from client's message to the next agent message.
+	ResponseTime int64 `protobuf:"varint,9,opt,name=response_time,json=responseTime,proto3" json:"response_time,omitempty"`
+	// Time from the first agent join to the conversation close.
+	HandleTime    int64 `protobuf:"varint,10,opt,name=handle_time,json=handleTime,proto3" json:"handle_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMetric) Reset() {
+	*x = ChatMetric{}
+	mi := &file_chat_messages_analytics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMetric) ProtoMessage() {}
+
+func (x *ChatMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_analytics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMetric.ProtoReflect.Descriptor instead.
+func (*ChatMetric) Descriptor() ([]byte, []int) {
+	return file_chat_messages_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChatMetric) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+func (x *ChatMetric) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetConversations() int32 {
+	if x != nil {
+		return x.Conversations
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetAbandoned() int32 {
+	if x != nil {
+		return x.Abandoned
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetMessages() int32 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetInbound() int32 {
+	if x != nil {
+		return x.Inbound
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetOutbound() int32 {
+	if x != nil {
+		return x.Outbound
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetFirstResponseTime() int64 {
+	if x != nil {
+		return x.FirstResponseTime
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetResponseTime() int64 {
+	if x != nil {
+		return x.ResponseTime
+	}
+	return 0
+}
+
+func (x *ChatMetric) GetHandleTime() int64 {
+	if x != nil {
+		return x.HandleTime
+	}
+	return 0
+}
+
+// ChatMetrics dataset
+type ChatMetrics struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dataset page of ChatMetric(s).
+	Data []*ChatMetric `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	// Page number of results.
+	Page int32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// Next page available ?
+	Next          bool `protobuf:"varint,3,opt,name=next,proto3" json:"next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMetrics) Reset() {
+	*x = ChatMetrics{}
+	mi := &file_chat_messages_analytics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMetrics) ProtoMessage() {}
+
+func (x *ChatMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_analytics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMetrics.ProtoReflect.Descriptor instead.
+func (*ChatMetrics) Descriptor() ([]byte, []int) {
+	return file_chat_messages_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChatMetrics) GetData() []*ChatMetric {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChatMetrics) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatMetrics) GetNext() bool {
+	if x != nil {
+		return x.Next
+	}
+	return false
+}
+
+type ChatMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Page number to return. **default**: 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Page records limit. **default**: 16.
+	Size int32 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Group aggregates by.
+	// Values: gateway, agent, hour.
+	// **default**: gateway.
+	Group string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	// [VIA] Text gateway.
+	Via *Peer `protobuf:"bytes,4,opt,name=via,proto3" json:"via,omitempty"`
+	// Conversation start date within timerange.
+	// **default**: current day.
+	Date *Timerange `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	// Time zone (IANA name) of the [hour] group
+	// and the default current day.
+	// **default**: UTC.
+	TimeZone      string `protobuf:"bytes,6,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMetricsRequest) Reset() {
+	*x = ChatMetricsRequest{}
+	mi := &file_chat_messages_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMetricsRequest) ProtoMessage() {}
+
+func (x *ChatMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMetricsRequest.ProtoReflect.Descriptor instead.
+func (*ChatMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_messages_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChatMetricsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatMetricsRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ChatMetricsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ChatMetricsRequest) GetVia() *Peer {
+	if x != nil {
+		return x.Via
+	}
+	return nil
+}
+
+func (x *ChatMetricsRequest) GetDate() *Timerange {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ChatMetricsRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+var File_chat_messages_analytics_proto protoreflect.FileDescriptor
+
+var file_chat_messages_analytics_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2f,
+	0x61, 0x6e, 0x61, 0x6c, 0x79, 0x74, 0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0c, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x63, 0x68, 0x61,
+	0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xd4, 0x02, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x26,
+	0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77,
+	0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x50, 0x65, 0x65, 0x72,
+	0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f,
+	0x6e, 0x76, 0x65, 0x72, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x61, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e,
+	0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x69, 0x6e, 0x62,
+	0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x2e, 0x0a, 0x13, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x68, 0x61, 0x6e, 0x64,
+	0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x63, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x2c, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68,
+	0x61, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x22, 0xc2, 0x01, 0x0a, 0x12,
+	0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x12, 0x24, 0x0a, 0x03, 0x76, 0x69, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x50, 0x65, 0x65,
+	0x72, 0x52, 0x03, 0x76, 0x69, 0x61, 0x12, 0x2b, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65,
+	0x32, 0x71, 0x0a, 0x09, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x74, 0x69, 0x63, 0x73, 0x12, 0x64, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12,
+	0x20, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x43,
+	0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x22, 0x15, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x0f, 0x12, 0x0d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_chat_messages_analytics_proto_rawDescOnce sync.Once
+	file_chat_messages_analytics_proto_rawDescData = file_chat_messages_analytics_proto_rawDesc
+)
+
+func file_chat_messages_analytics_proto_rawDescGZIP() []byte {
+	file_chat_messages_analytics_proto_rawDescOnce.Do(func() {
+		file_chat_messages_analytics_proto_rawDescData = protoimpl.X.CompressGZIP(file_chat_messages_analytics_proto_rawDescData)
+	})
+	return file_chat_messages_analytics_proto_rawDescData
+}
+
+var file_chat_messages_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_chat_messages_analytics_proto_goTypes = []any{
+	(*ChatMetric)(nil),         // 0: webitel.chat.ChatMetric
+	(*ChatMetrics)(nil),        // 1: webitel.chat.ChatMetrics
+	(*ChatMetricsRequest)(nil), // 2: webitel.chat.ChatMetricsRequest
+	(*Peer)(nil),               // 3: webitel.chat.Peer
+	(*Timerange)(nil),          // 4: webitel.chat.Timerange
+}
+var file_chat_messages_analytics_proto_depIdxs = []int32{
+	3, // 0: webitel.chat.ChatMetric.peer:type_name -> webitel.chat.Peer
+	0, // 1: webitel.chat.ChatMetrics.data:type_name -> webitel.chat.ChatMetric
+	3, // 2: webitel.chat.ChatMetricsRequest.via:type_name -> webitel.chat.Peer
+	4, // 3: webitel.chat.ChatMetricsRequest.date:type_name -> webitel.chat.Timerange
+	2, // 4: webitel.chat.Analytics.GetChatMetrics:input_type -> webitel.chat.ChatMetricsRequest
+	1, // 5: webitel.chat.Analytics.GetChatMetrics:output_type -> webitel.chat.ChatMetrics
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_chat_messages_analytics_proto_init() }
+func file_chat_messages_analytics_proto_init() {
+	if File_chat_messages_analytics_proto != nil {
+		return
+	}
+	file_chat_messages_peer_proto_init()
+	file_chat_messages_chat_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_chat_messages_analytics_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chat_messages_analytics_proto_goTypes,
+		DependencyIndexes: file_chat_messages_analytics_proto_depIdxs,
+		MessageInfos:      file_chat_messages_analytics_proto_msgTypes,
+	}.Build()
+	File_chat_messages_analytics_proto = out.File
+	file_chat_messages_analytics_proto_rawDesc = nil
+	file_chat_messages_analytics_proto_goTypes = nil
+	file_chat_messages_analytics_proto_depIdxs = nil
+}
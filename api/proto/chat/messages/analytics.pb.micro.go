@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: chat/messages/analytics.proto
+
+package messages
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Analytics service
+
+func NewAnalyticsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{
+		&api.Endpoint{
+			Name:    "Analytics.GetChatMetrics",
+			Path:    []string{"/chat/metrics"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
+	}
+}
+
+// Client API for Analytics service
+
+type AnalyticsService interface {
+	// Query of the conversation metrics aggregates
+	GetChatMetrics(ctx context.Context, in *ChatMetricsRequest, opts ...client.CallOption) (*ChatMetrics, error)
+}
+
+type analyticsService struct {
+	c    client.Client
+	name string
+}
+
+func NewAnalyticsService(name string, c client.Client) AnalyticsService {
+	return &analyticsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *analyticsService) GetChatMetrics(ctx context.Context, in *ChatMetricsRequest, opts ...client.CallOption) (*ChatMetrics, error) {
+	req := c.c.NewRequest(c.name, "Analytics.GetChatMetrics", in)
+	out := new(ChatMetrics)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Analytics service
+
+type AnalyticsHandler interface {
+	// Query of the conversation metrics aggregates
+	GetChatMetrics(context.Context, *ChatMetricsRequest, *ChatMetrics) error
+}
+
+func RegisterAnalyticsHandler(s server.Server, hdlr AnalyticsHandler, opts ...server.HandlerOption) error {
+	type analytics interface {
+		GetChatMetrics(ctx context.Context, in *ChatMetricsRequest, out *ChatMetrics) error
+	}
+	type Analytics struct {
+		analytics
+	}
+	h := &analyticsHandler{hdlr}
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Analytics.GetChatMetrics",
+		Path:    []string{"/chat/metrics"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
+	return s.Handle(s.NewHandler(&Analytics{h}, opts...))
+}
+
+type analyticsHandler struct {
+	AnalyticsHandler
+}
+
+func (h *analyticsHandler) GetChatMetrics(ctx context.Context, in *ChatMetricsRequest, out *ChatMetrics) error {
+	return h.AnalyticsHandler.GetChatMetrics(ctx, in, out)
+}
@@ -63,6 +63,12 @@ func NewCatalogEndpoints() []*api.Endpoint {
 			Method:  []string{"GET"},
 			Handler: "rpc",
 		},
+		&api.Endpoint{
+			Name:    "Catalog.GetRatings",
+			Path:    []string{"/chat/ratings"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
 	}
 }
 
@@ -81,6 +87,8 @@ type CatalogService interface {
 	// REQUIRE: peer, offset.
 	// INVALIDATE: search q.
 	GetUpdates(ctx context.Context, in *ChatMessagesRequest, opts ...client.CallOption) (*ChatMessages, error)
+	// Query of the post-chat survey rating aggregates
+	GetRatings(ctx context.Context, in *ChatRatingsRequest, opts ...client.CallOption) (*ChatRatings, error)
 }
 
 type catalogService struct {
@@ -145,6 +153,16 @@ func (c *catalogService) GetUpdates(ctx context.Context, in *ChatMessagesRequest
 	return out, nil
 }
 
+func (c *catalogService) GetRatings(ctx context.Context, in *ChatRatingsRequest, opts ...client.CallOption) (*ChatRatings, error) {
+	req := c.c.NewRequest(c.name, "Catalog.GetRatings", in)
+	out := new(ChatRatings)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Catalog service
 
 type CatalogHandler interface {
@@ -160,6 +178,8 @@ type CatalogHandler interface {
 	// REQUIRE: peer, offset.
 	// INVALIDATE: search q.
 	GetUpdates(context.Context, *ChatMessagesRequest, *ChatMessages) error
+	// Query of the post-chat survey rating aggregates
+	GetRatings(context.Context, *ChatRatingsRequest, *ChatRatings) error
 }
 
 func RegisterCatalogHandler(s server.Server, hdlr CatalogHandler, opts ...server.HandlerOption) error {
@@ -169,6 +189,7 @@ func RegisterCatalogHandler(s server.Server, hdlr CatalogHandler, opts ...server
 		GetMembers(ctx context.Context, in *ChatMembersRequest, out *ChatMembers) error
 		GetHistory(ctx context.Context, in *ChatMessagesRequest, out *ChatMessages) error
 		GetUpdates(ctx context.Context, in *ChatMessagesRequest, out *ChatMessages) error
+		GetRatings(ctx context.Context, in *ChatRatingsRequest, out *ChatRatings) error
 	}
 	type Catalog struct {
 		catalog
@@ -198,6 +219,12 @@ func RegisterCatalogHandler(s server.Server, hdlr CatalogHandler, opts ...server
 		Method:  []string{"GET"},
 		Handler: "rpc",
 	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Catalog.GetRatings",
+		Path:    []string{"/chat/ratings"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
 	return s.Handle(s.NewHandler(&Catalog{h}, opts...))
 }
 
@@ -224,3 +251,7 @@ func (h *catalogHandler) GetHistory(ctx context.Context, in *ChatMessagesRequest
 func (h *catalogHandler) GetUpdates(ctx context.Context, in *ChatMessagesRequest, out *ChatMessages) error {
 	return h.CatalogHandler.GetUpdates(ctx, in, out)
 }
+
+func (h *catalogHandler) GetRatings(ctx context.Context, in *ChatRatingsRequest, out *ChatRatings) error {
+	return h.CatalogHandler.GetRatings(ctx, in, out)
+}
@@ -55,9 +55,15 @@ type Dialog struct {
 	ClosedCause string `protobuf:"bytes,13,opt,name=closed_cause,json=closedCause,proto3" json:"closed_cause,omitempty"`
 	// OPTIONAL. Last dialog queue
 	Queue *Peer `protobuf:"bytes,14,opt,name=queue,proto3" json:"queue,omitempty"`
-	// Audit rate ID (call_center.cc_audit_rate.id) if this dialog has been rated (WTEL-9850).
 	// Zero value means - NOT rated yet.
-	RateId        int64 `protobuf:"varint,15,opt,name=rate_id,json=rateId,proto3" json:"rate_id,omitempty"`
+	RateId int64 `protobuf:"varint,15,opt,name=rate_id,json=rateId,proto3" json:"rate_id,omitempty"`
+	// OPTIONAL. Post-chat survey rating.
+	// Zero value means - NOT rated yet.
+	Rating *Rating `protobuf:"bytes,16,opt,name=rating,proto3" json:"rating,omitempty"`
+	// OPTIONAL. Tags of the dialog.
+	Tags []*Label `protobuf:"bytes,17,rep,name=tags,proto3" json:"tags,omitempty"`
+	// OPTIONAL. Wrap-up disposition of the dialog.
+	Disposition   *Label `protobuf:"bytes,18,opt,name=disposition,proto3" json:"disposition,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -190,6 +196,27 @@ func (x *Dialog) GetRateId() int64 {
 	return 0
 }
 
+func (x *Dialog) GetRating() *Rating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+func (x *Dialog) GetTags() []*Label {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Dialog) GetDisposition() *Label {
+	if x != nil {
+		return x.Disposition
+	}
+	return nil
+}
+
 // ChatDialogs dataset
 type ChatDialogs struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -282,7 +309,10 @@ type ChatDialogsRequest struct {
 	// Includes ONLY those chat dialogs
 	// whose member channel(s) contain
 	// a specified set of variables.
-	Group         map[string]string `protobuf:"bytes,13,rep,name=group,proto3" json:"group,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 10
+	Group map[string]string `protobuf:"bytes,13,rep,name=group,proto3" json:"group,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 10
+	// Dialogs ONLY that are tagged
+	// with ANY of the given tag(s) ID.
+	Tag           []int64 `protobuf:"varint,15,rep,packed,name=tag,proto3" json:"tag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -401,11 +431,18 @@ func (x *ChatDialogsRequest) GetGroup() map[string]string {
 	return nil
 }
 
+func (x *ChatDialogsRequest) GetTag() []int64 {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
 var File_chat_messages_dialog_proto protoreflect.FileDescriptor
 
 const file_chat_messages_dialog_proto_rawDesc = "" +
 	"\n" +
-	"\x1achat/messages/dialog.proto\x12\fwebitel.chat\x1a\x18chat/messages/peer.proto\x1a\x18chat/messages/chat.proto\x1a\x1bchat/messages/message.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x90\x04\n" +
+	"\x1achat/messages/dialog.proto\x12\fwebitel.chat\x1a\x18chat/messages/peer.proto\x1a\x18chat/messages/chat.proto\x1a\x1bchat/messages/message.proto\x1a\x1achat/messages/rating.proto\x1a chat/messages/dispositions.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x9e\x05\n" +
 	"\x06Dialog\x12\x0e\n" +
 	"\x02dc\x18\x01 \x01(\x03R\x02dc\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12$\n" +
@@ -421,14 +458,17 @@ const file_chat_messages_dialog_proto_rawDesc = "" +
 	"\amembers\x18\f \x03(\v2\x12.webitel.chat.ChatR\amembers\x12!\n" +
 	"\fclosed_cause\x18\r \x01(\tR\vclosedCause\x12(\n" +
 	"\x05queue\x18\x0e \x01(\v2\x12.webitel.chat.PeerR\x05queue\x12\x17\n" +
-	"\arate_id\x18\x0f \x01(\x03R\x06rateId\x1a:\n" +
+	"\arate_id\x18\x0f \x01(\x03R\x06rateId\x12,\n" +
+	"\x06rating\x18\x10 \x01(\v2\x14.webitel.chat.RatingR\x06rating\x12'\n" +
+	"\x04tags\x18\x11 \x03(\v2\x13.webitel.chat.LabelR\x04tags\x125\n" +
+	"\vdisposition\x18\x12 \x01(\v2\x13.webitel.chat.LabelR\vdisposition\x1a:\n" +
 	"\fContextEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"_\n" +
 	"\vChatDialogs\x12(\n" +
 	"\x04data\x18\x01 \x03(\v2\x14.webitel.chat.DialogR\x04data\x12\x12\n" +
 	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x12\n" +
-	"\x04next\x18\x03 \x01(\bR\x04next\"\xe4\x03\n" +
+	"\x04next\x18\x03 \x01(\bR\x04next\"\xf6\x03\n" +
 	"\x12ChatDialogsRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x05R\x04size\x12\f\n" +
@@ -442,7 +482,8 @@ const file_chat_messages_dialog_proto_rawDesc = "" +
 	"\x06online\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.BoolValueR\x06online\x120\n" +
 	"\x05rated\x18\x0e \x01(\v2\x1a.google.protobuf.BoolValueR\x05rated\x12A\n" +
-	"\x05group\x18\r \x03(\v2+.webitel.chat.ChatDialogsRequest.GroupEntryR\x05group\x1a8\n" +
+	"\x05group\x18\r \x03(\v2+.webitel.chat.ChatDialogsRequest.GroupEntryR\x05group\x12\x10\n" +
+	"\x03tag\x18\x0f \x03(\x03R\x03tag\x1a8\n" +
 	"\n" +
 	"GroupEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -470,8 +511,10 @@ var file_chat_messages_dialog_proto_goTypes = []any{
 	(*Peer)(nil),                 // 5: webitel.chat.Peer
 	(*Message)(nil),              // 6: webitel.chat.Message
 	(*Chat)(nil),                 // 7: webitel.chat.Chat
-	(*Timerange)(nil),            // 8: webitel.chat.Timerange
-	(*wrapperspb.BoolValue)(nil), // 9: google.protobuf.BoolValue
+	(*Rating)(nil),               // 8: webitel.chat.Rating
+	(*Label)(nil),                // 9: webitel.chat.Label
+	(*Timerange)(nil),            // 10: webitel.chat.Timerange
+	(*wrapperspb.BoolValue)(nil), // 11: google.protobuf.BoolValue
 }
 var file_chat_messages_dialog_proto_depIdxs = []int32{
 	5,  // 0: webitel.chat.Dialog.via:type_name -> webitel.chat.Peer
@@ -480,18 +523,21 @@ var file_chat_messages_dialog_proto_depIdxs = []int32{
 	3,  // 3: webitel.chat.Dialog.context:type_name -> webitel.chat.Dialog.ContextEntry
 	7,  // 4: webitel.chat.Dialog.members:type_name -> webitel.chat.Chat
 	5,  // 5: webitel.chat.Dialog.queue:type_name -> webitel.chat.Peer
-	0,  // 6: webitel.chat.ChatDialogs.data:type_name -> webitel.chat.Dialog
-	5,  // 7: webitel.chat.ChatDialogsRequest.via:type_name -> webitel.chat.Peer
-	5,  // 8: webitel.chat.ChatDialogsRequest.peer:type_name -> webitel.chat.Peer
-	8,  // 9: webitel.chat.ChatDialogsRequest.date:type_name -> webitel.chat.Timerange
-	9,  // 10: webitel.chat.ChatDialogsRequest.online:type_name -> google.protobuf.BoolValue
-	9,  // 11: webitel.chat.ChatDialogsRequest.rated:type_name -> google.protobuf.BoolValue
-	4,  // 12: webitel.chat.ChatDialogsRequest.group:type_name -> webitel.chat.ChatDialogsRequest.GroupEntry
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	8,  // 6: webitel.chat.Dialog.rating:type_name -> webitel.chat.Rating
+	9,  // 7: webitel.chat.Dialog.tags:type_name -> webitel.chat.Label
+	9,  // 8: webitel.chat.Dialog.disposition:type_name -> webitel.chat.Label
+	0,  // 9: webitel.chat.ChatDialogs.data:type_name -> webitel.chat.Dialog
+	5,  // 10: webitel.chat.ChatDialogsRequest.via:type_name -> webitel.chat.Peer
+	5,  // 11: webitel.chat.ChatDialogsRequest.peer:type_name -> webitel.chat.Peer
+	10, // 12: webitel.chat.ChatDialogsRequest.date:type_name -> webitel.chat.Timerange
+	11, // 13: webitel.chat.ChatDialogsRequest.online:type_name -> google.protobuf.BoolValue
+	11, // 14: webitel.chat.ChatDialogsRequest.rated:type_name -> google.protobuf.BoolValue
+	4,  // 15: webitel.chat.ChatDialogsRequest.group:type_name -> webitel.chat.ChatDialogsRequest.GroupEntry
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_chat_messages_dialog_proto_init() }
@@ -502,6 +548,8 @@ func file_chat_messages_dialog_proto_init() {
 	file_chat_messages_peer_proto_init()
 	file_chat_messages_chat_proto_init()
 	file_chat_messages_message_proto_init()
+	file_chat_messages_rating_proto_init()
+	file_chat_messages_dispositions_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,404 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: chat/messages/dispositions.proto
+
+package messages
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Dispositions service
+
+func NewDispositionsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{
+		&api.Endpoint{
+			Name:    "Dispositions.SearchTags",
+			Path:    []string{"/chat/tags"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.CreateTag",
+			Path:    []string{"/chat/tags"},
+			Method:  []string{"POST"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.UpdateTag",
+			Path:    []string{"/chat/tags/{id}"},
+			Method:  []string{"PUT"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.DeleteTag",
+			Path:    []string{"/chat/tags/{id}"},
+			Method:  []string{"DELETE"},
+			Body:    "",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.SearchDispositions",
+			Path:    []string{"/chat/dispositions"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.CreateDisposition",
+			Path:    []string{"/chat/dispositions"},
+			Method:  []string{"POST"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.UpdateDisposition",
+			Path:    []string{"/chat/dispositions/{id}"},
+			Method:  []string{"PUT"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.DeleteDisposition",
+			Path:    []string{"/chat/dispositions/{id}"},
+			Method:  []string{"DELETE"},
+			Body:    "",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.SetConversationTags",
+			Path:    []string{"/chat/dialogs/{chat_id}/tags"},
+			Method:  []string{"PUT"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Dispositions.SetDisposition",
+			Path:    []string{"/chat/dialogs/{chat_id}/disposition"},
+			Method:  []string{"PUT"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+	}
+}
+
+// Client API for Dispositions service
+
+type DispositionsService interface {
+	// Query of the domain's conversation tags dictionary
+	SearchTags(ctx context.Context, in *SearchLabelRequest, opts ...client.CallOption) (*LabelList, error)
+	// Create new conversation tag
+	CreateTag(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error)
+	// Update conversation tag
+	UpdateTag(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error)
+	// Delete conversation tag
+	DeleteTag(ctx context.Context, in *DeleteLabelRequest, opts ...client.CallOption) (*Label, error)
+	// Query of the domain's wrap-up disposition codes dictionary
+	SearchDispositions(ctx context.Context, in *SearchLabelRequest, opts ...client.CallOption) (*LabelList, error)
+	// Create new disposition code
+	CreateDisposition(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error)
+	// Update disposition code
+	UpdateDisposition(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error)
+	// Delete disposition code
+	DeleteDisposition(ctx context.Context, in *DeleteLabelRequest, opts ...client.CallOption) (*Label, error)
+	// Set (replace) tags of the conversation
+	SetConversationTags(ctx context.Context, in *SetConversationTagsRequest, opts ...client.CallOption) (*ConversationLabels, error)
+	// Set wrap-up disposition of the conversation
+	SetDisposition(ctx context.Context, in *SetDispositionRequest, opts ...client.CallOption) (*ConversationLabels, error)
+}
+
+type dispositionsService struct {
+	c    client.Client
+	name string
+}
+
+func NewDispositionsService(name string, c client.Client) DispositionsService {
+	return &dispositionsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *dispositionsService) SearchTags(ctx context.Context, in *SearchLabelRequest, opts ...client.CallOption) (*LabelList, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.SearchTags", in)
+	out := new(LabelList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) CreateTag(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.CreateTag", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) UpdateTag(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.UpdateTag", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) DeleteTag(ctx context.Context, in *DeleteLabelRequest, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.DeleteTag", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) SearchDispositions(ctx context.Context, in *SearchLabelRequest, opts ...client.CallOption) (*LabelList, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.SearchDispositions", in)
+	out := new(LabelList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) CreateDisposition(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.CreateDisposition", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) UpdateDisposition(ctx context.Context, in *Label, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.UpdateDisposition", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) DeleteDisposition(ctx context.Context, in *DeleteLabelRequest, opts ...client.CallOption) (*Label, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.DeleteDisposition", in)
+	out := new(Label)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) SetConversationTags(ctx context.Context, in *SetConversationTagsRequest, opts ...client.CallOption) (*ConversationLabels, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.SetConversationTags", in)
+	out := new(ConversationLabels)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dispositionsService) SetDisposition(ctx context.Context, in *SetDispositionRequest, opts ...client.CallOption) (*ConversationLabels, error) {
+	req := c.c.NewRequest(c.name, "Dispositions.SetDisposition", in)
+	out := new(ConversationLabels)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Dispositions service
+
+type DispositionsHandler interface {
+	// Query of the domain's conversation tags dictionary
+	SearchTags(context.Context, *SearchLabelRequest, *LabelList) error
+	// Create new conversation tag
+	CreateTag(context.Context, *Label, *Label) error
+	// Update conversation tag
+	UpdateTag(context.Context, *Label, *Label) error
+	// Delete conversation tag
+	DeleteTag(context.Context, *DeleteLabelRequest, *Label) error
+	// Query of the domain's wrap-up disposition codes dictionary
+	SearchDispositions(context.Context, *SearchLabelRequest, *LabelList) error
+	// Create new disposition code
+	CreateDisposition(context.Context, *Label, *Label) error
+	// Update disposition code
+	UpdateDisposition(context.Context, *Label, *Label) error
+	// Delete disposition code
+	DeleteDisposition(context.Context, *DeleteLabelRequest, *Label) error
+	// Set (replace) tags of the conversation
+	SetConversationTags(context.Context, *SetConversationTagsRequest, *ConversationLabels) error
+	// Set wrap-up disposition of the conversation
+	SetDisposition(context.Context, *SetDispositionRequest, *ConversationLabels) error
+}
+
+func RegisterDispositionsHandler(s server.Server, hdlr DispositionsHandler, opts ...server.HandlerOption) error {
+	type dispositions interface {
+		SearchTags(ctx context.Context, in *SearchLabelRequest, out *LabelList) error
+		CreateTag(ctx context.Context, in *Label, out *Label) error
+		UpdateTag(ctx context.Context, in *Label, out *Label) error
+		DeleteTag(ctx context.Context, in *DeleteLabelRequest, out *Label) error
+		SearchDispositions(ctx context.Context, in *SearchLabelRequest, out *LabelList) error
+		CreateDisposition(ctx context.Context, in *Label, out *Label) error
+		UpdateDisposition(ctx context.Context, in *Label, out *Label) error
+		DeleteDisposition(ctx context.Context, in *DeleteLabelRequest, out *Label) error
+		SetConversationTags(ctx context.Context, in *SetConversationTagsRequest, out *ConversationLabels) error
+		SetDisposition(ctx context.Context, in *SetDispositionRequest, out *ConversationLabels) error
+	}
+	type Dispositions struct {
+		dispositions
+	}
+	h := &dispositionsHandler{hdlr}
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.SearchTags",
+		Path:    []string{"/chat/tags"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.CreateTag",
+		Path:    []string{"/chat/tags"},
+		Method:  []string{"POST"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.UpdateTag",
+		Path:    []string{"/chat/tags/{id}"},
+		Method:  []string{"PUT"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.DeleteTag",
+		Path:    []string{"/chat/tags/{id}"},
+		Method:  []string{"DELETE"},
+		Body:    "",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.SearchDispositions",
+		Path:    []string{"/chat/dispositions"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.CreateDisposition",
+		Path:    []string{"/chat/dispositions"},
+		Method:  []string{"POST"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.UpdateDisposition",
+		Path:    []string{"/chat/dispositions/{id}"},
+		Method:  []string{"PUT"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.DeleteDisposition",
+		Path:    []string{"/chat/dispositions/{id}"},
+		Method:  []string{"DELETE"},
+		Body:    "",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.SetConversationTags",
+		Path:    []string{"/chat/dialogs/{chat_id}/tags"},
+		Method:  []string{"PUT"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Dispositions.SetDisposition",
+		Path:    []string{"/chat/dialogs/{chat_id}/disposition"},
+		Method:  []string{"PUT"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	return s.Handle(s.NewHandler(&Dispositions{h}, opts...))
+}
+
+type dispositionsHandler struct {
+	DispositionsHandler
+}
+
+func (h *dispositionsHandler) SearchTags(ctx context.Context, in *SearchLabelRequest, out *LabelList) error {
+	return h.DispositionsHandler.SearchTags(ctx, in, out)
+}
+
+func (h *dispositionsHandler) CreateTag(ctx context.Context, in *Label, out *Label) error {
+	return h.DispositionsHandler.CreateTag(ctx, in, out)
+}
+
+func (h *dispositionsHandler) UpdateTag(ctx context.Context, in *Label, out *Label) error {
+	return h.DispositionsHandler.UpdateTag(ctx, in, out)
+}
+
+func (h *dispositionsHandler) DeleteTag(ctx context.Context, in *DeleteLabelRequest, out *Label) error {
+	return h.DispositionsHandler.DeleteTag(ctx, in, out)
+}
+
+func (h *dispositionsHandler) SearchDispositions(ctx context.Context, in *SearchLabelRequest, out *LabelList) error {
+	return h.DispositionsHandler.SearchDispositions(ctx, in, out)
+}
+
+func (h *dispositionsHandler) CreateDisposition(ctx context.Context, in *Label, out *Label) error {
+	return h.DispositionsHandler.CreateDisposition(ctx, in, out)
+}
+
+func (h *dispositionsHandler) UpdateDisposition(ctx context.Context, in *Label, out *Label) error {
+	return h.DispositionsHandler.UpdateDisposition(ctx, in, out)
+}
+
+func (h *dispositionsHandler) DeleteDisposition(ctx context.Context, in *DeleteLabelRequest, out *Label) error {
+	return h.DispositionsHandler.DeleteDisposition(ctx, in, out)
+}
+
+func (h *dispositionsHandler) SetConversationTags(ctx context.Context, in *SetConversationTagsRequest, out *ConversationLabels) error {
+	return h.DispositionsHandler.SetConversationTags(ctx, in, out)
+}
+
+func (h *dispositionsHandler) SetDisposition(ctx context.Context, in *SetDispositionRequest, out *ConversationLabels) error {
+	return h.DispositionsHandler.SetDisposition(ctx, in, out)
+}
@@ -0,0 +1,446 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.3
+// 	protoc        v6.33.0
+// source: chat/messages/rating.proto
+
+package messages
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Rating of the Dialog.
+// Post-chat survey answer.
+type Rating struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rating scale type.
+	// Values: csat, nps, thumbs.
+	Scale string `protobuf:"bytes,1,opt,name=scale,proto3" json:"scale,omitempty"`
+	// Rating score value.
+	// csat: [1..5]; nps: [0..10]; thumbs: [0..1].
+	Value int32 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Optional. Client's comment.
+	Comment string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	// Timestamp when rated.
+	Date          int64 `protobuf:"varint,4,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rating) Reset() {
+	*x = Rating{}
+	mi := &file_chat_messages_rating_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rating) ProtoMessage() {}
+
+func (x *Rating) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_rating_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rating.ProtoReflect.Descriptor instead.
+func (*Rating) Descriptor() ([]byte, []int) {
+	return file_chat_messages_rating_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Rating) GetScale() string {
+	if x != nil {
+		return x.Scale
+	}
+	return ""
+}
+
+func (x *Rating) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Rating) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Rating) GetDate() int64 {
+	if x != nil {
+		return x.Date
+	}
+	return 0
+}
+
+// ChatRating aggregate.
+type ChatRating struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// [GROUP] Text gateway -or- Agent.
+	Peer *Peer `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// Rating scale type.
+	Scale string `protobuf:"bytes,2,opt,name=scale,proto3" json:"scale,omitempty"`
+	// Count of the rated dialogs.
+	Count int32 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// Average rating score value.
+	Average float64 `protobuf:"fixed64,4,opt,name=average,proto3" json:"average,omitempty"`
+	// Count of the satisfied answers.
+	// csat: >= 4; nps: >= 9; thumbs: 1.
+	Satisfied     int32 `protobuf:"varint,5,opt,name=satisfied,proto3" json:"satisfied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRating) Reset() {
+	*x = ChatRating{}
+	mi := &file_chat_messages_rating_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRating) ProtoMessage() {}
+
+func (x *ChatRating) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_rating_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRating.ProtoReflect.Descriptor instead.
+func (*ChatRating) Descriptor() ([]byte, []int) {
+	return file_chat_messages_rating_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChatRating) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+func (x *ChatRating) GetScale() string {
+	if x != nil {
+		return x.Scale
+	}
+	return ""
+}
+
+func (x *ChatRating) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ChatRating) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *ChatRating) GetSatisfied() int32 {
+	if x != nil {
+		return x.Satisfied
+	}
+	return 0
+}
+
+// ChatRatings dataset
+type ChatRatings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dataset page of ChatRating(s).
+	Data []*ChatRating `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	// Page number of results.
+	Page int32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// Next page available ?
+	Next          bool `protobuf:"varint,3,opt,name=next,proto3" json:"next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRatings) Reset() {
+	*x = ChatRatings{}
+	mi := &file_chat_messages_rating_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRatings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRatings) ProtoMessage() {}
+
+func (x *ChatRatings) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_rating_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRatings.ProtoReflect.Descriptor instead.
+func (*ChatRatings) Descriptor() ([]byte, []int) {
+	return file_chat_messages_rating_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChatRatings) GetData() []*ChatRating {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChatRatings) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatRatings) GetNext() bool {
+	if x != nil {
+		return x.Next
+	}
+	return false
+}
+
+type ChatRatingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Page number to return. **default**: 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Page records limit. **default**: 16.
+	Size int32 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Group aggregates by.
+	// Values: gateway, agent.
+	// **default**: gateway.
+	Group string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	// [VIA] Text gateway.
+	Via *Peer `protobuf:"bytes,4,opt,name=via,proto3" json:"via,omitempty"`
+	// Rating date within timerange.
+	Date *Timerange `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	// Rating scale type.
+	Scale         string `protobuf:"bytes,6,opt,name=scale,proto3" json:"scale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRatingsRequest) Reset() {
+	*x = ChatRatingsRequest{}
+	mi := &file_chat_messages_rating_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRatingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRatingsRequest) ProtoMessage() {}
+
+func (x *ChatRatingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_messages_rating_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRatingsRequest.ProtoReflect.Descriptor instead.
+func (*ChatRatingsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_messages_rating_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChatRatingsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatRatingsRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ChatRatingsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ChatRatingsRequest) GetVia() *Peer {
+	if x != nil {
+		return x.Via
+	}
+	return nil
+}
+
+func (x *ChatRatingsRequest) GetDate() *Timerange {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ChatRatingsRequest) GetScale() string {
+	if x != nil {
+		return x.Scale
+	}
+	return ""
+}
+
+var File_chat_messages_rating_proto protoreflect.FileDescriptor
+
+var file_chat_messages_rating_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2f,
+	0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x77, 0x65,
+	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x1a, 0x18, 0x63, 0x68, 0x61, 0x74,
+	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x73, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x62,
+	0x0a, 0x06, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x61, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x52, 0x61, 0x74, 0x69, 0x6e,
+	0x67, 0x12, 0x26, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x50,
+	0x65, 0x65, 0x72, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x61, 0x74, 0x69, 0x73, 0x66, 0x69, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x73, 0x61, 0x74, 0x69, 0x73, 0x66, 0x69, 0x65, 0x64, 0x22, 0x63, 0x0a,
+	0x0b, 0x43, 0x68, 0x61, 0x74, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x77, 0x65, 0x62,
+	0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x61,
+	0x74, 0x69, 0x6e, 0x67, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6e, 0x65,
+	0x78, 0x74, 0x22, 0xbb, 0x01, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x52, 0x61, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x24, 0x0a, 0x03, 0x76, 0x69, 0x61, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77, 0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x03, 0x76, 0x69, 0x61, 0x12, 0x2b, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x77, 0x65,
+	0x62, 0x69, 0x74, 0x65, 0x6c, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63,
+	0x61, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65,
+	0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77,
+	0x65, 0x62, 0x69, 0x74, 0x65, 0x6c, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x68,
+	0x61, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_chat_messages_rating_proto_rawDescOnce sync.Once
+	file_chat_messages_rating_proto_rawDescData = file_chat_messages_rating_proto_rawDesc
+)
+
+func file_chat_messages_rating_proto_rawDescGZIP() []byte {
+	file_chat_messages_rating_proto_rawDescOnce.Do(func() {
+		file_chat_messages_rating_proto_rawDescData = protoimpl.X.CompressGZIP(file_chat_messages_rating_proto_rawDescData)
+	})
+	return file_chat_messages_rating_proto_rawDescData
+}
+
+var file_chat_messages_rating_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_chat_messages_rating_proto_goTypes = []any{
+	(*Rating)(nil),             // 0: webitel.chat.Rating
+	(*ChatRating)(nil),         // 1: webitel.chat.ChatRating
+	(*ChatRatings)(nil),        // 2: webitel.chat.ChatRatings
+	(*ChatRatingsRequest)(nil), // 3: webitel.chat.ChatRatingsRequest
+	(*Peer)(nil),               // 4: webitel.chat.Peer
+	(*Timerange)(nil),          // 5: webitel.chat.Timerange
+}
+var file_chat_messages_rating_proto_depIdxs = []int32{
+	4, // 0: webitel.chat.ChatRating.peer:type_name -> webitel.chat.Peer
+	1, // 1: webitel.chat.ChatRatings.data:type_name -> webitel.chat.ChatRating
+	4, // 2: webitel.chat.ChatRatingsRequest.via:type_name -> webitel.chat.Peer
+	5, // 3: webitel.chat.ChatRatingsRequest.date:type_name -> webitel.chat.Timerange
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_chat_messages_rating_proto_init() }
+func file_chat_messages_rating_proto_init() {
+	if File_chat_messages_rating_proto != nil {
+		return
+	}
+	file_chat_messages_peer_proto_init()
+	file_chat_messages_chat_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_chat_messages_rating_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_chat_messages_rating_proto_goTypes,
+		DependencyIndexes: file_chat_messages_rating_proto_depIdxs,
+		MessageInfos:      file_chat_messages_rating_proto_msgTypes,
+	}.Build()
+	File_chat_messages_rating_proto = out.File
+	file_chat_messages_rating_proto_rawDesc = nil
+	file_chat_messages_rating_proto_goTypes = nil
+	file_chat_messages_rating_proto_depIdxs = nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: chat/messages/updates.proto
+
+package messages
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Updates service
+
+func NewUpdatesEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Updates service
+
+type UpdatesService interface {
+	// Subscribe to the stream of updates.
+	// Streams: message, join_conversation, leave_conversation,
+	// close_conversation, user_action (typing; if enabled), update_channel (read receipts), etc.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...client.CallOption) (Updates_SubscribeService, error)
+}
+
+type updatesService struct {
+	c    client.Client
+	name string
+}
+
+func NewUpdatesService(name string, c client.Client) UpdatesService {
+	return &updatesService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *updatesService) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...client.CallOption) (Updates_SubscribeService, error) {
+	req := c.c.NewRequest(c.name, "Updates.Subscribe", &SubscribeRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &updatesServiceSubscribe{stream}, nil
+}
+
+type Updates_SubscribeService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*Update, error)
+}
+
+type updatesServiceSubscribe struct {
+	stream client.Stream
+}
+
+func (x *updatesServiceSubscribe) Close() error {
+	return x.stream.Close()
+}
+
+func (x *updatesServiceSubscribe) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *updatesServiceSubscribe) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *updatesServiceSubscribe) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *updatesServiceSubscribe) Recv() (*Update, error) {
+	m := new(Update)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Updates service
+
+type UpdatesHandler interface {
+	// Subscribe to the stream of updates.
+	// Streams: message, join_conversation, leave_conversation,
+	// close_conversation, user_action (typing; if enabled), update_channel (read receipts), etc.
+	Subscribe(context.Context, *SubscribeRequest, Updates_SubscribeStream) error
+}
+
+func RegisterUpdatesHandler(s server.Server, hdlr UpdatesHandler, opts ...server.HandlerOption) error {
+	type updates interface {
+		Subscribe(ctx context.Context, stream server.Stream) error
+	}
+	type Updates struct {
+		updates
+	}
+	h := &updatesHandler{hdlr}
+	return s.Handle(s.NewHandler(&Updates{h}, opts...))
+}
+
+type updatesHandler struct {
+	UpdatesHandler
+}
+
+func (h *updatesHandler) Subscribe(ctx context.Context, stream server.Stream) error {
+	m := new(SubscribeRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.UpdatesHandler.Subscribe(ctx, m, &updatesSubscribeStream{stream})
+}
+
+type Updates_SubscribeStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*Update) error
+}
+
+type updatesSubscribeStream struct {
+	stream server.Stream
+}
+
+func (x *updatesSubscribeStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *updatesSubscribeStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *updatesSubscribeStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *updatesSubscribeStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *updatesSubscribeStream) Send(m *Update) error {
+	return x.stream.Send(m)
+}
@@ -0,0 +1,239 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: chat/messages/webhooks.proto
+
+package messages
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Webhooks service
+
+func NewWebhooksEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{
+		&api.Endpoint{
+			Name:    "Webhooks.SearchWebhooks",
+			Path:    []string{"/chat/webhooks"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Webhooks.CreateWebhook",
+			Path:    []string{"/chat/webhooks"},
+			Method:  []string{"POST"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Webhooks.UpdateWebhook",
+			Path:    []string{"/chat/webhooks/{id}"},
+			Method:  []string{"PUT"},
+			Body:    "*",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Webhooks.DeleteWebhook",
+			Path:    []string{"/chat/webhooks/{id}"},
+			Method:  []string{"DELETE"},
+			Body:    "",
+			Handler: "rpc",
+		},
+		&api.Endpoint{
+			Name:    "Webhooks.SearchWebhookFailures",
+			Path:    []string{"/chat/webhooks/failures"},
+			Method:  []string{"GET"},
+			Handler: "rpc",
+		},
+	}
+}
+
+// Client API for Webhooks service
+
+type WebhooksService interface {
+	// Query of the domain's webhook subscriptions
+	SearchWebhooks(ctx context.Context, in *SearchWebhookRequest, opts ...client.CallOption) (*WebhookList, error)
+	// Create new webhook subscription
+	CreateWebhook(ctx context.Context, in *Webhook, opts ...client.CallOption) (*Webhook, error)
+	// Update webhook subscription
+	UpdateWebhook(ctx context.Context, in *Webhook, opts ...client.CallOption) (*Webhook, error)
+	// Delete webhook subscription
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...client.CallOption) (*Webhook, error)
+	// Query of the failed (dead-letter) deliveries log
+	SearchWebhookFailures(ctx context.Context, in *SearchWebhookFailureRequest, opts ...client.CallOption) (*WebhookFailureList, error)
+}
+
+type webhooksService struct {
+	c    client.Client
+	name string
+}
+
+func NewWebhooksService(name string, c client.Client) WebhooksService {
+	return &webhooksService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *webhooksService) SearchWebhooks(ctx context.Context, in *SearchWebhookRequest, opts ...client.CallOption) (*WebhookList, error) {
+	req := c.c.NewRequest(c.name, "Webhooks.SearchWebhooks", in)
+	out := new(WebhookList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksService) CreateWebhook(ctx context.Context, in *Webhook, opts ...client.CallOption) (*Webhook, error) {
+	req := c.c.NewRequest(c.name, "Webhooks.CreateWebhook", in)
+	out := new(Webhook)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksService) UpdateWebhook(ctx context.Context, in *Webhook, opts ...client.CallOption) (*Webhook, error) {
+	req := c.c.NewRequest(c.name, "Webhooks.UpdateWebhook", in)
+	out := new(Webhook)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksService) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...client.CallOption) (*Webhook, error) {
+	req := c.c.NewRequest(c.name, "Webhooks.DeleteWebhook", in)
+	out := new(Webhook)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksService) SearchWebhookFailures(ctx context.Context, in *SearchWebhookFailureRequest, opts ...client.CallOption) (*WebhookFailureList, error) {
+	req := c.c.NewRequest(c.name, "Webhooks.SearchWebhookFailures", in)
+	out := new(WebhookFailureList)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Webhooks service
+
+type WebhooksHandler interface {
+	// Query of the domain's webhook subscriptions
+	SearchWebhooks(context.Context, *SearchWebhookRequest, *WebhookList) error
+	// Create new webhook subscription
+	CreateWebhook(context.Context, *Webhook, *Webhook) error
+	// Update webhook subscription
+	UpdateWebhook(context.Context, *Webhook, *Webhook) error
+	// Delete webhook subscription
+	DeleteWebhook(context.Context, *DeleteWebhookRequest, *Webhook) error
+	// Query of the failed (dead-letter) deliveries log
+	SearchWebhookFailures(context.Context, *SearchWebhookFailureRequest, *WebhookFailureList) error
+}
+
+func RegisterWebhooksHandler(s server.Server, hdlr WebhooksHandler, opts ...server.HandlerOption) error {
+	type webhooks interface {
+		SearchWebhooks(ctx context.Context, in *SearchWebhookRequest, out *WebhookList) error
+		CreateWebhook(ctx context.Context, in *Webhook, out *Webhook) error
+		UpdateWebhook(ctx context.Context, in *Webhook, out *Webhook) error
+		DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, out *Webhook) error
+		SearchWebhookFailures(ctx context.Context, in *SearchWebhookFailureRequest, out *WebhookFailureList) error
+	}
+	type Webhooks struct {
+		webhooks
+	}
+	h := &webhooksHandler{hdlr}
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Webhooks.SearchWebhooks",
+		Path:    []string{"/chat/webhooks"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Webhooks.CreateWebhook",
+		Path:    []string{"/chat/webhooks"},
+		Method:  []string{"POST"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Webhooks.UpdateWebhook",
+		Path:    []string{"/chat/webhooks/{id}"},
+		Method:  []string{"PUT"},
+		Body:    "*",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Webhooks.DeleteWebhook",
+		Path:    []string{"/chat/webhooks/{id}"},
+		Method:  []string{"DELETE"},
+		Body:    "",
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "Webhooks.SearchWebhookFailures",
+		Path:    []string{"/chat/webhooks/failures"},
+		Method:  []string{"GET"},
+		Handler: "rpc",
+	}))
+	return s.Handle(s.NewHandler(&Webhooks{h}, opts...))
+}
+
+type webhooksHandler struct {
+	WebhooksHandler
+}
+
+func (h *webhooksHandler) SearchWebhooks(ctx context.Context, in *SearchWebhookRequest, out *WebhookList) error {
+	return h.WebhooksHandler.SearchWebhooks(ctx, in, out)
+}
+
+func (h *webhooksHandler) CreateWebhook(ctx context.Context, in *Webhook, out *Webhook) error {
+	return h.WebhooksHandler.CreateWebhook(ctx, in, out)
+}
+
+func (h *webhooksHandler) UpdateWebhook(ctx context.Context, in *Webhook, out *Webhook) error {
+	return h.WebhooksHandler.UpdateWebhook(ctx, in, out)
+}
+
+func (h *webhooksHandler) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, out *Webhook) error {
+	return h.WebhooksHandler.DeleteWebhook(ctx, in, out)
+}
+
+func (h *webhooksHandler) SearchWebhookFailures(ctx context.Context, in *SearchWebhookFailureRequest, out *WebhookFailureList) error {
+	return h.WebhooksHandler.SearchWebhookFailures(ctx, in, out)
+}
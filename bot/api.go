@@ -71,7 +71,8 @@ func (srv *Service) SearchBot(ctx context.Context, req *pbbot.SearchBotRequest,
 			// operational
 			[]string{
 				"dc",
-				"metadata", "updates",
+				"metadata", "updates", "survey",
+				"business_hours",
 				"created_at", "created_by",
 				"updated_at", "updated_by",
 			},
@@ -135,6 +136,12 @@ func (srv *Service) SearchBot(ctx context.Context, req *pbbot.SearchBotRequest,
 
 	rsp.Items = list[0:size]
 
+	if !canViewSecrets(authN) {
+		for _, obj := range rsp.Items {
+			maskSecrets(obj)
+		}
+	}
+
 	return nil
 }
 
@@ -174,7 +181,8 @@ func (srv *Service) SelectBot(ctx context.Context, req *pbbot.SelectBotRequest,
 				"name", "uri",
 				"enabled", "flow",
 				"provider", "metadata",
-				"updates",
+				"updates", "survey",
+				"business_hours",
 				"created_at", "created_by",
 				"updated_at", "updated_by",
 			},
@@ -242,6 +250,10 @@ func (srv *Service) SelectBot(ctx context.Context, req *pbbot.SelectBotRequest,
 		)
 	}
 
+	if !canViewSecrets(authN) {
+		maskSecrets(obj)
+	}
+
 	// *(rsp) = *(obj)
 	app.MergeProto(rsp, obj, lookup.Fields...)
 
@@ -523,7 +535,8 @@ func (srv *Service) UpdateBot(ctx context.Context, req *pbbot.UpdateBotRequest,
 			// "id", "dc", "uri",
 			"name", "flow", "enabled",
 			// "provider",
-			"metadata", "updates",
+			"metadata", "updates", "survey",
+			"business_hours",
 			// "created_at", // "created_by",
 			// "updated_at", "updated_by",
 		}
@@ -543,7 +556,7 @@ func (srv *Service) UpdateBot(ctx context.Context, req *pbbot.UpdateBotRequest,
 				att,
 			)
 		// EDITABLE
-		case "name", "flow", "enabled", "updates", "metadata":
+		case "name", "flow", "enabled", "updates", "survey", "business_hours", "metadata":
 		// INVALID
 		default:
 			return errors.BadRequest(
@@ -626,6 +639,8 @@ func (srv *Service) UpdateBot(ctx context.Context, req *pbbot.UpdateBotRequest,
 	res := proto.Clone(src).(*pbbot.Bot) // NEW Target !
 	// DO: Merge changes ...
 	app.MergeProto(res, dst, fields...)
+	// Secrets sent back masked remain unchanged
+	unmaskSecrets(res.Metadata, src.Metadata)
 
 	// DO: REGISTER ?
 	if res.Enabled && !src.Enabled {
@@ -1483,6 +1498,42 @@ func (srv *Service) BroadcastMessage(ctx context.Context, req *pbbot.BroadcastMe
 	return sender.BroadcastMessage(ctx, req, rsp)
 }
 
+// GetBotStatus returns the last health check result of the bot gateway
+func (srv *Service) GetBotStatus(ctx context.Context, req *pbbot.GetBotStatusRequest, rsp *pbbot.BotStatus) error {
+
+	pid := req.GetId()
+	if pid == 0 {
+		return errors.BadRequest(
+			"chat.bot.status.id.required",
+			"chatbot: status of the bot id required",
+		)
+	}
+
+	// Authorize READ access to the bot
+	var obj Bot
+	err := srv.SelectBot(ctx,
+		&pbbot.SelectBotRequest{
+			Id:     pid,
+			Fields: []string{"id", "provider"},
+		}, &obj,
+	)
+	if err != nil {
+		return err
+	}
+
+	status, err := srv.store.GetStatus(ctx, pid)
+	if err != nil {
+		return err
+	}
+	if status != nil {
+		proto.Merge(rsp, status)
+	}
+	rsp.Id = obj.GetId()
+	rsp.Provider = obj.GetProvider()
+
+	return nil
+}
+
 func getClientIp(ctx context.Context) string {
 	v := ctx.Value("grpc_ctx")
 	info, ok := v.(metadata.MD)
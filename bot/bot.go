@@ -16,6 +16,11 @@ type (
 	Bot         = bot.Bot
 	Refer       = bot.Refer
 	ChatUpdates = bot.ChatUpdates
+	ChatSurvey  = bot.ChatSurvey
+	// Business hours calendar
+	BusinessHours = bot.BusinessHours
+	// Gateway health check status
+	BotStatus = bot.BotStatus
 )
 
 func IsNew(e *Bot) bool {
@@ -99,6 +104,14 @@ func Validate(e *Bot) error {
 		)
 	}
 
+	if err := ValidateSurvey(e.GetSurvey()); err != nil {
+		return err
+	}
+
+	if err := ValidateBusinessHours(e.GetBusinessHours()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -125,6 +138,9 @@ func (srv *Service) setup(add *Bot, fileStorage storage.FileService) (*Gateway,
 		),
 	)
 
+	// Business hours calendar; validated
+	hours, _ := NewSchedule(add.GetBusinessHours())
+
 	// Find provider implementation by code name
 	setup := GetProvider(add.GetProvider())
 
@@ -149,6 +165,7 @@ func (srv *Service) setup(add *Bot, fileStorage storage.FileService) (*Gateway,
 		Bot:           add,
 		Internal:      srv,
 		storageClient: fileStorage,
+		hours:         hours,
 	}
 
 	// NOTE: We oblige providers to independently manage templates for updates.
@@ -171,6 +188,8 @@ func (srv *Service) setup(add *Bot, fileStorage storage.FileService) (*Gateway,
 		agent.RWMutex = run.RWMutex
 		agent.internal = run.internal
 		agent.external = run.external
+		agent.surveys = run.surveys
+		agent.status = run.status
 		run.Unlock() // -RW
 		state = run.External
 	} else {
@@ -180,6 +199,7 @@ func (srv *Service) setup(add *Bot, fileStorage storage.FileService) (*Gateway,
 		agent.RWMutex = new(sync.RWMutex)
 		agent.internal = make(map[int64]*Channel)  // map[internal.user.id]
 		agent.external = make(map[string]*Channel) // map[provider.user.id]
+		agent.surveys = make(map[string]*chatSurvey)
 	}
 
 	// PERFORM ChatBot provider's driver setup
@@ -216,6 +236,7 @@ func (srv *Service) setup(add *Bot, fileStorage storage.FileService) (*Gateway,
 			for _, tmpl := range []string{
 				spec.Title, spec.Close,
 				spec.Join, spec.Left, spec.FilePolicyFail,
+				spec.OutOfHours,
 			} {
 				tmpl = strings.TrimSpace(tmpl)
 				if is = (tmpl != ""); is {
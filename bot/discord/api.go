@@ -0,0 +1,215 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// REST API endpoint
+var endpointURL = "https://discord.com/api/v10"
+
+// Error of the REST API
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#json
+type Error struct {
+	Status     int     `json:"-"`
+	Code       int     `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after,omitempty"` // seconds; 429
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("discord: (%d) %s", e.Status, e.Message)
+}
+
+// Client of the REST API
+type Client struct {
+	Token string // Bot token
+	HTTP  *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// maxRetryAfter to wait once when rate limited
+const maxRetryAfter = 5 * time.Second
+
+// do API request; req is the JSON body or nil
+func (c *Client) do(ctx context.Context, method, path string, req, res any) error {
+
+	var data []byte
+	if req != nil {
+		var err error
+		data, err = json.Marshal(req)
+		if err != nil {
+			return err
+		}
+	}
+
+	for retry := true; ; retry = false {
+		err := c.send(ctx, method, path, data, res)
+		re, _ := err.(*Error)
+		if !retry || re == nil || re.Status != http.StatusTooManyRequests {
+			return err
+		}
+		delay := time.Duration(re.RetryAfter * float64(time.Second))
+		if delay > maxRetryAfter {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, data []byte, res any) error {
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpointURL+path, body)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bot "+c.Token)
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode/100 != 2 {
+		re := &Error{Status: rsp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(rsp.Body, 4096)).Decode(re)
+		if re.Message == "" {
+			re.Message = http.StatusText(rsp.StatusCode)
+		}
+		return re
+	}
+	if res != nil && rsp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(rsp.Body).Decode(res)
+	}
+	return nil
+}
+
+// User object
+// https://discord.com/developers/docs/resources/user#user-object
+type User struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name,omitempty"`
+	Bot        bool   `json:"bot,omitempty"`
+}
+
+// DisplayName of the user
+func (u *User) DisplayName() string {
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+	return u.Username
+}
+
+// Me returns the bot user; GET /users/@me
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var res User
+	err := c.do(ctx, http.MethodGet, "/users/@me", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Channel object
+// https://discord.com/developers/docs/resources/channel#channel-object
+type Channel struct {
+	ID         string  `json:"id"`
+	Type       int     `json:"type"` // 1: DM
+	Recipients []*User `json:"recipients,omitempty"`
+}
+
+// CreateDM channel with the user; POST /users/@me/channels
+func (c *Client) CreateDM(ctx context.Context, userID string) (*Channel, error) {
+	var res Channel
+	err := c.do(ctx, http.MethodPost, "/users/@me/channels", map[string]string{
+		"recipient_id": userID,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateMessage in the channel; POST /channels/{channel.id}/messages
+func (c *Client) CreateMessage(ctx context.Context, channelID string, message *MessageSend) (*Message, error) {
+	var res Message
+	err := c.do(ctx, http.MethodPost, "/channels/"+url.PathEscape(channelID)+"/messages", message, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GatewayBot returns the WSS URL to connect to; GET /gateway/bot
+func (c *Client) GatewayBot(ctx context.Context) (string, error) {
+	var res struct {
+		URL string `json:"url"`
+	}
+	err := c.do(ctx, http.MethodGet, "/gateway/bot", nil, &res)
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
+}
+
+// InteractionCallback responds to the interaction received over the Gateway;
+// POST /interactions/{interaction.id}/{interaction.token}/callback
+func (c *Client) InteractionCallback(ctx context.Context, id, token string, res *InteractionResponse) error {
+	return c.do(ctx, http.MethodPost,
+		"/interactions/"+url.PathEscape(id)+"/"+url.PathEscape(token)+"/callback",
+		res, nil,
+	)
+}
+
+// Download the attachment from the Discord CDN
+func (c *Client) Download(ctx context.Context, link string, limit int64) (data []byte, mtype string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", &Error{Status: rsp.StatusCode, Message: http.StatusText(rsp.StatusCode)}
+	}
+	data, err = io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("discord: attachment size exceeds %d bytes", limit)
+	}
+	mtype, _, _ = strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	return data, mtype, nil
+}
@@ -0,0 +1,488 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "discord"
+	// Interaction request signature header(s)
+	// https://discord.com/developers/docs/interactions/overview#setting-up-an-endpoint-validating-security-request-headers
+	signatureHeader = "X-Signature-Ed25519"
+	timestampHeader = "X-Signature-Timestamp"
+	// Max size of the inbound request body
+	maxRequestSize = 1 << 20
+	// Max size of the attachment to download
+	maxFileSize = 32 << 20
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the Discord application's bot user provider.
+// Direct messages are received over the Gateway session
+// and button clicks over the Gateway or interactions endpoint
+type Bot struct {
+	Gateway   *bot.Gateway
+	client    *Client
+	publicKey ed25519.PublicKey // interactions endpoint
+	me        *User
+	// Gateway session; nil unless owned by this node
+	session *Session
+}
+
+// New initialize new agent.profile service Discord provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	token := profile["token"]
+	if token == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.discord.token.required",
+			"discord: bot token required",
+		)
+	}
+
+	var publicKey ed25519.PublicKey
+	if hexKey := profile["public_key"]; hexKey != "" {
+		key, err := hex.DecodeString(hexKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, errors.BadRequest(
+				"chat.bot.discord.public_key.invalid",
+				"discord: application public key invalid",
+			)
+		}
+		publicKey = key
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.discord.updates.invalid",
+			"discord: %v", err,
+		)
+	}
+
+	client := &Client{
+		Token: token,
+		HTTP:  &http.Client{Timeout: time.Minute},
+	}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.HTTP.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	// CHECK: Token is still valid !
+	me, err := client.Me(context.TODO())
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.discord.token.invalid",
+			"discord: %v", err,
+		)
+	}
+
+	app := &Bot{
+		Gateway:   agent,
+		client:    client,
+		publicKey: publicKey,
+		me:        me,
+	}
+	if bot.OwnsSession(profile["gateway"]) {
+		app.session = &Session{
+			Client:  client,
+			Intents: intentDirectMessages | intentMessageContent,
+			Log:     agent.Log,
+			OnEvent: app.onEvent,
+		}
+	}
+
+	// Latest (current) state
+	if last, _ := state.(*Bot); last != nil {
+		_ = last.Close()
+	}
+
+	if app.session != nil && agent.Bot.GetEnabled() {
+		app.session.Start()
+	}
+
+	return app, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register starts the Gateway session, if owned by this node.
+// The interactions endpoint URL must be set
+// in the Discord Developer Portal
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	if c.session != nil {
+		c.session.Start()
+	}
+	c.Gateway.Log.Info("discord/bot.register",
+		slog.String("bot", c.me.Username),
+		slog.Bool("gateway", c.session != nil),
+		slog.String("interactions_url", uri),
+	)
+	return nil
+}
+
+// Deregister stops the Gateway session
+func (c *Bot) Deregister(ctx context.Context) error {
+	if c.session != nil {
+		c.session.Close()
+	}
+	return nil
+}
+
+func (c *Bot) Close() error {
+	if c.session != nil {
+		c.session.Close()
+	}
+	return nil
+}
+
+// verifySignature of the interaction request
+func verifySignature(key ed25519.PublicKey, header http.Header, body []byte) bool {
+	sign, err := hex.DecodeString(header.Get(signatureHeader))
+	if err != nil || len(sign) != ed25519.SignatureSize {
+		return false
+	}
+	timestamp := header.Get(timestampHeader)
+	if timestamp == "" {
+		return false
+	}
+	return ed25519.Verify(key, append([]byte(timestamp), body...), sign)
+}
+
+// WebHook implements provider.Receiver interface for Discord;
+// the interactions endpoint
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if c.publicKey == nil {
+		http.Error(reply, "discord: interactions endpoint not configured", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(notice.Body, maxRequestSize))
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !verifySignature(c.publicKey, notice.Header, body) {
+		c.Gateway.Log.Warn("discord/bot.webhook",
+			slog.String("error", "invalid request signature"),
+		)
+		http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction Interaction
+	if err = json.Unmarshal(body, &interaction); err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var res *InteractionResponse
+	switch interaction.Type {
+	case interactionPing:
+		res = &InteractionResponse{Type: callbackPong}
+	case interactionComponent:
+		res, err = c.onInteraction(notice.Context(), &interaction)
+		if err != nil {
+			c.Gateway.Log.Error("discord/bot.onInteraction",
+				slog.Any("error", err),
+				slog.String("interaction", interaction.ID),
+			)
+			re := errors.FromError(err)
+			if re.Code == 0 {
+				re.Code = http.StatusBadGateway
+			}
+			http.Error(reply, re.Detail, int(re.Code))
+			return
+		}
+	default:
+		http.Error(reply, "discord: interaction type "+strconv.Itoa(interaction.Type)+" not supported", http.StatusBadRequest)
+		return
+	}
+
+	reply.Header().Set("Content-Type", "application/json")
+	reply.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(reply).Encode(res)
+}
+
+// onEvent dispatched over the Gateway session
+func (c *Bot) onEvent(ctx context.Context, event string, data json.RawMessage) {
+	var err error
+	switch event {
+	case "MESSAGE_CREATE":
+		var message Message
+		if err = json.Unmarshal(data, &message); err == nil {
+			err = c.onMessage(ctx, &message)
+		}
+	case "INTERACTION_CREATE":
+		var interaction Interaction
+		if err = json.Unmarshal(data, &interaction); err != nil || interaction.Type != interactionComponent {
+			break
+		}
+		var res *InteractionResponse
+		res, err = c.onInteraction(ctx, &interaction)
+		if err == nil {
+			err = c.client.InteractionCallback(ctx, interaction.ID, interaction.Token, res)
+		}
+	default:
+		return // IGNORE
+	}
+	if err != nil {
+		c.Gateway.Log.Error("discord/bot.on"+event,
+			slog.Any("error", err),
+		)
+	}
+}
+
+// getChannel of the direct message
+func (c *Bot) getChannel(ctx context.Context, channelID string, user *User) (*bot.Channel, *bot.Account, error) {
+
+	contact := &bot.Account{
+		ID:       0, // LOOKUP
+		Channel:  provider,
+		Contact:  channelID,
+		Username: user.Username,
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(user.DisplayName())
+
+	channel, err := c.Gateway.GetChannel(ctx, channelID, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return nil, nil, re // 502 Bad Gateway
+	}
+	return channel, contact, nil
+}
+
+// on: [MESSAGE_CREATE]; direct messages only
+func (c *Bot) onMessage(ctx context.Context, message *Message) error {
+
+	author := message.Author
+	if message.GuildID != "" || author == nil || author.Bot || author.ID == c.me.ID {
+		return nil // IGNORE
+	}
+
+	channel, contact, err := c.getChannel(ctx, message.ChannelID, author)
+	if err != nil {
+		return err
+	}
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if text := messageText(message); text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	for _, attachment := range message.Attachments {
+		file, err := c.getFile(ctx, attachment)
+		if err != nil {
+			return err
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getFile of the attachment
+func (c *Bot) getFile(ctx context.Context, attachment *Attachment) (*chat.File, error) {
+
+	data, mtype, err := c.client.Download(ctx, attachment.URL, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.ContentType != "" {
+		mtype = attachment.ContentType
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, attachment.Filename, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    attachment.Filename,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// on: [INTERACTION_CREATE]; button clicked.
+// Returns the response to remove the buttons of the message
+func (c *Bot) onInteraction(ctx context.Context, interaction *Interaction) (*InteractionResponse, error) {
+
+	res := &InteractionResponse{
+		Type: callbackUpdateMessage,
+		Data: map[string]any{"components": []any{}},
+	}
+	user := interaction.user()
+	if interaction.GuildID != "" || user == nil || interaction.Data == nil || interaction.Data.CustomID == "" {
+		return res, nil // IGNORE
+	}
+
+	channel, contact, err := c.getChannel(ctx, interaction.ChannelID, user)
+	if err != nil {
+		return nil, err
+	}
+	err = c.Gateway.Read(ctx, &bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+		Message: &chat.Message{
+			Type: "text",
+			Text: interaction.Data.CustomID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		sendMsg *MessageSend
+	)
+
+	switch message.Type {
+	case "text":
+		menu := message.Buttons
+		if menu == nil {
+			menu = message.Inline
+		}
+		sendMsg = newTextMessage(message.GetText(), menu)
+
+	case "file":
+		sendMsg = newFileMessage(message.GetFile(), message.GetText())
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("discord/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		sendMsg = newTextMessage(messageText, nil)
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("discord/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		sendMsg = newTextMessage(messageText, nil)
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("discord/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		sendMsg = newTextMessage(messageText, nil)
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	if sendMsg == nil {
+		return nil // IGNORE: empty message text !
+	}
+
+	_, err := c.client.CreateMessage(ctx, channel.ChatID, sendMsg)
+	if err != nil {
+		c.Gateway.Log.Error("discord/message.create",
+			slog.Any("error", err),
+			slog.String("channel", channel.ChatID),
+		)
+		return err
+	}
+
+	return nil
+}
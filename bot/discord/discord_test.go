@@ -0,0 +1,297 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+// restAPI is the local stand-in of the Discord REST API and Gateway
+type restAPI struct {
+	*httptest.Server
+
+	mx       sync.Mutex
+	messages []*MessageSend // created
+	// Gateway connection(s) served
+	serve []func(conn *websocket.Conn)
+}
+
+func newRestAPI(t *testing.T) *restAPI {
+	api := &restAPI{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bot token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":0,"message":"401: Unauthorized"}`))
+			return
+		}
+		switch {
+		case r.URL.Path == "/users/@me":
+			_, _ = w.Write([]byte(`{"id":"1","username":"helpdesk","bot":true}`))
+		case r.URL.Path == "/gateway/bot":
+			_, _ = w.Write([]byte(`{"url":"ws` + strings.TrimPrefix(api.URL, "http") + `/ws"}`))
+		case strings.HasPrefix(r.URL.Path, "/channels/") && strings.HasSuffix(r.URL.Path, "/messages"):
+			var message MessageSend
+			_ = json.NewDecoder(r.Body).Decode(&message)
+			api.mx.Lock()
+			api.messages = append(api.messages, &message)
+			api.mx.Unlock()
+			_, _ = w.Write([]byte(`{"id":"100","channel_id":"2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":0,"message":"404: Not Found"}`))
+		}
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		api.mx.Lock()
+		if len(api.serve) == 0 {
+			api.mx.Unlock()
+			return
+		}
+		serve := api.serve[0]
+		api.serve = api.serve[1:]
+		api.mx.Unlock()
+		serve(conn)
+	})
+	api.Server = httptest.NewServer(mux)
+	link := endpointURL
+	t.Cleanup(func() { endpointURL = link })
+	endpointURL = api.URL
+	return api
+}
+
+func newTestBot() *Bot {
+	return &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		client:  &Client{Token: "token"},
+		me:      &User{ID: "1", Username: "helpdesk", Bot: true},
+	}
+}
+
+func TestNewComponents(t *testing.T) {
+
+	row := &chat.Buttons{}
+	for _, code := range []string{"1", "2", "3", "4", "5", "6"} {
+		row.Button = append(row.Button, &chat.Button{Type: "reply", Text: "Option " + code, Code: code})
+	}
+	row.Button = append(row.Button,
+		&chat.Button{Type: "url", Text: "Site", Url: "https://example.com"},
+		&chat.Button{Type: "phone", Text: "Phone"}, // NOT supported
+	)
+
+	layout := newComponents([]*chat.Buttons{row})
+	if len(layout) != 2 || len(layout[0].Components) != maxRowButtons || len(layout[1].Components) != 2 {
+		t.Fatalf("action rows = %+v", layout)
+	}
+	if button := layout[0].Components[0]; button.Style != buttonPrimary || button.CustomID != "1" {
+		t.Errorf("reply button = %+v", button)
+	}
+	if button := layout[1].Components[1]; button.Style != buttonLink || button.URL != "https://example.com" || button.CustomID != "" {
+		t.Errorf("url button = %+v", button)
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	message := &Message{
+		Content: "see https://example.com/a",
+		Embeds: []*Embed{
+			{URL: "https://example.com/a", Title: "Preview"}, // link preview
+			{Title: "Order #42", Fields: []*EmbedField{{Name: "Status", Value: "lost"}}},
+		},
+	}
+	if text, want := messageText(message), "see https://example.com/a\n\nOrder #42\nStatus: lost"; text != want {
+		t.Errorf("messageText() = %q; want %q", text, want)
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	api := newRestAPI(t)
+	defer api.Close()
+	app := newTestBot()
+
+	channel := &bot.Channel{ChatID: "2"}
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: "Rate us", Buttons: []*chat.Buttons{{Button: []*chat.Button{
+			{Type: "postback", Text: "Good", Code: "5"},
+		}}}},
+		{Type: "file", Text: "Invoice", File: &chat.File{Name: "invoice.pdf", Mime: "application/pdf", Url: "https://example.com/f/1"}},
+		{Type: "file", File: &chat.File{Name: "photo.jpg", Mime: "image/jpeg", Url: "https://example.com/f/2"}},
+	} {
+		err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: message})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(api.messages) != 3 {
+		t.Fatalf("created %d messages; want 3", len(api.messages))
+	}
+	if sent := api.messages[0]; sent.Content != "Rate us" || len(sent.Components) != 1 ||
+		sent.Components[0].Components[0].CustomID != "5" {
+		t.Errorf("message[0] = %+v", sent)
+	}
+	if sent := api.messages[1]; sent.Content != "Invoice\n[invoice.pdf](https://example.com/f/1)" {
+		t.Errorf("message[1] = %+v", sent)
+	}
+	if sent := api.messages[2]; len(sent.Embeds) != 1 || sent.Embeds[0].Image.URL != "https://example.com/f/2" {
+		t.Errorf("message[2] = %+v", sent)
+	}
+
+	app.client.Token = "revoked"
+	err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: &chat.Message{Type: "text", Text: "Hello"}})
+	if re, ok := err.(*Error); !ok || re.Status != http.StatusUnauthorized {
+		t.Errorf("SendNotify(revoked) = %v; want 401", err)
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestBot()
+	app.publicKey = public
+
+	post := func(body string, sign bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		timestamp := "1700000000"
+		req.Header.Set(timestampHeader, timestamp)
+		if sign {
+			req.Header.Set(signatureHeader, hex.EncodeToString(ed25519.Sign(private, []byte(timestamp+body))))
+		}
+		rsp := httptest.NewRecorder()
+		app.WebHook(rsp, req)
+		return rsp
+	}
+
+	if rsp := post(`{"id":"1","type":1}`, false); rsp.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned interaction = (%d); want 401", rsp.Code)
+	}
+	if rsp := post(`{"id":"1","type":1}`, true); rsp.Code != http.StatusOK || strings.TrimSpace(rsp.Body.String()) != `{"type":1}` {
+		t.Errorf("ping = (%d) %s; want pong", rsp.Code, rsp.Body)
+	}
+	// guild interaction; buttons removed, NOT received
+	rsp := post(`{"id":"2","type":3,"guild_id":"9","channel_id":"3","member":{"user":{"id":"7"}},"data":{"custom_id":"5"}}`, true)
+	if rsp.Code != http.StatusOK || strings.TrimSpace(rsp.Body.String()) != `{"type":7,"data":{"components":[]}}` {
+		t.Errorf("component = (%d) %s; want update message", rsp.Code, rsp.Body)
+	}
+}
+
+func TestSession(t *testing.T) {
+
+	api := newRestAPI(t)
+	defer api.Close()
+
+	hello := func(conn *websocket.Conn) *payload {
+		_ = conn.WriteJSON(map[string]any{"op": opHello, "d": map[string]any{"heartbeat_interval": 50}})
+		var event payload
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Error(err)
+		}
+		return &event
+	}
+	dispatch := func(conn *websocket.Conn, seq int, event string, data any) {
+		_ = conn.WriteJSON(map[string]any{"op": opDispatch, "s": seq, "t": event, "d": data})
+	}
+	// reads until closed; acknowledges heartbeats
+	serve := func(conn *websocket.Conn) {
+		for {
+			var event payload
+			if err := conn.ReadJSON(&event); err != nil {
+				return
+			}
+			if event.Op == opHeartbeat {
+				_ = conn.WriteJSON(map[string]any{"op": opHeartbeatAck})
+			}
+		}
+	}
+
+	var identify, resume struct {
+		Token     string `json:"token"`
+		Intents   int    `json:"intents"`
+		SessionID string `json:"session_id"`
+		Seq       int64  `json:"seq"`
+	}
+	api.serve = []func(*websocket.Conn){
+		func(conn *websocket.Conn) {
+			if event := hello(conn); event.Op == opIdentify {
+				api.mx.Lock()
+				_ = json.Unmarshal(event.D, &identify)
+				api.mx.Unlock()
+			}
+			dispatch(conn, 1, "READY", map[string]any{
+				"session_id":         "s1",
+				"resume_gateway_url": "ws" + strings.TrimPrefix(api.URL, "http") + "/ws",
+				"user":               map[string]string{"id": "1", "username": "helpdesk"},
+			})
+			dispatch(conn, 2, "MESSAGE_CREATE", map[string]string{"id": "10", "content": "first"})
+			_ = conn.WriteJSON(map[string]any{"op": opReconnect})
+			serve(conn)
+		},
+		func(conn *websocket.Conn) {
+			if event := hello(conn); event.Op == opResume {
+				api.mx.Lock()
+				_ = json.Unmarshal(event.D, &resume)
+				api.mx.Unlock()
+			}
+			dispatch(conn, 3, "MESSAGE_CREATE", map[string]string{"id": "11", "content": "second"})
+			serve(conn)
+		},
+	}
+
+	received := make(chan string, 2)
+	session := &Session{
+		Client:  &Client{Token: "token"},
+		Intents: intentDirectMessages | intentMessageContent,
+		Log:     slog.Default(),
+		OnEvent: func(_ context.Context, event string, data json.RawMessage) {
+			var message Message
+			_ = json.Unmarshal(data, &message)
+			received <- event + ":" + message.Content
+		},
+	}
+	session.Start()
+	defer session.Close()
+
+	for _, want := range []string{"MESSAGE_CREATE:first", "MESSAGE_CREATE:second"} {
+		select {
+		case event := <-received:
+			if event != want {
+				t.Errorf("event = %s; want %s", event, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %s not received", want)
+		}
+	}
+	session.Close()
+
+	api.mx.Lock()
+	defer api.mx.Unlock()
+	if identify.Token != "token" || identify.Intents != intentDirectMessages|intentMessageContent {
+		t.Errorf("identify = %+v", identify)
+	}
+	if resume.SessionID != "s1" || resume.Seq != 2 {
+		t.Errorf("resume = %+v; want session s1 at seq 2", resume)
+	}
+}
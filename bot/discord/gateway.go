@@ -0,0 +1,324 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Gateway opcodes
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-opcodes
+const (
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opResume         = 6
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatAck   = 11
+)
+
+// Gateway intents
+// https://discord.com/developers/docs/topics/gateway#gateway-intents
+const (
+	intentDirectMessages = 1 << 12
+	intentMessageContent = 1 << 15 // privileged
+)
+
+// Gateway close event codes
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#gateway-gateway-close-event-codes
+const (
+	closeInvalidSeq     = 4007
+	closeSessionTimeout = 4009
+)
+
+// closeFatal codes; do NOT reconnect
+var closeFatal = []int{
+	4004, // Authentication failed
+	4010, // Invalid shard
+	4011, // Sharding required
+	4012, // Invalid API version
+	4013, // Invalid intent(s)
+	4014, // Disallowed intent(s)
+}
+
+var errReconnect = errors.New("discord: gateway requested reconnect")
+
+// payload of the Gateway event
+type payload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+	S  int64           `json:"s,omitempty"`
+	T  string          `json:"t,omitempty"`
+}
+
+// Session of the Gateway connection;
+// reconnects and resumes until closed
+type Session struct {
+	Client  *Client
+	Intents int
+	Dialer  *websocket.Dialer
+	Log     *slog.Logger
+	// OnEvent dispatched; handled sequentially
+	OnEvent func(ctx context.Context, event string, data json.RawMessage)
+
+	mx   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+
+	// resume state; owned by run
+	seq       atomic.Int64
+	sessionID string
+	resumeURL string
+}
+
+// Start the session, unless running
+func (s *Session) Start() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.stop != nil {
+		return // running
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Close the session and stop reconnecting
+func (s *Session) Close() {
+	s.mx.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mx.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (s *Session) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.connect(stop)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err == errReconnect {
+			continue // resume at once
+		}
+		var ce *websocket.CloseError
+		if errors.As(err, &ce) {
+			for _, code := range closeFatal {
+				if ce.Code == code {
+					s.Log.Error("discord/gateway.close",
+						slog.Any("error", err),
+					)
+					return
+				}
+			}
+			if ce.Code == closeInvalidSeq || ce.Code == closeSessionTimeout {
+				s.sessionID = "" // identify
+			}
+		}
+		s.Log.Warn("discord/gateway.reconnect",
+			slog.Any("error", err),
+			slog.Bool("resume", s.sessionID != ""),
+		)
+		if time.Since(started) > time.Minute {
+			backoff = time.Second
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// connect to the Gateway and serve events until disconnected
+func (s *Session) connect(stop <-chan struct{}) error {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	link := s.resumeURL
+	resume := s.sessionID != "" && link != ""
+	if !resume {
+		var err error
+		link, err = s.Client.GatewayBot(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	dialer := s.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, _, err := dialer.DialContext(ctx, link+"?v=10&encoding=json", nil)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	var wmx sync.Mutex
+	send := func(op int, data any) error {
+		wmx.Lock()
+		defer wmx.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(map[string]any{"op": op, "d": data})
+	}
+
+	// Hello
+	var event payload
+	if err = conn.ReadJSON(&event); err != nil {
+		return err
+	}
+	if event.Op != opHello {
+		return fmt.Errorf("discord: gateway op %d; expected hello", event.Op)
+	}
+	var hello struct {
+		HeartbeatInterval int64 `json:"heartbeat_interval"`
+	}
+	if err = json.Unmarshal(event.D, &hello); err != nil {
+		return err
+	}
+	if hello.HeartbeatInterval <= 0 {
+		return fmt.Errorf("discord: gateway heartbeat interval %d", hello.HeartbeatInterval)
+	}
+
+	if resume {
+		err = send(opResume, map[string]any{
+			"token":      s.Client.Token,
+			"session_id": s.sessionID,
+			"seq":        s.seq.Load(),
+		})
+	} else {
+		s.seq.Store(0)
+		err = send(opIdentify, map[string]any{
+			"token":   s.Client.Token,
+			"intents": s.Intents,
+			"properties": map[string]string{
+				"os":      runtime.GOOS,
+				"browser": "webitel",
+				"device":  "webitel",
+			},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	heartbeat := func() error {
+		var seq any // null
+		if n := s.seq.Load(); n > 0 {
+			seq = n
+		}
+		return send(opHeartbeat, seq)
+	}
+
+	// Heartbeat; connection is zombied if NOT acknowledged
+	var ack atomic.Bool
+	ack.Store(true)
+	go func() {
+		ticker := time.NewTicker(time.Duration(hello.HeartbeatInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if !ack.Swap(false) {
+				s.Log.Warn("discord/gateway.heartbeat",
+					slog.String("error", "heartbeat not acknowledged"),
+				)
+				cancel()
+				return
+			}
+			if err := heartbeat(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	for {
+		event = payload{}
+		if err = conn.ReadJSON(&event); err != nil {
+			return err
+		}
+		switch event.Op {
+		case opDispatch:
+			if event.S > 0 {
+				s.seq.Store(event.S)
+			}
+			switch event.T {
+			case "READY":
+				var ready struct {
+					SessionID        string `json:"session_id"`
+					ResumeGatewayURL string `json:"resume_gateway_url"`
+					User             *User  `json:"user"`
+				}
+				if err = json.Unmarshal(event.D, &ready); err != nil {
+					return err
+				}
+				s.sessionID = ready.SessionID
+				s.resumeURL = ready.ResumeGatewayURL
+				if ready.User != nil {
+					s.Log.Info("discord/gateway.ready",
+						slog.String("user", ready.User.Username),
+					)
+				}
+			case "RESUMED":
+				s.Log.Info("discord/gateway.resumed")
+			default:
+				if s.OnEvent != nil {
+					s.OnEvent(ctx, event.T, event.D)
+				}
+			}
+		case opHeartbeat:
+			if err = heartbeat(); err != nil {
+				return err
+			}
+		case opHeartbeatAck:
+			ack.Store(true)
+		case opReconnect:
+			return errReconnect
+		case opInvalidSession:
+			var resumable bool
+			_ = json.Unmarshal(event.D, &resumable)
+			if !resumable {
+				s.sessionID = ""
+			}
+			return fmt.Errorf("discord: gateway invalid session; resumable: %t", resumable)
+		}
+	}
+}
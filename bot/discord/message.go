@@ -0,0 +1,266 @@
+package discord
+
+import (
+	"strings"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Message limits
+// https://discord.com/developers/docs/resources/message#create-message
+// https://discord.com/developers/docs/interactions/message-components#buttons
+const (
+	maxContentLength = 2000 // characters
+	maxActionRows    = 5
+	maxRowButtons    = 5
+	maxButtonLabel   = 80  // characters
+	maxCustomID      = 100 // characters
+)
+
+// Component types
+const (
+	componentActionRow = 1
+	componentButton    = 2
+)
+
+// Button styles
+const (
+	buttonPrimary = 1
+	buttonLink    = 5
+)
+
+// Message object received
+// https://discord.com/developers/docs/resources/message#message-object
+type Message struct {
+	ID          string        `json:"id"`
+	ChannelID   string        `json:"channel_id"`
+	GuildID     string        `json:"guild_id,omitempty"`
+	Author      *User         `json:"author"`
+	Content     string        `json:"content"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
+	Embeds      []*Embed      `json:"embeds,omitempty"`
+}
+
+// Attachment object
+// https://discord.com/developers/docs/resources/message#attachment-object
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+}
+
+// Embed object
+// https://discord.com/developers/docs/resources/message#embed-object
+type Embed struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Image       *EmbedMedia   `json:"image,omitempty"`
+	Fields      []*EmbedField `json:"fields,omitempty"`
+}
+
+type EmbedMedia struct {
+	URL string `json:"url"`
+}
+
+type EmbedField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Component of the message; action row or button
+// https://discord.com/developers/docs/interactions/message-components
+type Component struct {
+	Type       int          `json:"type"`
+	Style      int          `json:"style,omitempty"`
+	Label      string       `json:"label,omitempty"`
+	CustomID   string       `json:"custom_id,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	Components []*Component `json:"components,omitempty"`
+}
+
+// MessageSend is the message to be created
+type MessageSend struct {
+	Content    string       `json:"content,omitempty"`
+	Embeds     []*Embed     `json:"embeds,omitempty"`
+	Components []*Component `json:"components,omitempty"`
+}
+
+// Interaction types
+const (
+	interactionPing      = 1
+	interactionComponent = 3
+)
+
+// Interaction callback types
+const (
+	callbackPong          = 1
+	callbackUpdateMessage = 7
+)
+
+// Interaction object
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object
+type Interaction struct {
+	ID        string `json:"id"`
+	Type      int    `json:"type"`
+	Token     string `json:"token"`
+	ChannelID string `json:"channel_id,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+	User      *User  `json:"user,omitempty"` // DM
+	Member    *struct {
+		User *User `json:"user"`
+	} `json:"member,omitempty"` // guild
+	Message *Message `json:"message,omitempty"`
+	Data    *struct {
+		CustomID string `json:"custom_id"`
+	} `json:"data,omitempty"`
+}
+
+// user who invoked the interaction
+func (i *Interaction) user() *User {
+	if i.User != nil {
+		return i.User
+	}
+	if i.Member != nil {
+		return i.Member.User
+	}
+	return nil
+}
+
+// InteractionResponse to be sent
+type InteractionResponse struct {
+	Type int `json:"type"`
+	Data any `json:"data,omitempty"`
+}
+
+// truncate s to n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func buttonLabel(button *chat.Button) string {
+	label := strings.TrimSpace(button.GetText())
+	if label == "" {
+		label = strings.TrimSpace(button.GetCaption())
+	}
+	return label
+}
+
+// newComponents renders the buttons as the action rows;
+// URL buttons as link buttons, reply and postback buttons
+// as primary buttons with the code as the custom_id
+func newComponents(rows []*chat.Buttons) []*Component {
+	var layout []*Component
+	for _, row := range rows {
+		var line *Component
+		for _, button := range row.GetButton() {
+			label := buttonLabel(button)
+			if label == "" {
+				continue
+			}
+			item := &Component{
+				Type:  componentButton,
+				Label: truncate(label, maxButtonLabel),
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if button.GetUrl() == "" {
+					continue
+				}
+				item.Style = buttonLink
+				item.URL = button.GetUrl()
+			case "reply", "postback":
+				code := button.GetCode()
+				if code == "" {
+					code = label
+				}
+				if len([]rune(code)) > maxCustomID {
+					continue
+				}
+				item.Style = buttonPrimary
+				item.CustomID = code
+			default:
+				continue // NOT supported
+			}
+			if line == nil || len(line.Components) == maxRowButtons {
+				if len(layout) == maxActionRows {
+					return layout
+				}
+				line = &Component{Type: componentActionRow}
+				layout = append(layout, line)
+			}
+			line.Components = append(line.Components, item)
+		}
+	}
+	return layout
+}
+
+// newTextMessage renders text with the buttons
+func newTextMessage(text string, rows []*chat.Buttons) *MessageSend {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return &MessageSend{
+		Content:    truncate(text, maxContentLength),
+		Components: newComponents(rows),
+	}
+}
+
+// newFileMessage renders the file; images are embedded,
+// other files are sent as the link
+func newFileMessage(file *chat.File, caption string) *MessageSend {
+	message := &MessageSend{
+		Content: strings.TrimSpace(caption),
+	}
+	if strings.HasPrefix(file.GetMime(), "image/") && strings.HasPrefix(file.GetUrl(), "https://") {
+		message.Embeds = []*Embed{{Image: &EmbedMedia{URL: file.GetUrl()}}}
+	} else {
+		name := file.GetName()
+		if name == "" {
+			name = "file"
+		}
+		link := "[" + name + "](" + file.GetUrl() + ")"
+		message.Content = strings.TrimSpace(message.Content + "\n" + link)
+	}
+	message.Content = truncate(message.Content, maxContentLength)
+	return message
+}
+
+// embedText renders the embed as plain text
+func embedText(embed *Embed) string {
+	var text []string
+	for _, line := range []string{embed.Title, embed.Description} {
+		if line = strings.TrimSpace(line); line != "" {
+			text = append(text, line)
+		}
+	}
+	for _, field := range embed.Fields {
+		text = append(text, field.Name+": "+field.Value)
+	}
+	if embed.URL != "" {
+		text = append(text, embed.URL)
+	}
+	if embed.Image != nil && embed.Image.URL != "" {
+		text = append(text, embed.Image.URL)
+	}
+	return strings.Join(text, "\n")
+}
+
+// messageText of the received message with the embeds;
+// link previews of the content are skipped
+func messageText(message *Message) string {
+	text := []string{strings.TrimSpace(message.Content)}
+	for _, embed := range message.Embeds {
+		if embed.URL != "" && strings.Contains(message.Content, embed.URL) {
+			continue
+		}
+		text = append(text, embedText(embed))
+	}
+	return strings.TrimSpace(strings.Join(text, "\n\n"))
+}
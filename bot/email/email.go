@@ -0,0 +1,756 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "email"
+	// HashHeader of the inbound MIME webhook request;
+	// HMAC-SHA256 hex digest of the body signed with the secret
+	HashHeader = "X-Webitel-Sign"
+	// Message size limit; both inbound and outbound attachment(s)
+	maxMessageSize = 25 << 20
+)
+
+// Channel properties to keep track of the mail thread
+const (
+	threadSubject    = "email_subject"
+	threadMessageID  = "email_message_id"
+	threadReferences = "email_references"
+	// Max thread references to keep
+	maxReferences = 16
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Config of the mailbox
+type Config struct {
+	// Sender address of the outbound messages
+	From *mail.Address
+	// Default subject of the new thread
+	Subject string
+	// SMTP server; outbound messages
+	SMTP struct {
+		Addr     string // host:port
+		Username string
+		Password string
+		// Security mode: "tls" (implicit), "starttls" (required),
+		// "none" (plain) or "" (STARTTLS if offered)
+		Security string
+	}
+	// IMAP server; inbound messages polling (optional)
+	IMAP struct {
+		Addr     string // host:port; if empty - no polling
+		Username string
+		Password string
+		Mailbox  string
+		TLS      bool
+		Interval time.Duration
+	}
+	// Secret to verify inbound MIME webhook requests (optional)
+	Secret string
+}
+
+// Bot is the email channel provider.
+// Inbound messages are polled from the IMAP mailbox
+// or pushed as raw MIME content to the webhook URI.
+// Agent replies are sent via SMTP.
+type Bot struct {
+	*bot.Gateway
+	config *Config
+	client *http.Client
+	// protects channel(s) thread properties
+	threadMx sync.Mutex
+	// IMAP poller
+	pollMx sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// New initialize new agent.profile service email provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	config, err := newConfig(agent.Bot.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse and validate message templates
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.email.updates.invalid",
+			"email: %v", err,
+		)
+	}
+
+	// Latest (current) state
+	if app, _ := state.(*Bot); app != nil {
+		app.stopPolling()
+	}
+
+	app := &Bot{
+		Gateway: agent,
+		config:  config,
+		client:  &http.Client{Timeout: time.Minute},
+	}
+
+	if agent.Bot.GetEnabled() {
+		app.startPolling()
+	}
+
+	return app, nil
+}
+
+func newConfig(profile map[string]string) (*Config, error) {
+
+	var (
+		err  error
+		conf Config
+	)
+
+	conf.From, err = mail.ParseAddress(profile["from"])
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.email.from.invalid",
+			"email: sender address required; %v",
+			err,
+		)
+	}
+	if name := profile["from_name"]; name != "" {
+		conf.From.Name = name
+	}
+	conf.Subject = profile["subject"]
+	conf.Secret = profile["secret"]
+
+	conf.SMTP.Addr = profile["smtp_host"]
+	if _, _, err = net.SplitHostPort(conf.SMTP.Addr); err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.email.smtp_host.invalid",
+			"email: smtp_host:port required",
+		)
+	}
+	conf.SMTP.Username = profile["smtp_username"]
+	conf.SMTP.Password = profile["smtp_password"]
+	switch conf.SMTP.Security = strings.ToLower(profile["smtp_security"]); conf.SMTP.Security {
+	case "", "tls", "starttls", "none":
+	default:
+		return nil, errors.BadRequest(
+			"chat.bot.email.smtp_security.invalid",
+			"email: smtp_security %q is invalid; expect: tls, starttls or none",
+			conf.SMTP.Security,
+		)
+	}
+
+	if conf.IMAP.Addr = profile["imap_host"]; conf.IMAP.Addr != "" {
+		_, port, err := net.SplitHostPort(conf.IMAP.Addr)
+		if err != nil {
+			return nil, errors.BadRequest(
+				"chat.bot.email.imap_host.invalid",
+				"email: imap_host:port invalid",
+			)
+		}
+		conf.IMAP.Username = profile["imap_username"]
+		conf.IMAP.Password = profile["imap_password"]
+		if conf.IMAP.Mailbox = profile["imap_mailbox"]; conf.IMAP.Mailbox == "" {
+			conf.IMAP.Mailbox = "INBOX"
+		}
+		conf.IMAP.TLS = (port == "993")
+		if v := profile["imap_tls"]; v != "" {
+			conf.IMAP.TLS, _ = strconv.ParseBool(v)
+		}
+		conf.IMAP.Interval = time.Minute
+		if v := profile["imap_interval"]; v != "" {
+			conf.IMAP.Interval, err = time.ParseDuration(v)
+			if err != nil || conf.IMAP.Interval < time.Second {
+				return nil, errors.BadRequest(
+					"chat.bot.email.imap_interval.invalid",
+					"email: imap_interval %q is invalid",
+					v,
+				)
+			}
+		}
+	}
+
+	return &conf, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register starts IMAP mailbox polling, if configured
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.startPolling()
+	return nil
+}
+
+// Deregister stops IMAP mailbox polling
+func (c *Bot) Deregister(ctx context.Context) error {
+	c.stopPolling()
+	return nil
+}
+
+func (c *Bot) Close() error {
+	c.stopPolling()
+	return nil
+}
+
+func (c *Bot) startPolling() {
+	if c.config.IMAP.Addr == "" {
+		return // disabled
+	}
+	c.pollMx.Lock()
+	defer c.pollMx.Unlock()
+	if c.stop != nil {
+		return // running
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.poll(c.stop, c.done)
+}
+
+func (c *Bot) stopPolling() {
+	c.pollMx.Lock()
+	defer c.pollMx.Unlock()
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	c.stop, c.done = nil, nil
+}
+
+func (c *Bot) poll(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	tick := time.NewTicker(c.config.IMAP.Interval)
+	defer tick.Stop()
+	for {
+		if err := c.fetch(stop); err != nil {
+			c.Gateway.Log.Error("email/imap.poll",
+				slog.Any("error", err),
+			)
+		}
+		select {
+		case <-stop:
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// fetch unseen messages of the IMAP mailbox
+func (c *Bot) fetch(stop <-chan struct{}) error {
+
+	conf := c.config.IMAP
+	client, err := dialIMAP(conf.Addr, conf.TLS, 30*time.Second)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err = client.Login(conf.Username, conf.Password); err != nil {
+		return err
+	}
+	if err = client.Select(conf.Mailbox); err != nil {
+		return err
+	}
+	uids, err := client.SearchUnseen()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, uid := range uids {
+		select {
+		case <-stop:
+			return client.Logout()
+		default:
+		}
+		data, err := client.Fetch(uid)
+		if err != nil {
+			return err
+		}
+		err = c.receive(ctx, bytes.NewReader(data))
+		if err != nil {
+			// NOTE: keep unseen to retry next time
+			c.Gateway.Log.Error("email/imap.receive",
+				slog.Any("error", err),
+				slog.Uint64("uid", uint64(uid)),
+			)
+			continue
+		}
+		if err = client.Seen(uid); err != nil {
+			return err
+		}
+	}
+
+	return client.Logout()
+}
+
+// WebHook accepts inbound raw MIME (message/rfc822) content
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(notice.Body, maxMessageSize))
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if secret := c.config.Secret; secret != "" {
+		if !hmac.Equal([]byte(notice.Header.Get(HashHeader)), []byte(calculateHash(body, secret))) {
+			http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	err = c.receive(notice.Context(), bytes.NewReader(body))
+	if err != nil {
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = http.StatusBadGateway
+		}
+		http.Error(reply, re.Detail, int(re.Code))
+		return
+	}
+
+	reply.WriteHeader(http.StatusOK)
+}
+
+func calculateHash(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// receive inbound message
+func (c *Bot) receive(ctx context.Context, r io.Reader) error {
+
+	message, err := parseMessage(r)
+	if err != nil {
+		return errors.BadRequest(
+			"chat.bot.email.message.invalid",
+			"email: %v", err,
+		)
+	}
+
+	sender := message.From.Address
+	if strings.EqualFold(sender, c.config.From.Address) {
+		return nil // IGNORE: own message
+	}
+	if message.AutoReply {
+		c.Gateway.Log.Warn("email/receive",
+			slog.String("error", "ignore: auto-submitted message"),
+			slog.String("from", sender),
+		)
+		return nil // IGNORE: mail loop
+	}
+
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: sender,
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(message.From.Name)
+
+	channel, err := c.Gateway.GetChannel(ctx, sender, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return re // 502 Bad Gateway
+	}
+
+	text := message.Text
+	if channel.IsNew() && message.Subject != "" {
+		text = strings.TrimSpace(message.Subject + "\n\n" + text)
+	}
+	c.setThread(channel, message)
+
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range message.Files {
+		name := file.Name
+		if name == "" {
+			name = "attachment"
+		}
+		media, err := c.Gateway.UploadFile(
+			ctx, 4096, file.Mime, name, uuid.NewString(), bytes.NewReader(file.Data),
+		)
+		if err != nil {
+			return err
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: &chat.File{
+				Id:      media.Id,
+				Url:     media.Url,
+				Mime:    file.Mime,
+				Name:    name,
+				Size:    media.Size,
+				Malware: media.Malware,
+			},
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// channel's thread properties
+func threadOf(channel *bot.Channel) map[string]string {
+	props, _ := channel.Properties.(map[string]string)
+	if props == nil {
+		props = make(map[string]string)
+		channel.Properties = props
+	}
+	return props
+}
+
+// appendReference to the thread; keeps the last maxReferences
+func appendReference(refs []string, id string) []string {
+	if id == "" {
+		return refs
+	}
+	for _, ref := range refs {
+		if ref == id {
+			return refs
+		}
+	}
+	refs = append(refs, id)
+	if n := len(refs); n > maxReferences {
+		refs = refs[n-maxReferences:]
+	}
+	return refs
+}
+
+// setThread tracks the inbound message headers
+// to reply within the same mail thread
+func (c *Bot) setThread(channel *bot.Channel, message *inboundMessage) {
+	c.threadMx.Lock()
+	defer c.threadMx.Unlock()
+
+	props := threadOf(channel)
+	if message.Subject != "" && (message.InReplyTo == "" || props[threadSubject] == "") {
+		// NEW thread
+		props[threadSubject] = message.Subject
+	}
+	refs := strings.Fields(props[threadReferences])
+	for _, id := range message.References {
+		refs = appendReference(refs, id)
+	}
+	refs = appendReference(refs, message.InReplyTo)
+	refs = appendReference(refs, message.MessageID)
+	props[threadReferences] = strings.Join(refs, " ")
+	if message.MessageID != "" {
+		props[threadMessageID] = message.MessageID
+	}
+}
+
+// newMessage to be sent within the channel's mail thread
+func (c *Bot) newMessage(channel *bot.Channel) *outboundMessage {
+	c.threadMx.Lock()
+	defer c.threadMx.Unlock()
+
+	var (
+		props  = threadOf(channel)
+		domain = c.config.From.Address[strings.LastIndexByte(c.config.From.Address, '@')+1:]
+		sendTo = &mail.Address{
+			Name:    channel.Account.DisplayName(),
+			Address: channel.ChatID,
+		}
+	)
+
+	subject := props[threadSubject]
+	if subject == "" {
+		subject = c.config.Subject
+		if subject == "" {
+			subject = channel.Title
+		}
+	} else if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+
+	message := &outboundMessage{
+		From:       c.config.From,
+		To:         sendTo,
+		Subject:    subject,
+		MessageID:  "<" + uuid.NewString() + "@" + domain + ">",
+		InReplyTo:  props[threadMessageID],
+		References: strings.Fields(props[threadReferences]),
+		Date:       time.Now(),
+	}
+
+	// Next reply follows this one
+	props[threadMessageID] = message.MessageID
+	props[threadReferences] = strings.Join(
+		appendReference(message.References, message.MessageID), " ",
+	)
+	if props[threadSubject] == "" {
+		props[threadSubject] = subject
+	}
+
+	return message
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		text    string
+		files   []*attachment
+	)
+
+	switch message.Type {
+	case "text":
+		text = strings.TrimSpace(message.GetText())
+		if text == "" {
+			return nil
+		}
+
+	case "file":
+		doc := message.GetFile()
+		data, err := c.download(ctx, doc.GetUrl())
+		if err != nil {
+			c.Gateway.Log.Error("email/bot.sendFile",
+				slog.Any("error", err),
+				slog.String("url", doc.GetUrl()),
+			)
+			return err
+		}
+		files = append(files, &attachment{
+			Name: doc.GetName(),
+			Mime: doc.GetMime(),
+			Data: data,
+		})
+		text = strings.TrimSpace(message.GetText())
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("email/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("email/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = strings.TrimSpace(messageText)
+		if text == "" {
+			return nil
+		}
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("email/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = strings.TrimSpace(messageText)
+		if text == "" {
+			return nil
+		}
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	sendMessage := c.newMessage(channel)
+	sendMessage.Text = text
+	sendMessage.Files = files
+
+	data, err := sendMessage.Bytes()
+	if err != nil {
+		return err
+	}
+
+	err = c.sendMail(sendMessage.To.Address, data)
+	if err != nil {
+		c.Gateway.Log.Error("email/smtp.send",
+			slog.Any("error", err),
+			slog.String("to", sendMessage.To.Address),
+		)
+		return errors.BadGateway(
+			"chat.bot.email.send.error",
+			"email: %v", err,
+		)
+	}
+
+	return nil
+}
+
+// download file content to be attached
+func (c *Bot) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: HTTP %s", rsp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMessageSize {
+		return nil, fmt.Errorf("download: file size exceeds %d bytes", maxMessageSize)
+	}
+	return data, nil
+}
+
+// sendMail message data via SMTP server
+func (c *Bot) sendMail(to string, data []byte) error {
+
+	conf := c.config.SMTP
+	host, _, _ := net.SplitHostPort(conf.Addr)
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var (
+		err  error
+		conn net.Conn
+	)
+	if conf.Security == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", conf.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", conf.Addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Minute))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if conf.Security == "" || conf.Security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			err = client.StartTLS(&tls.Config{ServerName: host})
+			if err != nil {
+				return err
+			}
+		} else if conf.Security == "starttls" {
+			return fmt.Errorf("smtp: server does not support STARTTLS")
+		}
+	}
+
+	if conf.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", conf.Username, conf.Password, host))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(c.config.From.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,125 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestMessageRoundtrip(t *testing.T) {
+	send := &outboundMessage{
+		From:       &mail.Address{Name: "Support", Address: "support@example.com"},
+		To:         &mail.Address{Name: "Jane Doe", Address: "jane@example.org"},
+		Subject:    "Re: Привіт",
+		MessageID:  "<2@example.com>",
+		InReplyTo:  "<1@example.org>",
+		References: []string{"<1@example.org>"},
+		Text:       "Hello,\nhow can we help?",
+		Files: []*attachment{
+			{Name: "note.txt", Mime: "text/plain", Data: []byte("attached")},
+		},
+	}
+	data, err := send.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv, err := parseMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recv.From.Address != "support@example.com" || recv.Subject != send.Subject {
+		t.Errorf("from=%s subject=%q", recv.From.Address, recv.Subject)
+	}
+	if recv.MessageID != send.MessageID || recv.InReplyTo != send.InReplyTo {
+		t.Errorf("message-id=%s in-reply-to=%s", recv.MessageID, recv.InReplyTo)
+	}
+	if recv.Text != send.Text {
+		t.Errorf("text=%q; want %q", recv.Text, send.Text)
+	}
+	if len(recv.Files) != 1 || recv.Files[0].Name != "note.txt" || string(recv.Files[0].Data) != "attached" {
+		t.Errorf("files=%+v", recv.Files)
+	}
+}
+
+func TestTrimQuoted(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{"Thanks!\n\nOn Mon, Jan 1, 2024 Support wrote:\n> Hello", "Thanks!"},
+		{"Ok\n-----Original Message-----\nFrom: x", "Ok"},
+		{"No quotes", "No quotes"},
+	} {
+		if got := trimQuoted(tc.text); got != tc.want {
+			t.Errorf("trimQuoted(%q) = %q; want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestIMAPClient(t *testing.T) {
+	const message = "From: jane@example.org\r\nSubject: Hi\r\n\r\nHello\r\n"
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var seen []string
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+		recv := bufio.NewReader(conn)
+		for {
+			line, err := recv.ReadString('\n')
+			if err != nil {
+				return
+			}
+			tag, cmd, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch {
+			case strings.HasPrefix(cmd, "UID SEARCH"):
+				fmt.Fprint(conn, "* SEARCH 7\r\n")
+			case strings.HasPrefix(cmd, "UID FETCH"):
+				fmt.Fprintf(conn, "* 1 FETCH (UID 7 BODY[] {%d}\r\n%s)\r\n", len(message), message)
+			case strings.HasPrefix(cmd, "UID STORE"):
+				seen = append(seen, cmd)
+			}
+			fmt.Fprintf(conn, "%s OK done\r\n", tag)
+		}
+	}()
+
+	c, err := dialIMAP(ln.Addr().String(), false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err = c.Login("user", `pa"ss`); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Select("INBOX"); err != nil {
+		t.Fatal(err)
+	}
+	uids, err := c.SearchUnseen()
+	if err != nil || len(uids) != 1 || uids[0] != 7 {
+		t.Fatalf("SearchUnseen() = %v, %v", uids, err)
+	}
+	data, err := c.Fetch(7)
+	if err != nil || string(data) != message {
+		t.Fatalf("Fetch() = %q, %v", data, err)
+	}
+	if err = c.Seen(7); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0] != `UID STORE 7 +FLAGS.SILENT (\Seen)` {
+		t.Errorf("seen = %q", seen)
+	}
+}
@@ -0,0 +1,198 @@
+package email
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapClient is a minimal IMAP4rev1 (RFC 3501) client
+// sufficient to poll unseen messages of a single mailbox.
+type imapClient struct {
+	conn    net.Conn
+	recv    *bufio.Reader
+	tag     int
+	timeout time.Duration
+}
+
+// imapResponse line with optional literal(s) content
+type imapResponse struct {
+	Line    string
+	Literal []byte
+}
+
+func dialIMAP(addr string, secure bool, timeout time.Duration) (*imapClient, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+	dialer := &net.Dialer{Timeout: timeout}
+	if secure {
+		host, _, _ := net.SplitHostPort(addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &imapClient{
+		conn:    conn,
+		recv:    bufio.NewReader(conn),
+		timeout: timeout,
+	}
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+	// Server greeting
+	greet, err := c.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greet, "* OK") && !strings.HasPrefix(greet, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("imap: greeting %q", greet)
+	}
+	return c, nil
+}
+
+func (c *imapClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.recv.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readResponse reads single server response line
+// including {n} literal(s) content, if any.
+func (c *imapClient) readResponse() (*imapResponse, error) {
+	var res imapResponse
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		res.Line += line
+		// literal: {size}
+		if !strings.HasSuffix(line, "}") {
+			break
+		}
+		at := strings.LastIndexByte(line, '{')
+		if at < 0 {
+			break
+		}
+		size, err := strconv.Atoi(line[at+1 : len(line)-1])
+		if err != nil {
+			break
+		}
+		data := make([]byte, size)
+		if _, err = io.ReadFull(c.recv, data); err != nil {
+			return nil, err
+		}
+		res.Literal = append(res.Literal, data...)
+		// continue reading the rest of the line
+	}
+	return &res, nil
+}
+
+// Exec command; returns untagged response(s) on success
+func (c *imapClient) Exec(command string, args ...any) ([]*imapResponse, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	_, err := fmt.Fprintf(c.conn, tag+" "+command+"\r\n", args...)
+	if err != nil {
+		return nil, err
+	}
+	var untagged []*imapResponse
+	for {
+		res, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(res.Line, tag+" ") {
+			untagged = append(untagged, res)
+			continue
+		}
+		status := strings.TrimPrefix(res.Line, tag+" ")
+		if !strings.HasPrefix(status, "OK") {
+			return untagged, fmt.Errorf("imap: %s", status)
+		}
+		return untagged, nil
+	}
+}
+
+// quote string argument
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (c *imapClient) Login(username, password string) error {
+	_, err := c.Exec("LOGIN %s %s", imapQuote(username), imapQuote(password))
+	return err
+}
+
+func (c *imapClient) Select(mailbox string) error {
+	_, err := c.Exec("SELECT %s", imapQuote(mailbox))
+	return err
+}
+
+// SearchUnseen returns UID(s) of the unseen messages
+func (c *imapClient) SearchUnseen() ([]uint32, error) {
+	res, err := c.Exec("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, e := range res {
+		if !strings.HasPrefix(e.Line, "* SEARCH") {
+			continue
+		}
+		for _, v := range strings.Fields(strings.TrimPrefix(e.Line, "* SEARCH")) {
+			uid, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				continue
+			}
+			uids = append(uids, uint32(uid))
+		}
+	}
+	return uids, nil
+}
+
+// Fetch message content (RFC 822) by UID; does not set \Seen flag
+func (c *imapClient) Fetch(uid uint32) ([]byte, error) {
+	res, err := c.Exec("UID FETCH %d BODY.PEEK[]", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range res {
+		if strings.Contains(e.Line, "FETCH") && e.Literal != nil {
+			return e.Literal, nil
+		}
+	}
+	return nil, fmt.Errorf("imap: message uid=%d not found", uid)
+}
+
+// Seen marks message with the \Seen flag
+func (c *imapClient) Seen(uid uint32) error {
+	_, err := c.Exec("UID STORE %d +FLAGS.SILENT (\\Seen)", uid)
+	return err
+}
+
+func (c *imapClient) Logout() error {
+	_, err := c.Exec("LOGOUT")
+	return err
+}
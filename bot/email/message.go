@@ -0,0 +1,367 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// attachment file content
+type attachment struct {
+	Name string
+	Mime string
+	Data []byte
+}
+
+// inboundMessage is the parsed RFC 5322 message received
+type inboundMessage struct {
+	MessageID  string
+	InReplyTo  string
+	References []string
+	From       *mail.Address
+	Subject    string
+	Text       string
+	Files      []*attachment
+	// Auto-Submitted; e.g.: vacation auto-replies.
+	// Never answered to prevent mail loops.
+	AutoReply bool
+}
+
+var headerDecoder = &mime.WordDecoder{}
+
+func decodeHeader(s string) string {
+	if dec, err := headerDecoder.DecodeHeader(s); err == nil {
+		return dec
+	}
+	return s
+}
+
+// messageIDs parses the list of <msg-id> tokens
+func messageIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Fields(s) {
+		if strings.HasPrefix(id, "<") && strings.HasSuffix(id, ">") {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseMessage parses raw RFC 5322 message
+func parseMessage(r io.Reader) (*inboundMessage, error) {
+
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return nil, fmt.Errorf("email: message From address required")
+	}
+
+	res := &inboundMessage{
+		MessageID:  strings.TrimSpace(msg.Header.Get("Message-Id")),
+		InReplyTo:  strings.TrimSpace(msg.Header.Get("In-Reply-To")),
+		References: messageIDs(msg.Header.Get("References")),
+		From:       from[0],
+		Subject:    decodeHeader(msg.Header.Get("Subject")),
+	}
+	res.From.Address = strings.ToLower(res.From.Address)
+
+	auto := strings.ToLower(strings.TrimSpace(msg.Header.Get("Auto-Submitted")))
+	res.AutoReply = (auto != "" && auto != "no") || msg.Header.Get("X-Autoreply") != ""
+
+	var htmlText string
+	err = walkPart(textproto.MIMEHeader(msg.Header), msg.Body, res, &htmlText)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Text == "" && htmlText != "" {
+		res.Text = htmlToText(htmlText)
+	}
+	res.Text = trimQuoted(res.Text)
+
+	return res, nil
+}
+
+// walkPart of the MIME message content, recursively
+func walkPart(header textproto.MIMEHeader, body io.Reader, res *inboundMessage, htmlText *string) error {
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		parts := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			err = walkPart(part.Header, part, res, htmlText)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := decodeHeader(dparams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+
+	if disposition == "attachment" || filename != "" || !strings.HasPrefix(mediaType, "text/") {
+		if len(data) == 0 {
+			return nil
+		}
+		res.Files = append(res.Files, &attachment{
+			Name: filename,
+			Mime: mediaType,
+			Data: data,
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/html":
+		if *htmlText == "" {
+			*htmlText = string(data)
+		}
+	default:
+		if res.Text == "" {
+			res.Text = string(data)
+		}
+	}
+
+	return nil
+}
+
+var (
+	htmlBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</tr>`)
+	htmlSkip   = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	htmlTags   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText converts HTML content into plain text
+func htmlToText(s string) string {
+	s = htmlSkip.ReplaceAllString(s, "")
+	s = htmlBreaks.ReplaceAllString(s, "\n")
+	s = htmlTags.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\r", "")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = strings.Join(lines, "\n")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// trimQuoted removes the quoted history of the reply text
+func trimQuoted(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-----Original Message-----") {
+			lines = lines[:i]
+			break
+		}
+		if strings.HasPrefix(line, ">") {
+			// On <date>, <someone> wrote:
+			for i > 0 && strings.TrimSpace(lines[i-1]) == "" {
+				i--
+			}
+			if i > 0 && strings.HasSuffix(strings.TrimSpace(lines[i-1]), ":") {
+				i--
+			}
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// outboundMessage to be composed and sent
+type outboundMessage struct {
+	From       *mail.Address
+	To         *mail.Address
+	Subject    string
+	MessageID  string
+	InReplyTo  string
+	References []string
+	Date       time.Time
+	Text       string
+	Files      []*attachment
+}
+
+// Bytes returns RFC 5322 message content
+// with text/plain and text/html alternatives
+func (m *outboundMessage) Bytes() ([]byte, error) {
+
+	var (
+		out  bytes.Buffer
+		body bytes.Buffer
+		root = multipart.NewWriter(&body)
+	)
+
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	contentType := "multipart/alternative"
+	if len(m.Files) > 0 {
+		contentType = "multipart/mixed"
+	}
+
+	for _, h := range [][2]string{
+		{"From", m.From.String()},
+		{"To", m.To.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-Id", m.MessageID},
+		{"In-Reply-To", m.InReplyTo},
+		{"References", strings.Join(m.References, " ")},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", contentType + "; boundary=" + root.Boundary()},
+	} {
+		if h[1] != "" {
+			fmt.Fprintf(&out, "%s: %s\r\n", h[0], h[1])
+		}
+	}
+	out.WriteString("\r\n")
+
+	if len(m.Files) == 0 {
+		err := writeAlternatives(root, m.Text)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(body.Bytes())
+		return out.Bytes(), nil
+	}
+
+	var (
+		alt  bytes.Buffer
+		text = multipart.NewWriter(&alt)
+	)
+	err := writeAlternatives(text, m.Text)
+	if err != nil {
+		return nil, err
+	}
+	part, err := root.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + text.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(alt.Bytes()); err != nil {
+		return nil, err
+	}
+	for _, file := range m.Files {
+		err = writeAttachment(root, file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = root.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// writeAlternatives of the message text: plain and html; closes w
+func writeAlternatives(w *multipart.Writer, text string) error {
+
+	htmlText := "<html><body><p>" + strings.ReplaceAll(
+		html.EscapeString(text), "\n", "<br>\n",
+	) + "</p></body></html>"
+
+	for _, part := range []struct {
+		mime string
+		text string
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", htmlText},
+	} {
+		dst, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.mime},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+		enc := quotedprintable.NewWriter(dst)
+		if _, err = enc.Write([]byte(part.text)); err != nil {
+			return err
+		}
+		if err = enc.Close(); err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// writeAttachment file part; base64 encoded
+func writeAttachment(w *multipart.Writer, file *attachment) error {
+
+	mediaType := file.Mime
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	name := mime.QEncoding.Encode("utf-8", file.Name)
+	dst, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, map[string]string{"name": name})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": file.Name})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(dst)
+	data := base64.StdEncoding.EncodeToString(file.Data)
+	for len(data) > 76 {
+		out.WriteString(data[:76])
+		out.WriteString("\r\n")
+		data = data[76:]
+	}
+	out.WriteString(data)
+	out.WriteString("\r\n")
+
+	return out.Flush()
+}
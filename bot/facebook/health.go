@@ -0,0 +1,97 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/webitel/chat_manager/bot"
+	graph "github.com/webitel/chat_manager/bot/facebook/graph/v12.0"
+	"github.com/webitel/chat_manager/bot/facebook/webhooks"
+)
+
+// HealthCheck of the App webhook subscription
+// and the Messenger pages access tokens (debug_token)
+func (c *Client) HealthCheck(ctx context.Context, uri string) (*bot.Health, error) {
+
+	subs, err := c.subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lost := true
+	for _, sub := range subs {
+		if sub.Object == "page" && sub.Active && sub.CallbackURL == uri {
+			lost = false
+			break
+		}
+	}
+
+	// Messenger pages; Instagram accounts are linked to
+	pages, _ := c.pages.getPages()
+	names := make([]string, 0, len(pages))
+	for _, page := range pages {
+		if !page.IsAuthorized() {
+			continue // deauthorized
+		}
+		names = append(names, page.Name)
+		debug, err := c.introspect(page.GetAccessToken())
+		if err != nil {
+			return nil, fmt.Errorf("page %s (%s): %v", page.Name, page.ID, err)
+		}
+		if valid, _ := debug["is_valid"].(bool); !valid {
+			return nil, fmt.Errorf("page %s (%s): access token is invalid; authorize the page again", page.Name, page.ID)
+		}
+	}
+
+	return &bot.Health{
+		Account:     strings.Join(names, ", "),
+		WebhookLost: lost,
+	}, nil
+}
+
+// subscriptions of the App webhooks
+//
+// GET /{app-id}/subscriptions
+func (c *Client) subscriptions(ctx context.Context) ([]webhooks.Subscription, error) {
+
+	token, err := c.creds.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	query := c.requestForm(url.Values{}, token.AccessToken)
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		"https://graph.facebook.com"+path.Join(
+			"/", c.Version, c.Config.ClientID, "subscriptions",
+		)+"?"+query.Encode(), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	var res struct {
+		Data  []webhooks.Subscription `json:"data"`
+		Error *graph.Error            `json:"error,omitempty"`
+	}
+	err = json.NewDecoder(rsp.Body).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return res.Data, nil
+}
@@ -44,10 +44,20 @@ type Gateway struct {
 	loadMx *sync.Mutex
 	// cache: memory
 	*sync.RWMutex
-	internal      map[int64]*Channel  // map[internal.user.id]
-	external      map[string]*Channel // map[provider.user.id]
-	deleted       bool                // indicate whether we need to dispose this bot gateway after last channel closed
+	internal      map[int64]*Channel     // map[internal.user.id]
+	external      map[string]*Channel    // map[provider.user.id]
+	surveys       map[string]*chatSurvey // map[provider.chat.id] pending post-chat survey(s)
+	deleted       bool                   // indicate whether we need to dispose this bot gateway after last channel closed
 	storageClient storage.FileService
+	// business hours calendar; nil - open 24/7
+	hours *Schedule
+	// the last health check status; nil - not checked yet
+	status *BotStatus
+}
+
+// IsOpen reports whether t is within the bot's business hours
+func (c *Gateway) IsOpen(t time.Time) bool {
+	return c.hours.IsOpen(t)
 }
 
 type UploadedFileMetadata struct {
@@ -960,6 +970,7 @@ func (c *Gateway) Send(ctx context.Context, notify *gate.SendMessageRequest) err
 	// So active channel(s) must work with the corresponding *Gateway controller(s), that was started on !
 	gate := recepient.Gateway
 	err = gate.External.SendNotify(ctx, &sendUpdate)
+	gate.countOutbound(ctx, err)
 
 	emit := recepient.Log.With(
 		"msg", slog.GroupValue(
@@ -977,6 +988,8 @@ func (c *Gateway) Send(ctx context.Context, notify *gate.SendMessageRequest) err
 		// 	recepient.Title = recepient.Account.DisplayName()
 		// }
 		emit.Debug("[ SEND::MESSAGE ]")
+		// post-chat survey sent ? await for an answer
+		c.awaitSurvey(ctx, recepient, sendMessage)
 	} else {
 		emit.Error("[ SEND::MESSAGE ]",
 			slog.Any("error", err),
@@ -1056,6 +1069,23 @@ func (c *Gateway) Read(ctx context.Context, notify *Update) (err error) {
 	// TODO: transform envelope due to event mime-type code name
 	sendMessage := notify.Message
 
+	// post-chat survey answer ?
+	if ok, re := c.readSurvey(ctx, channel, sendMessage); ok {
+		return re
+	}
+
+	// New conversation out of the business hours ?
+	outOfHours := channel.IsNew() && !c.IsOpen(time.Now())
+	if outOfHours {
+		// Let the flow branch on the conversation variable
+		metadata, _ := channel.Properties.(map[string]string)
+		if metadata == nil {
+			metadata = make(map[string]string, 4)
+			channel.Properties = metadata
+		}
+		metadata[BusinessHoursVariable] = BusinessHoursClosed
+	}
+
 	// PERFORM: receive !
 	err = channel.Recv(ctx, sendMessage)
 	if err != nil {
@@ -1072,6 +1102,22 @@ func (c *Gateway) Read(ctx context.Context, notify *Update) (err error) {
 		}
 		return err
 	}
+	c.countInbound(ctx)
+
+	if outOfHours && !channel.IsNew() {
+		chatId := channel.SessionID
+		if chatId == "" {
+			chatId = channel.ChannelID
+		}
+		// Out of hours auto-reply; if template specified
+		err = c.SendServiceMessageByTemplate(ctx, OutOfHoursType, chatId, contact)
+		if err != nil {
+			channel.Log.Warn("bot.outOfHours",
+				slog.Any("error", err),
+			)
+			err = nil
+		}
+	}
 
 	return nil // ACK(+)
 }
@@ -0,0 +1,476 @@
+package generic
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "generic"
+	// Default request signature header; HMAC-SHA256 hex of the body
+	defaultSignHeader = "X-Webitel-Sign"
+	// Max size of the inbound request body
+	maxRequestSize = 1 << 20
+	// Max size of the file to download
+	maxFileSize = 32 << 20
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the generic JSON-over-HTTP provider.
+// Inbound webhook documents and outbound requests
+// are mapped declaratively by the profile metadata:
+//
+//	secret        [OPTIONAL] HMAC-SHA256 secret of the inbound and outbound requests
+//	sign_header   [OPTIONAL] signature header name; default: X-Webitel-Sign
+//	in.events     [OPTIONAL] path of the events array; the document itself if empty
+//	in.chat       [REQUIRED] path of the chat (sender) ID
+//	in.contact    [OPTIONAL] path of the sender contact; in.chat if empty
+//	in.name       [OPTIONAL] path of the sender display name
+//	in.text       [OPTIONAL] path of the message text
+//	in.files      [OPTIONAL] path of the message file(s)
+//	in.file.url   [OPTIONAL] path of the file URL relative to in.files
+//	in.file.name  [OPTIONAL] path of the file name relative to in.files
+//	in.file.mime  [OPTIONAL] path of the file MIME type relative to in.files
+//	in.response   [OPTIONAL] static webhook reply body
+//	out.method    [OPTIONAL] request method; default: POST
+//	out.url       [REQUIRED] request URL template
+//	out.headers   [OPTIONAL] request headers template; "Name: value" lines
+//	out.body      [REQUIRED] request body template
+//
+// Paths are JSONPath-like (see Path); templates are text/template
+// with $( ) delimiters of the Message data and json, query functions
+type Bot struct {
+	Gateway    *bot.Gateway
+	client     *http.Client
+	secret     string
+	signHeader string
+	inbound    *Inbound
+	outbound   *Outbound
+	response   string
+}
+
+// New initialize new agent.profile service generic provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	inbound, err := newInbound(profile)
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.generic.inbound.invalid",
+			"generic: %v", err,
+		)
+	}
+	outbound, err := newOutbound(profile)
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.generic.outbound.invalid",
+			"generic: %v", err,
+		)
+	}
+	signHeader := strings.TrimSpace(profile["sign_header"])
+	if signHeader == "" {
+		signHeader = defaultSignHeader
+	}
+
+	// Parse and validate message templates
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.generic.updates.invalid",
+			"generic: %v", err,
+		)
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	return &Bot{
+		Gateway:    agent,
+		client:     client,
+		secret:     profile["secret"],
+		signHeader: signHeader,
+		inbound:    inbound,
+		outbound:   outbound,
+		response:   profile["in.response"],
+	}, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register does nothing; the webhook URL
+// must be set at the remote service side
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	return nil
+}
+
+// Deregister does nothing; see Register
+func (c *Bot) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (c *Bot) Close() error {
+	return nil
+}
+
+// calculateHash of the body; HMAC-SHA256 hex
+func calculateHash(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WebHook implements provider.Receiver interface
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(reply, notice.Body, maxRequestSize))
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if c.secret != "" {
+		sign := calculateHash(body, c.secret)
+		if !hmac.Equal([]byte(sign), []byte(notice.Header.Get(c.signHeader))) {
+			c.Gateway.Log.Warn("generic/bot.webhook",
+				slog.String("error", "invalid request signature"),
+			)
+			http.Error(reply, "(403) Forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	updates, err := c.inbound.parseUpdates(body)
+	if err != nil {
+		c.Gateway.Log.Error("generic/bot.webhook",
+			slog.Any("error", err),
+		)
+		http.Error(reply, "(400) Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, update := range updates {
+		if err = c.onUpdate(notice.Context(), update); err != nil {
+			c.Gateway.Log.Error("generic/bot.onUpdate",
+				slog.Any("error", err),
+				slog.String("chat", update.Chat),
+			)
+			re := errors.FromError(err)
+			if re.Code == 0 {
+				re.Code = http.StatusBadGateway
+			}
+			http.Error(reply, re.Detail, int(re.Code))
+			return
+		}
+	}
+
+	if c.response == "" {
+		reply.WriteHeader(http.StatusOK)
+		return
+	}
+	if json.Valid([]byte(c.response)) {
+		reply.Header().Set("Content-Type", "application/json")
+	}
+	reply.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(reply, c.response)
+}
+
+// on: inbound event
+func (c *Bot) onUpdate(ctx context.Context, e *event) error {
+
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: e.Contact,
+	}
+	if e.Name != "" {
+		contact.FirstName, contact.LastName = util.ParseFullName(e.Name)
+	}
+
+	channel, err := c.Gateway.GetChannel(ctx, e.Chat, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return re // 502 Bad Gateway
+	}
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if e.Text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: e.Text,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	for _, doc := range e.Files {
+		media, err := c.getFile(ctx, doc)
+		if err != nil {
+			return err
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: media,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getFile of the remote URL into the storage
+func (c *Bot) getFile(ctx context.Context, doc *file) (*chat.File, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("generic: GET %s: %s", doc.URL, rsp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("generic: file size exceeds %d bytes", maxFileSize)
+	}
+
+	mtype := doc.Mime
+	if mtype == "" {
+		mtype, _, _ = strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	}
+	name := doc.Name
+	if name == "" {
+		name = path.Base(req.URL.Path)
+		if name == "/" || name == "." {
+			name = "file"
+		}
+		if path.Ext(name) == "" {
+			if ext, _ := mime.ExtensionsByType(mtype); len(ext) != 0 {
+				name += ext[0]
+			}
+		}
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		send    = Message{
+			Chat: channel.ChatID,
+			Type: message.Type,
+		}
+	)
+
+	switch message.Type {
+	case "text":
+		send.Text = message.GetText()
+		buttons := message.GetButtons()
+		if buttons == nil {
+			buttons = message.GetInline()
+		}
+		send.Buttons = newButtons(buttons)
+
+	case "file":
+		send.Text = message.GetText()
+		doc := message.GetFile()
+		send.File = &File{
+			URL:  doc.GetUrl(),
+			Name: doc.GetName(),
+			Mime: doc.GetMime(),
+			Size: doc.GetSize(),
+		}
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("generic/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		send.Text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("generic/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		send.Text = messageText
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("generic/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		send.Text = messageText
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	send.Text = strings.TrimSpace(send.Text)
+	if send.Text == "" && send.File == nil {
+		return nil // IGNORE: empty message text !
+	}
+
+	link, header, body, err := c.outbound.render(&send)
+	if err != nil {
+		c.Gateway.Log.Error("generic/message.render",
+			slog.Any("error", err),
+			slog.String("update", message.Type),
+		)
+		return errors.InternalServerError(
+			"chat.bot.generic.send.render",
+			"generic: %v", err,
+		)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.outbound.Method, link, bytes.NewReader(body))
+	if err != nil {
+		return errors.InternalServerError(
+			"chat.bot.generic.send.request",
+			"generic: %v", err,
+		)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.secret != "" {
+		req.Header.Set(c.signHeader, calculateHash(body, c.secret))
+	}
+
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		c.Gateway.Log.Error("generic/message.send",
+			slog.Any("error", err),
+			slog.String("chat", channel.ChatID),
+		)
+		return errors.InternalServerError(
+			"chat.bot.generic.send.request",
+			"generic: %v", err,
+		)
+	}
+	defer rsp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(rsp.Body, 4<<10))
+
+	if rsp.StatusCode/100 != 2 {
+		detail := fmt.Sprintf("generic: %s %s responded %s: %s",
+			req.Method, req.URL.Redacted(), rsp.Status, bytes.TrimSpace(snippet),
+		)
+		c.Gateway.Log.Error("generic/message.send",
+			slog.String("error", detail),
+			slog.String("chat", channel.ChatID),
+		)
+		if rsp.StatusCode/100 == 4 {
+			return errors.BadRequest("chat.bot.generic.send.rejected", "%s", detail)
+		}
+		return errors.InternalServerError("chat.bot.generic.send.rejected", "%s", detail)
+	}
+	c.Gateway.Log.Debug("generic/message.send",
+		slog.String("status", rsp.Status),
+		slog.String("chat", channel.ChatID),
+	)
+
+	return nil
+}
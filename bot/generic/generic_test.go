@@ -0,0 +1,222 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+func TestPath(t *testing.T) {
+	var doc any
+	_ = json.Unmarshal([]byte(`{"entry":[{"messaging":[{"text":"a"},{"text":"b"}]}],"x-data":{"id":7}}`), &doc)
+	for _, tc := range []struct {
+		expr string
+		want []any
+	}{
+		{"$.entry[0].messaging[*].text", []any{"a", "b"}},
+		{"entry[-1].messaging[1].text", []any{"b"}},
+		{`$["x-data"].id`, []any{float64(7)}},
+		{"$.entry[1]", nil},
+		{"", nil},
+	} {
+		path, err := ParsePath(tc.expr)
+		if err != nil {
+			t.Fatalf("ParsePath(%q) = %v", tc.expr, err)
+		}
+		got := path.Select(doc)
+		if len(got) != len(tc.want) {
+			t.Errorf("Select(%q) = %v; want %v", tc.expr, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("Select(%q) = %v; want %v", tc.expr, got, tc.want)
+			}
+		}
+	}
+	for _, expr := range []string{"$.a..b", "$.a[1", "$.a[x]"} {
+		if _, err := ParsePath(expr); err == nil {
+			t.Errorf("ParsePath(%q) = <nil>; want error", expr)
+		}
+	}
+}
+
+func TestParseUpdates(t *testing.T) {
+
+	in, err := newInbound(map[string]string{
+		"in.events":    "$.events[*]",
+		"in.chat":      "$.from.id",
+		"in.name":      "$.from.name",
+		"in.text":      "$.message.text",
+		"in.files":     "$.message.attachments[*]",
+		"in.file.url":  "link",
+		"in.file.name": "filename",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates, err := in.parseUpdates([]byte(`{"events":[
+		{"from":{"id":12345678901234567890,"name":"Alice Liddell"},"message":{"text":" Hi ","attachments":[{"link":"https://example.com/1","filename":"a.png"},{"filename":"none"}]}},
+		{"from":{"id":"u2"},"message":{}},
+		{"message":{"text":"anonymous"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("parseUpdates() = %d updates; want 1", len(updates))
+	}
+	e := updates[0]
+	// NOTE: big number is NOT rounded
+	if e.Chat != "12345678901234567890" || e.Contact != e.Chat || e.Name != "Alice Liddell" || e.Text != "Hi" {
+		t.Errorf("update = %+v", e)
+	}
+	if len(e.Files) != 1 || e.Files[0].URL != "https://example.com/1" || e.Files[0].Name != "a.png" {
+		t.Errorf("files = %+v", e.Files)
+	}
+
+	if _, err = newInbound(map[string]string{"in.text": "text"}); err == nil {
+		t.Error("newInbound(no in.chat) = <nil>; want error")
+	}
+}
+
+func TestNewOutbound(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		profile map[string]string
+	}{
+		{"url", map[string]string{"out.url": "/send", "out.body": `{}`}},
+		{"body", map[string]string{"out.url": "https://example.com", "out.body": `{"text":$(.Text)}`}},
+		{"headers", map[string]string{"out.url": "https://example.com", "out.body": `{}`, "out.headers": "Authorization"}},
+		{"template", map[string]string{"out.url": "https://example.com", "out.body": `{"text":$(json .Text}`}},
+	} {
+		if _, err := newOutbound(tc.profile); err == nil {
+			t.Errorf("newOutbound(invalid %s) = <nil>; want error", tc.name)
+		}
+	}
+}
+
+func newTestBot(t *testing.T, profile map[string]string) *Bot {
+	state, err := New(&bot.Gateway{
+		Bot: &bot.Bot{Metadata: profile},
+		Log: slog.Default(),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state.(*Bot)
+}
+
+func TestSendNotify(t *testing.T) {
+
+	var (
+		mx   sync.Mutex
+		reqs []*http.Request
+		body [][]byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mx.Lock()
+		reqs = append(reqs, r)
+		body = append(body, data)
+		mx.Unlock()
+		if r.URL.Query().Get("chat") == "blocked" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"user blocked"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	app := newTestBot(t, map[string]string{
+		"secret":      "secret",
+		"in.chat":     "$.chat",
+		"out.url":     srv.URL + "/send?chat=$(query .Chat)",
+		"out.headers": "Authorization: Bearer token\nx-type: $(.Type)",
+		"out.body":    `{"to":$(json .Chat),"text":$(json .Text)$(with .File),"url":$(json .URL)$(end)$(with .Buttons),"buttons":$(json .)$(end)}`,
+	})
+
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: `Say "hi"`, Buttons: []*chat.Buttons{{Button: []*chat.Button{{Type: "reply", Text: "Hi", Code: "hi"}}}}},
+		{Type: "file", File: &chat.File{Name: "a.pdf", Url: "https://example.com/a.pdf"}},
+	} {
+		err := app.SendNotify(context.Background(), &bot.Update{Chat: &bot.Channel{ChatID: "u 1"}, Message: message})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(reqs) != 2 {
+		t.Fatalf("sent %d requests; want 2", len(reqs))
+	}
+	req := reqs[0]
+	if req.Method != http.MethodPost || req.URL.Query().Get("chat") != "u 1" {
+		t.Errorf("request = %s %s", req.Method, req.URL)
+	}
+	if req.Header.Get("Authorization") != "Bearer token" || req.Header.Get("X-Type") != "text" || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", req.Header)
+	}
+	if req.Header.Get(defaultSignHeader) != calculateHash(body[0], "secret") {
+		t.Errorf("signature = %s", req.Header.Get(defaultSignHeader))
+	}
+	var sent struct {
+		To      string
+		Text    string
+		URL     string
+		Buttons [][]Button
+	}
+	if err := json.Unmarshal(body[0], &sent); err != nil || sent.To != "u 1" || sent.Text != `Say "hi"` || len(sent.Buttons) != 1 || sent.Buttons[0][0].Code != "hi" {
+		t.Errorf("body[0] = %s (%v)", body[0], err)
+	}
+	if err := json.Unmarshal(body[1], &sent); err != nil || sent.URL != "https://example.com/a.pdf" {
+		t.Errorf("body[1] = %s (%v)", body[1], err)
+	}
+
+	err := app.SendNotify(context.Background(), &bot.Update{Chat: &bot.Channel{ChatID: "blocked"}, Message: &chat.Message{Type: "text", Text: "Hello"}})
+	if err == nil || !strings.Contains(err.Error(), "user blocked") {
+		t.Errorf("SendNotify(blocked) = %v; want rejected", err)
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	app := newTestBot(t, map[string]string{
+		"secret":      "secret",
+		"sign_header": "X-Sign",
+		"in.chat":     "$.chat",
+		"in.text":     "$.text",
+		"in.response": `{"ok":true}`,
+		"out.url":     "https://example.com/send",
+		"out.body":    `{}`,
+	})
+
+	post := func(body, sign string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/chat/generic", strings.NewReader(body))
+		req.Header.Set("X-Sign", sign)
+		rsp := httptest.NewRecorder()
+		app.WebHook(rsp, req)
+		return rsp
+	}
+
+	body := `{"chat":"1"}` // no content; ignored
+	if rsp := post(body, calculateHash([]byte(body), "invalid")); rsp.Code != http.StatusForbidden {
+		t.Errorf("unsigned webhook = (%d); want 403", rsp.Code)
+	}
+	if rsp := post(body, calculateHash([]byte(body), "secret")); rsp.Code != http.StatusOK || rsp.Body.String() != `{"ok":true}` {
+		t.Errorf("webhook = (%d) %s; want 200", rsp.Code, rsp.Body)
+	}
+	body = `{"chat":`
+	if rsp := post(body, calculateHash([]byte(body), "secret")); rsp.Code != http.StatusBadRequest {
+		t.Errorf("invalid webhook = (%d); want 400", rsp.Code)
+	}
+}
@@ -0,0 +1,293 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	tmpl "text/template"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Inbound is the declarative mapping of the
+// incoming webhook JSON document into the chat updates
+type Inbound struct {
+	Events   Path // [OPTIONAL] events array of the document; document itself if empty
+	Chat     Path // [REQUIRED] chat (sender) ID of the event
+	Contact  Path // [OPTIONAL] contact of the sender; Chat if empty
+	Name     Path // [OPTIONAL] sender's display name
+	Text     Path // [OPTIONAL] message text
+	Files    Path // [OPTIONAL] file(s) of the event
+	FileURL  Path // [OPTIONAL] URL of the file, relative to Files; the file value itself if empty
+	FileName Path // [OPTIONAL] name of the file, relative to Files
+	FileMime Path // [OPTIONAL] MIME type of the file, relative to Files
+}
+
+// event of the inbound document mapped
+type event struct {
+	Chat    string
+	Contact string
+	Name    string
+	Text    string
+	Files   []*file
+}
+
+type file struct {
+	URL  string
+	Name string
+	Mime string
+}
+
+// newInbound mapping of the profile metadata in.* keys
+func newInbound(profile map[string]string) (*Inbound, error) {
+	var (
+		err error
+		in  Inbound
+	)
+	for _, e := range []struct {
+		key  string
+		path *Path
+	}{
+		{"in.events", &in.Events},
+		{"in.chat", &in.Chat},
+		{"in.contact", &in.Contact},
+		{"in.name", &in.Name},
+		{"in.text", &in.Text},
+		{"in.files", &in.Files},
+		{"in.file.url", &in.FileURL},
+		{"in.file.name", &in.FileName},
+		{"in.file.mime", &in.FileMime},
+	} {
+		if *(e.path), err = ParsePath(profile[e.key]); err != nil {
+			return nil, fmt.Errorf("%s: %v", e.key, err)
+		}
+	}
+	if in.Chat == nil {
+		return nil, fmt.Errorf("in.chat: path required")
+	}
+	if in.Contact == nil {
+		in.Contact = in.Chat
+	}
+	return &in, nil
+}
+
+// parseUpdates of the inbound JSON document;
+// events without chat ID or content are skipped
+func (in *Inbound) parseUpdates(body []byte) ([]*event, error) {
+
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	events := []any{doc}
+	if in.Events != nil {
+		events = in.Events.Select(doc)
+	}
+
+	var res []*event
+	for _, data := range events {
+		e := &event{
+			Chat:    in.Chat.String(data),
+			Contact: in.Contact.String(data),
+			Name:    strings.TrimSpace(in.Name.String(data)),
+			Text:    strings.TrimSpace(in.Text.String(data)),
+		}
+		for _, data := range in.Files.Select(data) {
+			doc := &file{
+				URL:  in.FileURL.String(data),
+				Name: in.FileName.String(data),
+				Mime: in.FileMime.String(data),
+			}
+			if in.FileURL == nil {
+				doc.URL, _ = data.(string)
+			}
+			if doc.URL != "" {
+				e.Files = append(e.Files, doc)
+			}
+		}
+		if e.Chat == "" || (e.Text == "" && len(e.Files) == 0) {
+			continue // IGNORE
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// Outbound is the request template
+// of the message sent to the chat
+type Outbound struct {
+	Method string
+	root   *tmpl.Template
+}
+
+// Message is the outbound template data
+type Message struct {
+	Chat    string     // chat (recipient) ID
+	Type    string     // text | file | joined | left | closed
+	Text    string     // text or file caption
+	File    *File      // file to send; nil if none
+	Buttons [][]Button // keyboard rows
+}
+
+type File struct {
+	URL  string
+	Name string
+	Mime string
+	Size int64
+}
+
+type Button struct {
+	Type string // reply | postback | url | ..
+	Text string
+	Code string
+	URL  string
+}
+
+// Outbound template names
+const (
+	templateURL     = "url"
+	templateHeaders = "headers"
+	templateBody    = "body"
+)
+
+// sampleMessage to test the outbound templates with
+var sampleMessage = Message{
+	Chat: "7654321",
+	Type: "file",
+	Text: "Hello, \"world\"!",
+	File: &File{
+		URL:  "https://example.com/any/file/url",
+		Name: "file.pdf",
+		Mime: "application/pdf",
+		Size: 1024,
+	},
+	Buttons: [][]Button{{
+		{Type: "reply", Text: "Yes", Code: "yes"},
+		{Type: "url", Text: "Site", URL: "https://example.com"},
+	}},
+}
+
+var templateFuncs = tmpl.FuncMap{
+	// json encoded value, e.g.: "text":$(json .Text)
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// query escaped value, e.g.: ?chat=$(query .Chat)
+	"query": url.QueryEscape,
+}
+
+// newOutbound request templates of the profile metadata out.* keys
+func newOutbound(profile map[string]string) (*Outbound, error) {
+
+	out := &Outbound{
+		Method: strings.ToUpper(strings.TrimSpace(profile["out.method"])),
+		root:   tmpl.New("out").Delims("$(", ")").Funcs(templateFuncs).Option("missingkey=zero"),
+	}
+	if out.Method == "" {
+		out.Method = http.MethodPost
+	}
+	for _, e := range []struct {
+		name, key string
+		required  bool
+	}{
+		{templateURL, "out.url", true},
+		{templateHeaders, "out.headers", false},
+		{templateBody, "out.body", true},
+	} {
+		text := strings.TrimSpace(profile[e.key])
+		if text == "" {
+			if e.required {
+				return nil, fmt.Errorf("%s: template required", e.key)
+			}
+			continue
+		}
+		if _, err := out.root.New(e.name).Parse(text); err != nil {
+			return nil, fmt.Errorf("%s: %v", e.key, err)
+		}
+	}
+
+	// Quick tests !
+	link, header, body, err := out.render(&sampleMessage)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint, err := url.ParseRequestURI(link); err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("out.url: invalid URL %q", link)
+	}
+	if ctype := header.Get("Content-Type"); (ctype == "" || strings.Contains(ctype, "json")) && !json.Valid(body) {
+		return nil, fmt.Errorf("out.body: invalid JSON %s", body)
+	}
+	return out, nil
+}
+
+// render the request parts of the message
+func (out *Outbound) render(msg *Message) (link string, header http.Header, body []byte, err error) {
+
+	var buf bytes.Buffer
+	execute := func(name string) ([]byte, error) {
+		buf.Reset()
+		node := out.root.Lookup(name)
+		if node == nil {
+			return nil, nil
+		}
+		if err := node.Execute(&buf, msg); err != nil {
+			return nil, fmt.Errorf("out.%s: %v", name, err)
+		}
+		return bytes.Clone(buf.Bytes()), nil
+	}
+
+	data, err := execute(templateURL)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	link = strings.TrimSpace(string(data))
+
+	if data, err = execute(templateHeaders); err != nil {
+		return "", nil, nil, err
+	}
+	header = make(http.Header)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return "", nil, nil, fmt.Errorf("out.headers: invalid line %q; want Name: value", line)
+		}
+		header.Add(textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(value))
+	}
+
+	if body, err = execute(templateBody); err != nil {
+		return "", nil, nil, err
+	}
+	return link, header, body, nil
+}
+
+// newButtons of the message keyboard
+func newButtons(rows []*chat.Buttons) [][]Button {
+	var res [][]Button
+	for _, row := range rows {
+		var line []Button
+		for _, btn := range row.GetButton() {
+			line = append(line, Button{
+				Type: btn.GetType(),
+				Text: btn.GetText(),
+				Code: btn.GetCode(),
+				URL:  btn.GetUrl(),
+			})
+		}
+		if len(line) != 0 {
+			res = append(res, line)
+		}
+	}
+	return res
+}
@@ -0,0 +1,147 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is the JSONPath-like selector of the document's values:
+//
+//	$.message.from.id
+//	$.entry[0].messaging[*].text
+//	$["x-data"].attachments[*]
+//
+// Supported: root ($, optional), child (.name, ["name"]),
+// array index ([n]) and wildcard (.* or [*]) steps
+type Path []step
+
+type step struct {
+	name  string
+	index int
+	kind  byte // 'n' name | 'i' index | '*' wildcard
+}
+
+// ParsePath expression; empty expression selects nothing
+func ParsePath(expr string) (Path, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+	rest := strings.TrimPrefix(expr, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest // relative: name.name
+	}
+	path := Path{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			n := strings.IndexAny(rest, ".[")
+			if n < 0 {
+				n = len(rest)
+			}
+			name := rest[:n]
+			rest = rest[n:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("path %q: empty name", expr)
+			case "*":
+				path = append(path, step{kind: '*'})
+			default:
+				path = append(path, step{kind: 'n', name: name})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: missing ]", expr)
+			}
+			arg := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case arg == "*":
+				path = append(path, step{kind: '*'})
+			case len(arg) > 1 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0]:
+				path = append(path, step{kind: 'n', name: arg[1 : len(arg)-1]})
+			default:
+				i, err := strconv.Atoi(arg)
+				if err != nil {
+					return nil, fmt.Errorf("path %q: invalid index [%s]", expr, arg)
+				}
+				path = append(path, step{kind: 'i', index: i})
+			}
+		default:
+			return nil, fmt.Errorf("path %q: unexpected %q", expr, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// Select values of the document; decoded as any
+func (p Path) Select(doc any) []any {
+	if p == nil {
+		return nil
+	}
+	nodes := []any{doc}
+	for _, e := range p {
+		var next []any
+		for _, node := range nodes {
+			switch e.kind {
+			case 'n':
+				if obj, ok := node.(map[string]any); ok {
+					if v, ok := obj[e.name]; ok {
+						next = append(next, v)
+					}
+				}
+			case 'i':
+				if arr, ok := node.([]any); ok {
+					i := e.index
+					if i < 0 {
+						i += len(arr) // from the end
+					}
+					if 0 <= i && i < len(arr) {
+						next = append(next, arr[i])
+					}
+				}
+			case '*':
+				switch v := node.(type) {
+				case []any:
+					next = append(next, v...)
+				case map[string]any:
+					for _, item := range v {
+						next = append(next, item)
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// String of the first value selected; empty if none
+func (p Path) String(doc any) string {
+	for _, v := range p.Select(doc) {
+		if s := toString(v); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// toString of the scalar value; JSON otherwise
+func toString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
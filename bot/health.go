@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultHealthCheckInterval of the gateways health checks
+const DefaultHealthCheckInterval = 5 * time.Minute
+
+// healthCheckTimeout of the single gateway check
+const healthCheckTimeout = 30 * time.Second
+
+// healthCheckWorkers is the max number of the concurrent gateway checks
+const healthCheckWorkers = 8
+
+// healthCheck runs the gateways health checks periodically until stop
+func (srv *Service) healthCheck(stop <-chan struct{}) {
+	interval := srv.HealthCheckInterval
+	if interval <= 0 {
+		return // disabled
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		srv.indexMx.RLock()
+		gates := make([]*Gateway, 0, len(srv.profiles))
+		for _, gate := range srv.profiles {
+			gates = append(gates, gate)
+		}
+		srv.indexMx.RUnlock()
+
+		srv.healthCheckRound(gates, interval)
+	}
+}
+
+// healthCheckRound of the gateways, by healthCheckWorkers at a time.
+// The round must complete within the interval, so the next tick
+// is NOT dropped; gateways not checked in time are skipped.
+func (srv *Service) healthCheckRound(gates []*Gateway, interval time.Duration) {
+	round, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		pool = make(chan struct{}, healthCheckWorkers)
+	)
+	for i, gate := range gates {
+		if !gate.GetEnabled() {
+			continue
+		}
+		select {
+		case pool <- struct{}{}:
+		case <-round.Done():
+			srv.Log.Warn("[ GATE::HEALTH ] round timeout; skip",
+				slog.Int("gates", len(gates)-i),
+				slog.Duration("interval", interval),
+			)
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(gate *Gateway) {
+			defer func() {
+				<-pool
+				wg.Done()
+			}()
+			ctx, cancel := context.WithTimeout(round, healthCheckTimeout)
+			defer cancel()
+			if srv.claimHealthCheck(ctx, gate, interval) {
+				_, _ = gate.HealthCheck(ctx)
+			}
+		}(gate)
+	}
+	wg.Wait()
+}
+
+// claimHealthCheck of the gateway for this node.
+// Every node runs the gateways it has loaded, so only the one
+// that claims the check first within the interval runs it;
+// others skip to NOT race on the webhook (re-)registration.
+func (srv *Service) claimHealthCheck(ctx context.Context, gate *Gateway, interval time.Duration) bool {
+	if _, is := gate.External.(HealthChecker); !is {
+		return false // not supported
+	}
+	now := time.Now()
+	ok, err := srv.store.ClaimHealthCheck(ctx,
+		gate.Bot.GetId(), now.UnixMilli(), now.Add(-interval/2).UnixMilli(),
+	)
+	if err != nil {
+		gate.Log.Error("[ GATE::HEALTH ] claim",
+			slog.Any("error", err),
+		)
+		return false
+	}
+	return ok
+}
+
+// HealthCheck probes the remote bot account if the provider supports it.
+// The lost webhook callback URL gets registered again.
+// Returns <nil> status if the provider does not support health checks.
+func (c *Gateway) HealthCheck(ctx context.Context) (*BotStatus, error) {
+
+	checker, is := c.External.(HealthChecker)
+	if !is {
+		return nil, nil // not supported
+	}
+
+	// Last status; may be checked by the other node
+	last, err := c.Internal.store.GetStatus(ctx, c.Bot.GetId())
+	if err != nil || last == nil {
+		c.RLock()
+		last = c.status
+		c.RUnlock()
+	}
+
+	var (
+		now  = time.Now()
+		stat = &BotStatus{
+			Id:       c.Bot.GetId(),
+			Provider: c.Bot.GetProvider(),
+		}
+		link = c.CallbackURL()
+	)
+	if last != nil {
+		stat.HealthyAt = last.HealthyAt
+		stat.RegisteredAt = last.RegisteredAt
+	}
+	stat.CheckedAt = now.UnixMilli()
+
+	health, err := checker.HealthCheck(ctx, link)
+	if err == nil && health != nil {
+		stat.Account = health.Account
+		if stat.WebhookLost = health.WebhookLost; stat.WebhookLost {
+			c.Log.Warn("[ GATE::HEALTH ] webhook lost",
+				slog.String("link", link),
+			)
+			// Register webhook callback URL again
+			if err = c.Register(ctx, true); err == nil {
+				stat.RegisteredAt = now.UnixMilli()
+			}
+		}
+	}
+	if err != nil {
+		stat.Error = err.Error()
+		c.Log.Error("[ GATE::HEALTH ]",
+			slog.Any("error", err),
+		)
+	} else {
+		stat.Healthy = true
+		stat.HealthyAt = now.UnixMilli()
+	}
+	c.Lock()
+	c.status = stat
+	c.Unlock()
+
+	if re := c.Internal.store.SetStatus(ctx, stat); re != nil {
+		c.Log.Error("[ GATE::HEALTH ] save",
+			slog.Any("error", re),
+		)
+	}
+
+	return stat, err
+}
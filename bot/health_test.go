@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type healthStore struct {
+	Store
+	status *BotStatus
+}
+
+func (s *healthStore) SetStatus(_ context.Context, status *BotStatus) error {
+	s.status = status
+	return nil
+}
+
+func (s *healthStore) GetStatus(context.Context, int64) (*BotStatus, error) {
+	return s.status, nil
+}
+
+type healthProvider struct {
+	Provider
+	webhook string
+	err     error
+}
+
+func (c *healthProvider) HealthCheck(_ context.Context, uri string) (*Health, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &Health{Account: "@bot", WebhookLost: c.webhook != uri}, nil
+}
+
+func (c *healthProvider) Register(_ context.Context, uri string) error {
+	c.webhook = uri
+	return nil
+}
+
+func (c *healthProvider) WebHook(http.ResponseWriter, *http.Request) {}
+
+func TestHealthCheck(t *testing.T) {
+
+	store := &healthStore{}
+	srv := NewService(store, slog.Default(), nil, nil, nil)
+	srv.URL = "https://chat.example.com"
+
+	remote := &healthProvider{}
+	gate := &Gateway{
+		Bot:      &Bot{Id: 1, Provider: "test", Uri: "/bot", Enabled: true},
+		Log:      slog.Default(),
+		Internal: srv,
+		External: remote,
+		RWMutex:  new(sync.RWMutex),
+	}
+
+	// Webhook lost; register again
+	status, err := gate.HealthCheck(context.Background())
+	if err != nil || !status.Healthy || !status.WebhookLost || status.RegisteredAt == 0 || status.Account != "@bot" {
+		t.Fatalf("check: %+v, %v", status, err)
+	}
+	if remote.webhook != "https://chat.example.com/bot" {
+		t.Errorf("webhook: %q; not registered", remote.webhook)
+	}
+	if store.status != status {
+		t.Error("status not saved")
+	}
+
+	// Token expired
+	remote.err = errors.New("Unauthorized")
+	status, err = gate.HealthCheck(context.Background())
+	if err == nil || status.Healthy || status.Error != "Unauthorized" || status.HealthyAt == 0 || status.RegisteredAt == 0 {
+		t.Errorf("check: %+v, %v", status, err)
+	}
+
+	// Not supported
+	gate.External = struct{ Provider }{remote}
+	if status, err = gate.HealthCheck(context.Background()); status != nil || err != nil {
+		t.Errorf("check: %+v, %v; not supported", status, err)
+	}
+}
+
+type roundStore struct {
+	Store
+}
+
+func (roundStore) ClaimHealthCheck(context.Context, int64, int64, int64) (bool, error) {
+	return true, nil
+}
+
+func (roundStore) GetStatus(context.Context, int64) (*BotStatus, error) { return nil, nil }
+func (roundStore) SetStatus(context.Context, *BotStatus) error          { return nil }
+
+// hangProvider never responds; until timeout
+type hangProvider struct {
+	healthProvider
+	running, peak, checks int32
+}
+
+func (c *hangProvider) HealthCheck(ctx context.Context, _ string) (*Health, error) {
+	n := atomic.AddInt32(&c.running, 1)
+	defer atomic.AddInt32(&c.running, -1)
+	for {
+		peak := atomic.LoadInt32(&c.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&c.peak, peak, n) {
+			break
+		}
+	}
+	atomic.AddInt32(&c.checks, 1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestHealthCheckRound(t *testing.T) {
+
+	srv := NewService(roundStore{}, slog.Default(), nil, nil, nil)
+	srv.URL = "https://chat.example.com"
+	remote := &hangProvider{}
+	gates := make([]*Gateway, 3*healthCheckWorkers)
+	for i := range gates {
+		gates[i] = &Gateway{
+			Bot:      &Bot{Id: int64(i + 1), Provider: "test", Uri: "/bot", Enabled: true},
+			Log:      slog.Default(),
+			Internal: srv,
+			External: remote,
+			RWMutex:  new(sync.RWMutex),
+		}
+	}
+
+	const interval = 100 * time.Millisecond
+	start := time.Now()
+	srv.healthCheckRound(gates, interval)
+	if spent := time.Since(start); spent > 2*interval {
+		t.Errorf("round: %s; expect within the %s interval", spent, interval)
+	}
+	if peak := atomic.LoadInt32(&remote.peak); peak != healthCheckWorkers {
+		t.Errorf("concurrent checks: %d; expect %d", peak, healthCheckWorkers)
+	}
+	if checks := atomic.LoadInt32(&remote.checks); checks != healthCheckWorkers {
+		t.Errorf("checks: %d; expect %d, others skipped", checks, healthCheckWorkers)
+	}
+}
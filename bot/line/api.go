@@ -0,0 +1,180 @@
+package line
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Messaging API endpoint(s)
+var (
+	endpointURL     = "https://api.line.me/v2/bot"
+	dataEndpointURL = "https://api-data.line.me/v2/bot"
+)
+
+// Error of the Messaging API
+// https://developers.line.biz/en/reference/messaging-api/#error-responses
+type Error struct {
+	Code    int    `json:"-"`
+	Message string `json:"message"`
+	Details []struct {
+		Message  string `json:"message"`
+		Property string `json:"property"`
+	} `json:"details,omitempty"`
+}
+
+func (e *Error) Error() string {
+	text := fmt.Sprintf("line: (%d) %s", e.Code, e.Message)
+	for _, detail := range e.Details {
+		text += "; " + detail.Property + ": " + detail.Message
+	}
+	return text
+}
+
+// Client of the Messaging API
+type Client struct {
+	Token string
+	HTTP  *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// do API request; req is the JSON body or nil
+func (c *Client) do(ctx context.Context, method, link string, req, res any) error {
+
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	call, err := http.NewRequestWithContext(ctx, method, link, body)
+	if err != nil {
+		return err
+	}
+	call.Header.Set("Authorization", "Bearer "+c.Token)
+	if req != nil {
+		call.Header.Set("Content-Type", "application/json")
+	}
+
+	rsp, err := c.httpClient().Do(call)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		re := &Error{Code: rsp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(rsp.Body, 4096)).Decode(re)
+		if re.Message == "" {
+			re.Message = http.StatusText(rsp.StatusCode)
+		}
+		return re
+	}
+	if res != nil {
+		return json.NewDecoder(rsp.Body).Decode(res)
+	}
+	return nil
+}
+
+// BotInfo https://developers.line.biz/en/reference/messaging-api/#get-bot-info
+type BotInfo struct {
+	UserID      string `json:"userId"`
+	BasicID     string `json:"basicId"`
+	DisplayName string `json:"displayName"`
+}
+
+func (c *Client) BotInfo(ctx context.Context) (*BotInfo, error) {
+	var res BotInfo
+	err := c.do(ctx, http.MethodGet, endpointURL+"/info", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Profile of the user
+// https://developers.line.biz/en/reference/messaging-api/#get-profile
+type Profile struct {
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	PictureURL  string `json:"pictureUrl"`
+	Language    string `json:"language"`
+}
+
+func (c *Client) Profile(ctx context.Context, userID string) (*Profile, error) {
+	var res Profile
+	err := c.do(ctx, http.MethodGet, endpointURL+"/profile/"+url.PathEscape(userID), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Reply messages using the reply token;
+// https://developers.line.biz/en/reference/messaging-api/#send-reply-message
+func (c *Client) Reply(ctx context.Context, replyToken string, messages []*Message) error {
+	return c.do(ctx, http.MethodPost, endpointURL+"/message/reply", map[string]any{
+		"replyToken": replyToken,
+		"messages":   messages,
+	}, nil)
+}
+
+// Push messages to the user, group or room;
+// https://developers.line.biz/en/reference/messaging-api/#send-push-message
+func (c *Client) Push(ctx context.Context, to string, messages []*Message) error {
+	return c.do(ctx, http.MethodPost, endpointURL+"/message/push", map[string]any{
+		"to":       to,
+		"messages": messages,
+	}, nil)
+}
+
+// Multicast messages to multiple users; max 500 per request
+// https://developers.line.biz/en/reference/messaging-api/#send-multicast-message
+func (c *Client) Multicast(ctx context.Context, to []string, messages []*Message) error {
+	return c.do(ctx, http.MethodPost, endpointURL+"/message/multicast", map[string]any{
+		"to":       to,
+		"messages": messages,
+	}, nil)
+}
+
+// Content of the message (image, video, audio, file) sent by the user
+// https://developers.line.biz/en/reference/messaging-api/#get-content
+func (c *Client) Content(ctx context.Context, messageID string, limit int64) (data []byte, mtype string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		dataEndpointURL+"/message/"+url.PathEscape(messageID)+"/content", nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", &Error{Code: rsp.StatusCode, Message: http.StatusText(rsp.StatusCode)}
+	}
+	data, err = io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("line: content size exceeds %d bytes", limit)
+	}
+	mtype, _, _ = strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	return data, mtype, nil
+}
@@ -0,0 +1,682 @@
+package line
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pbbot "github.com/webitel/chat_manager/api/proto/bot"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "line"
+	// Request signature header
+	// https://developers.line.biz/en/reference/messaging-api/#signature-validation
+	signatureHeader = "X-Line-Signature"
+	// Max size of the inbound request body
+	maxRequestSize = 1 << 20
+	// Max size of the content to download
+	maxFileSize = 32 << 20
+	// Reply token is valid for a short period after the webhook;
+	// use push message afterwards
+	replyTokenTTL = 50 * time.Second
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the LINE Official Account provider
+type Bot struct {
+	Gateway *bot.Gateway
+	client  *Client
+	secret  string
+	me      *BotInfo
+
+	mx sync.Mutex
+	// latest unused reply token; map[chat.id]
+	replies map[string]*replyToken
+	// cache of the user profiles; map[user.id]
+	profiles map[string]*Profile
+}
+
+type replyToken struct {
+	token   string
+	expires time.Time
+}
+
+// New initialize new agent.profile service LINE provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	token := profile["token"]
+	if token == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.line.token.required",
+			"line: channel access token required",
+		)
+	}
+	secret := profile["secret"]
+	if secret == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.line.secret.required",
+			"line: channel secret required",
+		)
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.line.updates.invalid",
+			"line: %v", err,
+		)
+	}
+
+	client := &Client{
+		Token: token,
+		HTTP:  &http.Client{Timeout: time.Minute},
+	}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.HTTP.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	// CHECK: Token is still valid !
+	me, err := client.BotInfo(context.TODO())
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.line.token.invalid",
+			"line: %v", err,
+		)
+	}
+
+	app := &Bot{
+		Gateway:  agent,
+		client:   client,
+		secret:   secret,
+		me:       me,
+		replies:  make(map[string]*replyToken),
+		profiles: make(map[string]*Profile),
+	}
+	// Can we upgrade latest bot account ?
+	if last, _ := state.(*Bot); last != nil && last.me.UserID == me.UserID {
+		last.mx.Lock()
+		for id, token := range last.replies {
+			app.replies[id] = token
+		}
+		last.mx.Unlock()
+	}
+
+	return app, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register does nothing; LINE channel's webhook URL
+// must be set in the LINE Developers Console
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.Gateway.Log.Info("line/bot.register",
+		slog.String("bot", c.me.BasicID),
+		slog.String("webhook_url", uri),
+	)
+	return nil
+}
+
+// Deregister does nothing; see Register
+func (c *Bot) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (c *Bot) Close() error {
+	return nil
+}
+
+// calculateSignature of the request body
+func calculateSignature(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// webhook request body
+// https://developers.line.biz/en/reference/messaging-api/#request-body
+type webhook struct {
+	Destination string   `json:"destination"`
+	Events      []*Event `json:"events"`
+}
+
+// Event of the webhook
+// https://developers.line.biz/en/reference/messaging-api/#webhook-event-objects
+type Event struct {
+	Type       string `json:"type"` // message | postback | follow | unfollow
+	ReplyToken string `json:"replyToken"`
+	Source     struct {
+		Type    string `json:"type"` // user | group | room
+		UserID  string `json:"userId"`
+		GroupID string `json:"groupId"`
+		RoomID  string `json:"roomId"`
+	} `json:"source"`
+	Timestamp      int64         `json:"timestamp"`
+	WebhookEventID string        `json:"webhookEventId"`
+	Message        *EventMessage `json:"message"`
+	Postback       *struct {
+		Data string `json:"data"`
+	} `json:"postback"`
+}
+
+// chatID is the user, group or room ID
+func (e *Event) chatID() string {
+	switch e.Source.Type {
+	case "group":
+		return e.Source.GroupID
+	case "room":
+		return e.Source.RoomID
+	}
+	return e.Source.UserID
+}
+
+// EventMessage of the message event
+// https://developers.line.biz/en/reference/messaging-api/#message-event
+type EventMessage struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"` // text | image | video | audio | file | location | sticker
+	Text      string  `json:"text"`
+	FileName  string  `json:"fileName"`
+	FileSize  int64   `json:"fileSize"`
+	Title     string  `json:"title"`
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	PackageID string  `json:"packageId"`
+	StickerID string  `json:"stickerId"`
+	// image, video, audio
+	ContentProvider struct {
+		Type               string `json:"type"` // line | external
+		OriginalContentURL string `json:"originalContentUrl"`
+	} `json:"contentProvider"`
+}
+
+// WebHook implements provider.Receiver interface for LINE
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(notice.Body, maxRequestSize))
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sign := calculateSignature(body, c.secret)
+	if !hmac.Equal([]byte(sign), []byte(notice.Header.Get(signatureHeader))) {
+		c.Gateway.Log.Warn("line/bot.webhook",
+			slog.String("error", "invalid request signature"),
+		)
+		http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update webhook
+	if err = json.Unmarshal(body, &update); err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := notice.Context()
+	for _, event := range update.Events {
+		if event.chatID() == "" {
+			continue
+		}
+		if event.ReplyToken != "" {
+			c.setReplyToken(event.chatID(), event.ReplyToken)
+		}
+		switch event.Type {
+		case "message":
+			err = c.onMessage(ctx, event)
+		case "postback":
+			err = c.onPostback(ctx, event)
+		case "follow":
+			err = c.onFollow(ctx, event)
+		case "unfollow":
+			err = c.onUnfollow(ctx, event)
+		default:
+			continue // IGNORE
+		}
+		if err != nil {
+			c.Gateway.Log.Error("line/bot.on"+strings.Title(event.Type),
+				slog.Any("error", err),
+				slog.String("event_id", event.WebhookEventID),
+			)
+			re := errors.FromError(err)
+			if re.Code == 0 {
+				re.Code = http.StatusBadGateway
+			}
+			http.Error(reply, re.Detail, int(re.Code))
+			return
+		}
+	}
+
+	reply.WriteHeader(http.StatusOK)
+}
+
+func (c *Bot) setReplyToken(chatID, token string) {
+	c.mx.Lock()
+	c.replies[chatID] = &replyToken{
+		token:   token,
+		expires: time.Now().Add(replyTokenTTL),
+	}
+	c.mx.Unlock()
+}
+
+// takeReplyToken returns fresh reply token, if any;
+// the token can be used only once
+func (c *Bot) takeReplyToken(chatID string) string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	token := c.replies[chatID]
+	delete(c.replies, chatID)
+	if token == nil || time.Now().After(token.expires) {
+		return ""
+	}
+	return token.token
+}
+
+// getProfile of the user; cached
+func (c *Bot) getProfile(ctx context.Context, userID string) *Profile {
+	c.mx.Lock()
+	profile := c.profiles[userID]
+	c.mx.Unlock()
+	if profile != nil {
+		return profile
+	}
+	profile, err := c.client.Profile(ctx, userID)
+	if err != nil {
+		c.Gateway.Log.Warn("line/bot.getProfile",
+			slog.Any("error", err),
+			slog.String("user", userID),
+		)
+		return &Profile{UserID: userID}
+	}
+	c.mx.Lock()
+	c.profiles[userID] = profile
+	c.mx.Unlock()
+	return profile
+}
+
+// getChannel of the event's source
+func (c *Bot) getChannel(ctx context.Context, event *Event) (*bot.Channel, *bot.Account, error) {
+
+	chatID := event.chatID()
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: chatID,
+	}
+	if userID := event.Source.UserID; userID != "" {
+		profile := c.getProfile(ctx, userID)
+		contact.FirstName, contact.LastName = util.ParseFullName(profile.DisplayName)
+	}
+
+	channel, err := c.Gateway.GetChannel(ctx, chatID, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return nil, nil, re // 502 Bad Gateway
+	}
+	return channel, contact, nil
+}
+
+// read message of the event
+func (c *Bot) read(ctx context.Context, event *Event, message *chat.Message) error {
+	channel, contact, err := c.getChannel(ctx, event)
+	if err != nil {
+		return err
+	}
+	return c.Gateway.Read(ctx, &bot.Update{
+		Title:   channel.Title,
+		Chat:    channel,
+		User:    contact,
+		Message: message,
+	})
+}
+
+// on: [message]
+func (c *Bot) onMessage(ctx context.Context, event *Event) error {
+
+	update := event.Message
+	if update == nil {
+		return nil
+	}
+
+	var message *chat.Message
+	switch update.Type {
+	case "text":
+		message = &chat.Message{
+			Type: "text",
+			Text: update.Text,
+		}
+
+	case "image", "video", "audio", "file":
+		file, err := c.getFile(ctx, update)
+		if err != nil {
+			return err
+		}
+		message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+
+	case "location":
+		text := fmt.Sprintf(
+			"https://www.google.com/maps/place/%f,%f",
+			update.Latitude, update.Longitude,
+		)
+		if place := strings.TrimSpace(update.Title + "\n" + update.Address); place != "" {
+			text = place + "\n" + text
+		}
+		message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+
+	case "sticker":
+		// Sticker image to display
+		message = &chat.Message{
+			Type: "file",
+			File: &chat.File{
+				Url:  "https://stickershop.line-scdn.net/stickershop/v1/sticker/" + update.StickerID + "/android/sticker.png",
+				Mime: "image/png",
+				Name: "sticker.png",
+			},
+		}
+
+	default:
+		c.Gateway.Log.Warn("line/bot.onMessage",
+			slog.String("error", "message: type \""+update.Type+"\" not supported"),
+		)
+		return nil // IGNORE
+	}
+
+	return c.read(ctx, event, message)
+}
+
+// getFile of the media message
+func (c *Bot) getFile(ctx context.Context, update *EventMessage) (*chat.File, error) {
+
+	if provider := update.ContentProvider; provider.Type == "external" {
+		// source URL to download from ...
+		return &chat.File{
+			Url:  provider.OriginalContentURL,
+			Name: update.FileName,
+		}, nil
+	}
+
+	data, mtype, err := c.client.Content(ctx, update.ID, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	name := update.FileName
+	if name == "" {
+		name = update.Type + "_" + update.ID
+		if ext, _ := mime.ExtensionsByType(mtype); len(ext) != 0 {
+			name += ext[0]
+		}
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// on: [postback]; button pressed
+func (c *Bot) onPostback(ctx context.Context, event *Event) error {
+	if event.Postback == nil || event.Postback.Data == "" {
+		return nil
+	}
+	return c.read(ctx, event, &chat.Message{
+		Type: "text",
+		Text: event.Postback.Data,
+	})
+}
+
+// on: [follow]; bot added as a friend or unblocked
+func (c *Bot) onFollow(ctx context.Context, event *Event) error {
+	channel, contact, err := c.getChannel(ctx, event)
+	if err != nil {
+		return err
+	}
+	if !channel.IsNew() {
+		return nil
+	}
+	return c.Gateway.Read(ctx, &bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+		Message: &chat.Message{
+			Type: "text",
+			Text: "/welcome",
+		},
+	})
+}
+
+// on: [unfollow]; bot blocked
+func (c *Bot) onUnfollow(ctx context.Context, event *Event) error {
+	channel, err := c.Gateway.GetChannel(ctx, event.chatID(), nil)
+	if err == nil && !channel.IsNew() {
+		err = channel.Close()
+	}
+	return err
+}
+
+// send messages; reply if fresh token available, push otherwise
+func (c *Bot) send(ctx context.Context, chatID string, messages []*Message) error {
+	if len(messages) > maxMessages {
+		messages = messages[:maxMessages]
+	}
+	if token := c.takeReplyToken(chatID); token != "" {
+		err := c.client.Reply(ctx, token, messages)
+		if err == nil {
+			return nil
+		}
+		c.Gateway.Log.Warn("line/message.reply",
+			slog.Any("error", err),
+			slog.String("chat", chatID),
+		)
+		// FALLBACK: push
+	}
+	return c.client.Push(ctx, chatID, messages)
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel  = notify.Chat
+		message  = notify.Message
+		updates  = c.Gateway.Template
+		messages []*Message
+	)
+
+	switch message.Type {
+	case "text":
+		menu := message.Buttons
+		if menu == nil {
+			menu = message.Inline
+		}
+		messages = newTextMessage(message.GetText(), menu)
+
+	case "file":
+		messages = newFileMessage(message.GetFile(), message.GetText())
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("line/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		messages = newTextMessage(messageText, nil)
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("line/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messages = newTextMessage(messageText, nil)
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("line/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messages = newTextMessage(messageText, nil)
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	if len(messages) == 0 {
+		return nil // IGNORE: empty message text !
+	}
+
+	err := c.send(ctx, channel.ChatID, messages)
+	if err != nil {
+		c.Gateway.Log.Error("line/message.push",
+			slog.Any("error", err),
+			slog.String("chat", channel.ChatID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Broadcast given `req.Message` message [to] provided `req.Peer(s)`;
+// LINE user IDs, multicast by 500 recipients per request
+func (c *Bot) BroadcastMessage(ctx context.Context, req *pbbot.BroadcastMessageRequest, rsp *pbbot.BroadcastMessageResponse) error {
+
+	setError := func(peerId string, err error) {
+		var re *status.Status
+		switch err := err.(type) {
+		case *Error:
+			re = status.New(codes.Unknown, err.Error())
+			switch err.Code {
+			case http.StatusBadRequest:
+				re = status.New(codes.InvalidArgument, err.Error())
+			case http.StatusTooManyRequests:
+				re = status.New(codes.ResourceExhausted, err.Error())
+			}
+		default:
+			re = status.New(codes.Unknown, err.Error())
+		}
+		rsp.Failure = append(rsp.Failure, &pbbot.BroadcastPeer{
+			Peer:  peerId,
+			Error: re.Proto(),
+		})
+	}
+
+	var messages []*Message
+	message := req.GetMessage()
+	switch message.GetType() {
+	case "text":
+		messages = newTextMessage(message.GetText(), nil)
+	case "file":
+		messages = newFileMessage(message.GetFile(), message.GetText())
+	}
+	if len(messages) == 0 {
+		return errors.BadRequest(
+			"chat.broadcast.message.invalid",
+			"line: broadcast message is empty",
+		)
+	}
+
+	peers := req.GetPeer()
+	for len(peers) > 0 {
+		n := min(len(peers), maxMulticastPeers)
+		batch := peers[:n]
+		peers = peers[n:]
+		if err := c.client.Multicast(ctx, batch, messages); err != nil {
+			c.Gateway.Log.Error("line/message.multicast",
+				slog.Any("error", err),
+				slog.Int("peers", len(batch)),
+			)
+			for _, peerId := range batch {
+				setError(peerId, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package line
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pbbot "github.com/webitel/chat_manager/api/proto/bot"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+func TestNewTextMessage(t *testing.T) {
+
+	// URL button(s) fit the buttons template
+	messages := newTextMessage("Need help?", []*chat.Buttons{{Button: []*chat.Button{
+		{Type: "url", Text: "FAQ", Url: "https://example.com/faq"},
+		{Type: "postback", Text: "Agent", Code: "agent"},
+	}}})
+	if len(messages) != 1 || messages[0].Type != "template" || len(messages[0].Template.Actions) != 2 {
+		t.Fatalf("template message = %+v", messages)
+	}
+
+	// quick replies; URL rendered as link
+	messages = newTextMessage("Need help?", []*chat.Buttons{
+		{Button: []*chat.Button{
+			{Type: "url", Text: "FAQ", Url: "https://example.com/faq"},
+			{Type: "reply", Text: "Agent", Code: "agent"},
+		}},
+		{Button: []*chat.Button{
+			{Type: "location", Text: "Send location"},
+			{Type: "phone", Text: "Phone"},
+		}},
+	})
+	if len(messages) != 1 || messages[0].Type != "text" {
+		t.Fatalf("text message = %+v", messages)
+	}
+	if text := messages[0].Text; text != "Need help?\nFAQ: https://example.com/faq" {
+		t.Errorf("text = %q", text)
+	}
+	items := messages[0].QuickReply.Items
+	if len(items) != 2 || items[0].Action.Data != "agent" || items[1].Action.Type != "location" {
+		t.Errorf("quick replies = %+v, %+v", items[0].Action, items[1].Action)
+	}
+
+	if messages = newTextMessage("  ", nil); len(messages) != 0 {
+		t.Errorf("empty text message = %+v", messages)
+	}
+}
+
+// messagingAPI is the local stand-in of the LINE Messaging API
+type messagingAPI struct {
+	*httptest.Server
+	mx    sync.Mutex
+	calls []string // path(s)
+	peers int      // multicast recipient(s)
+}
+
+func newMessagingAPI(t *testing.T) *messagingAPI {
+	api := &messagingAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ReplyToken string `json:"replyToken"`
+			To         any    `json:"to"`
+			Messages   []any  `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		api.mx.Lock()
+		api.calls = append(api.calls, r.URL.Path)
+		api.mx.Unlock()
+		switch r.URL.Path {
+		case "/message/reply":
+			if req.ReplyToken != "fresh" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"Invalid reply token"}`))
+				return
+			}
+		case "/message/multicast":
+			to, _ := req.To.([]any)
+			api.mx.Lock()
+			api.peers += len(to)
+			api.mx.Unlock()
+			if len(to) < maxMulticastPeers {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"The request body has 1 error(s)"}`))
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	link := endpointURL
+	t.Cleanup(func() { endpointURL = link })
+	endpointURL = api.URL
+	return api
+}
+
+func newTestBot() *Bot {
+	return &Bot{
+		Gateway:  &bot.Gateway{Log: slog.Default()},
+		client:   &Client{Token: "token"},
+		secret:   "secret",
+		me:       &BotInfo{UserID: "Ubot"},
+		replies:  make(map[string]*replyToken),
+		profiles: make(map[string]*Profile),
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	api := newMessagingAPI(t)
+	defer api.Close()
+	app := newTestBot()
+
+	channel := &bot.Channel{ChatID: "U1"}
+	send := func() {
+		err := app.SendNotify(context.Background(), &bot.Update{
+			Chat:    channel,
+			Message: &chat.Message{Type: "text", Text: "Hello"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app.setReplyToken("U1", "fresh")
+	send() // reply
+	send() // push; token used
+	app.setReplyToken("U1", "expired")
+	send() // reply fails; push
+
+	want := []string{"/message/reply", "/message/push", "/message/reply", "/message/push"}
+	if strings.Join(api.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v; want %v", api.calls, want)
+	}
+}
+
+func TestBroadcastMessage(t *testing.T) {
+
+	api := newMessagingAPI(t)
+	defer api.Close()
+	app := newTestBot()
+
+	req := &pbbot.BroadcastMessageRequest{
+		Message: &chat.Message{Type: "text", Text: "News"},
+	}
+	for i := 0; i < maxMulticastPeers+2; i++ {
+		req.Peer = append(req.Peer, "U"+strconv.Itoa(i))
+	}
+	var rsp pbbot.BroadcastMessageResponse
+	err := app.BroadcastMessage(context.Background(), req, &rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.peers != len(req.Peer) {
+		t.Errorf("multicast peers = %d; want %d", api.peers, len(req.Peer))
+	}
+	// last batch failed
+	if len(rsp.Failure) != 2 || rsp.Failure[0].Peer != "U500" {
+		t.Errorf("failure = %v", rsp.Failure)
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	app := newTestBot()
+	body := `{"destination":"Ubot","events":[]}`
+
+	for _, tc := range []struct {
+		sign string
+		code int
+	}{
+		{"invalid", http.StatusUnauthorized},
+		{calculateSignature([]byte(body), app.secret), http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(signatureHeader, tc.sign)
+		rsp := httptest.NewRecorder()
+		app.WebHook(rsp, req)
+		if rsp.Code != tc.code {
+			t.Errorf("WebHook(sign: %s) = (%d); want %d", tc.sign, rsp.Code, tc.code)
+		}
+	}
+
+	// reply token expires
+	app.replies["U1"] = &replyToken{token: "t", expires: time.Now().Add(-time.Second)}
+	if token := app.takeReplyToken("U1"); token != "" {
+		t.Errorf("takeReplyToken() = %q; want expired", token)
+	}
+}
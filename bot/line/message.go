@@ -0,0 +1,198 @@
+package line
+
+import (
+	"strings"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Message object limits
+// https://developers.line.biz/en/reference/messaging-api/#message-objects
+const (
+	maxMessages        = 5    // per request
+	maxTextLength      = 5000 // characters
+	maxQuickReplies    = 13
+	maxTemplateActions = 4
+	maxTemplateText    = 160 // characters; without image
+	maxActionLabel     = 20  // characters
+	maxPostbackData    = 300
+	maxAltText         = 400
+	maxMulticastPeers  = 500
+)
+
+// Message object to be sent
+type Message struct {
+	Type string `json:"type"` // text | image | template
+	Text string `json:"text,omitempty"`
+	// image
+	OriginalContentURL string `json:"originalContentUrl,omitempty"`
+	PreviewImageURL    string `json:"previewImageUrl,omitempty"`
+	// template
+	AltText  string    `json:"altText,omitempty"`
+	Template *Template `json:"template,omitempty"`
+
+	QuickReply *QuickReply `json:"quickReply,omitempty"`
+}
+
+// Template of the buttons type
+// https://developers.line.biz/en/reference/messaging-api/#buttons
+type Template struct {
+	Type    string    `json:"type"` // buttons
+	Text    string    `json:"text"`
+	Actions []*Action `json:"actions"`
+}
+
+// QuickReply buttons
+// https://developers.line.biz/en/reference/messaging-api/#quick-reply
+type QuickReply struct {
+	Items []*QuickReplyItem `json:"items"`
+}
+
+type QuickReplyItem struct {
+	Type   string  `json:"type"` // action
+	Action *Action `json:"action"`
+}
+
+// Action object
+// https://developers.line.biz/en/reference/messaging-api/#action-objects
+type Action struct {
+	Type        string `json:"type"` // postback | uri | location
+	Label       string `json:"label"`
+	Data        string `json:"data,omitempty"`
+	DisplayText string `json:"displayText,omitempty"`
+	URI         string `json:"uri,omitempty"`
+}
+
+// truncate s to n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func buttonLabel(button *chat.Button) string {
+	label := strings.TrimSpace(button.GetText())
+	if label == "" {
+		label = strings.TrimSpace(button.GetCaption())
+	}
+	return label
+}
+
+// newTextMessage renders text with the buttons.
+// URL buttons are rendered as the buttons template, if possible;
+// reply, postback and location buttons as quick replies.
+func newTextMessage(text string, rows []*chat.Buttons) []*Message {
+
+	var (
+		links   []*Action
+		replies []*Action
+	)
+	for _, row := range rows {
+		for _, button := range row.GetButton() {
+			label := buttonLabel(button)
+			if label == "" {
+				continue
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if button.GetUrl() == "" {
+					continue
+				}
+				links = append(links, &Action{
+					Type:  "uri",
+					Label: truncate(label, maxActionLabel),
+					URI:   button.GetUrl(),
+				})
+			case "reply", "postback":
+				data := button.GetCode()
+				if data == "" {
+					data = label
+				}
+				if len(data) > maxPostbackData {
+					continue
+				}
+				replies = append(replies, &Action{
+					Type:        "postback",
+					Label:       truncate(label, maxActionLabel),
+					Data:        data,
+					DisplayText: label,
+				})
+			case "location":
+				replies = append(replies, &Action{
+					Type:  "location",
+					Label: truncate(label, maxActionLabel),
+				})
+			default:
+				continue // NOT supported
+			}
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if len(links) != 0 && text != "" && len([]rune(text)) <= maxTemplateText &&
+		len(links)+len(replies) <= maxTemplateActions && !hasLocation(replies) {
+		// Buttons template
+		return []*Message{{
+			Type:     "template",
+			AltText:  truncate(text, maxAltText),
+			Template: &Template{Type: "buttons", Text: text, Actions: append(links, replies...)},
+		}}
+	}
+
+	// URL buttons as the text links
+	for _, link := range links {
+		text += "\n" + link.Label + ": " + link.URI
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	message := &Message{
+		Type: "text",
+		Text: truncate(text, maxTextLength),
+	}
+	if len(replies) > maxQuickReplies {
+		replies = replies[:maxQuickReplies]
+	}
+	if len(replies) != 0 {
+		message.QuickReply = &QuickReply{}
+		for _, action := range replies {
+			message.QuickReply.Items = append(message.QuickReply.Items,
+				&QuickReplyItem{Type: "action", Action: action},
+			)
+		}
+	}
+	return []*Message{message}
+}
+
+// hasLocation action; NOT allowed in templates
+func hasLocation(actions []*Action) bool {
+	for _, action := range actions {
+		if action.Type == "location" {
+			return true
+		}
+	}
+	return false
+}
+
+// newFileMessage renders the file; images are sent as is,
+// other files as the link since LINE does not accept documents
+// and requires preview image or duration for the video and audio
+func newFileMessage(file *chat.File, caption string) []*Message {
+	var messages []*Message
+	if caption = strings.TrimSpace(caption); caption != "" {
+		messages = append(messages, &Message{Type: "text", Text: truncate(caption, maxTextLength)})
+	}
+	if strings.HasPrefix(file.GetMime(), "image/") && strings.HasPrefix(file.GetUrl(), "https://") {
+		return append(messages, &Message{
+			Type:               "image",
+			OriginalContentURL: file.GetUrl(),
+			PreviewImageURL:    file.GetUrl(),
+		})
+	}
+	return append(messages, &Message{
+		Type: "text",
+		Text: file.GetName() + "\n" + file.GetUrl(),
+	})
+}
@@ -0,0 +1,257 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client-Server API path prefix(es)
+const (
+	clientPath      = "/_matrix/client/v3"
+	mediaPath       = "/_matrix/media/v3"
+	clientMediaPath = "/_matrix/client/v1/media" // authenticated media
+)
+
+// Error of the Client-Server API
+// https://spec.matrix.org/v1.11/client-server-api/#standard-error-response
+type Error struct {
+	Status  int    `json:"-"`
+	Code    string `json:"errcode"`
+	Message string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("matrix: (%d) %s %s", e.Status, e.Code, e.Message)
+}
+
+// Client of the homeserver on behalf of the bot account
+type Client struct {
+	Homeserver string // base URL
+	Token      string // access token
+	HTTP       *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// do API request; req is the JSON body or nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, req, res any) error {
+
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	link := strings.TrimRight(c.Homeserver, "/") + path
+	if len(query) != 0 {
+		link += "?" + query.Encode()
+	}
+	call, err := http.NewRequestWithContext(ctx, method, link, body)
+	if err != nil {
+		return err
+	}
+	call.Header.Set("Authorization", "Bearer "+c.Token)
+	if req != nil {
+		call.Header.Set("Content-Type", "application/json")
+	}
+	return c.roundTrip(call, res)
+}
+
+func (c *Client) roundTrip(req *http.Request, res any) error {
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		re := &Error{Status: rsp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(rsp.Body, 4096)).Decode(re)
+		if re.Message == "" {
+			re.Message = http.StatusText(rsp.StatusCode)
+		}
+		return re
+	}
+	if res != nil {
+		return json.NewDecoder(rsp.Body).Decode(res)
+	}
+	return nil
+}
+
+// Whoami returns the bot's user ID
+func (c *Client) Whoami(ctx context.Context) (string, error) {
+	var res struct {
+		UserID string `json:"user_id"`
+	}
+	err := c.do(ctx, http.MethodGet, clientPath+"/account/whoami", nil, nil, &res)
+	if err != nil {
+		return "", err
+	}
+	return res.UserID, nil
+}
+
+// Sync long-polls the homeserver for updates since the batch token
+// https://spec.matrix.org/v1.11/client-server-api/#get_matrixclientv3sync
+func (c *Client) Sync(ctx context.Context, since, filter string, timeout time.Duration) (*SyncResponse, error) {
+	query := url.Values{
+		"timeout": {strconv.FormatInt(timeout.Milliseconds(), 10)},
+	}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	var res SyncResponse
+	err := c.do(ctx, http.MethodGet, clientPath+"/sync", query, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func roomPath(roomID string) string {
+	return clientPath + "/rooms/" + url.PathEscape(roomID)
+}
+
+// JoinRoom the bot is invited to
+func (c *Client) JoinRoom(ctx context.Context, roomID string) error {
+	return c.do(ctx, http.MethodPost, roomPath(roomID)+"/join", nil, struct{}{}, nil)
+}
+
+// LeaveRoom or decline the invite
+func (c *Client) LeaveRoom(ctx context.Context, roomID string) error {
+	return c.do(ctx, http.MethodPost, roomPath(roomID)+"/leave", nil, struct{}{}, nil)
+}
+
+// Member of the room
+type Member struct {
+	DisplayName string `json:"display_name"`
+}
+
+// JoinedMembers of the room; map[user.id]
+func (c *Client) JoinedMembers(ctx context.Context, roomID string) (map[string]*Member, error) {
+	var res struct {
+		Joined map[string]*Member `json:"joined"`
+	}
+	err := c.do(ctx, http.MethodGet, roomPath(roomID)+"/joined_members", nil, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Joined, nil
+}
+
+// SendMessage event to the room; returns the event ID
+func (c *Client) SendMessage(ctx context.Context, roomID string, content *MessageContent) (string, error) {
+	var res struct {
+		EventID string `json:"event_id"`
+	}
+	err := c.do(ctx, http.MethodPut,
+		roomPath(roomID)+"/send/m.room.message/"+uuid.NewString(),
+		nil, content, &res,
+	)
+	if err != nil {
+		return "", err
+	}
+	return res.EventID, nil
+}
+
+// Typing notification of the user in the room
+func (c *Client) Typing(ctx context.Context, roomID, userID string, typing bool, timeout time.Duration) error {
+	req := map[string]any{"typing": typing}
+	if typing {
+		req["timeout"] = timeout.Milliseconds()
+	}
+	return c.do(ctx, http.MethodPut, roomPath(roomID)+"/typing/"+url.PathEscape(userID), nil, req, nil)
+}
+
+// ReadReceipt for the event in the room
+func (c *Client) ReadReceipt(ctx context.Context, roomID, eventID string) error {
+	return c.do(ctx, http.MethodPost, roomPath(roomID)+"/receipt/m.read/"+url.PathEscape(eventID), nil, struct{}{}, nil)
+}
+
+// Upload content to the media repository; returns the mxc:// URI
+func (c *Client) Upload(ctx context.Context, name, mtype string, data []byte) (string, error) {
+	link := strings.TrimRight(c.Homeserver, "/") + mediaPath + "/upload?" +
+		url.Values{"filename": {name}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, link, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", mtype)
+	var res struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err = c.roundTrip(req, &res); err != nil {
+		return "", err
+	}
+	return res.ContentURI, nil
+}
+
+// Download content of the mxc:// URI from the media repository;
+// authenticated media, falls back to the legacy endpoint
+func (c *Client) Download(ctx context.Context, mxc string, limit int64) (data []byte, mtype string, err error) {
+	server, mediaID, ok := strings.Cut(strings.TrimPrefix(mxc, "mxc://"), "/")
+	if !ok || !strings.HasPrefix(mxc, "mxc://") || server == "" || mediaID == "" {
+		return nil, "", fmt.Errorf("matrix: invalid content URI %q", mxc)
+	}
+	media := "/download/" + url.PathEscape(server) + "/" + url.PathEscape(mediaID)
+	data, mtype, err = c.download(ctx, clientMediaPath+media, limit)
+	if re, _ := err.(*Error); re != nil && (re.Status == http.StatusNotFound || re.Code == "M_UNRECOGNIZED") {
+		data, mtype, err = c.download(ctx, mediaPath+media, limit)
+	}
+	return data, mtype, err
+}
+
+func (c *Client) download(ctx context.Context, path string, limit int64) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.Homeserver, "/")+path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	return fetch(c.httpClient(), req, limit)
+}
+
+// fetch the content; limit size
+func fetch(client *http.Client, req *http.Request, limit int64) ([]byte, string, error) {
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		re := &Error{Status: rsp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(rsp.Body, 4096)).Decode(re)
+		if re.Message == "" {
+			re.Message = http.StatusText(rsp.StatusCode)
+		}
+		return nil, "", re
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("matrix: content size exceeds %d bytes", limit)
+	}
+	mtype, _, _ := strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	return data, mtype, nil
+}
@@ -0,0 +1,613 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "matrix"
+	// Max size of the content to download
+	maxFileSize = 32 << 20
+	// Typing notification timeout while uploading the file
+	typingTimeout = 30 * time.Second
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the Matrix bot account provider.
+// Direct rooms are synced by the single node
+// owning the sync; a conversation per room
+type Bot struct {
+	Gateway *bot.Gateway
+	client  *Client
+	userID  string
+	// Sync of the account; nil unless owned by this node
+	syncer *Syncer
+
+	mx sync.Mutex
+	// cache of the rooms joined; map[room.id]
+	rooms map[string]*room
+	// latest options sent; map[room.id]map[number|label]code
+	menus map[string]map[string]string
+}
+
+// room joined by the bot
+type room struct {
+	direct bool
+	peer   string // user.id; direct room
+	name   string // peer's display name
+}
+
+// New initialize new agent.profile service Matrix provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	homeserver, err := url.ParseRequestURI(profile["homeserver"])
+	if err != nil || (homeserver.Scheme != "https" && homeserver.Scheme != "http") || homeserver.Host == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.matrix.homeserver.invalid",
+			"matrix: homeserver URL required",
+		)
+	}
+	token := profile["token"]
+	if token == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.matrix.token.required",
+			"matrix: access token required",
+		)
+	}
+
+	// Parse and validate message templates
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.matrix.updates.invalid",
+			"matrix: %v", err,
+		)
+	}
+
+	client := &Client{
+		Homeserver: homeserver.String(),
+		Token:      token,
+		// MUST exceed the /sync long-polling timeout
+		HTTP: &http.Client{Timeout: syncTimeout + time.Minute},
+	}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.HTTP.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	// CHECK: Token is still valid !
+	userID, err := client.Whoami(context.TODO())
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.matrix.token.invalid",
+			"matrix: %v", err,
+		)
+	}
+
+	app := &Bot{
+		Gateway: agent,
+		client:  client,
+		userID:  userID,
+		rooms:   make(map[string]*room),
+		menus:   make(map[string]map[string]string),
+	}
+	if bot.OwnsSession(profile["sync"]) {
+		app.syncer = &Syncer{
+			Client: client,
+			Filter: syncFilter,
+			Log:    agent.Log,
+			OnSync: app.onSync,
+		}
+	}
+
+	// Latest (current) state
+	if last, _ := state.(*Bot); last != nil {
+		_ = last.Close()
+		// Can we upgrade latest bot account ?
+		if last.userID == userID {
+			if app.syncer != nil && last.syncer != nil {
+				app.syncer.since = last.syncer.since
+			}
+			last.mx.Lock()
+			for id, room := range last.rooms {
+				app.rooms[id] = room
+			}
+			for id, menu := range last.menus {
+				app.menus[id] = menu
+			}
+			last.mx.Unlock()
+		}
+	}
+
+	if app.syncer != nil && agent.Bot.GetEnabled() {
+		app.syncer.Start()
+	}
+
+	return app, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register starts the sync, if owned by this node
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	if c.syncer == nil {
+		c.Gateway.Log.Warn("matrix/bot.register",
+			slog.String("bot", c.userID),
+			slog.String("error", "sync is not owned by this node"),
+		)
+		return nil
+	}
+	c.syncer.Start()
+	return nil
+}
+
+// Deregister stops the sync
+func (c *Bot) Deregister(ctx context.Context) error {
+	if c.syncer != nil {
+		c.syncer.Close()
+	}
+	return nil
+}
+
+func (c *Bot) Close() error {
+	if c.syncer != nil {
+		c.syncer.Close()
+	}
+	return nil
+}
+
+// WebHook is not supported; events are received over the sync
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+	http.Error(reply, "matrix: webhook not supported", http.StatusNotFound)
+}
+
+// onSync handles invites and the new events of the rooms joined
+func (c *Bot) onSync(ctx context.Context, res *SyncResponse, initial bool) {
+	for roomID, invite := range res.Rooms.Invite {
+		if err := c.onInvite(ctx, roomID, invite.InviteState.Events); err != nil {
+			c.Gateway.Log.Error("matrix/bot.onInvite",
+				slog.Any("error", err),
+				slog.String("room", roomID),
+			)
+		}
+	}
+	if initial {
+		return // history
+	}
+	for roomID, joined := range res.Rooms.Join {
+		for _, event := range joined.Timeline.Events {
+			if err := c.onEvent(ctx, roomID, event); err != nil {
+				c.Gateway.Log.Error("matrix/bot.onEvent",
+					slog.Any("error", err),
+					slog.String("room", roomID),
+					slog.String("event", event.EventID),
+				)
+			}
+		}
+	}
+}
+
+// memberEvent of the user in the events, if any
+func memberEvent(events []*Event, userID string) (*Event, *MemberContent) {
+	for _, event := range events {
+		if event.Type != "m.room.member" || event.StateKey == nil || *event.StateKey != userID {
+			continue
+		}
+		var member MemberContent
+		if json.Unmarshal(event.Content, &member) == nil {
+			return event, &member
+		}
+	}
+	return nil, nil
+}
+
+// on: invite; joins direct rooms only, declines others
+func (c *Bot) onInvite(ctx context.Context, roomID string, events []*Event) error {
+
+	invite, member := memberEvent(events, c.userID)
+	if member == nil || member.Membership != "invite" {
+		return nil
+	}
+	if !member.IsDirect {
+		c.Gateway.Log.Info("matrix/bot.onInvite",
+			slog.String("room", roomID),
+			slog.String("decline", "not a direct room"),
+		)
+		return c.client.LeaveRoom(ctx, roomID)
+	}
+	if err := c.client.JoinRoom(ctx, roomID); err != nil {
+		return err
+	}
+
+	peer := &room{direct: true, peer: invite.Sender}
+	if _, inviter := memberEvent(events, invite.Sender); inviter != nil {
+		peer.name = inviter.DisplayName
+	}
+	c.mx.Lock()
+	c.rooms[roomID] = peer
+	c.mx.Unlock()
+
+	channel, contact, err := c.getChannel(ctx, roomID, peer)
+	if err != nil {
+		return err
+	}
+	if !channel.IsNew() {
+		return nil
+	}
+	return c.Gateway.Read(ctx, &bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+		Message: &chat.Message{
+			Type: "text",
+			Text: "/welcome",
+		},
+	})
+}
+
+// getRoom joined; cached
+func (c *Bot) getRoom(ctx context.Context, roomID string) (*room, error) {
+	c.mx.Lock()
+	joined := c.rooms[roomID]
+	c.mx.Unlock()
+	if joined != nil {
+		return joined, nil
+	}
+	members, err := c.client.JoinedMembers(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	joined = &room{}
+	if _, ok := members[c.userID]; ok && len(members) == 2 {
+		joined.direct = true
+		for userID, member := range members {
+			if userID != c.userID {
+				joined.peer = userID
+				joined.name = member.DisplayName
+			}
+		}
+	}
+	c.mx.Lock()
+	c.rooms[roomID] = joined
+	c.mx.Unlock()
+	return joined, nil
+}
+
+// getChannel of the direct room
+func (c *Bot) getChannel(ctx context.Context, roomID string, peer *room) (*bot.Channel, *bot.Account, error) {
+
+	contact := &bot.Account{
+		ID:       0, // LOOKUP
+		Channel:  provider,
+		Contact:  roomID,
+		Username: peer.peer,
+	}
+	name := peer.name
+	if name == "" {
+		// @localpart:server
+		name, _, _ = strings.Cut(strings.TrimPrefix(peer.peer, "@"), ":")
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(name)
+
+	channel, err := c.Gateway.GetChannel(ctx, roomID, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return nil, nil, re // 502 Bad Gateway
+	}
+	return channel, contact, nil
+}
+
+// on: room event; direct rooms only
+func (c *Bot) onEvent(ctx context.Context, roomID string, event *Event) error {
+
+	if event.Sender == c.userID {
+		return nil // ECHO
+	}
+
+	switch event.Type {
+	case "m.room.member":
+		var member MemberContent
+		if event.StateKey == nil || *event.StateKey != event.Sender ||
+			json.Unmarshal(event.Content, &member) != nil || member.Membership != "leave" {
+			return nil
+		}
+		return c.onLeave(ctx, roomID, event.Sender)
+
+	case "m.room.encrypted":
+		c.Gateway.Log.Warn("matrix/bot.onEvent",
+			slog.String("error", "encrypted rooms not supported"),
+			slog.String("room", roomID),
+		)
+		return nil
+
+	case "m.room.message":
+		// below
+	default:
+		return nil // IGNORE
+	}
+
+	peer, err := c.getRoom(ctx, roomID)
+	if err != nil || !peer.direct {
+		return err
+	}
+
+	var content MessageContent
+	if err = json.Unmarshal(event.Content, &content); err != nil {
+		return err
+	}
+	var message *chat.Message
+	if text := messageText(&content); text != "" {
+		message = &chat.Message{
+			Type: "text",
+			Text: c.takeOption(roomID, text),
+		}
+	} else if content.URL != "" {
+		file, err := c.getFile(ctx, &content)
+		if err != nil {
+			return err
+		}
+		message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+	} else {
+		return nil // IGNORE
+	}
+
+	channel, contact, err := c.getChannel(ctx, roomID, peer)
+	if err != nil {
+		return err
+	}
+	err = c.Gateway.Read(ctx, &bot.Update{
+		Title:   channel.Title,
+		Chat:    channel,
+		User:    contact,
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+	// Received !
+	if err = c.client.ReadReceipt(ctx, roomID, event.EventID); err != nil {
+		c.Gateway.Log.Warn("matrix/bot.readReceipt",
+			slog.Any("error", err),
+			slog.String("room", roomID),
+		)
+	}
+	return nil
+}
+
+// on: peer left the direct room; close the conversation
+func (c *Bot) onLeave(ctx context.Context, roomID, userID string) error {
+	c.mx.Lock()
+	peer := c.rooms[roomID]
+	delete(c.rooms, roomID)
+	delete(c.menus, roomID)
+	c.mx.Unlock()
+	if peer == nil || !peer.direct || peer.peer != userID {
+		return nil
+	}
+	channel, err := c.Gateway.GetChannel(ctx, roomID, nil)
+	if err == nil && !channel.IsNew() {
+		err = channel.Close()
+	}
+	if err != nil {
+		return err
+	}
+	return c.client.LeaveRoom(ctx, roomID)
+}
+
+// takeOption code of the options sent to the room, if text selects one
+func (c *Bot) takeOption(roomID, text string) string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	code, ok := c.menus[roomID][strings.ToLower(text)]
+	if !ok {
+		return text
+	}
+	delete(c.menus, roomID)
+	return code
+}
+
+// getFile of the media message
+func (c *Bot) getFile(ctx context.Context, content *MessageContent) (*chat.File, error) {
+
+	data, mtype, err := c.client.Download(ctx, content.URL, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	if content.Info != nil && content.Info.MimeType != "" {
+		mtype = content.Info.MimeType
+	}
+	name := content.Filename
+	if name == "" {
+		name = content.Body
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// uploadFile to the media repository; typing meanwhile
+func (c *Bot) uploadFile(ctx context.Context, roomID string, file *chat.File) (*MessageContent, error) {
+
+	_ = c.client.Typing(ctx, roomID, c.userID, true, typingTimeout)
+	defer func() {
+		_ = c.client.Typing(ctx, roomID, c.userID, false, 0)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.GetUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, mtype, err := fetch(c.client.httpClient(), req, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	if file.GetMime() == "" {
+		file.Mime = mtype
+	}
+	mxc, err := c.client.Upload(ctx, file.GetName(), file.GetMime(), data)
+	if err != nil {
+		return nil, err
+	}
+	return newFileContent(file, mxc, int64(len(data))), nil
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel  = notify.Chat
+		message  = notify.Message
+		updates  = c.Gateway.Template
+		roomID   = channel.ChatID
+		contents []*MessageContent
+	)
+
+	switch message.Type {
+	case "text":
+		menu := message.Buttons
+		if menu == nil {
+			menu = message.Inline
+		}
+		content, options := newTextContent(message.GetText(), menu)
+		c.mx.Lock()
+		if options != nil {
+			c.menus[roomID] = options
+		} else {
+			delete(c.menus, roomID)
+		}
+		c.mx.Unlock()
+		if content != nil {
+			contents = append(contents, content)
+		}
+
+	case "file":
+		if caption, _ := newTextContent(message.GetText(), nil); caption != nil {
+			contents = append(contents, caption)
+		}
+		content, err := c.uploadFile(ctx, roomID, message.GetFile())
+		if err != nil {
+			c.Gateway.Log.Error("matrix/media.upload",
+				slog.Any("error", err),
+				slog.String("room", roomID),
+			)
+			return err
+		}
+		contents = append(contents, content)
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("matrix/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		content, _ := newTextContent(messageText, nil)
+		contents = append(contents, content)
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("matrix/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if content, _ := newTextContent(messageText, nil); content != nil {
+			contents = append(contents, content)
+		}
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("matrix/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if content, _ := newTextContent(messageText, nil); content != nil {
+			contents = append(contents, content)
+		}
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	for _, content := range contents {
+		_, err := c.client.SendMessage(ctx, roomID, content)
+		if err != nil {
+			c.Gateway.Log.Error("matrix/message.send",
+				slog.Any("error", err),
+				slog.String("room", roomID),
+			)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+const testUserID = "@helpdesk:example.org"
+
+// homeserver is the local stand-in of the Matrix homeserver
+type homeserver struct {
+	*httptest.Server
+
+	mx     sync.Mutex
+	calls  []string          // method path(s)
+	sent   []*MessageContent // m.room.message(s)
+	since  []string          // /sync batch token(s) requested
+	syncs  []string          // /sync response(s) queue
+	typing []bool
+}
+
+func newHomeserver(t *testing.T) *homeserver {
+	hs := &homeserver{}
+	hs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// public content; agent's file
+		if r.URL.Path == "/files/1" {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png"))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`))
+			return
+		}
+		path := r.URL.Path
+		hs.mx.Lock()
+		hs.calls = append(hs.calls, r.Method+" "+path)
+		hs.mx.Unlock()
+
+		switch {
+		case path == clientPath+"/account/whoami":
+			_, _ = w.Write([]byte(`{"user_id":"` + testUserID + `"}`))
+
+		case path == clientPath+"/sync":
+			hs.mx.Lock()
+			hs.since = append(hs.since, r.URL.Query().Get("since"))
+			var res string
+			if len(hs.syncs) != 0 {
+				res, hs.syncs = hs.syncs[0], hs.syncs[1:]
+			}
+			hs.mx.Unlock()
+			if res == "" {
+				// long-polling; no updates
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+				res = `{"next_batch":"idle"}`
+			}
+			_, _ = w.Write([]byte(res))
+
+		case strings.HasSuffix(path, "/joined_members"):
+			_, _ = w.Write([]byte(`{"joined":{"` + testUserID + `":{},"@alice:example.org":{"display_name":"Alice Liddell"}}}`))
+
+		case strings.Contains(path, "/send/m.room.message/"):
+			var content MessageContent
+			_ = json.NewDecoder(r.Body).Decode(&content)
+			hs.mx.Lock()
+			hs.sent = append(hs.sent, &content)
+			hs.mx.Unlock()
+			_, _ = w.Write([]byte(`{"event_id":"$sent"}`))
+
+		case strings.Contains(path, "/typing/"):
+			var req struct {
+				Typing bool `json:"typing"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			hs.mx.Lock()
+			hs.typing = append(hs.typing, req.Typing)
+			hs.mx.Unlock()
+			_, _ = w.Write([]byte(`{}`))
+
+		case path == mediaPath+"/upload":
+			data, _ := io.ReadAll(r.Body)
+			if string(data) != "png" || r.Header.Get("Content-Type") != "image/png" || r.URL.Query().Get("filename") != "photo.png" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errcode":"M_BAD_JSON","error":"unexpected content"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"content_uri":"mxc://example.org/photo"}`))
+
+		case path == mediaPath+"/download/example.org/legacy":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("legacy"))
+
+		case strings.HasSuffix(path, "/join"), strings.HasSuffix(path, "/leave"), strings.Contains(path, "/receipt/"):
+			_, _ = w.Write([]byte(`{}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errcode":"M_UNRECOGNIZED","error":"Unrecognized request"}`))
+		}
+	}))
+	t.Cleanup(hs.Close)
+	return hs
+}
+
+func newTestBot(hs *homeserver) *Bot {
+	return &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		client:  &Client{Homeserver: hs.URL, Token: "token"},
+		userID:  testUserID,
+		rooms:   make(map[string]*room),
+		menus:   make(map[string]map[string]string),
+	}
+}
+
+func TestNewTextContent(t *testing.T) {
+
+	content, menu := newTextContent("Rate us", []*chat.Buttons{{Button: []*chat.Button{
+		{Type: "reply", Text: "Good", Code: "5"},
+		{Type: "postback", Text: "Bad", Code: "1"},
+		{Type: "url", Text: "Site", Url: "https://example.com"},
+		{Type: "location", Text: "Location"}, // NOT supported
+	}}})
+	if want := "Rate us\n\n1. Good\n2. Bad\nSite: https://example.com"; content.Body != want {
+		t.Errorf("body = %q; want %q", content.Body, want)
+	}
+	if menu["1"] != "5" || menu["bad"] != "1" || len(menu) != 4 {
+		t.Errorf("menu = %v", menu)
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	for _, tc := range []struct {
+		content MessageContent
+		text    string
+	}{
+		{MessageContent{MsgType: "m.text", Body: "> <@helpdesk:example.org> Rate us\n> 1. Good\n\n1"}, "1"},
+		{MessageContent{MsgType: "m.location", Body: "Office", GeoURI: "geo:50.45,30.52;u=35"}, "https://www.google.com/maps/place/50.450000,30.520000"},
+		{MessageContent{MsgType: "m.image", Body: "photo.png", URL: "mxc://example.org/photo"}, ""},
+	} {
+		if text := messageText(&tc.content); text != tc.text {
+			t.Errorf("messageText(%s) = %q; want %q", tc.content.MsgType, text, tc.text)
+		}
+	}
+}
+
+func TestTakeOption(t *testing.T) {
+	app := newTestBot(newHomeserver(t))
+	_, app.menus["!a:example.org"] = newTextContent("Rate us", []*chat.Buttons{{Button: []*chat.Button{
+		{Type: "reply", Text: "Good", Code: "5"},
+	}}})
+	if code := app.takeOption("!a:example.org", "good"); code != "5" {
+		t.Errorf("takeOption(good) = %q; want 5", code)
+	}
+	// options taken
+	if code := app.takeOption("!a:example.org", "1"); code != "1" {
+		t.Errorf("takeOption(1) = %q; want text", code)
+	}
+}
+
+func TestGetRoom(t *testing.T) {
+	hs := newHomeserver(t)
+	app := newTestBot(hs)
+	for i := 0; i < 2; i++ {
+		peer, err := app.getRoom(context.Background(), "!a:example.org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !peer.direct || peer.peer != "@alice:example.org" || peer.name != "Alice Liddell" {
+			t.Errorf("getRoom() = %+v", peer)
+		}
+	}
+	if len(hs.calls) != 1 {
+		t.Errorf("joined_members requested %d times; want cached", len(hs.calls))
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	hs := newHomeserver(t)
+	app := newTestBot(hs)
+	channel := &bot.Channel{ChatID: "!a:example.org"}
+
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: "Hello"},
+		{Type: "file", Text: "Screenshot", File: &chat.File{Name: "photo.png", Mime: "image/png", Url: hs.URL + "/files/1"}},
+	} {
+		err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: message})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(hs.sent) != 3 {
+		t.Fatalf("sent %d messages; want 3", len(hs.sent))
+	}
+	if sent := hs.sent[1]; sent.MsgType != "m.text" || sent.Body != "Screenshot" {
+		t.Errorf("caption = %+v", sent)
+	}
+	if sent := hs.sent[2]; sent.MsgType != "m.image" || sent.URL != "mxc://example.org/photo" || sent.Info.Size != 3 {
+		t.Errorf("image = %+v", sent)
+	}
+	if len(hs.typing) != 2 || !hs.typing[0] || hs.typing[1] {
+		t.Errorf("typing = %v; want [true false]", hs.typing)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	hs := newHomeserver(t)
+	client := &Client{Homeserver: hs.URL, Token: "token"}
+
+	// authenticated media NOT supported; legacy
+	data, mtype, err := client.Download(context.Background(), "mxc://example.org/legacy", 1024)
+	if err != nil || string(data) != "legacy" || mtype != "text/plain" {
+		t.Errorf("Download() = (%q, %q, %v)", data, mtype, err)
+	}
+	if _, _, err = client.Download(context.Background(), "https://example.org/file", 1024); err == nil {
+		t.Error("Download(https://) = <nil>; want invalid URI")
+	}
+}
+
+func TestSyncer(t *testing.T) {
+
+	hs := newHomeserver(t)
+	hs.syncs = []string{
+		`{"next_batch":"s1","rooms":{"join":{"!a:example.org":{"timeline":{"events":[{"type":"m.room.message","sender":"@alice:example.org"}]}}}}}`,
+		`{"next_batch":"s2"}`,
+	}
+
+	type update struct {
+		batch   string
+		initial bool
+	}
+	updates := make(chan update, 2)
+	syncer := &Syncer{
+		Client: &Client{Homeserver: hs.URL, Token: "token"},
+		Filter: syncFilter,
+		Log:    slog.Default(),
+		OnSync: func(_ context.Context, res *SyncResponse, initial bool) {
+			select {
+			case updates <- update{res.NextBatch, initial}:
+			default:
+			}
+		},
+	}
+	syncer.Start()
+	defer syncer.Close()
+
+	for _, want := range []update{{"s1", true}, {"s2", false}} {
+		select {
+		case got := <-updates:
+			if got != want {
+				t.Errorf("sync = %+v; want %+v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("sync %s not received", want.batch)
+		}
+	}
+	syncer.Close()
+
+	hs.mx.Lock()
+	defer hs.mx.Unlock()
+	if len(hs.since) < 2 || hs.since[0] != "" || hs.since[1] != "s1" {
+		t.Errorf("since = %v; want [\"\" s1 ...]", hs.since)
+	}
+}
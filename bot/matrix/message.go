@@ -0,0 +1,180 @@
+package matrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// SyncResponse of the /sync; rooms only
+// https://spec.matrix.org/v1.11/client-server-api/#get_matrixclientv3sync
+type SyncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]*struct {
+			Timeline struct {
+				Events []*Event `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+		Invite map[string]*struct {
+			InviteState struct {
+				Events []*Event `json:"events"`
+			} `json:"invite_state"`
+		} `json:"invite"`
+	} `json:"rooms"`
+}
+
+// Event of the room
+// https://spec.matrix.org/v1.11/client-server-api/#room-event-format
+type Event struct {
+	Type           string          `json:"type"`
+	EventID        string          `json:"event_id"`
+	Sender         string          `json:"sender"`
+	StateKey       *string         `json:"state_key,omitempty"`
+	OriginServerTS int64           `json:"origin_server_ts"`
+	Content        json.RawMessage `json:"content"`
+}
+
+// MemberContent of the m.room.member event
+type MemberContent struct {
+	Membership  string `json:"membership"` // invite | join | leave | ban
+	DisplayName string `json:"displayname,omitempty"`
+	IsDirect    bool   `json:"is_direct,omitempty"`
+}
+
+// MessageContent of the m.room.message event
+// https://spec.matrix.org/v1.11/client-server-api/#mroommessage-msgtypes
+type MessageContent struct {
+	MsgType  string    `json:"msgtype"` // m.text | m.notice | m.emote | m.image | m.file | m.video | m.audio | m.location
+	Body     string    `json:"body"`
+	URL      string    `json:"url,omitempty"` // mxc://
+	Filename string    `json:"filename,omitempty"`
+	Info     *FileInfo `json:"info,omitempty"`
+	GeoURI   string    `json:"geo_uri,omitempty"`
+}
+
+// FileInfo of the media message
+type FileInfo struct {
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+func buttonLabel(button *chat.Button) string {
+	label := strings.TrimSpace(button.GetText())
+	if label == "" {
+		label = strings.TrimSpace(button.GetCaption())
+	}
+	return label
+}
+
+// newTextContent renders text with the buttons.
+// Matrix has no buttons, so reply and postback buttons
+// are rendered as the numbered options; URL buttons as links.
+// Returns the options menu: map[number|label]code
+func newTextContent(text string, rows []*chat.Buttons) (*MessageContent, map[string]string) {
+
+	var (
+		lines []string
+		menu  map[string]string
+		items int
+	)
+	for _, row := range rows {
+		for _, button := range row.GetButton() {
+			label := buttonLabel(button)
+			if label == "" {
+				continue
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if button.GetUrl() != "" {
+					lines = append(lines, label+": "+button.GetUrl())
+				}
+			case "reply", "postback":
+				code := button.GetCode()
+				if code == "" {
+					code = label
+				}
+				if menu == nil {
+					menu = make(map[string]string)
+				}
+				items++
+				n := strconv.Itoa(items)
+				menu[n] = code
+				menu[strings.ToLower(label)] = code
+				lines = append(lines, n+". "+label)
+			}
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if len(lines) != 0 {
+		text = strings.TrimSpace(text + "\n\n" + strings.Join(lines, "\n"))
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return &MessageContent{MsgType: "m.text", Body: text}, menu
+}
+
+// msgType of the file's media
+func msgType(mtype string) string {
+	switch media, _, _ := strings.Cut(mtype, "/"); media {
+	case "image", "video", "audio":
+		return "m." + media
+	}
+	return "m.file"
+}
+
+// newFileContent of the uploaded content
+func newFileContent(file *chat.File, mxc string, size int64) *MessageContent {
+	name := file.GetName()
+	if name == "" {
+		name = "file"
+	}
+	return &MessageContent{
+		MsgType:  msgType(file.GetMime()),
+		Body:     name,
+		URL:      mxc,
+		Filename: name,
+		Info: &FileInfo{
+			MimeType: file.GetMime(),
+			Size:     size,
+		},
+	}
+}
+
+// stripReplyFallback removes the quoted original message
+// prepended to the body of the reply
+// https://spec.matrix.org/v1.11/client-server-api/#fallbacks-for-rich-replies
+func stripReplyFallback(body string) string {
+	if !strings.HasPrefix(body, "> ") {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ">") {
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+	return ""
+}
+
+// messageText of the text or location message; empty otherwise
+func messageText(content *MessageContent) string {
+	switch content.MsgType {
+	case "m.text", "m.notice", "m.emote":
+		return strings.TrimSpace(stripReplyFallback(content.Body))
+	case "m.location":
+		// geo:lat,lon[;u=uncertainty]
+		var lat, lon float64
+		point, _, _ := strings.Cut(strings.TrimPrefix(content.GeoURI, "geo:"), ";")
+		if _, err := fmt.Sscanf(point, "%f,%f", &lat, &lon); err != nil {
+			return strings.TrimSpace(content.Body)
+		}
+		return fmt.Sprintf("https://www.google.com/maps/place/%f,%f", lat, lon)
+	}
+	return ""
+}
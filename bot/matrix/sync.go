@@ -0,0 +1,107 @@
+package matrix
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Long-polling timeout of the /sync
+const syncTimeout = 30 * time.Second
+
+// syncFilter of the bot's /sync: room messages and membership;
+// NO presence, account data, receipts or typing of others
+const syncFilter = `{"presence":{"not_types":["*"]},"account_data":{"not_types":["*"]},` +
+	`"room":{"timeline":{"limit":50,"types":["m.room.message","m.room.member","m.room.encrypted"]},` +
+	`"state":{"types":["m.room.member"]},"ephemeral":{"not_types":["*"]},"account_data":{"not_types":["*"]}}}`
+
+// Syncer of the bot account; long-polls the homeserver until closed
+type Syncer struct {
+	Client *Client
+	Filter string
+	Log    *slog.Logger
+	// OnSync handles the updates; initial sync returns
+	// the current state of the rooms joined, NOT the new events
+	OnSync func(ctx context.Context, res *SyncResponse, initial bool)
+
+	mx   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+
+	// batch token; owned by run
+	since string
+}
+
+// Start the sync, unless running
+func (s *Syncer) Start() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.stop != nil {
+		return // running
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Close the sync; it can be started again
+func (s *Syncer) Close() {
+	s.mx.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mx.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (s *Syncer) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := time.Second
+	for {
+		res, err := s.Client.Sync(ctx, s.since, s.Filter, syncTimeout)
+		if ctx.Err() != nil {
+			return // closed
+		}
+		if err != nil {
+			if re, _ := err.(*Error); re != nil && re.Status == http.StatusUnauthorized {
+				s.Log.Error("matrix/sync",
+					slog.Any("error", err),
+				)
+				return // M_UNKNOWN_TOKEN
+			}
+			s.Log.Warn("matrix/sync",
+				slog.Any("error", err),
+			)
+			select {
+			case <-stop:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > time.Minute {
+				backoff = time.Minute
+			}
+			continue
+		}
+		backoff = time.Second
+		if s.OnSync != nil {
+			s.OnSync(ctx, res, s.since == "")
+		}
+		s.since = res.NextBatch
+	}
+}
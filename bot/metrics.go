@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var meter = otel.Meter("github.com/webitel/chat_manager/bot")
+
+var (
+	// messagesInbound [FROM] external: chat.provider [TO] internal: chat.server
+	messagesInbound, _ = meter.Int64Counter("chat.bot.messages.inbound",
+		metric.WithDescription("Messages received from the chat provider"),
+		metric.WithUnit("{message}"),
+	)
+	// messagesOutbound [FROM] internal: chat.server [TO] external: chat.provider
+	messagesOutbound, _ = meter.Int64Counter("chat.bot.messages.outbound",
+		metric.WithDescription("Messages delivered to the chat provider"),
+		metric.WithUnit("{message}"),
+	)
+	// sendErrors of the messages delivery to the chat provider
+	sendErrors, _ = meter.Int64Counter("chat.bot.send.errors",
+		metric.WithDescription("Messages failed to deliver to the chat provider"),
+		metric.WithUnit("{message}"),
+	)
+	// webhookDuration of the chat provider callback requests
+	webhookDuration, _ = meter.Float64Histogram("chat.bot.webhook.duration",
+		metric.WithDescription("Duration of the chat provider webhook requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+		),
+	)
+)
+
+// providerAttrs of the gateway metrics
+func (c *Gateway) providerAttrs() metric.MeasurementOption {
+	return metric.WithAttributes(
+		attribute.String("provider", c.Bot.GetProvider()),
+	)
+}
+
+// countInbound message received from the chat provider
+func (c *Gateway) countInbound(ctx context.Context) {
+	if messagesInbound != nil {
+		messagesInbound.Add(ctx, 1, c.providerAttrs())
+	}
+}
+
+// countOutbound message delivered to the chat provider
+func (c *Gateway) countOutbound(ctx context.Context, err error) {
+	if err != nil {
+		if sendErrors != nil {
+			sendErrors.Add(ctx, 1, c.providerAttrs())
+		}
+		return
+	}
+	if messagesOutbound != nil {
+		messagesOutbound.Add(ctx, 1, c.providerAttrs())
+	}
+}
+
+// observeWebhook request duration since start
+func (c *Gateway) observeWebhook(ctx context.Context, start time.Time) {
+	if webhookDuration != nil {
+		webhookDuration.Record(ctx, time.Since(start).Seconds(), c.providerAttrs())
+	}
+}
+
+// observeConversations registers the open conversations gauge
+// of the running gateways, by provider
+func (srv *Service) observeConversations() (metric.Registration, error) {
+	gauge, err := meter.Int64ObservableGauge("chat.bot.conversations.open",
+		metric.WithDescription("Conversations open at the moment"),
+		metric.WithUnit("{conversation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return meter.RegisterCallback(func(_ context.Context, obs metric.Observer) error {
+		for provider, count := range srv.openConversations() {
+			obs.ObserveInt64(gauge, count, metric.WithAttributes(
+				attribute.String("provider", provider),
+			))
+		}
+		return nil
+	}, gauge)
+}
+
+// openConversations count by provider
+func (srv *Service) openConversations() map[string]int64 {
+	srv.indexMx.RLock()
+	gates := make([]*Gateway, 0, len(srv.profiles))
+	for _, gate := range srv.profiles {
+		gates = append(gates, gate)
+	}
+	srv.indexMx.RUnlock()
+
+	open := make(map[string]int64)
+	for _, gate := range gates {
+		provider := gate.Bot.GetProvider()
+		count := open[provider]
+		gate.RLock()
+		for _, channel := range gate.external {
+			if !channel.IsNew() && channel.Closed == 0 {
+				count++
+			}
+		}
+		gate.RUnlock()
+		open[provider] = count // report zero
+	}
+	return open
+}
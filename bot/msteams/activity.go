@@ -0,0 +1,80 @@
+package msteams
+
+import (
+	"encoding/json"
+)
+
+// Activity types
+const (
+	activityMessage            = "message"
+	activityConversationUpdate = "conversationUpdate"
+)
+
+// Activity of the Bot Framework protocol
+// https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-api-reference#activity-object
+type Activity struct {
+	Type           string               `json:"type"`
+	ID             string               `json:"id,omitempty"`
+	Timestamp      string               `json:"timestamp,omitempty"`
+	ServiceURL     string               `json:"serviceUrl,omitempty"`
+	ChannelID      string               `json:"channelId,omitempty"`
+	From           *ChannelAccount      `json:"from,omitempty"`
+	Conversation   *ConversationAccount `json:"conversation,omitempty"`
+	Recipient      *ChannelAccount      `json:"recipient,omitempty"`
+	TextFormat     string               `json:"textFormat,omitempty"` // markdown | plain | xml
+	Text           string               `json:"text,omitempty"`
+	Attachments    []*Attachment        `json:"attachments,omitempty"`
+	Value          json.RawMessage      `json:"value,omitempty"`
+	ReplyToID      string               `json:"replyToId,omitempty"`
+	MembersAdded   []*ChannelAccount    `json:"membersAdded,omitempty"`
+	MembersRemoved []*ChannelAccount    `json:"membersRemoved,omitempty"`
+}
+
+// ChannelAccount is the user or bot account
+type ChannelAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	AADObjectID string `json:"aadObjectId,omitempty"`
+}
+
+// ConversationAccount is the conversation;
+// Teams channel thread has its own conversation ID
+type ConversationAccount struct {
+	ID               string `json:"id"`
+	Name             string `json:"name,omitempty"`
+	ConversationType string `json:"conversationType,omitempty"` // personal | groupChat | channel
+	TenantID         string `json:"tenantId,omitempty"`
+	IsGroup          bool   `json:"isGroup,omitempty"`
+}
+
+// Attachment of the message activity
+type Attachment struct {
+	ContentType string          `json:"contentType"`
+	ContentURL  string          `json:"contentUrl,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Content     json.RawMessage `json:"content,omitempty"`
+}
+
+// Attachment content type(s)
+const (
+	// Teams file shared in personal chat
+	contentTypeFileDownload = "application/vnd.microsoft.teams.file.download.info"
+	contentTypeAdaptiveCard = "application/vnd.microsoft.card.adaptive"
+)
+
+// fileDownloadInfo content of the shared file
+type fileDownloadInfo struct {
+	DownloadURL string `json:"downloadUrl"`
+	UniqueID    string `json:"uniqueId"`
+	FileType    string `json:"fileType"`
+}
+
+// has reports whether the account is listed
+func has(members []*ChannelAccount, id string) bool {
+	for _, member := range members {
+		if member != nil && member.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package msteams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default Bot Framework identity endpoints
+const (
+	// OpenID metadata of the Bot Connector service tokens
+	defaultOpenIDMetadata = "https://login.botframework.com/v1/.well-known/openidconfiguration"
+	// OAuth token endpoint; multi-tenant bot
+	defaultTokenURL = "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token"
+	// OAuth scope of the Bot Connector API
+	tokenScope = "https://api.botframework.com/.default"
+	// Signing keys cache lifetime
+	keysTTL = 24 * time.Hour
+	// Minimum interval between the signing keys refresh attempts;
+	// unknown key IDs are rejected in between
+	keysRefreshInterval = time.Minute
+	// Allowed clock skew
+	clockSkew = 5 * time.Minute
+)
+
+// signingKey of the OpenID JWKS document
+type signingKey struct {
+	*rsa.PublicKey
+	// channel IDs this key is valid for
+	Endorsements []string
+}
+
+// keySet is the cached signing keys
+// of the configured OpenID metadata document
+type keySet struct {
+	MetadataURL string
+	Client      *http.Client
+
+	mx      sync.Mutex
+	issuer  string
+	keys    map[string]*signingKey
+	expires time.Time
+	// last refresh attempt
+	fetched time.Time
+}
+
+// fetch GET JSON document
+func fetch(ctx context.Context, client *http.Client, link string, into any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %s", link, rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(into)
+}
+
+// refresh the OpenID metadata and signing keys
+func (s *keySet) refresh(ctx context.Context) error {
+
+	var metadata struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	err := fetch(ctx, s.Client, s.MetadataURL, &metadata)
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid          string   `json:"kid"`
+			Kty          string   `json:"kty"`
+			N            string   `json:"n"`
+			E            string   `json:"e"`
+			Endorsements []string `json:"endorsements"`
+		} `json:"keys"`
+	}
+	err = fetch(ctx, s.Client, metadata.JWKSURI, &jwks)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*signingKey, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			continue
+		}
+		keys[key.Kid] = &signingKey{
+			PublicKey: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+			Endorsements: key.Endorsements,
+		}
+	}
+
+	s.issuer = metadata.Issuer
+	s.keys = keys
+	s.expires = time.Now().Add(keysTTL)
+	return nil
+}
+
+// key lookup by ID; refresh once if unknown,
+// but not more often than keysRefreshInterval
+func (s *keySet) key(ctx context.Context, kid string) (*signingKey, string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	now := time.Now()
+	if key := s.keys[kid]; key != nil && now.Before(s.expires) {
+		return key, s.issuer, nil
+	}
+	if now.Sub(s.fetched) < keysRefreshInterval {
+		return nil, "", fmt.Errorf("jwt: signing key %q not found", kid)
+	}
+	s.fetched = now
+	if err := s.refresh(ctx); err != nil {
+		return nil, "", err
+	}
+	key := s.keys[kid]
+	if key == nil {
+		return nil, "", fmt.Errorf("jwt: signing key %q not found", kid)
+	}
+	return key, s.issuer, nil
+}
+
+// claims of the Bot Connector service token
+type claims struct {
+	Issuer     string      `json:"iss"`
+	Audience   string      `json:"aud"`
+	Expires    json.Number `json:"exp"`
+	NotBefore  json.Number `json:"nbf"`
+	ServiceURL string      `json:"serviceurl"`
+}
+
+// Verify the bearer token of the inbound activity;
+// audience is the bot's Microsoft App ID
+func (s *keySet) Verify(ctx context.Context, token, audience string, activity *Activity, now time.Time) error {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("jwt: malformed token")
+	}
+
+	var head struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return err
+	}
+	if head.Alg != "RS256" {
+		return fmt.Errorf("jwt: algorithm %q not allowed", head.Alg)
+	}
+
+	key, issuer, err := s.key(ctx, head.Kid)
+	if err != nil {
+		return err
+	}
+
+	sign, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("jwt: malformed signature")
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key.PublicKey, crypto.SHA256, hash[:], sign); err != nil {
+		return fmt.Errorf("jwt: invalid signature")
+	}
+
+	var claim claims
+	if err = decodeSegment(parts[1], &claim); err != nil {
+		return err
+	}
+	if claim.Issuer != issuer {
+		return fmt.Errorf("jwt: invalid issuer %q", claim.Issuer)
+	}
+	if claim.Audience != audience {
+		return fmt.Errorf("jwt: invalid audience %q", claim.Audience)
+	}
+	exp, err := claim.Expires.Int64()
+	if err != nil || now.After(time.Unix(exp, 0).Add(clockSkew)) {
+		return fmt.Errorf("jwt: token expired")
+	}
+	if nbf, err := claim.NotBefore.Int64(); err == nil && now.Add(clockSkew).Before(time.Unix(nbf, 0)) {
+		return fmt.Errorf("jwt: token not valid yet")
+	}
+	if activity != nil {
+		if claim.ServiceURL != "" && claim.ServiceURL != activity.ServiceURL {
+			return fmt.Errorf("jwt: serviceUrl mismatch")
+		}
+		if len(key.Endorsements) != 0 && !endorsed(key.Endorsements, activity.ChannelID) {
+			return fmt.Errorf("jwt: key is not endorsed for %q channel", activity.ChannelID)
+		}
+	}
+
+	return nil
+}
+
+func endorsed(endorsements []string, channelID string) bool {
+	for _, e := range endorsements {
+		if e == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, into any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("jwt: malformed segment")
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err = dec.Decode(into); err != nil {
+		return fmt.Errorf("jwt: malformed segment; %v", err)
+	}
+	return nil
+}
+
+// tokenSource of the bot's OAuth access token
+// for the outbound Bot Connector API calls
+type tokenSource struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Client       *http.Client
+
+	mx      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns cached or new access token
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.ClientID},
+		"client_secret": {s.ClientSecret},
+		"scope":         {tokenScope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rsp, err := s.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+
+	var res struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err = json.NewDecoder(rsp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("oauth: HTTP %s; %v", rsp.Status, err)
+	}
+	if res.AccessToken == "" {
+		return "", fmt.Errorf("oauth: %s; %s", res.Error, res.Description)
+	}
+	s.token = res.AccessToken
+	// refresh in advance
+	s.expires = time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - clockSkew)
+	return s.token, nil
+}
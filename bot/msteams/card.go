@@ -0,0 +1,104 @@
+package msteams
+
+import (
+	"strings"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Adaptive Card schema version supported by Teams
+const cardVersion = "1.4"
+
+// card is the Adaptive Card content
+// https://adaptivecards.io/explorer/AdaptiveCard.html
+type card struct {
+	Schema  string     `json:"$schema"`
+	Type    string     `json:"type"` // AdaptiveCard
+	Version string     `json:"version"`
+	Body    []*element `json:"body"`
+}
+
+// element of the card body; TextBlock or ActionSet
+type element struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text,omitempty"`
+	Wrap    bool      `json:"wrap,omitempty"`
+	Actions []*action `json:"actions,omitempty"`
+}
+
+// action of the ActionSet; Action.OpenUrl or Action.Submit
+type action struct {
+	Type  string      `json:"type"`
+	Title string      `json:"title"`
+	URL   string      `json:"url,omitempty"`
+	Data  *submitData `json:"data,omitempty"`
+}
+
+// submitData is posted back as the message activity value
+type submitData struct {
+	Code string `json:"code"`
+}
+
+// newCard renders message text with the buttons
+// as a TextBlock followed by the ActionSet per each row.
+// Only url, reply and postback buttons are supported.
+func newCard(text string, rows []*chat.Buttons) *card {
+
+	layout := &card{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: cardVersion,
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		layout.Body = append(layout.Body, &element{
+			Type: "TextBlock",
+			Text: text,
+			Wrap: true,
+		})
+	}
+
+	for _, row := range rows {
+		var actions []*action
+		for _, button := range row.GetButton() {
+			title := strings.TrimSpace(button.GetText())
+			if title == "" {
+				title = strings.TrimSpace(button.GetCaption())
+			}
+			if title == "" {
+				continue
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if button.GetUrl() == "" {
+					continue
+				}
+				actions = append(actions, &action{
+					Type:  "Action.OpenUrl",
+					Title: title,
+					URL:   button.GetUrl(),
+				})
+			case "reply", "postback":
+				code := button.GetCode()
+				if code == "" {
+					code = title
+				}
+				actions = append(actions, &action{
+					Type:  "Action.Submit",
+					Title: title,
+					Data:  &submitData{Code: code},
+				})
+			default:
+				continue // NOT supported
+			}
+		}
+		if len(actions) == 0 {
+			continue
+		}
+		layout.Body = append(layout.Body, &element{
+			Type:    "ActionSet",
+			Actions: actions,
+		})
+	}
+
+	return layout
+}
@@ -0,0 +1,647 @@
+package msteams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "msteams"
+	// Channel property of the conversation's Bot Connector service URL
+	serviceURLProperty = "msteams_service_url"
+	// Max size of the inbound activity
+	maxRequestSize = 1 << 20
+	// Max size of the attachment to download
+	maxFileSize = 32 << 20
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the Microsoft Teams (Bot Framework) provider.
+// Receives activities on the messaging endpoint (webhook URI)
+// and replies to the conversation's Bot Connector service URL.
+type Bot struct {
+	Gateway *bot.Gateway
+	appID   string
+	keys    *keySet
+	token   *tokenSource
+	client  *http.Client
+
+	mx sync.Mutex
+	// conversation(s) Bot Connector service URL; map[conversation.id]
+	services map[string]string
+}
+
+// New initialize new agent.profile service Microsoft Teams provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	appID := profile["app_id"]
+	if appID == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.msteams.app_id.required",
+			"msteams: Microsoft App ID required",
+		)
+	}
+	appPassword := profile["app_password"]
+	if appPassword == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.msteams.app_password.required",
+			"msteams: Microsoft App password required",
+		)
+	}
+	tokenURL := profile["token_url"]
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+		if tenant := profile["tenant"]; tenant != "" {
+			// single-tenant bot
+			tokenURL = "https://login.microsoftonline.com/" + url.PathEscape(tenant) + "/oauth2/v2.0/token"
+		}
+	}
+	metadataURL := profile["openid_metadata"]
+	if metadataURL == "" {
+		metadataURL = defaultOpenIDMetadata
+	}
+	for param, link := range map[string]string{"token_url": tokenURL, "openid_metadata": metadataURL} {
+		if u, err := url.Parse(link); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, errors.BadRequest(
+				"chat.bot.msteams."+param+".invalid",
+				"msteams: %s %q is invalid", param, link,
+			)
+		}
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.msteams.updates.invalid",
+			"msteams: %v", err,
+		)
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	app := &Bot{
+		Gateway: agent,
+		appID:   appID,
+		client:  client,
+		keys: &keySet{
+			MetadataURL: metadataURL,
+			Client:      client,
+		},
+		token: &tokenSource{
+			TokenURL:     tokenURL,
+			ClientID:     appID,
+			ClientSecret: appPassword,
+			Client:       client,
+		},
+		services: make(map[string]string),
+	}
+	// Can we upgrade latest bot account ?
+	if last, _ := state.(*Bot); last != nil && last.appID == appID {
+		last.mx.Lock()
+		for id, link := range last.services {
+			app.services[id] = link
+		}
+		last.mx.Unlock()
+	}
+
+	return app, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register does nothing; Azure Bot's messaging endpoint
+// must be set to the webhook URI manually
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.Gateway.Log.Info("msteams/bot.register",
+		slog.String("app_id", c.appID),
+		slog.String("messaging_endpoint", uri),
+	)
+	return nil
+}
+
+// Deregister does nothing; see Register
+func (c *Bot) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (c *Bot) Close() error {
+	return nil
+}
+
+// setServiceURL remembers the conversation's Bot Connector service URL
+func (c *Bot) setServiceURL(channel *bot.Channel, link string) {
+	if link == "" {
+		return
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.services[channel.ChatID] = link
+	props, _ := channel.Properties.(map[string]string)
+	if props == nil {
+		props = make(map[string]string)
+		channel.Properties = props
+	}
+	props[serviceURLProperty] = link
+}
+
+// serviceURL of the conversation
+func (c *Bot) serviceURL(channel *bot.Channel) string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if link := c.services[channel.ChatID]; link != "" {
+		return link
+	}
+	// RECOVER: started conversation
+	props, _ := channel.Properties.(map[string]string)
+	return props[serviceURLProperty]
+}
+
+// WebHook implements provider.Receiver interface for Microsoft Teams
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutPrefix(notice.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var activity Activity
+	err := json.NewDecoder(io.LimitReader(notice.Body, maxRequestSize)).Decode(&activity)
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := notice.Context()
+	err = c.keys.Verify(ctx, token, c.appID, &activity, time.Now())
+	if err != nil {
+		c.Gateway.Log.Warn("msteams/bot.webhook",
+			slog.Any("error", err),
+		)
+		http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if activity.Conversation == nil || activity.Conversation.ID == "" || activity.From == nil {
+		reply.WriteHeader(http.StatusOK)
+		return // IGNORE
+	}
+
+	switch activity.Type {
+	case activityMessage:
+		err = c.onMessage(ctx, &activity)
+	case activityConversationUpdate:
+		err = c.onConversationUpdate(ctx, &activity)
+	default:
+		// typing, messageReaction, installationUpdate, etc.
+	}
+
+	if err != nil {
+		c.Gateway.Log.Error("msteams/bot.on"+strings.ToUpper(activity.Type[:1])+activity.Type[1:],
+			slog.Any("error", err),
+			slog.String("conversation", activity.Conversation.ID),
+		)
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = http.StatusBadGateway
+		}
+		http.Error(reply, re.Detail, int(re.Code))
+		return
+	}
+
+	reply.WriteHeader(http.StatusOK)
+}
+
+// getChannel of the activity's conversation
+func (c *Bot) getChannel(ctx context.Context, activity *Activity) (*bot.Channel, *bot.Account, error) {
+
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: activity.Conversation.ID,
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(activity.From.Name)
+
+	channel, err := c.Gateway.GetChannel(ctx, activity.Conversation.ID, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return nil, nil, re // 502 Bad Gateway
+	}
+	c.setServiceURL(channel, activity.ServiceURL)
+
+	return channel, contact, nil
+}
+
+var mentionRe = regexp.MustCompile(`<at>([^<]*)</at>`)
+
+// messageText of the activity; bot mention removed
+func messageText(activity *Activity) string {
+	text := activity.Text
+	if bot := activity.Recipient; bot != nil {
+		text = mentionRe.ReplaceAllStringFunc(text, func(m string) string {
+			if name := m[4 : len(m)-5]; name != bot.Name {
+				return name
+			}
+			return ""
+		})
+	}
+	text = strings.TrimSpace(text)
+	if text == "" && len(activity.Value) != 0 {
+		// Adaptive Card Action.Submit
+		var data submitData
+		if json.Unmarshal(activity.Value, &data) == nil {
+			text = data.Code
+		}
+	}
+	return text
+}
+
+// on: [message]
+func (c *Bot) onMessage(ctx context.Context, activity *Activity) error {
+
+	channel, contact, err := c.getChannel(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if text := messageText(activity); text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, doc := range activity.Attachments {
+		file, err := c.attachment(ctx, activity, doc)
+		if err != nil {
+			return err
+		}
+		if file == nil {
+			continue // NOT a file
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachment downloads and uploads the file;
+// returns nil for non-file attachment(s), e.g.: cards
+func (c *Bot) attachment(ctx context.Context, activity *Activity, doc *Attachment) (*chat.File, error) {
+
+	var (
+		link    string
+		private bool
+		name    = doc.Name
+		mtype   = doc.ContentType
+	)
+	switch {
+	case doc.ContentType == contentTypeFileDownload:
+		var info fileDownloadInfo
+		if err := json.Unmarshal(doc.Content, &info); err != nil {
+			return nil, err
+		}
+		link = info.DownloadURL // pre-authenticated
+		mtype = mime.TypeByExtension("." + info.FileType)
+	case strings.HasPrefix(doc.ContentType, "application/vnd.microsoft."),
+		strings.HasPrefix(doc.ContentType, "text/html"):
+		return nil, nil // card or rich text copy
+	case doc.ContentURL != "":
+		link = doc.ContentURL
+		// Bot Connector hosted content requires bot token
+		private = activity.ServiceURL != "" && strings.HasPrefix(link, strings.TrimRight(activity.ServiceURL, "/"))
+	default:
+		return nil, nil
+	}
+	if link == "" {
+		return nil, nil
+	}
+	if name == "" {
+		name = path.Base(strings.SplitN(link, "?", 2)[0])
+	}
+
+	data, err := c.download(ctx, link, private)
+	if err != nil {
+		return nil, err
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// on: [conversationUpdate]
+func (c *Bot) onConversationUpdate(ctx context.Context, activity *Activity) error {
+
+	me := activity.Recipient
+	if me == nil {
+		return nil
+	}
+	personal := activity.Conversation.ConversationType == "" ||
+		activity.Conversation.ConversationType == "personal"
+
+	switch {
+	case personal && has(activity.MembersAdded, me.ID):
+		// Bot installed for the user; start dialog
+		channel, contact, err := c.getChannel(ctx, activity)
+		if err != nil {
+			return err
+		}
+		if !channel.IsNew() {
+			return nil
+		}
+		return c.Gateway.Read(ctx, &bot.Update{
+			Title: channel.Title,
+			Chat:  channel,
+			User:  contact,
+			Message: &chat.Message{
+				Type: "text",
+				Text: "/welcome",
+			},
+		})
+
+	case has(activity.MembersRemoved, me.ID):
+		// Bot uninstalled; close dialog
+		channel, err := c.Gateway.GetChannel(ctx, activity.Conversation.ID, nil)
+		if err == nil && !channel.IsNew() {
+			err = channel.Close()
+		}
+		c.mx.Lock()
+		delete(c.services, activity.Conversation.ID)
+		c.mx.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// download attachment content
+func (c *Bot) download(ctx context.Context, link string, private bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if private {
+		token, err := c.token.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: HTTP %s", rsp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("download: file size exceeds %d bytes", maxFileSize)
+	}
+	return data, nil
+}
+
+// send activity to the conversation
+// https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-api-reference#send-to-conversation
+func (c *Bot) send(ctx context.Context, serviceURL string, activity *Activity) error {
+
+	token, err := c.token.Token(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	link := strings.TrimRight(serviceURL, "/") + "/v3/conversations/" +
+		url.PathEscape(activity.Conversation.ID) + "/activities"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, link, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if code := rsp.StatusCode; code < 200 || code >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(rsp.Body, 512))
+		return fmt.Errorf("msteams: send activity: HTTP %s; %s", rsp.Status, data)
+	}
+	return nil
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		send    = Activity{
+			Type:         activityMessage,
+			Conversation: &ConversationAccount{ID: channel.ChatID},
+			TextFormat:   "markdown",
+		}
+	)
+
+	serviceURL := c.serviceURL(channel)
+	if serviceURL == "" {
+		return errors.BadRequest(
+			"chat.bot.msteams.conversation.not_found",
+			"msteams: conversation %s reference not found",
+			channel.ChatID,
+		)
+	}
+
+	switch message.Type {
+	case "text":
+		send.Text = strings.TrimSpace(message.GetText())
+		menu := message.Buttons
+		if menu == nil {
+			menu = message.Inline
+		}
+		if len(menu) != 0 {
+			content, err := json.Marshal(newCard(send.Text, menu))
+			if err != nil {
+				return err
+			}
+			send.Text = ""
+			send.Attachments = []*Attachment{{
+				ContentType: contentTypeAdaptiveCard,
+				Content:     content,
+			}}
+		}
+		if send.Text == "" && len(send.Attachments) == 0 {
+			return nil
+		}
+
+	case "file":
+		doc := message.GetFile()
+		send.Text = strings.TrimSpace(message.GetText())
+		if strings.HasPrefix(doc.GetMime(), "image/") {
+			send.Attachments = []*Attachment{{
+				ContentType: doc.GetMime(),
+				ContentURL:  doc.GetUrl(),
+				Name:        doc.GetName(),
+			}}
+			break
+		}
+		// NOTE: Teams does not accept files by URL but images;
+		// file consent flow is required to upload to user's OneDrive
+		link := "[" + doc.GetName() + "](" + doc.GetUrl() + ")"
+		if send.Text != "" {
+			link = send.Text + "\n\n" + link
+		}
+		send.Text = link
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("msteams/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		send.Text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("msteams/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if send.Text = strings.TrimSpace(messageText); send.Text == "" {
+			return nil
+		}
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("msteams/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if send.Text = strings.TrimSpace(messageText); send.Text == "" {
+			return nil
+		}
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	err := c.send(ctx, serviceURL, &send)
+	if err != nil {
+		c.Gateway.Log.Error("msteams/bot.sendActivity",
+			slog.Any("error", err),
+			slog.String("conversation", channel.ChatID),
+		)
+		return err
+	}
+
+	return nil
+}
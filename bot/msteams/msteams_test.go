@@ -0,0 +1,280 @@
+package msteams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+const (
+	testIssuer = "https://api.botframework.com"
+	testAppID  = "00000000-0000-0000-0000-000000000001"
+)
+
+// connector is the local stand-in of the Bot Framework
+// identity endpoints and the Bot Connector service
+type connector struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mx       sync.Mutex
+	tokens   int
+	metadata int
+	received []*Activity
+}
+
+func newConnector(t *testing.T) *connector {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &connector{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openid", func(w http.ResponseWriter, r *http.Request) {
+		c.mx.Lock()
+		c.metadata++
+		c.mx.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   testIssuer,
+			"jwks_uri": c.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{{
+				"kty":          "RSA",
+				"kid":          "test",
+				"n":            base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				"endorsements": []string{"msteams"},
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_id") != testAppID || r.PostFormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		c.mx.Lock()
+		c.tokens++
+		c.mx.Unlock()
+		_, _ = w.Write([]byte(`{"access_token":"bot-token","expires_in":3600}`))
+	})
+	mux.HandleFunc("/v3/conversations/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer bot-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var activity Activity
+		_ = json.NewDecoder(r.Body).Decode(&activity)
+		c.mx.Lock()
+		c.received = append(c.received, &activity)
+		c.mx.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	})
+	c.Server = httptest.NewServer(mux)
+	return c
+}
+
+// sign the service token
+func (c *connector) sign(t *testing.T, key *rsa.PrivateKey, claim map[string]any) string {
+	head, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	body, _ := json.Marshal(claim)
+	data := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	hash := sha256.Sum256([]byte(data))
+	sign, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data + "." + base64.RawURLEncoding.EncodeToString(sign)
+}
+
+func (c *connector) claim(now time.Time) map[string]any {
+	return map[string]any{
+		"iss":        testIssuer,
+		"aud":        testAppID,
+		"exp":        now.Add(time.Hour).Unix(),
+		"nbf":        now.Add(-time.Minute).Unix(),
+		"serviceurl": c.URL,
+	}
+}
+
+func newTestBot(c *connector) *Bot {
+	return &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		appID:   testAppID,
+		client:  c.Client(),
+		keys:    &keySet{MetadataURL: c.URL + "/openid", Client: c.Client()},
+		token: &tokenSource{
+			TokenURL:     c.URL + "/token",
+			ClientID:     testAppID,
+			ClientSecret: "secret",
+			Client:       c.Client(),
+		},
+		services: make(map[string]string),
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	c := newConnector(t)
+	defer c.Close()
+	app := newTestBot(c)
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	now := time.Now()
+	activity := &Activity{ServiceURL: c.URL, ChannelID: "msteams"}
+
+	for _, tc := range []struct {
+		name  string
+		key   *rsa.PrivateKey
+		patch func(claim map[string]any, activity *Activity)
+		ok    bool
+	}{
+		{"valid", c.key, nil, true},
+		{"signature", other, nil, false},
+		{"audience", c.key, func(claim map[string]any, _ *Activity) { claim["aud"] = "other" }, false},
+		{"issuer", c.key, func(claim map[string]any, _ *Activity) { claim["iss"] = "https://example.com" }, false},
+		{"expired", c.key, func(claim map[string]any, _ *Activity) { claim["exp"] = now.Add(-time.Hour).Unix() }, false},
+		{"serviceUrl", c.key, func(_ map[string]any, a *Activity) { a.ServiceURL = "https://example.com" }, false},
+		{"endorsement", c.key, func(_ map[string]any, a *Activity) { a.ChannelID = "webchat" }, false},
+	} {
+		claim, check := c.claim(now), *activity
+		if tc.patch != nil {
+			tc.patch(claim, &check)
+		}
+		err := app.keys.Verify(context.Background(), c.sign(t, tc.key, claim), testAppID, &check, now)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: Verify() = %v", tc.name, err)
+		}
+	}
+}
+
+func TestKeyRefresh(t *testing.T) {
+
+	c := newConnector(t)
+	defer c.Close()
+	keys := newTestBot(c).keys
+	ctx := context.Background()
+
+	if _, _, err := keys.key(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	// Unknown kid: rejected with NO refresh in between
+	for i := 0; i < 3; i++ {
+		if _, _, err := keys.key(ctx, "unknown"); err == nil {
+			t.Fatal("key( unknown ): error expected")
+		}
+	}
+	if c.metadata != 1 {
+		t.Errorf("refresh: %d; expect 1", c.metadata)
+	}
+	// Refresh allowed again
+	keys.fetched = time.Now().Add(-keysRefreshInterval)
+	if _, _, err := keys.key(ctx, "unknown"); err == nil {
+		t.Fatal("key( unknown ): error expected")
+	}
+	if c.metadata != 2 {
+		t.Errorf("refresh: %d; expect 2", c.metadata)
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	c := newConnector(t)
+	defer c.Close()
+	app := newTestBot(c)
+
+	body := `{"type":"typing","channelId":"msteams","serviceUrl":"` + c.URL + `",` +
+		`"from":{"id":"29:1"},"conversation":{"id":"a:1"},"recipient":{"id":"28:` + testAppID + `"}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rsp := httptest.NewRecorder()
+	app.WebHook(rsp, req)
+	if rsp.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous activity = (%d); want 401", rsp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+c.sign(t, c.key, c.claim(time.Now())))
+	rsp = httptest.NewRecorder()
+	app.WebHook(rsp, req)
+	if rsp.Code != http.StatusOK {
+		t.Errorf("authorized activity = (%d) %s; want 200", rsp.Code, rsp.Body)
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	for _, tc := range []struct {
+		activity Activity
+		text     string
+	}{
+		{Activity{Text: "<at>Helpdesk</at> my laptop is broken", Recipient: &ChannelAccount{Name: "Helpdesk"}}, "my laptop is broken"},
+		{Activity{Text: "ask <at>John</at>", Recipient: &ChannelAccount{Name: "Helpdesk"}}, "ask John"},
+		{Activity{Value: json.RawMessage(`{"code":"yes"}`)}, "yes"},
+	} {
+		if text := messageText(&tc.activity); text != tc.text {
+			t.Errorf("messageText(%q) = %q; want %q", tc.activity.Text, text, tc.text)
+		}
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	c := newConnector(t)
+	defer c.Close()
+	app := newTestBot(c)
+
+	channel := &bot.Channel{ChatID: "a:1Xyz"}
+	app.setServiceURL(channel, c.URL)
+
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: "Hello"},
+		{Type: "text", Text: "Rate us", Buttons: []*chat.Buttons{{Button: []*chat.Button{
+			{Type: "reply", Text: "Good", Code: "5"},
+			{Type: "url", Text: "Site", Url: "https://example.com"},
+		}}}},
+	} {
+		err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: message})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if c.tokens != 1 {
+		t.Errorf("access token requested %d times; want 1", c.tokens)
+	}
+	if len(c.received) != 2 {
+		t.Fatalf("connector received %d activities; want 2", len(c.received))
+	}
+	if sent := c.received[0]; sent.Text != "Hello" || sent.Conversation.ID != channel.ChatID {
+		t.Errorf("activity[0] = %+v", sent)
+	}
+	sent := c.received[1]
+	if len(sent.Attachments) != 1 || sent.Attachments[0].ContentType != contentTypeAdaptiveCard {
+		t.Fatalf("activity[1] attachments = %+v", sent.Attachments)
+	}
+	var layout card
+	_ = json.Unmarshal(sent.Attachments[0].Content, &layout)
+	if len(layout.Body) != 2 || layout.Body[0].Text != "Rate us" || len(layout.Body[1].Actions) != 2 ||
+		layout.Body[1].Actions[0].Data.Code != "5" || layout.Body[1].Actions[1].URL != "https://example.com" {
+		t.Errorf("adaptive card = %+v", layout)
+	}
+}
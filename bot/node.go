@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"os"
+	"strings"
+)
+
+// OwnsSession reports whether this service node runs the bot's
+// persistent session, e.g. Discord Gateway or Matrix sync.
+// The platform delivers updates to every connected session,
+// so a single node of the cluster must own the connection;
+// node is "*" for any (single node) or the node's hostname
+func OwnsSession(node string) bool {
+	switch node = strings.TrimSpace(node); node {
+	case "":
+		return false
+	case "*":
+		return true
+	}
+	hostname, _ := os.Hostname()
+	return strings.EqualFold(node, hostname)
+}
@@ -73,4 +73,19 @@ func Register(provider string, factory NewProvider) {
 // or nil if not yet registered
 func GetProvider(name string) NewProvider {
 	return providers[name]
-}
\ No newline at end of file
+}
+// HealthChecker is an optional Provider interface
+// to probe the remote bot account state periodically
+type HealthChecker interface {
+	// HealthCheck probes the remote bot account credentials
+	// and whether the webhook callback uri is still registered
+	HealthCheck(ctx context.Context, uri string) (*Health, error)
+}
+
+// Health of the remote bot account
+type Health struct {
+	// Account name, e.g.: @bot username
+	Account string
+	// WebhookLost indicates the callback URL is not registered (anymore)
+	WebhookLost bool
+}
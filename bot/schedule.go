@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/errors"
+)
+
+const (
+	// BusinessHoursVariable of the conversation started out of the business hours
+	BusinessHoursVariable = "business_hours"
+	// BusinessHoursClosed value of the BusinessHoursVariable
+	BusinessHoursClosed = "closed"
+)
+
+// Schedule of the working hours compiled from the BusinessHours calendar
+//
+//	{"timezone": "Europe/Kyiv", "weekly": [{"days": ["mon","tue","wed","thu","fri"], "open": "09:00", "close": "18:00"}], "holidays": [{"date": "2026-12-25"}]}
+type Schedule struct {
+	loc *time.Location
+	// minutes [open, close) of the day, by time.Weekday
+	week [7][][2]int
+	// minutes [open, close) of the holiday, by date; nil - closed
+	holidays map[string][][2]int
+}
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// scheduleMinute of the "hh:mm" time of the day
+func scheduleMinute(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err == nil {
+		return t.Hour()*60 + t.Minute(), true
+	}
+	if s == "24:00" {
+		return 24 * 60, true
+	}
+	return 0, false
+}
+
+// scheduleSpan [open, close) minutes of the day
+func scheduleSpan(open, close string) ([2]int, error) {
+	from, ok := scheduleMinute(open)
+	if !ok || from == 24*60 {
+		return [2]int{}, fmt.Errorf("open %q is invalid; expect hh:mm", open)
+	}
+	till, ok := scheduleMinute(close)
+	if !ok || till <= from {
+		return [2]int{}, fmt.Errorf("close %q is invalid; expect hh:mm after open", close)
+	}
+	return [2]int{from, till}, nil
+}
+
+// NewSchedule validates and compiles the calendar.
+// Returns nil for the empty calendar; open 24/7.
+func NewSchedule(e *BusinessHours) (*Schedule, error) {
+	if len(e.GetWeekly()) == 0 && len(e.GetHolidays()) == 0 {
+		return nil, nil
+	}
+	var (
+		err   error
+		sched = &Schedule{}
+	)
+	if sched.loc, err = time.LoadLocation(e.GetTimezone()); err != nil {
+		return nil, fmt.Errorf("timezone: %v", err)
+	}
+	for i, hours := range e.GetWeekly() {
+		if len(hours.GetDays()) == 0 {
+			return nil, fmt.Errorf("weekly[%d]: days required", i)
+		}
+		span, err := scheduleSpan(hours.GetOpen(), hours.GetClose())
+		if err != nil {
+			return nil, fmt.Errorf("weekly[%d]: %v", i, err)
+		}
+		for _, name := range hours.GetDays() {
+			day, ok := scheduleDays[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("weekly[%d]: day %q is invalid", i, name)
+			}
+			sched.week[day] = append(sched.week[day], span)
+		}
+	}
+	for i, holiday := range e.GetHolidays() {
+		date, err := time.Parse(time.DateOnly, holiday.GetDate())
+		if err != nil {
+			return nil, fmt.Errorf("holidays[%d]: date %q is invalid; expect YYYY-MM-DD", i, holiday.GetDate())
+		}
+		if sched.holidays == nil {
+			sched.holidays = make(map[string][][2]int)
+		}
+		day := date.Format(time.DateOnly)
+		hours := sched.holidays[day]
+		if holiday.GetOpen() != "" || holiday.GetClose() != "" {
+			span, err := scheduleSpan(holiday.GetOpen(), holiday.GetClose())
+			if err != nil {
+				return nil, fmt.Errorf("holidays[%d]: %v", i, err)
+			}
+			hours = append(hours, span)
+		}
+		sched.holidays[day] = hours
+	}
+	return sched, nil
+}
+
+// ParseSchedule of the JSON-encoded BusinessHours calendar
+func ParseSchedule(spec string) (*Schedule, error) {
+	var hours BusinessHours
+	if err := json.Unmarshal([]byte(spec), &hours); err != nil {
+		return nil, err
+	}
+	return NewSchedule(&hours)
+}
+
+// ValidateBusinessHours checks
+// the business hours calendar is well formed
+func ValidateBusinessHours(e *BusinessHours) error {
+	if _, err := NewSchedule(e); err != nil {
+		return errors.BadRequest(
+			"chat.bot.business_hours.invalid",
+			"chatbot: business_hours: %v", err,
+		)
+	}
+	return nil
+}
+
+// IsOpen reports whether t is within the working hours
+func (s *Schedule) IsOpen(t time.Time) bool {
+	if s == nil {
+		return true // no schedule; 24/7
+	}
+	t = t.In(s.loc)
+	hours, holiday := s.holidays[t.Format(time.DateOnly)]
+	if !holiday {
+		hours = s.week[t.Weekday()]
+	}
+	now := t.Hour()*60 + t.Minute()
+	for _, span := range hours {
+		if span[0] <= now && now < span[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+
+	sched, err := ParseSchedule(`{"timezone":"Europe/Kyiv","weekly":[
+		{"days":["mon","tue","wed","thu","fri"],"open":"09:00","close":"18:00"},
+		{"days":["SAT"],"open":"10:00","close":"24:00"}
+	],"holidays":[
+		{"date":"2026-10-20","name":"Day off"},
+		{"date":"2026-10-21","open":"09:00","close":"13:00"}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for date, open := range map[string]bool{
+		"2026-10-19T06:00:00Z": true,  // Mon 09:00 Kyiv
+		"2026-10-19T05:59:00Z": false, // Mon 08:59
+		"2026-10-19T15:00:00Z": false, // Mon 18:00
+		"2026-10-24T20:59:00Z": true,  // Sat 23:59 (EEST)
+		"2026-10-25T10:00:00Z": false, // Sun
+		"2026-10-20T10:00:00Z": false, // Tue holiday
+		"2026-10-21T09:59:00Z": true,  // Wed 12:59 short day
+		"2026-10-21T10:00:00Z": false, // Wed 13:00
+	} {
+		at, _ := time.Parse(time.RFC3339, date)
+		if sched.IsOpen(at) != open {
+			t.Errorf("%s: open=%t expected", date, open)
+		}
+	}
+
+	if !(*Schedule)(nil).IsOpen(time.Now()) {
+		t.Error("no schedule must be open")
+	}
+	if sched, err = NewSchedule(&BusinessHours{Timezone: "UTC"}); err != nil || sched != nil {
+		t.Errorf("empty calendar: %v; open 24/7 expected", err)
+	}
+
+	for _, spec := range []string{
+		`{"timezone":"Mars/Olympus","weekly":[{"days":["mon"],"open":"09:00","close":"18:00"}]}`,
+		`{"holidays":[{"date":"25.12.2026"}]}`,
+		`{"holidays":[{"date":"2026-12-25","open":"09:00"}]}`,
+		`{"weekly":[{"days":["mon"],"open":"18:00","close":"09:00"}]}`,
+		`{"weekly":[{"days":["someday"],"open":"09:00","close":"18:00"}]}`,
+		`{"weekly":[{"days":["mon"],"open":"9am","close":"18:00"}]}`,
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("%s: error expected", spec)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/micro/micro/v3/service/errors"
+	authN "github.com/webitel/chat_manager/api/proto/auth"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+	"github.com/webitel/chat_manager/internal/secret"
+	"google.golang.org/protobuf/proto"
+)
+
+// SecretMetadata keys of the provider credentials
+// encrypted at rest and masked for the API clients
+var SecretMetadata = map[string]bool{
+	"token":           true, // telegram, viber, vk, discord, matrix, slack, line
+	"secret":          true, // custom, generic, email, line
+	"password":        true, // smpp, xmpp
+	"api_key":         true, // infobip
+	"api_hash":        true, // gotd
+	"auth_token":      true, // twilio
+	"access_token":    true, // corezoid
+	"app_password":    true, // msteams
+	"signing_secret":  true, // slack
+	"client_secret":   true, // facebook
+	"verify_token":    true, // facebook
+	"whatsapp_token":  true, // facebook
+	"smtp_password":   true, // email
+	"imap_password":   true, // email
+	"identity_secret": true, // webchat
+	"captcha":         true, // webchat; JSON settings with the "secret"
+	"out.headers":     true, // generic; "Authorization: Bearer .." template
+	// facebook pages, instagram and whatsapp accounts backup; access tokens
+	"fb": true, "ig": true, "wa": true,
+	// gotd session data
+	".gotd": true, ".auth": true,
+}
+
+// SecretMask of the secret value for the API clients
+const SecretMask = "********"
+
+// secretStore encrypts the .metadata secrets on save
+// and decrypts them back on load, transparently
+type secretStore struct {
+	Store
+	srv *Service
+}
+
+func (s *secretStore) Create(ctx *app.CreateOptions, obj *Bot) error {
+	enc, err := s.srv.encryptBot(obj)
+	if err != nil {
+		return err
+	}
+	err = s.Store.Create(ctx, enc)
+	if err != nil {
+		return err
+	}
+	enc.Metadata = obj.Metadata
+	app.MergeProto(obj, enc, ctx.Fields...)
+	return nil
+}
+
+func (s *secretStore) Update(ctx *app.UpdateOptions, set *Bot) error {
+	enc, err := s.srv.encryptBot(set)
+	if err != nil {
+		return err
+	}
+	err = s.Store.Update(ctx, enc)
+	if err != nil {
+		return err
+	}
+	enc.Metadata = set.Metadata
+	app.MergeProto(set, enc, ctx.Fields...)
+	return nil
+}
+
+func (s *secretStore) Search(ctx *app.SearchOptions) ([]*Bot, error) {
+	list, err := s.Store.Search(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range list {
+		err = s.srv.decryptMetadata(obj.Metadata)
+		if err != nil {
+			return nil, errors.InternalServerError(
+				"chat.bot.secret.decrypt",
+				"chatbot: decrypt bot.id=%d .metadata; %v",
+				obj.GetId(), err,
+			)
+		}
+	}
+	return list, nil
+}
+
+// encryptBot returns a copy of the obj with the .metadata secrets encrypted.
+// The obj itself may be shared with the running Gateway, so is not modified.
+func (srv *Service) encryptBot(obj *Bot) (*Bot, error) {
+	enc := proto.Clone(obj).(*Bot)
+	for key, val := range enc.Metadata {
+		if !SecretMetadata[key] || val == "" || secret.IsEncrypted(val) {
+			continue
+		}
+		val, err := srv.Secrets.Encrypt(val)
+		if err != nil {
+			return nil, errors.InternalServerError(
+				"chat.bot.secret.encrypt",
+				"chatbot: encrypt .metadata.%s; %v",
+				key, err,
+			)
+		}
+		enc.Metadata[key] = val
+	}
+	return enc, nil
+}
+
+// decryptMetadata secrets in place
+func (srv *Service) decryptMetadata(md map[string]string) error {
+	for key, val := range md {
+		if !secret.IsEncrypted(val) {
+			continue
+		}
+		val, err := srv.Secrets.Decrypt(val)
+		if err != nil {
+			return err
+		}
+		md[key] = val
+	}
+	return nil
+}
+
+// canViewSecrets reports whether the caller is granted
+// to see the .metadata secrets unmasked
+func canViewSecrets(ctx *app.Context) bool {
+	return ctx.HasPermission(auth.PermissionUpdateAny.Id)
+}
+
+// maskSecrets of the .metadata in place
+func maskSecrets(obj *Bot) {
+	for key, val := range obj.GetMetadata() {
+		if SecretMetadata[key] && val != "" {
+			obj.Metadata[key] = SecretMask
+		}
+	}
+}
+
+// unmaskSecrets of the dst .metadata; the masked values,
+// sent back by the client unchanged, are restored from src
+func unmaskSecrets(dst, src map[string]string) {
+	for key, val := range dst {
+		if val != SecretMask || !SecretMetadata[key] {
+			continue
+		}
+		if val, ok := src[key]; ok {
+			dst[key] = val
+		} else {
+			delete(dst, key)
+		}
+	}
+}
+
+// RotateSecrets re-encrypts the .metadata secrets of all the bots
+// with the primary key, including the plaintext ones stored before
+// encryption was enabled. Returns the number of bots updated.
+func (srv *Service) RotateSecrets(ctx context.Context) (int, error) {
+
+	if srv.Secrets == nil {
+		return 0, secret.ErrNoKeys
+	}
+
+	lookup := app.SearchOptions{
+		Context: app.Context{
+			Context: ctx,
+			Authorization: auth.Authorization{
+				Service: "webitel.chat.bot",
+				Method:  "internal",
+				Token:   "webitel.chat.bot",
+			},
+		},
+		Fields: []string{
+			"dc", "id", "metadata",
+			"updated_at", "updated_by",
+		},
+		Size: -1,
+	}
+
+	// Raw store; do not decrypt
+	store := srv.store
+	if s, is := store.(*secretStore); is {
+		store = s.Store
+	}
+	list, err := store.Search(&lookup)
+	if err != nil {
+		return 0, err
+	}
+
+	kid := srv.Secrets.KeyID()
+	n := 0
+	for _, obj := range list {
+		rotate := false
+		for key, val := range obj.Metadata {
+			if SecretMetadata[key] && val != "" && secret.KeyID(val) != kid {
+				rotate = true
+				break
+			}
+		}
+		if !rotate {
+			continue
+		}
+		err = srv.decryptMetadata(obj.Metadata)
+		if err != nil {
+			return n, errors.InternalServerError(
+				"chat.bot.secret.decrypt",
+				"chatbot: decrypt bot.id=%d .metadata; %v",
+				obj.GetId(), err,
+			)
+		}
+		// Keep the latest .updated_* values; NOT a user update
+		err = srv.store.Update(
+			&app.UpdateOptions{
+				Context: app.Context{
+					Context: ctx,
+					Authorization: auth.Authorization{
+						Service: lookup.Authorization.Service,
+						Method:  lookup.Authorization.Method,
+						Token:   lookup.Authorization.Token,
+						Creds: &authN.Userinfo{
+							Dc:     obj.GetDc().GetId(),
+							Domain: obj.GetDc().GetName(),
+						},
+					},
+				},
+				Fields: []string{"metadata"},
+			},
+			obj,
+		)
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/internal/secret"
+	"google.golang.org/protobuf/proto"
+)
+
+type secretMemStore struct {
+	Store
+	saved *Bot
+}
+
+func (s *secretMemStore) Update(_ *app.UpdateOptions, set *Bot) error {
+	s.saved = proto.Clone(set).(*Bot)
+	return nil
+}
+
+func (s *secretMemStore) Search(*app.SearchOptions) ([]*Bot, error) {
+	return []*Bot{proto.Clone(s.saved).(*Bot)}, nil
+}
+
+func TestSecretStore(t *testing.T) {
+
+	store := &secretMemStore{}
+	srv := NewService(store, slog.Default(), nil, nil, nil)
+	srv.Secrets, _ = secret.ParseKeyring("k1:" + base64.StdEncoding.EncodeToString(
+		[]byte(strings.Repeat("k", 32)),
+	))
+
+	obj := &Bot{Id: 1, Metadata: map[string]string{"token": "123:ABC", "url": "https://example.com"}}
+	err := srv.store.Update(&app.UpdateOptions{Fields: []string{"metadata"}}, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Metadata["token"] != "123:ABC" {
+		t.Errorf("update: token %q; plaintext expected", obj.Metadata["token"])
+	}
+	if vs := store.saved.Metadata["token"]; secret.KeyID(vs) != "k1" {
+		t.Errorf("saved: token %q; encrypted expected", vs)
+	}
+	if vs := store.saved.Metadata["url"]; vs != "https://example.com" {
+		t.Errorf("saved: url %q; plaintext expected", vs)
+	}
+
+	list, err := srv.store.Search(&app.SearchOptions{})
+	if err != nil || list[0].Metadata["token"] != "123:ABC" {
+		t.Fatalf("search: %v, %v; decrypted expected", list, err)
+	}
+
+	maskSecrets(list[0])
+	if list[0].Metadata["token"] != SecretMask || list[0].Metadata["url"] != "https://example.com" {
+		t.Errorf("mask: %v", list[0].Metadata)
+	}
+	unmaskSecrets(list[0].Metadata, obj.Metadata)
+	if list[0].Metadata["token"] != "123:ABC" {
+		t.Errorf("unmask: %v", list[0].Metadata)
+	}
+}
+
+func TestSecretStoreNested(t *testing.T) {
+
+	store := &secretMemStore{}
+	srv := NewService(store, slog.Default(), nil, nil, nil)
+	srv.Secrets, _ = secret.ParseKeyring("k1:" + base64.StdEncoding.EncodeToString(
+		[]byte(strings.Repeat("k", 32)),
+	))
+
+	captcha := `{"provider":"hcaptcha","enabled":true,"secret":"0x4AAA"}`
+	headers := "Authorization: Bearer s3cr3t"
+	obj := &Bot{Id: 1, Provider: "webchat", Metadata: map[string]string{
+		"captcha": captcha, "out.headers": headers,
+	}}
+	err := srv.store.Update(&app.UpdateOptions{Fields: []string{"metadata"}}, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range obj.Metadata {
+		if vs := store.saved.Metadata[key]; !strings.HasPrefix(vs, secret.Prefix) {
+			t.Errorf("saved: %s %q; encrypted expected", key, vs)
+		}
+	}
+
+	list, err := srv.store.Search(&app.SearchOptions{})
+	if err != nil || list[0].Metadata["captcha"] != captcha || list[0].Metadata["out.headers"] != headers {
+		t.Fatalf("search: %v, %v; decrypted expected", list, err)
+	}
+
+	maskSecrets(list[0])
+	if list[0].Metadata["captcha"] != SecretMask || list[0].Metadata["out.headers"] != SecretMask {
+		t.Errorf("mask: %v", list[0].Metadata)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"net/http/pprof"
 
@@ -20,15 +21,22 @@ import (
 	pbstorage "github.com/webitel/chat_manager/api/proto/storage"
 	"github.com/webitel/chat_manager/app"
 	"github.com/webitel/chat_manager/auth"
+	"github.com/webitel/chat_manager/internal/health"
+	"github.com/webitel/chat_manager/internal/secret"
 	wlog "github.com/webitel/chat_manager/log"
 	audit "github.com/webitel/chat_manager/logger"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
 	CaptchaSuffix = "/captcha"
+	// PrechatSuffix of the WebChat pre-chat form definition URI
+	PrechatSuffix = "/prechat"
 
 	// Template type
 	FilePolicyFailType = "file_policy_fail"
+	// Out of business hours auto-reply template type
+	OutOfHoursType = "out_of_hours"
 )
 
 const (
@@ -51,6 +59,17 @@ type Service struct {
 	// Address to listen HTTP callbacks (webhook) requests
 	Addr    string
 	WebRoot string
+	// Address to listen internal [/healthz], [/readyz] and [/metrics] requests;
+	// NOT exposed with the public webhook listener. Empty - not served
+	InternalAddr string
+	// Health probes [/healthz] and [/readyz]; optional
+	Health *health.Checker
+	// Metrics exposition [/metrics]; optional
+	Metrics http.Handler
+	// HealthCheckInterval of the running gateways; zero - disabled
+	HealthCheckInterval time.Duration
+	// Secrets keyring to encrypt bot credentials at rest; nil - disabled
+	Secrets *secret.Keyring
 
 	Log    *slog.Logger
 	Auth   *auth.Client
@@ -68,6 +87,8 @@ type Service struct {
 	audit    *audit.Client
 
 	fileService pbstorage.FileService
+	// open conversations gauge callback
+	observer metric.Registration
 }
 
 func NewService(
@@ -83,21 +104,23 @@ func NewService(
 		logger = slog.Default()
 	}
 
-	return &Service{
+	srv := &Service{
 
 		Log:    logger,
 		Client: client, // chat.NewChatService("webitel.chat.server"),
 
 		exit: make(chan chan error),
 
-		store: store,
-
 		gateways: make(map[string]int64),
 		profiles: make(map[int64]*Gateway),
 		audit:    auditClient,
 
 		fileService: fileService,
 	}
+	// Encrypt .metadata secrets at rest
+	srv.store = &secretStore{Store: store, srv: srv}
+
+	return srv
 }
 
 func (srv *Service) onStart() {
@@ -142,7 +165,8 @@ func (srv *Service) onStart() {
 			"dc", "id",
 			"name", "uri",
 			"enabled", "flow",
-			"updates",
+			"updates", "survey",
+			"business_hours",
 			"provider", "metadata",
 			"created_at", "created_by",
 			"updated_at", "updated_by",
@@ -293,17 +317,84 @@ func (srv *Service) Start() error {
 
 	}()
 
+	internal, err := srv.serveInternal()
+	if err != nil {
+		_ = ln.Close()
+		return err
+	}
+
+	stop := make(chan struct{})
+	go srv.healthCheck(stop)
+
 	go func() {
 		ch := <-srv.exit
+		close(stop)
+		if internal != nil {
+			_ = internal.Close()
+		}
 		ch <- ln.Close()
 	}()
 
+	srv.observer, err = srv.observeConversations()
+	if err != nil {
+		srv.Log.Warn("Server [metrics] conversations.open",
+			slog.Any("error", err),
+		)
+	}
+
 	// TODO: onStartup()
 	srv.onStart()
 
 	return nil
 }
 
+// serveInternal starts background http.Server to listen
+// the [/healthz], [/readyz] and [/metrics] requests on the srv.InternalAddr.
+// Returns <nil> listener if disabled
+func (srv *Service) serveInternal() (net.Listener, error) {
+
+	if srv.InternalAddr == "" || (srv.Health == nil && srv.Metrics == nil) {
+		return nil, nil // disabled
+	}
+
+	ln, err := net.Listen("tcp", srv.InternalAddr)
+	if err != nil {
+		return nil, err
+	}
+	// Normalize address
+	srv.InternalAddr = ln.Addr().String()
+
+	rmux := http.NewServeMux()
+	if srv.Health != nil {
+		srv.Health.Register(rmux)
+	}
+	if srv.Metrics != nil {
+		rmux.Handle("/metrics", srv.Metrics)
+	}
+
+	srv.Log.Info("Server [http] Internal listening",
+		slog.String("addr", srv.InternalAddr),
+	)
+
+	go func() {
+		err := http.Serve(ln, rmux)
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+		emit := slog.LevelWarn
+		if err != nil {
+			emit = slog.LevelError
+		}
+		srv.Log.Log(context.TODO(), emit,
+			"Server [http] Internal shutted down",
+			slog.String("addr", ln.Addr().String()),
+			slog.Any("error", err),
+		)
+	}()
+
+	return ln, nil
+}
+
 // Close background http.Server
 func (srv *Service) Close() error {
 
@@ -311,6 +402,11 @@ func (srv *Service) Close() error {
 	srv.exit <- ch
 	<-ch // await: http.ErrServerClosed, "use of closed network connection"
 
+	if srv.observer != nil {
+		_ = srv.observer.Unregister()
+		srv.observer = nil
+	}
+
 	// b.log.Info().
 	// 	Msg("removing webhooks")
 	// for k := range b.bots {
@@ -756,16 +852,18 @@ func (srv *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			origin = "*"
 		}
 		header.Set("Access-Control-Allow-Origin", origin)
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return // 200 OK
-		}
+		header.Add("Vary", "Origin")
 		// File exists ? static asset !
 		name := filepath.Join(srv.WebRoot, r.URL.Path)
 		if file, re := os.Stat(name); re == nil && !file.IsDir() {
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusOK)
+				return // 200 OK
+			}
 			http.ServeFile(w, r, name)
 			return
 		}
+		// Preflight; the gateway may restrict the Origin
 	case http.MethodPost:
 		// Receive Update Event !
 	default:
@@ -776,6 +874,7 @@ func (srv *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// if strings.HasSuffix(uri, CaptchaSuffix) { // the CAPTCHA check ! Find the underlying gateway...
 	uri = strings.TrimSuffix(uri, CaptchaSuffix)
 	// }
+	uri = strings.TrimSuffix(uri, PrechatSuffix)
 
 	//uri := r.URL.Path // strings.TrimLeft(r.URL.Path, "/")
 
@@ -786,6 +885,12 @@ func (srv *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	gate, err := srv.Gateway(r.Context(), 0, uri)
 
+	if err != nil && r.Method == http.MethodOptions {
+		// Preflight; NOT a gateway URI
+		w.WriteHeader(http.StatusOK)
+		return // 200 OK
+	}
+
 	if err != nil {
 		re := errors.FromError(err)
 		switch re.Code {
@@ -922,7 +1027,26 @@ func (srv *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// return
 	}
 
+	// Does provider restrict the request Origin ?
+	if checker, is := gate.External.(interface {
+		CheckOrigin(req *http.Request) bool
+	}); is && r.Header.Get(hdrOrigin) != "" && !checker.CheckOrigin(r) {
+		header := w.Header()
+		header.Del("Access-Control-Allow-Credentials")
+		header.Del("Access-Control-Allow-Methods")
+		header.Del("Access-Control-Allow-Headers")
+		header.Del("Access-Control-Allow-Origin")
+		http.Error(w, "(403) Origin Not Allowed", http.StatusForbidden)
+		return // 403 Forbidden
+	}
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return // 200 OK
+	}
+
 	// Invoke bot's gateway callback handler !
+	defer gate.observeWebhook(r.Context(), time.Now())
 	gate.WebHook(w, r)
 
 	return // 200
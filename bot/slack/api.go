@@ -0,0 +1,264 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// endpointURL of the Slack Web API
+var endpointURL = "https://slack.com/api"
+
+// Error of the Web API method call
+type Error struct {
+	Method string
+	Code   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("slack: %s: %s", e.Method, e.Code)
+}
+
+// response envelope of the Web API method
+type response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Client of the Slack Web API
+type Client struct {
+	Token string
+	HTTP  *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// call Web API method with JSON request body (POST) or query (GET; req is url.Values)
+func (c *Client) call(ctx context.Context, method string, req, res any) error {
+
+	var (
+		err  error
+		call *http.Request
+		link = strings.TrimRight(endpointURL, "/") + "/" + method
+	)
+
+	if query, is := req.(url.Values); is {
+		call, err = http.NewRequestWithContext(ctx, http.MethodGet, link+"?"+query.Encode(), nil)
+	} else {
+		var body []byte
+		body, err = json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		call, err = http.NewRequestWithContext(ctx, http.MethodPost, link, bytes.NewReader(body))
+		if err == nil {
+			call.Header.Set("Content-Type", "application/json; charset=utf-8")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	call.Header.Set("Authorization", "Bearer "+c.Token)
+
+	rsp, err := c.httpClient().Do(call)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return &Error{Method: method, Code: rsp.Status}
+	}
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	var status response
+	if err = json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	if !status.OK {
+		return &Error{Method: method, Code: status.Error}
+	}
+	if res != nil {
+		return json.Unmarshal(body, res)
+	}
+	return nil
+}
+
+// download file content; private Slack files
+// must be authorized with the bot token
+func (c *Client) download(ctx context.Context, link string, private bool, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	if private {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: HTTP %s", rsp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("download: file size exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+// AuthTest identifies the bot token owner
+// https://api.slack.com/methods/auth.test
+type AuthTest struct {
+	URL    string `json:"url"`
+	Team   string `json:"team"`
+	TeamID string `json:"team_id"`
+	User   string `json:"user"`
+	UserID string `json:"user_id"`
+	BotID  string `json:"bot_id"`
+}
+
+func (c *Client) AuthTest(ctx context.Context) (*AuthTest, error) {
+	var res AuthTest
+	err := c.call(ctx, "auth.test", url.Values{}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// User profile
+// https://api.slack.com/types/user
+type User struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	IsBot   bool   `json:"is_bot"`
+	Profile struct {
+		RealName    string `json:"real_name"`
+		DisplayName string `json:"display_name"`
+		Email       string `json:"email"`
+	} `json:"profile"`
+}
+
+// UsersInfo https://api.slack.com/methods/users.info
+func (c *Client) UsersInfo(ctx context.Context, userID string) (*User, error) {
+	var res struct {
+		User *User `json:"user"`
+	}
+	err := c.call(ctx, "users.info", url.Values{"user": {userID}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.User, nil
+}
+
+// PostMessage https://api.slack.com/methods/chat.postMessage
+type PostMessage struct {
+	Channel  string  `json:"channel"`
+	ThreadTS string  `json:"thread_ts,omitempty"`
+	Text     string  `json:"text"`
+	Blocks   []Block `json:"blocks,omitempty"`
+	Mrkdwn   bool    `json:"mrkdwn"`
+}
+
+// PostMessage returns the ts of the posted message
+func (c *Client) PostMessage(ctx context.Context, req *PostMessage) (string, error) {
+	var res struct {
+		TS string `json:"ts"`
+	}
+	err := c.call(ctx, "chat.postMessage", req, &res)
+	if err != nil {
+		return "", err
+	}
+	return res.TS, nil
+}
+
+// UploadFile shares the file to the channel (thread);
+// https://api.slack.com/messaging/files#uploading_files
+func (c *Client) UploadFile(ctx context.Context, channel, threadTS, name, comment string, data []byte) error {
+
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	err := c.call(ctx, "files.getUploadURLExternal", url.Values{
+		"filename": {name},
+		"length":   {fmt.Sprint(len(data))},
+	}, &upload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upload.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return &Error{Method: "files.upload", Code: rsp.Status}
+	}
+
+	type file struct {
+		ID    string `json:"id"`
+		Title string `json:"title,omitempty"`
+	}
+	return c.call(ctx, "files.completeUploadExternal", &struct {
+		Files    []file `json:"files"`
+		Channel  string `json:"channel_id"`
+		ThreadTS string `json:"thread_ts,omitempty"`
+		Comment  string `json:"initial_comment,omitempty"`
+	}{
+		Files:    []file{{ID: upload.FileID, Title: name}},
+		Channel:  channel,
+		ThreadTS: threadTS,
+		Comment:  comment,
+	}, nil)
+}
+
+// respond to the interaction's response_url
+// https://api.slack.com/interactivity/handling#message_responses
+func (c *Client) respond(ctx context.Context, responseURL string, msg any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return &Error{Method: "response_url", Code: rsp.Status}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"strconv"
+	"strings"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Block Kit layout limits
+// https://api.slack.com/reference/block-kit/blocks
+const (
+	maxSectionText  = 3000
+	maxActionsBlock = 25 // elements
+	maxButtonText   = 75 // characters
+	maxButtonValue  = 2000
+	maxButtonURL    = 3000
+	maxBlocks       = 50
+)
+
+// Block of the message layout
+// https://api.slack.com/reference/block-kit/blocks
+type Block struct {
+	Type     string     `json:"type"` // section | actions
+	BlockID  string     `json:"block_id,omitempty"`
+	Text     *Text      `json:"text,omitempty"`
+	Elements []*Element `json:"elements,omitempty"`
+}
+
+// Text composition object
+type Text struct {
+	Type  string `json:"type"` // plain_text | mrkdwn
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// Element of the actions block; button
+// https://api.slack.com/reference/block-kit/block-elements#button
+type Element struct {
+	Type     string `json:"type"` // button
+	ActionID string `json:"action_id"`
+	Text     *Text  `json:"text"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// truncate s to n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// newBlocks renders message text with the buttons
+// as a section followed by the actions block per each row.
+// Only url, reply and postback buttons are supported;
+// Slack has no keyboard to request user's contact or location.
+func newBlocks(text string, rows []*chat.Buttons) []Block {
+
+	var blocks []Block
+	if text = strings.TrimSpace(text); text != "" {
+		blocks = append(blocks, Block{
+			Type: "section",
+			Text: &Text{Type: "mrkdwn", Text: truncate(text, maxSectionText)},
+		})
+	}
+
+	for r, row := range rows {
+		var elements []*Element
+		for c, button := range row.GetButton() {
+			caption := strings.TrimSpace(button.GetText())
+			if caption == "" {
+				caption = strings.TrimSpace(button.GetCaption())
+			}
+			if caption == "" {
+				continue
+			}
+			element := &Element{
+				Type:     "button",
+				ActionID: "btn_" + strconv.Itoa(r) + "_" + strconv.Itoa(c),
+				Text:     &Text{Type: "plain_text", Text: truncate(caption, maxButtonText), Emoji: true},
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if element.URL = button.GetUrl(); element.URL == "" || len(element.URL) > maxButtonURL {
+					continue
+				}
+			case "reply", "postback":
+				element.Value = button.GetCode()
+				if element.Value == "" {
+					element.Value = caption
+				}
+				if len(element.Value) > maxButtonValue {
+					continue
+				}
+			default:
+				continue // NOT supported
+			}
+			if len(elements) == maxActionsBlock {
+				break
+			}
+			elements = append(elements, element)
+		}
+		if len(elements) == 0 {
+			continue
+		}
+		if len(blocks) == maxBlocks {
+			break
+		}
+		blocks = append(blocks, Block{
+			Type:     "actions",
+			BlockID:  "row_" + strconv.Itoa(r),
+			Elements: elements,
+		})
+	}
+
+	return blocks
+}
@@ -0,0 +1,739 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "slack"
+	// Request signature header(s)
+	// https://api.slack.com/authentication/verifying-requests-from-slack
+	signatureHeader = "X-Slack-Signature"
+	timestampHeader = "X-Slack-Request-Timestamp"
+	signatureScheme = "v0"
+	// Max age of the signed request; replay attack protection
+	maxRequestAge = 5 * time.Minute
+	// Max size of the inbound request body
+	maxRequestSize = 1 << 20
+	// Max size of the file to download
+	maxFileSize = 32 << 20
+	// Delivered event(s) retention; Slack retries within minutes
+	eventsRetention = 10 * time.Minute
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the Slack workspace app provider.
+// Receives Events API and interactivity requests on the webhook URI
+// and replies with the Web API using the bot token.
+//
+// Conversation is started per direct message (DM) channel,
+// or per channel thread when the app is mentioned.
+type Bot struct {
+	Gateway *bot.Gateway
+	client  *Client
+	secret  string
+	// bot user identity
+	me *AuthTest
+
+	mx sync.Mutex
+	// cache of the user profiles; map[user.id]
+	users map[string]*User
+	// channel thread(s) conversations; map[chat.id]
+	threads map[string]struct{}
+	// recently handled event(s); map[event.id]
+	events map[string]time.Time
+}
+
+// New initialize new agent.profile service Slack app provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	token := profile["token"]
+	if token == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.slack.token.required",
+			"slack: bot token required",
+		)
+	}
+	secret := profile["signing_secret"]
+	if secret == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.slack.signing_secret.required",
+			"slack: app signing secret required",
+		)
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.slack.updates.invalid",
+			"slack: %v", err,
+		)
+	}
+
+	client := &Client{
+		Token: token,
+		HTTP:  &http.Client{Timeout: time.Minute},
+	}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.HTTP.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	// CHECK: Token is still valid !
+	me, err := client.AuthTest(context.TODO())
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.slack.token.invalid",
+			"slack: %v", err,
+		)
+	}
+
+	app := &Bot{
+		Gateway: agent,
+		client:  client,
+		secret:  secret,
+		me:      me,
+		users:   make(map[string]*User),
+		threads: make(map[string]struct{}),
+		events:  make(map[string]time.Time),
+	}
+	// Can we upgrade latest bot account ?
+	if last, _ := state.(*Bot); last != nil && last.me.TeamID == me.TeamID {
+		last.mx.Lock()
+		for id := range last.threads {
+			app.threads[id] = struct{}{}
+		}
+		last.mx.Unlock()
+	}
+
+	return app, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register does nothing; Slack app's Event Subscriptions
+// and Interactivity request URL must be set to the webhook URI manually
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.Gateway.Log.Info("slack/bot.register",
+		slog.String("team", c.me.Team),
+		slog.String("request_url", uri),
+	)
+	return nil
+}
+
+// Deregister does nothing; see Register
+func (c *Bot) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (c *Bot) Close() error {
+	return nil
+}
+
+// chatID of the conversation:
+// DM channel ID or "channel:thread_ts"
+func chatID(channel, threadTS string) string {
+	if threadTS == "" {
+		return channel
+	}
+	return channel + ":" + threadTS
+}
+
+// parseChatID is the reverse of the chatID
+func parseChatID(id string) (channel, threadTS string) {
+	channel, threadTS, _ = strings.Cut(id, ":")
+	return
+}
+
+// follow channel thread conversation
+func (c *Bot) follow(id string) {
+	c.mx.Lock()
+	c.threads[id] = struct{}{}
+	c.mx.Unlock()
+}
+
+func (c *Bot) unfollow(id string) {
+	c.mx.Lock()
+	delete(c.threads, id)
+	c.mx.Unlock()
+}
+
+func (c *Bot) following(id string) bool {
+	c.mx.Lock()
+	_, ok := c.threads[id]
+	c.mx.Unlock()
+	return ok
+}
+
+// delivered reports whether event was already handled; marks it otherwise.
+// Slack retries delivery if no response within 3 seconds.
+func (c *Bot) delivered(eventID string) bool {
+	if eventID == "" {
+		return false
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	now := time.Now()
+	for id, at := range c.events {
+		if now.Sub(at) > eventsRetention {
+			delete(c.events, id)
+		}
+	}
+	if _, ok := c.events[eventID]; ok {
+		return true
+	}
+	c.events[eventID] = now
+	return false
+}
+
+// undelivered forgets failed event to accept retry
+func (c *Bot) undelivered(eventID string) {
+	c.mx.Lock()
+	delete(c.events, eventID)
+	c.mx.Unlock()
+}
+
+// verifySignature of the request signed with the app signing secret
+func verifySignature(header http.Header, body []byte, secret string, now time.Time) error {
+	ts := header.Get(timestampHeader)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("slack: invalid %s header", timestampHeader)
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("slack: request timestamp expired")
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(signatureScheme + ":" + ts + ":"))
+	h.Write(body)
+	sign := signatureScheme + "=" + hex.EncodeToString(h.Sum(nil))
+	if !hmac.Equal([]byte(sign), []byte(header.Get(signatureHeader))) {
+		return fmt.Errorf("slack: invalid request signature")
+	}
+	return nil
+}
+
+// eventCallback is the Events API request envelope
+// https://api.slack.com/apis/connections/events-api#callback-field
+type eventCallback struct {
+	Type      string `json:"type"` // url_verification | event_callback
+	Challenge string `json:"challenge,omitempty"`
+	TeamID    string `json:"team_id"`
+	EventID   string `json:"event_id"`
+	Event     *Event `json:"event"`
+}
+
+// Event of the message or app_mention type
+// https://api.slack.com/events/message
+type Event struct {
+	Type        string  `json:"type"`
+	Subtype     string  `json:"subtype"`
+	Channel     string  `json:"channel"`
+	ChannelType string  `json:"channel_type"` // im | channel | group | mpim
+	User        string  `json:"user"`
+	BotID       string  `json:"bot_id"`
+	Text        string  `json:"text"`
+	TS          string  `json:"ts"`
+	ThreadTS    string  `json:"thread_ts"`
+	Files       []*File `json:"files"`
+}
+
+// File shared within the message
+// https://api.slack.com/types/file
+type File struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Mimetype    string `json:"mimetype"`
+	Size        int64  `json:"size"`
+	URLPrivate  string `json:"url_private"`
+	URLDownload string `json:"url_private_download"`
+}
+
+// interaction payload of the block_actions type
+// https://api.slack.com/reference/interaction-payloads/block-actions
+type interaction struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Text     string `json:"text"`
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts"`
+	} `json:"message"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// WebHook implements provider.Receiver interface for Slack
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(notice.Body, maxRequestSize))
+	if err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = verifySignature(notice.Header, body, c.secret, time.Now())
+	if err != nil {
+		c.Gateway.Log.Warn("slack/bot.webhook",
+			slog.Any("error", err),
+		)
+		http.Error(reply, "(401) Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := notice.Context()
+	// Interactivity: application/x-www-form-urlencoded
+	if payload := formValue(body, "payload"); payload != "" {
+		var action interaction
+		if err = json.Unmarshal([]byte(payload), &action); err != nil {
+			http.Error(reply, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = c.onAction(ctx, &action); err != nil {
+			c.Gateway.Log.Error("slack/bot.onAction",
+				slog.Any("error", err),
+			)
+		}
+		reply.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var callback eventCallback
+	if err = json.Unmarshal(body, &callback); err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch callback.Type {
+	case "url_verification":
+		reply.Header().Set("Content-Type", "text/plain")
+		_, _ = reply.Write([]byte(callback.Challenge))
+		return
+	case "event_callback":
+	default:
+		reply.WriteHeader(http.StatusOK)
+		return // IGNORE
+	}
+
+	if callback.Event == nil || c.delivered(callback.EventID) {
+		reply.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err = c.onEvent(ctx, callback.Event); err != nil {
+		c.undelivered(callback.EventID)
+		c.Gateway.Log.Error("slack/bot.onEvent",
+			slog.Any("error", err),
+			slog.String("event", callback.Event.Type),
+			slog.String("event_id", callback.EventID),
+		)
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = http.StatusBadGateway
+		}
+		http.Error(reply, re.Detail, int(re.Code))
+		return
+	}
+
+	reply.WriteHeader(http.StatusOK)
+}
+
+// formValue of the urlencoded body; empty for JSON body
+func formValue(body []byte, key string) string {
+	if len(body) == 0 || body[0] == '{' {
+		return ""
+	}
+	req := http.Request{
+		Method: http.MethodPost,
+		Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Body:   io.NopCloser(bytes.NewReader(body)),
+	}
+	return req.PostFormValue(key)
+}
+
+// onEvent handles message or app_mention event
+func (c *Bot) onEvent(ctx context.Context, e *Event) error {
+
+	if e.BotID != "" || e.User == "" || e.User == c.me.UserID {
+		return nil // IGNORE: bot(s) message
+	}
+	switch e.Subtype {
+	case "", "file_share", "thread_broadcast":
+	default:
+		return nil // IGNORE: edited, deleted, joined, etc.
+	}
+
+	var id string
+	switch e.Type {
+	case "app_mention":
+		// NEW thread conversation; or continue existing one
+		threadTS := e.ThreadTS
+		if threadTS == "" {
+			threadTS = e.TS
+		}
+		id = chatID(e.Channel, threadTS)
+		c.follow(id)
+
+	case "message":
+		if e.ChannelType == "im" {
+			id = chatID(e.Channel, "")
+			break
+		}
+		// Channel thread reply(s) only
+		if e.ThreadTS == "" {
+			return nil
+		}
+		// Mentions are delivered as app_mention event
+		if strings.Contains(e.Text, "<@"+c.me.UserID+">") {
+			return nil
+		}
+		id = chatID(e.Channel, e.ThreadTS)
+		if !c.following(id) {
+			return nil
+		}
+
+	default:
+		return nil
+	}
+
+	return c.receive(ctx, id, e.User, plainText(e.Text, c.me.UserID), e.Files)
+}
+
+// onAction handles block_actions button click
+func (c *Bot) onAction(ctx context.Context, e *interaction) error {
+
+	if e.Type != "block_actions" || len(e.Actions) == 0 {
+		return nil
+	}
+	value := e.Actions[0].Value
+	if value == "" {
+		return nil // url button
+	}
+
+	id := chatID(e.Channel.ID, "")
+	if !strings.HasPrefix(e.Channel.ID, "D") {
+		threadTS := e.Message.ThreadTS
+		if threadTS == "" {
+			threadTS = e.Message.TS
+		}
+		id = chatID(e.Channel.ID, threadTS)
+		c.follow(id)
+	}
+
+	// Remove buttons from the original message
+	if e.ResponseURL != "" {
+		err := c.client.respond(ctx, e.ResponseURL, map[string]any{
+			"replace_original": true,
+			"text":             e.Message.Text,
+		})
+		if err != nil {
+			c.Gateway.Log.Warn("slack/bot.onAction",
+				slog.String("error", "remove buttons: "+err.Error()),
+			)
+		}
+	}
+
+	return c.receive(ctx, id, e.User.ID, value, nil)
+}
+
+var (
+	mentionRe = regexp.MustCompile(`<@([UW][A-Z0-9]+)>`)
+	linkRe    = regexp.MustCompile(`<((?:https?|mailto|tel):[^|>]+)(?:\|([^>]+))?>`)
+	entities  = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// plainText of the Slack formatted message;
+// removes bot mention and unwraps link(s)
+func plainText(text, botUserID string) string {
+	text = mentionRe.ReplaceAllStringFunc(text, func(m string) string {
+		if m[2:len(m)-1] == botUserID {
+			return ""
+		}
+		return m
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(m string) string {
+		link := linkRe.FindStringSubmatch(m)
+		href, label := link[1], link[2]
+		if addr, ok := strings.CutPrefix(href, "mailto:"); ok {
+			href = addr
+		} else if addr, ok = strings.CutPrefix(href, "tel:"); ok {
+			href = addr
+		}
+		if label == "" || label == href {
+			return href
+		}
+		return label + " (" + href + ")"
+	})
+	return strings.TrimSpace(entities.Replace(text))
+}
+
+// getUser profile; cached
+func (c *Bot) getUser(ctx context.Context, userID string) *User {
+	c.mx.Lock()
+	user := c.users[userID]
+	c.mx.Unlock()
+	if user != nil {
+		return user
+	}
+	user, err := c.client.UsersInfo(ctx, userID)
+	if err != nil || user == nil {
+		c.Gateway.Log.Warn("slack/users.info",
+			slog.Any("error", err),
+			slog.String("user", userID),
+		)
+		return &User{ID: userID, Name: userID}
+	}
+	c.mx.Lock()
+	c.users[userID] = user
+	c.mx.Unlock()
+	return user
+}
+
+// receive inbound message of the conversation
+func (c *Bot) receive(ctx context.Context, id, userID, text string, files []*File) error {
+
+	user := c.getUser(ctx, userID)
+	contact := &bot.Account{
+		ID:       0, // LOOKUP
+		Channel:  provider,
+		Contact:  id,
+		Username: user.Name,
+	}
+	fullName := user.Profile.RealName
+	if fullName == "" {
+		fullName = user.Profile.DisplayName
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(fullName)
+
+	channel, err := c.Gateway.GetChannel(ctx, id, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return re // 502 Bad Gateway
+	}
+
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		link := file.URLDownload
+		if link == "" {
+			link = file.URLPrivate
+		}
+		data, err := c.client.download(ctx, link, true, maxFileSize)
+		if err != nil {
+			return err
+		}
+		name := file.Name
+		if name == "" {
+			name = file.Title
+		}
+		media, err := c.Gateway.UploadFile(
+			ctx, 4096, file.Mimetype, name, uuid.NewString(), bytes.NewReader(data),
+		)
+		if err != nil {
+			return err
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: &chat.File{
+				Id:      media.Id,
+				Url:     media.Url,
+				Mime:    file.Mimetype,
+				Name:    name,
+				Size:    media.Size,
+				Malware: media.Malware,
+			},
+		}
+		err = c.Gateway.Read(ctx, &update)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		send    = PostMessage{Mrkdwn: true}
+	)
+
+	send.Channel, send.ThreadTS = parseChatID(channel.ChatID)
+	if send.ThreadTS != "" {
+		// agent is replying within the thread
+		c.follow(channel.ChatID)
+	}
+
+	switch message.Type {
+	case "text":
+		send.Text = strings.TrimSpace(message.GetText())
+		menu := message.Buttons
+		if menu == nil {
+			menu = message.Inline
+		}
+		if len(menu) != 0 {
+			send.Blocks = newBlocks(send.Text, menu)
+		}
+		if send.Text == "" && len(send.Blocks) == 0 {
+			return nil
+		}
+
+	case "file":
+		doc := message.GetFile()
+		data, err := c.client.download(ctx, doc.GetUrl(), false, maxFileSize)
+		if err == nil {
+			err = c.client.UploadFile(
+				ctx, send.Channel, send.ThreadTS, doc.GetName(),
+				strings.TrimSpace(message.GetText()), data,
+			)
+		}
+		if err != nil {
+			c.Gateway.Log.Error("slack/bot.sendFile",
+				slog.Any("error", err),
+				slog.String("url", doc.GetUrl()),
+			)
+			return err
+		}
+		return nil
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("slack/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		send.Text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("slack/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if send.Text = strings.TrimSpace(messageText); send.Text == "" {
+			return nil
+		}
+
+	case "closed":
+		// Thread replies no longer belong to the conversation
+		defer c.unfollow(channel.ChatID)
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("slack/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		if send.Text = strings.TrimSpace(messageText); send.Text == "" {
+			return nil
+		}
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	_, err := c.client.PostMessage(ctx, &send)
+	if err != nil {
+		c.Gateway.Log.Error("slack/chat.postMessage",
+			slog.Any("error", err),
+			slog.String("channel", send.Channel),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+func signRequest(req *http.Request, body, secret string, at time.Time) {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("v0:" + ts + ":" + body))
+	req.Header.Set(timestampHeader, ts)
+	req.Header.Set(signatureHeader, "v0="+hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const (
+		secret = "8f742231b10e8888abcd99yyyzzz85a5"
+		body   = `{"type":"event_callback"}`
+	)
+	now := time.Now()
+	for _, tc := range []struct {
+		name   string
+		secret string
+		at     time.Time
+		ok     bool
+	}{
+		{"valid", secret, now, true},
+		{"secret", "invalid", now, false},
+		{"expired", secret, now.Add(-maxRequestAge - time.Second), false},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		signRequest(req, body, tc.secret, tc.at)
+		err := verifySignature(req.Header, []byte(body), secret, now)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: verifySignature() = %v", tc.name, err)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	for text, want := range map[string]string{
+		"<@UBOT> hello":                    "hello",
+		"ask <@U123> &amp; me":             "ask <@U123> & me",
+		"see <https://example.com>":        "see https://example.com",
+		"see <https://example.com|docs>":   "see docs (https://example.com)",
+		"mail <mailto:a@b.com|a@b.com> me": "mail a@b.com me",
+	} {
+		if got := plainText(text, "UBOT"); got != want {
+			t.Errorf("plainText(%q) = %q; want %q", text, got, want)
+		}
+	}
+}
+
+func TestChatID(t *testing.T) {
+	for _, tc := range [][2]string{{"D024BE91L", ""}, {"C2147483705", "1355517523.000005"}} {
+		channel, threadTS := parseChatID(chatID(tc[0], tc[1]))
+		if channel != tc[0] || threadTS != tc[1] {
+			t.Errorf("parseChatID(chatID(%q, %q)) = %q, %q", tc[0], tc[1], channel, threadTS)
+		}
+	}
+}
+
+func TestNewBlocks(t *testing.T) {
+	blocks := newBlocks("Choose *one*", []*chat.Buttons{
+		{Button: []*chat.Button{
+			{Type: "reply", Text: "Yes", Code: "yes"},
+			{Type: "postback", Text: "No"},
+		}},
+		{Button: []*chat.Button{
+			{Type: "url", Text: "Docs", Url: "https://example.com"},
+			{Type: "location", Text: "Location"},
+		}},
+		{Button: []*chat.Button{
+			{Type: "phone", Text: "Phone"},
+		}},
+	})
+	if len(blocks) != 3 {
+		t.Fatalf("newBlocks() = %d blocks; want 3", len(blocks))
+	}
+	if blocks[0].Type != "section" || blocks[0].Text.Text != "Choose *one*" {
+		t.Errorf("section = %+v", blocks[0])
+	}
+	row := blocks[1].Elements
+	if len(row) != 2 || row[0].Value != "yes" || row[1].Value != "No" || row[0].ActionID == row[1].ActionID {
+		t.Errorf("actions[0] = %+v, %+v", row[0], row[1])
+	}
+	if row = blocks[2].Elements; len(row) != 1 || row[0].URL != "https://example.com" {
+		t.Errorf("actions[1] = %+v", row)
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	var posted []*PostMessage
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			var req PostMessage
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			posted = append(posted, &req)
+			_, _ = w.Write([]byte(`{"ok":true,"ts":"1.000001"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"error":"unknown_method"}`))
+		}
+	}))
+	defer api.Close()
+	defer func(link string) { endpointURL = link }(endpointURL)
+	endpointURL = api.URL
+
+	const secret = "secret"
+	app := &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		client:  &Client{Token: "xoxb-test"},
+		secret:  secret,
+		me:      &AuthTest{UserID: "UBOT"},
+		users:   make(map[string]*User),
+		threads: make(map[string]struct{}),
+		events:  make(map[string]time.Time),
+	}
+
+	// url_verification
+	body := `{"type":"url_verification","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	signRequest(req, body, secret, time.Now())
+	rsp := httptest.NewRecorder()
+	app.WebHook(rsp, req)
+	if rsp.Code != http.StatusOK || rsp.Body.String() != "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P" {
+		t.Errorf("url_verification = (%d) %s", rsp.Code, rsp.Body)
+	}
+
+	// unsigned
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rsp = httptest.NewRecorder()
+	app.WebHook(rsp, req)
+	if rsp.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned request = (%d); want 401", rsp.Code)
+	}
+
+	// thread replies of unknown conversation are ignored
+	body = `{"type":"event_callback","event_id":"Ev1","event":{"type":"message","channel":"C1","channel_type":"channel","user":"U1","text":"hi","ts":"2.0","thread_ts":"1.0"}}`
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	signRequest(req, body, secret, time.Now())
+	rsp = httptest.NewRecorder()
+	app.WebHook(rsp, req)
+	if rsp.Code != http.StatusOK || app.following("C1:1.0") {
+		t.Errorf("thread reply = (%d) %s", rsp.Code, rsp.Body)
+	}
+
+	// agent reply within the thread
+	err := app.SendNotify(context.Background(), &bot.Update{
+		Chat: &bot.Channel{ChatID: "C1:1.0"},
+		Message: &chat.Message{
+			Type: "text",
+			Text: "Hello",
+			Buttons: []*chat.Buttons{{Button: []*chat.Button{
+				{Type: "reply", Text: "OK", Code: "ok"},
+			}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 1 {
+		t.Fatalf("chat.postMessage calls = %d; want 1", len(posted))
+	}
+	if sent := posted[0]; sent.Channel != "C1" || sent.ThreadTS != "1.0" || len(sent.Blocks) != 2 {
+		t.Errorf("chat.postMessage = %+v", sent)
+	}
+	if !app.following("C1:1.0") {
+		t.Errorf("thread C1:1.0 is not followed after reply")
+	}
+}
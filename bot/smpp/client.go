@@ -0,0 +1,326 @@
+package smpp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errNotBound = errors.New("smpp: session is not bound")
+	errClosed   = errors.New("smpp: session closed")
+)
+
+// StatusError is the non-zero command_status of the response
+type StatusError uint32
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("smpp: command_status 0x%08X", uint32(e))
+}
+
+// Client of the SMSC; bound as transceiver.
+// Keeps the session alive with enquire_link
+// and reconnects on failure.
+type Client struct {
+	Addr        string
+	Bind        bindRequest
+	EnquireLink time.Duration
+	Timeout     time.Duration
+	Log         *slog.Logger
+	// OnDeliver handles deliver_sm request;
+	// non-nil error responds with system error
+	// and SMSC is expected to retry delivery later
+	OnDeliver func(sm *shortMessage) error
+
+	mx   sync.Mutex
+	sess *session
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start connecting the SMSC in background
+func (c *Client) Start() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.stop != nil {
+		return // running
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(c.stop, c.done)
+}
+
+// Close unbinds the session and stops reconnecting
+func (c *Client) Close() {
+	c.mx.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mx.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (c *Client) session() *session {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.sess
+}
+
+func (c *Client) setSession(sess *session) {
+	c.mx.Lock()
+	c.sess = sess
+	c.mx.Unlock()
+}
+
+func (c *Client) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	backoff := time.Second
+	for {
+		sess, err := c.bind()
+		if err != nil {
+			c.Log.Error("smpp/bind",
+				slog.String("addr", c.Addr),
+				slog.Any("error", err),
+			)
+			select {
+			case <-stop:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > 30*time.Second {
+				backoff = 30 * time.Second
+			}
+			continue
+		}
+		backoff = time.Second
+		c.setSession(sess)
+		c.Log.Info("smpp/bind",
+			slog.String("addr", c.Addr),
+			slog.String("system_id", c.Bind.SystemID),
+		)
+		select {
+		case <-stop:
+			c.setSession(nil)
+			sess.unbind()
+			return
+		case <-sess.closed:
+			c.setSession(nil)
+			c.Log.Warn("smpp/session",
+				slog.String("addr", c.Addr),
+				slog.Any("error", sess.err),
+			)
+		}
+	}
+}
+
+func (c *Client) bind() (*session, error) {
+	conn, err := net.DialTimeout("tcp", c.Addr, c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	sess := &session{
+		conn:    conn,
+		timeout: c.Timeout,
+		deliver: c.OnDeliver,
+		pending: make(map[uint32]chan *pdu),
+		inbox:   make(chan *pdu, 64),
+		closed:  make(chan struct{}),
+	}
+	go sess.readLoop()
+	go sess.deliverLoop()
+	_, err = sess.request(cmdBindTransceiver, c.Bind.Bytes())
+	if err != nil {
+		sess.close(err)
+		return nil, err
+	}
+	if c.EnquireLink > 0 {
+		go sess.enquireLoop(c.EnquireLink)
+	}
+	return sess, nil
+}
+
+// Submit short message; returns SMSC assigned message_id
+func (c *Client) Submit(sm *shortMessage) (string, error) {
+	sess := c.session()
+	if sess == nil {
+		return "", errNotBound
+	}
+	res, err := sess.request(cmdSubmitSm, sm.Bytes())
+	if err != nil {
+		return "", err
+	}
+	r := &reader{data: res.Body}
+	return r.CString(), nil
+}
+
+// session of the bound connection
+type session struct {
+	conn    net.Conn
+	seq     uint32
+	timeout time.Duration
+	deliver func(sm *shortMessage) error
+
+	wmx sync.Mutex // write
+	mx  sync.Mutex
+	// responses awaiting by sequence number
+	pending map[uint32]chan *pdu
+	// deliver_sm requests to be handled in order
+	inbox chan *pdu
+
+	once   sync.Once
+	err    error
+	closed chan struct{}
+}
+
+func (s *session) close(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.closed)
+		s.conn.Close()
+	})
+}
+
+func (s *session) write(p *pdu) error {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	if s.timeout > 0 {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	}
+	_, err := s.conn.Write(p.Bytes())
+	return err
+}
+
+// request sends command and waits for the response
+func (s *session) request(id uint32, body []byte) (*pdu, error) {
+	seq := atomic.AddUint32(&s.seq, 1)
+	wait := make(chan *pdu, 1)
+	s.mx.Lock()
+	s.pending[seq] = wait
+	s.mx.Unlock()
+	defer func() {
+		s.mx.Lock()
+		delete(s.pending, seq)
+		s.mx.Unlock()
+	}()
+
+	err := s.write(&pdu{ID: id, Seq: seq, Body: body})
+	if err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	select {
+	case res := <-wait:
+		if res.ID == cmdGenericNack {
+			return nil, StatusError(res.Status)
+		}
+		if res.Status != statusOK {
+			return res, StatusError(res.Status)
+		}
+		return res, nil
+	case <-s.closed:
+		return nil, errClosed
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("smpp: command 0x%08X timeout", id)
+	}
+}
+
+func (s *session) readLoop() {
+	recv := bufio.NewReader(s.conn)
+	for {
+		p, err := readPDU(recv)
+		if err != nil {
+			s.close(err)
+			return
+		}
+		if p.IsResponse() {
+			s.mx.Lock()
+			wait := s.pending[p.Seq]
+			s.mx.Unlock()
+			if wait != nil {
+				wait <- p
+			}
+			continue
+		}
+		switch p.ID {
+		case cmdEnquireLink:
+			err = s.write(&pdu{ID: cmdEnquireLinkResp, Seq: p.Seq})
+		case cmdDeliverSm:
+			select {
+			case s.inbox <- p:
+			case <-s.closed:
+				return
+			}
+		case cmdUnbind:
+			_ = s.write(&pdu{ID: cmdUnbindResp, Seq: p.Seq})
+			s.close(errClosed)
+			return
+		default:
+			err = s.write(&pdu{ID: cmdGenericNack, Status: statusInvCmdID, Seq: p.Seq})
+		}
+		if err != nil {
+			s.close(err)
+			return
+		}
+	}
+}
+
+// deliverLoop handles deliver_sm requests
+// apart from the readLoop so the handler
+// is free to submit messages within the session
+func (s *session) deliverLoop() {
+	for {
+		var p *pdu
+		select {
+		case <-s.closed:
+			return
+		case p = <-s.inbox:
+		}
+		status := statusOK
+		sm, err := parseShortMessage(p.Body)
+		if err == nil && s.deliver != nil {
+			err = s.deliver(sm)
+		}
+		if err != nil {
+			status = statusSysErr
+		}
+		err = s.write(&pdu{ID: cmdDeliverSmResp, Status: status, Seq: p.Seq, Body: []byte{0}})
+		if err != nil {
+			s.close(err)
+			return
+		}
+	}
+}
+
+func (s *session) enquireLoop(every time.Duration) {
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-tick.C:
+		}
+		if _, err := s.request(cmdEnquireLink, nil); err != nil {
+			s.close(err)
+			return
+		}
+	}
+}
+
+func (s *session) unbind() {
+	_, _ = s.request(cmdUnbind, nil)
+	s.close(errClosed)
+}
@@ -0,0 +1,201 @@
+package smpp
+
+import (
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// data_coding scheme
+const (
+	codingDefault byte = 0x00 // SMSC default alphabet; GSM 03.38
+	codingIA5     byte = 0x01 // IA5 (CCITT T.50)/ASCII
+	codingLatin1  byte = 0x03 // ISO-8859-1
+	codingUCS2    byte = 0x08 // ISO/IEC-10646; UTF-16BE
+)
+
+// Message length limits; single / concatenated segment
+const (
+	gsm7Single  = 160 // septets
+	gsm7Segment = 153 // septets; 7 octets UDH
+	ucs2Single  = 70  // UTF-16 code units
+	ucs2Segment = 67  // UTF-16 code units; 6 octets UDH
+)
+
+// GSM 03.38 default alphabet
+var gsm7Basic = []rune("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// GSM 03.38 extension table; escaped with 0x1B
+var gsm7Ext = map[rune]byte{
+	'\f': 0x0A, '^': 0x14, '{': 0x28, '}': 0x29, '\\': 0x2F,
+	'[': 0x3C, '~': 0x3D, ']': 0x3E, '|': 0x40, '€': 0x65,
+}
+
+const gsm7Escape = 0x1B
+
+var (
+	gsm7Encode = make(map[rune]byte, len(gsm7Basic))
+	gsm7Decode = make(map[byte]rune, len(gsm7Ext))
+)
+
+func init() {
+	for i, r := range gsm7Basic {
+		if r != gsm7Escape {
+			gsm7Encode[r] = byte(i)
+		}
+	}
+	for r, b := range gsm7Ext {
+		gsm7Decode[b] = r
+	}
+}
+
+// encodeGSM7 encodes text into unpacked GSM 7-bit septets;
+// ok is false if text has characters out of the alphabet
+func encodeGSM7(text string) (septets []byte, ok bool) {
+	septets = make([]byte, 0, len(text))
+	for _, r := range text {
+		if b, is := gsm7Encode[r]; is {
+			septets = append(septets, b)
+			continue
+		}
+		if b, is := gsm7Ext[r]; is {
+			septets = append(septets, gsm7Escape, b)
+			continue
+		}
+		return nil, false
+	}
+	return septets, true
+}
+
+// decodeGSM7 decodes unpacked GSM 7-bit septets
+func decodeGSM7(septets []byte) string {
+	text := make([]rune, 0, len(septets))
+	for i := 0; i < len(septets); i++ {
+		b := septets[i] & 0x7F
+		if b == gsm7Escape && i+1 < len(septets) {
+			i++
+			if r, ok := gsm7Decode[septets[i]&0x7F]; ok {
+				text = append(text, r)
+			} else {
+				text = append(text, ' ')
+			}
+			continue
+		}
+		text = append(text, gsm7Basic[b])
+	}
+	return string(text)
+}
+
+func encodeUCS2(units []uint16) []byte {
+	data := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(data[2*i:], u)
+	}
+	return data
+}
+
+func decodeUCS2(data []byte) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeText of the short message due to data_coding
+func decodeText(coding byte, data []byte) string {
+	switch coding & 0x0F {
+	case codingUCS2:
+		return decodeUCS2(data)
+	case codingLatin1:
+		text := make([]rune, len(data))
+		for i, b := range data {
+			text[i] = rune(b)
+		}
+		return string(text)
+	case codingIA5:
+		return string(data)
+	default:
+		return decodeGSM7(data)
+	}
+}
+
+// encodeText selects the data_coding for the text:
+// GSM 7-bit if possible, UCS-2 otherwise;
+// and splits encoded text into segments
+// to be sent as concatenated short messages.
+func encodeText(text string) (coding byte, segments [][]byte) {
+
+	if septets, ok := encodeGSM7(text); ok {
+		if len(septets) <= gsm7Single {
+			return codingDefault, [][]byte{septets}
+		}
+		for len(septets) > gsm7Segment {
+			n := gsm7Segment
+			// never split the escape sequence
+			if septets[n-1] == gsm7Escape {
+				n--
+			}
+			segments = append(segments, septets[:n])
+			septets = septets[n:]
+		}
+		return codingDefault, append(segments, septets)
+	}
+
+	units := utf16.Encode([]rune(text))
+	if len(units) <= ucs2Single {
+		return codingUCS2, [][]byte{encodeUCS2(units)}
+	}
+	for len(units) > ucs2Segment {
+		n := ucs2Segment
+		// never split the surrogate pair
+		if utf16.IsSurrogate(rune(units[n-1])) && units[n-1] < 0xDC00 {
+			n--
+		}
+		segments = append(segments, encodeUCS2(units[:n]))
+		units = units[n:]
+	}
+	return codingUCS2, append(segments, encodeUCS2(units))
+}
+
+// concatUDH is the User Data Header
+// of the concatenated short message;
+// 8-bit reference number
+func concatUDH(ref byte, total, seq int) []byte {
+	return []byte{0x05, 0x00, 0x03, ref, byte(total), byte(seq)}
+}
+
+// concatPart of the received short message
+type concatPart struct {
+	Ref   uint16
+	Total int
+	Seq   int
+}
+
+// parseUDH strips the User Data Header from the short message
+// and returns concatenation info, if any
+func parseUDH(data []byte) (part *concatPart, text []byte) {
+	if len(data) == 0 {
+		return nil, data
+	}
+	size := int(data[0]) + 1
+	if size > len(data) {
+		return nil, data
+	}
+	head, text := data[1:size], data[size:]
+	for len(head) >= 2 {
+		iei, n := head[0], int(head[1])
+		if 2+n > len(head) {
+			break
+		}
+		val := head[2 : 2+n]
+		switch {
+		case iei == 0x00 && n == 3: // 8-bit reference
+			part = &concatPart{Ref: uint16(val[0]), Total: int(val[1]), Seq: int(val[2])}
+		case iei == 0x08 && n == 4: // 16-bit reference
+			part = &concatPart{Ref: binary.BigEndian.Uint16(val), Total: int(val[2]), Seq: int(val[3])}
+		}
+		head = head[2+n:]
+	}
+	return part, text
+}
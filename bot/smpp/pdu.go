@@ -0,0 +1,278 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// SMPP v3.4 command IDs
+const (
+	cmdGenericNack         uint32 = 0x80000000
+	cmdBindTransceiver     uint32 = 0x00000009
+	cmdBindTransceiverResp uint32 = 0x80000009
+	cmdSubmitSm            uint32 = 0x00000004
+	cmdSubmitSmResp        uint32 = 0x80000004
+	cmdDeliverSm           uint32 = 0x00000005
+	cmdDeliverSmResp       uint32 = 0x80000005
+	cmdUnbind              uint32 = 0x00000006
+	cmdUnbindResp          uint32 = 0x80000006
+	cmdEnquireLink         uint32 = 0x00000015
+	cmdEnquireLinkResp     uint32 = 0x80000015
+
+	// response bit of the command ID
+	cmdResp uint32 = 0x80000000
+)
+
+// SMPP v3.4 command status
+const (
+	statusOK         uint32 = 0x00000000
+	statusInvCmdID   uint32 = 0x00000003
+	statusSysErr     uint32 = 0x00000008
+	interfaceVersion byte   = 0x34
+)
+
+// Optional parameters (TLV) tags
+const (
+	tagReceiptedMessageID uint16 = 0x001E
+	tagMessageState       uint16 = 0x0427
+	tagMessagePayload     uint16 = 0x0424
+)
+
+// esm_class flags
+const (
+	esmDeliveryReceipt byte = 0x04
+	esmReceiptMask     byte = 0x3C
+	esmUDHI            byte = 0x40
+)
+
+// max PDU size to accept
+const maxPDUSize = 64 << 10
+
+// pdu is the SMPP protocol data unit
+type pdu struct {
+	ID     uint32
+	Status uint32
+	Seq    uint32
+	Body   []byte
+}
+
+func (p *pdu) IsResponse() bool {
+	return p.ID&cmdResp != 0
+}
+
+// Bytes encodes the PDU; header and body
+func (p *pdu) Bytes() []byte {
+	data := make([]byte, 16+len(p.Body))
+	binary.BigEndian.PutUint32(data[0:], uint32(len(data)))
+	binary.BigEndian.PutUint32(data[4:], p.ID)
+	binary.BigEndian.PutUint32(data[8:], p.Status)
+	binary.BigEndian.PutUint32(data[12:], p.Seq)
+	copy(data[16:], p.Body)
+	return data
+}
+
+// readPDU decodes next PDU from the stream
+func readPDU(r io.Reader) (*pdu, error) {
+	var head [16]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(head[0:])
+	if size < 16 || size > maxPDUSize {
+		return nil, fmt.Errorf("smpp: invalid command_length %d", size)
+	}
+	p := &pdu{
+		ID:     binary.BigEndian.Uint32(head[4:]),
+		Status: binary.BigEndian.Uint32(head[8:]),
+		Seq:    binary.BigEndian.Uint32(head[12:]),
+		Body:   make([]byte, size-16),
+	}
+	if _, err := io.ReadFull(r, p.Body); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// writer of the PDU body fields
+type writer struct {
+	bytes.Buffer
+}
+
+// CString writes C-Octet string; NULL terminated
+func (w *writer) CString(s string) {
+	w.WriteString(s)
+	w.WriteByte(0)
+}
+
+// reader of the PDU body fields
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) Byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 1 {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *reader) CString() string {
+	if r.err != nil {
+		return ""
+	}
+	n := bytes.IndexByte(r.data, 0)
+	if n < 0 {
+		r.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n+1:]
+	return s
+}
+
+func (r *reader) Bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[:n:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// TLV reads optional parameters; the rest of the body
+func (r *reader) TLV() map[uint16][]byte {
+	if r.err != nil || len(r.data) == 0 {
+		return nil
+	}
+	opts := make(map[uint16][]byte)
+	for len(r.data) >= 4 {
+		tag := binary.BigEndian.Uint16(r.data[0:])
+		size := int(binary.BigEndian.Uint16(r.data[2:]))
+		if len(r.data) < 4+size {
+			r.err = io.ErrUnexpectedEOF
+			return opts
+		}
+		opts[tag] = r.data[4 : 4+size : 4+size]
+		r.data = r.data[4+size:]
+	}
+	return opts
+}
+
+// bindRequest is the bind_transceiver body
+type bindRequest struct {
+	SystemID   string
+	Password   string
+	SystemType string
+	AddrTON    byte
+	AddrNPI    byte
+	AddrRange  string
+}
+
+func (b *bindRequest) Bytes() []byte {
+	var w writer
+	w.CString(b.SystemID)
+	w.CString(b.Password)
+	w.CString(b.SystemType)
+	w.WriteByte(interfaceVersion)
+	w.WriteByte(b.AddrTON)
+	w.WriteByte(b.AddrNPI)
+	w.CString(b.AddrRange)
+	return w.Bytes()
+}
+
+// shortMessage is the submit_sm / deliver_sm body
+type shortMessage struct {
+	ServiceType        string
+	SourceTON          byte
+	SourceNPI          byte
+	Source             string
+	DestTON            byte
+	DestNPI            byte
+	Dest               string
+	ESMClass           byte
+	ProtocolID         byte
+	Priority           byte
+	Schedule           string
+	Validity           string
+	RegisteredDelivery byte
+	ReplaceIfPresent   byte
+	DataCoding         byte
+	DefaultMsgID       byte
+	Message            []byte
+	TLV                map[uint16][]byte
+}
+
+func (m *shortMessage) Bytes() []byte {
+	var w writer
+	w.CString(m.ServiceType)
+	w.WriteByte(m.SourceTON)
+	w.WriteByte(m.SourceNPI)
+	w.CString(m.Source)
+	w.WriteByte(m.DestTON)
+	w.WriteByte(m.DestNPI)
+	w.CString(m.Dest)
+	w.WriteByte(m.ESMClass)
+	w.WriteByte(m.ProtocolID)
+	w.WriteByte(m.Priority)
+	w.CString(m.Schedule)
+	w.CString(m.Validity)
+	w.WriteByte(m.RegisteredDelivery)
+	w.WriteByte(m.ReplaceIfPresent)
+	w.WriteByte(m.DataCoding)
+	w.WriteByte(m.DefaultMsgID)
+	w.WriteByte(byte(len(m.Message)))
+	w.Write(m.Message)
+	for tag, val := range m.TLV {
+		var head [4]byte
+		binary.BigEndian.PutUint16(head[0:], tag)
+		binary.BigEndian.PutUint16(head[2:], uint16(len(val)))
+		w.Write(head[:])
+		w.Write(val)
+	}
+	return w.Bytes()
+}
+
+func parseShortMessage(data []byte) (*shortMessage, error) {
+	r := &reader{data: data}
+	m := &shortMessage{
+		ServiceType:        r.CString(),
+		SourceTON:          r.Byte(),
+		SourceNPI:          r.Byte(),
+		Source:             r.CString(),
+		DestTON:            r.Byte(),
+		DestNPI:            r.Byte(),
+		Dest:               r.CString(),
+		ESMClass:           r.Byte(),
+		ProtocolID:         r.Byte(),
+		Priority:           r.Byte(),
+		Schedule:           r.CString(),
+		Validity:           r.CString(),
+		RegisteredDelivery: r.Byte(),
+		ReplaceIfPresent:   r.Byte(),
+		DataCoding:         r.Byte(),
+		DefaultMsgID:       r.Byte(),
+	}
+	m.Message = r.Bytes(int(r.Byte()))
+	m.TLV = r.TLV()
+	if r.err != nil {
+		return nil, fmt.Errorf("smpp: short message: %w", r.err)
+	}
+	// Long message content
+	if len(m.Message) == 0 {
+		m.Message = m.TLV[tagMessagePayload]
+	}
+	return m, nil
+}
@@ -0,0 +1,589 @@
+package smpp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/errors"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+const provider = "smpp"
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Type of Number (TON)
+const (
+	tonInternational byte = 0x01
+	tonAlphanumeric  byte = 0x05
+	npiUnknown       byte = 0x00
+	npiISDN          byte = 0x01 // E.164
+)
+
+// Message delivery state; delivery receipt stat:
+const (
+	stateDelivered   = "DELIVRD"
+	stateExpired     = "EXPIRED"
+	stateDeleted     = "DELETED"
+	stateUndelivered = "UNDELIV"
+	stateAccepted    = "ACCEPTD"
+	stateUnknown     = "UNKNOWN"
+	stateRejected    = "REJECTD"
+	stateEnroute     = "ENROUTE"
+)
+
+// message_state TLV values to delivery receipt stat:
+var messageStates = map[byte]string{
+	1: stateEnroute,
+	2: stateDelivered,
+	3: stateExpired,
+	4: stateDeleted,
+	5: stateUndelivered,
+	6: stateAccepted,
+	7: stateUnknown,
+	8: stateRejected,
+}
+
+const (
+	// keep track of the sent messages awaiting delivery receipt
+	receiptTimeout = 48 * time.Hour
+	// wait for the rest of the concatenated message parts
+	concatTimeout = 10 * time.Minute
+)
+
+// sentMessage awaiting delivery receipt
+type sentMessage struct {
+	ChatID    string // chat.channel.id
+	MessageID int64  // chat.message.id
+	Date      time.Time
+}
+
+// concatMessage parts received
+type concatMessage struct {
+	Parts []string
+	Count int
+	Date  time.Time
+}
+
+// Bot is the SMS channel provider
+// bound to the SMSC as SMPP v3.4 transceiver.
+// Chats are keyed by the sender MSISDN.
+type Bot struct {
+	*bot.Gateway
+	Client *Client
+	// Source address (sender ID) of the outbound messages
+	Source    string
+	SourceTON byte
+	SourceNPI byte
+	DestTON   byte
+	DestNPI   byte
+	// Request delivery receipts
+	Receipts bool
+
+	mx     sync.Mutex
+	ref    byte                      // concatenated message reference
+	sent   map[string]*sentMessage   // [SMSC.message_id]
+	concat map[string]*concatMessage // [source:ref]
+}
+
+// New initialize new agent.profile service SMPP provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+
+	addr := profile["host"]
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.smpp.host.invalid",
+			"smpp: SMSC host:port required",
+		)
+	}
+	systemID := profile["system_id"]
+	if systemID == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.smpp.system_id.required",
+			"smpp: system_id required",
+		)
+	}
+	source := profile["source_addr"]
+	if source == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.smpp.source_addr.required",
+			"smpp: source_addr required",
+		)
+	}
+
+	enquireLink := 30 * time.Second
+	if v := profile["enquire_link"]; v != "" {
+		var err error
+		enquireLink, err = time.ParseDuration(v)
+		if err != nil || enquireLink < time.Second {
+			return nil, errors.BadRequest(
+				"chat.bot.smpp.enquire_link.invalid",
+				"smpp: enquire_link %q is invalid",
+				v,
+			)
+		}
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.smpp.updates.invalid",
+			"smpp: %v", err,
+		)
+	}
+
+	app := &Bot{
+		Gateway:   agent,
+		Source:    source,
+		SourceTON: tonInternational,
+		SourceNPI: npiISDN,
+		DestTON:   tonInternational,
+		DestNPI:   npiISDN,
+		Receipts:  true,
+		sent:      make(map[string]*sentMessage),
+		concat:    make(map[string]*concatMessage),
+	}
+	if !isNumber(source) {
+		app.SourceTON, app.SourceNPI = tonAlphanumeric, npiUnknown
+	}
+	for param, dst := range map[string]*byte{
+		"source_ton": &app.SourceTON,
+		"source_npi": &app.SourceNPI,
+		"dest_ton":   &app.DestTON,
+		"dest_npi":   &app.DestNPI,
+	} {
+		if v := profile[param]; v != "" {
+			n, err := strconv.ParseUint(v, 10, 8)
+			if err != nil {
+				return nil, errors.BadRequest(
+					"chat.bot.smpp."+param+".invalid",
+					"smpp: %s %q is invalid",
+					param, v,
+				)
+			}
+			*dst = byte(n)
+		}
+	}
+	if v := profile["receipts"]; v != "" {
+		app.Receipts, _ = strconv.ParseBool(v)
+	}
+
+	app.Client = &Client{
+		Addr: addr,
+		Bind: bindRequest{
+			SystemID:   systemID,
+			Password:   profile["password"],
+			SystemType: profile["system_type"],
+		},
+		EnquireLink: enquireLink,
+		Timeout:     30 * time.Second,
+		Log:         agent.Log,
+		OnDeliver:   app.onDeliver,
+	}
+
+	// Latest (current) state
+	if last, _ := state.(*Bot); last != nil {
+		last.Client.Close()
+	}
+
+	if agent.Bot.GetEnabled() {
+		app.Client.Start()
+	}
+
+	return app, nil
+}
+
+func isNumber(s string) bool {
+	s = strings.TrimPrefix(s, "+")
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register binds the SMSC session
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.Client.Start()
+	return nil
+}
+
+// Deregister unbinds the SMSC session
+func (c *Bot) Deregister(ctx context.Context) error {
+	c.Client.Close()
+	return nil
+}
+
+func (c *Bot) Close() error {
+	c.Client.Close()
+	return nil
+}
+
+// WebHook is not supported; messages are delivered over SMPP session
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+	http.Error(reply, "smpp: webhook not supported", http.StatusNotFound)
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		text    string
+	)
+
+	switch message.Type {
+	case "text":
+		text = message.GetText()
+
+	case "file":
+		// SMS has no media; send the link instead
+		doc := message.GetFile()
+		text = strings.TrimSpace(message.GetText() + "\n" + doc.GetUrl())
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("smpp/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("smpp/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("smpp/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil // IGNORE: empty message text !
+	}
+
+	ids, err := c.submit(channel.ChatID, text)
+	if err != nil {
+		c.Gateway.Log.Error("smpp/submit_sm",
+			slog.Any("error", err),
+			slog.String("to", channel.ChatID),
+		)
+		return errors.BadGateway(
+			"chat.bot.smpp.submit.error",
+			"smpp: %v", err,
+		)
+	}
+
+	if c.Receipts && message.GetId() != 0 {
+		c.track(ids, &sentMessage{
+			ChatID:    channel.ChannelID,
+			MessageID: message.GetId(),
+			Date:      time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// submit text to the destination MSISDN;
+// long text is sent as concatenated message
+func (c *Bot) submit(dest, text string) (ids []string, err error) {
+
+	coding, segments := encodeText(text)
+
+	var ref byte
+	if len(segments) > 1 {
+		c.mx.Lock()
+		c.ref++
+		ref = c.ref
+		c.mx.Unlock()
+	}
+
+	var receipt byte
+	if c.Receipts {
+		receipt = 0x01 // SMSC Delivery Receipt requested
+	}
+
+	for i, segment := range segments {
+		sm := &shortMessage{
+			SourceTON:          c.SourceTON,
+			SourceNPI:          c.SourceNPI,
+			Source:             c.Source,
+			DestTON:            c.DestTON,
+			DestNPI:            c.DestNPI,
+			Dest:               strings.TrimPrefix(dest, "+"),
+			RegisteredDelivery: receipt,
+			DataCoding:         coding,
+			Message:            segment,
+		}
+		if len(segments) > 1 {
+			sm.ESMClass |= esmUDHI
+			sm.Message = append(concatUDH(ref, len(segments), i+1), segment...)
+		}
+		id, err := c.Client.Submit(sm)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// track sent message(s) awaiting delivery receipt
+func (c *Bot) track(ids []string, msg *sentMessage) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	for id, e := range c.sent {
+		if time.Since(e.Date) > receiptTimeout {
+			delete(c.sent, id)
+		}
+	}
+	for _, id := range ids {
+		if id != "" {
+			c.sent[id] = msg
+		}
+	}
+}
+
+// onDeliver handles inbound deliver_sm: message or delivery receipt
+func (c *Bot) onDeliver(sm *shortMessage) error {
+	if sm.ESMClass&esmReceiptMask == esmDeliveryReceipt {
+		c.onReceipt(sm)
+		return nil
+	}
+	return c.onMessage(sm)
+}
+
+// onMessage received from the MSISDN
+func (c *Bot) onMessage(sm *shortMessage) error {
+
+	data := sm.Message
+	var part *concatPart
+	if sm.ESMClass&esmUDHI != 0 {
+		part, data = parseUDH(data)
+	}
+	text := decodeText(sm.DataCoding, data)
+	sender := strings.TrimPrefix(sm.Source, "+")
+
+	if part != nil && part.Total > 1 {
+		var ok bool
+		if text, ok = c.reassemble(sender, part, text); !ok {
+			return nil // wait for the rest parts
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil // IGNORE
+	}
+
+	ctx := context.Background()
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: sender,
+	}
+
+	channel, err := c.Gateway.GetChannel(ctx, sender, contact)
+	if err != nil {
+		return err
+	}
+
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+		Message: &chat.Message{
+			Type: "text",
+			Text: text,
+		},
+	}
+
+	err = c.Gateway.Read(ctx, &update)
+	if err != nil {
+		c.Gateway.Log.Error("smpp/onMessage",
+			slog.Any("error", err),
+			slog.String("from", sender),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// reassemble concatenated message;
+// ok is true when all the parts received
+func (c *Bot) reassemble(sender string, part *concatPart, text string) (string, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for key, e := range c.concat {
+		if time.Since(e.Date) > concatTimeout {
+			delete(c.concat, key)
+		}
+	}
+
+	key := sender + ":" + strconv.Itoa(int(part.Ref))
+	e := c.concat[key]
+	if e == nil || len(e.Parts) != part.Total {
+		e = &concatMessage{
+			Parts: make([]string, part.Total),
+			Date:  time.Now(),
+		}
+		c.concat[key] = e
+	}
+	if part.Seq < 1 || part.Seq > part.Total {
+		return "", false
+	}
+	if e.Parts[part.Seq-1] == "" {
+		e.Count++
+	}
+	e.Parts[part.Seq-1] = text
+	if e.Count < part.Total {
+		return "", false
+	}
+	delete(c.concat, key)
+	return strings.Join(e.Parts, ""), true
+}
+
+var receiptField = regexp.MustCompile(`(?i)\b(id|stat|err):(\S*)`)
+
+// parseReceipt returns receipted message_id and it's final state
+func parseReceipt(sm *shortMessage) (id, state, code string) {
+	for _, m := range receiptField.FindAllStringSubmatch(string(sm.Message), -1) {
+		switch strings.ToLower(m[1]) {
+		case "id":
+			id = m[2]
+		case "stat":
+			state = strings.ToUpper(m[2])
+		case "err":
+			code = m[2]
+		}
+	}
+	if v := sm.TLV[tagReceiptedMessageID]; len(v) > 0 {
+		id = strings.TrimRight(string(v), "\x00")
+	}
+	if v := sm.TLV[tagMessageState]; len(v) == 1 {
+		if s, ok := messageStates[v[0]]; ok {
+			state = s
+		}
+	}
+	return id, state, code
+}
+
+// onReceipt maps delivery receipt to the sent message status.
+// Failed deliveries are reported to the chat as service message.
+func (c *Bot) onReceipt(sm *shortMessage) {
+
+	id, state, code := parseReceipt(sm)
+
+	c.mx.Lock()
+	sent := c.sent[id]
+	switch state {
+	case stateEnroute, stateAccepted:
+		// not final
+	default:
+		delete(c.sent, id)
+	}
+	c.mx.Unlock()
+
+	log := c.Gateway.Log.With(
+		slog.String("id", id),
+		slog.String("stat", state),
+		slog.String("err", code),
+		slog.String("to", sm.Source),
+	)
+	if sent != nil {
+		log = log.With(
+			slog.String("chat.id", sent.ChatID),
+			slog.Int64("msg.id", sent.MessageID),
+		)
+	}
+
+	switch state {
+	case stateDelivered, stateEnroute, stateAccepted:
+		log.Debug("smpp/receipt")
+	default:
+		log.Warn("smpp/receipt")
+		if sent == nil || sent.ChatID == "" {
+			return
+		}
+		err := c.Gateway.SendServiceMessage(
+			context.Background(),
+			fmt.Sprintf("SMS delivery failed: %s (err:%s)", state, code),
+			sent.ChatID,
+		)
+		if err != nil {
+			log.Error("smpp/receipt",
+				slog.Any("error", err),
+			)
+		}
+	}
+}
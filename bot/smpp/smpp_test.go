@@ -0,0 +1,155 @@
+package smpp
+
+import (
+	"bufio"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeText(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		text     string
+		coding   byte
+		segments int
+	}{
+		{"gsm7", "Hello {world}!", codingDefault, 1},
+		{"gsm7.single", strings.Repeat("a", gsm7Single), codingDefault, 1},
+		{"gsm7.concat", strings.Repeat("a", gsm7Single+1), codingDefault, 2},
+		{"ucs2", "Привіт", codingUCS2, 1},
+		{"ucs2.concat", strings.Repeat("ї", ucs2Single+1), codingUCS2, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			coding, segments := encodeText(tc.text)
+			if coding != tc.coding || len(segments) != tc.segments {
+				t.Fatalf("encodeText() = 0x%02X x%d; want 0x%02X x%d",
+					coding, len(segments), tc.coding, tc.segments)
+			}
+			var text string
+			for _, segment := range segments {
+				text += decodeText(coding, segment)
+			}
+			if text != tc.text {
+				t.Errorf("decodeText() = %q; want %q", text, tc.text)
+			}
+		})
+	}
+	// never split surrogate pair
+	_, segments := encodeText(strings.Repeat("a", ucs2Segment-1) + strings.Repeat("😀", 10))
+	if text := decodeUCS2(segments[0]); strings.ContainsRune(text, '�') {
+		t.Errorf("segment split surrogate pair: %q", text)
+	}
+}
+
+func TestParseReceipt(t *testing.T) {
+	id, state, code := parseReceipt(&shortMessage{
+		Message: []byte("id:A1B2 sub:001 dlvrd:000 submit date:2401011200 done date:2401011201 stat:UNDELIV err:034 text:Hi"),
+	})
+	if id != "A1B2" || state != stateUndelivered || code != "034" {
+		t.Errorf("parseReceipt() = %s %s %s", id, state, code)
+	}
+}
+
+// smsc simulator accepts single session
+func smsc(t *testing.T, ln net.Listener, submits chan<- *shortMessage) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	recv := bufio.NewReader(conn)
+	for {
+		p, err := readPDU(recv)
+		if err != nil {
+			return
+		}
+		res := &pdu{ID: p.ID | cmdResp, Seq: p.Seq}
+		switch p.ID {
+		case cmdBindTransceiver:
+			res.Body = []byte("SMSC\x00")
+		case cmdSubmitSm:
+			sm, err := parseShortMessage(p.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			res.Body = []byte("M" + string(rune('0'+len(submits))) + "\x00")
+			submits <- sm
+			if len(submits) == cap(submits) {
+				// answer with the mobile originated message
+				mo := &shortMessage{Source: "380501234567", DataCoding: codingDefault, Message: []byte("Hi")}
+				_, _ = conn.Write(res.Bytes())
+				_, _ = conn.Write((&pdu{ID: cmdDeliverSm, Seq: 1, Body: mo.Bytes()}).Bytes())
+				continue
+			}
+		case cmdDeliverSmResp:
+			continue
+		}
+		_, _ = conn.Write(res.Bytes())
+	}
+}
+
+func TestClient(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	submits := make(chan *shortMessage, 2)
+	go smsc(t, ln, submits)
+
+	delivered := make(chan *shortMessage, 1)
+	c := &Client{
+		Addr:        ln.Addr().String(),
+		Bind:        bindRequest{SystemID: "test", Password: "secret"},
+		EnquireLink: time.Second,
+		Timeout:     5 * time.Second,
+		Log:         slog.Default(),
+		OnDeliver: func(sm *shortMessage) error {
+			delivered <- sm
+			return nil
+		},
+	}
+	c.Start()
+	defer c.Close()
+
+	for i := 0; c.session() == nil; i++ {
+		if i > 100 {
+			t.Fatal("bind timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, segments := encodeText(strings.Repeat("x", gsm7Single+10))
+	for i, segment := range segments {
+		id, err := c.Submit(&shortMessage{
+			Dest:     "380501234567",
+			ESMClass: esmUDHI,
+			Message:  append(concatUDH(7, len(segments), i+1), segment...),
+		})
+		if err != nil || id == "" {
+			t.Fatalf("Submit() = %q, %v", id, err)
+		}
+	}
+
+	for i := 1; i <= len(segments); i++ {
+		sm := <-submits
+		part, _ := parseUDH(sm.Message)
+		if part == nil || part.Ref != 7 || part.Total != 2 || part.Seq != i {
+			t.Errorf("segment #%d udh = %+v", i, part)
+		}
+	}
+
+	select {
+	case sm := <-delivered:
+		if sm.Source != "380501234567" || decodeText(sm.DataCoding, sm.Message) != "Hi" {
+			t.Errorf("deliver_sm = %+v", sm)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliver_sm timeout")
+	}
+}
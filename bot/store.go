@@ -18,6 +18,13 @@ type Store interface {
 	AnalyticsActiveBotsCount(ctx context.Context, pdc int64) (n int, err error)
 	// FIXME:
 	UpdateContact(ctx context.Context, client *app.User) (ok bool, err error)
+	// SetStatus saves the last health check status of the bot
+	SetStatus(ctx context.Context, status *BotStatus) error
+	// GetStatus returns the last health check status of the bot; nil if not checked yet
+	GetStatus(ctx context.Context, pid int64) (*BotStatus, error)
+	// ClaimHealthCheck of the bot at date (unix ms) unless checked by any node since (unix ms).
+	// Reports whether the check is claimed by the calling node.
+	ClaimHealthCheck(ctx context.Context, pid, date, since int64) (bool, error)
 }
 
 // LocateBot fetches single result entry or returns an error
@@ -38,7 +45,8 @@ func (srv *Service) LocateBot(req *app.SearchOptions) (*Bot, error) {
 				"name", "uri",
 				"enabled", "flow",
 				"provider", "metadata",
-				"updates",
+				"updates", "survey",
+				"business_hours",
 				"created_at", "created_by",
 				"updated_at", "updated_by",
 			},
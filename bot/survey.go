@@ -0,0 +1,420 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/webitel/chat_manager/api/proto/chat"
+)
+
+// Post-chat survey rating scale(s)
+const (
+	SurveyScaleCSAT   = "csat"   // [1..5]
+	SurveyScaleNPS    = "nps"    // [0..10]
+	SurveyScaleThumbs = "thumbs" // [0..1]
+)
+
+const (
+	// SurveyVariable is the survey request message variable
+	// that holds the conversation ID being rated
+	SurveyVariable = "survey"
+	// SurveyTimeout is the default timeout
+	// to accept an answer after the chat is closed
+	SurveyTimeout = 24 * time.Hour
+	// survey answer button(s) postback code prefix
+	surveyPostback = "survey:"
+)
+
+// SurveyScale returns the [min..max] score range for the given scale.
+// Empty scale implies csat.
+func SurveyScale(scale string) (min, max int32, ok bool) {
+	switch scale {
+	case "", SurveyScaleCSAT:
+		return 1, 5, true
+	case SurveyScaleNPS:
+		return 0, 10, true
+	case SurveyScaleThumbs:
+		return 0, 1, true
+	}
+	return 0, 0, false
+}
+
+// ValidateSurvey normalizes and checks
+// the post-chat survey settings are well formed
+func ValidateSurvey(e *ChatSurvey) error {
+
+	if e == nil {
+		return nil // disabled
+	}
+
+	e.Scale = strings.ToLower(strings.TrimSpace(e.Scale))
+	if e.Scale == "" {
+		e.Scale = SurveyScaleCSAT
+	}
+	if _, _, ok := SurveyScale(e.Scale); !ok {
+		return errors.BadRequest(
+			"chat.bot.survey.scale.invalid",
+			"chatbot: survey scale %q not supported",
+			e.Scale,
+		)
+	}
+
+	e.Question = strings.TrimSpace(e.Question)
+	if e.Question == "" {
+		return errors.BadRequest(
+			"chat.bot.survey.question.required",
+			"chatbot: survey question text required",
+		)
+	}
+
+	e.Comment = strings.TrimSpace(e.Comment)
+	for name, text := range map[string]string{
+		"question": e.Question,
+		"comment":  e.Comment,
+	} {
+		_, err := surveyText(name, text, &templatePeer)
+		if err != nil {
+			return errors.BadRequest(
+				"chat.bot.survey."+name+".invalid",
+				"chatbot: survey %s; %v",
+				name, err,
+			)
+		}
+	}
+
+	if e.Timeout < 0 {
+		return errors.BadRequest(
+			"chat.bot.survey.timeout.invalid",
+			"chatbot: survey timeout must be positive",
+		)
+	}
+
+	return nil
+}
+
+// surveyText renders the survey message text template
+func surveyText(name, text string, peer *chat.Account) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl := NewTemplate(name)
+	_, err := tmpl.Root().Parse(text)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.MessageText(name, peer)
+}
+
+// SurveyMessage builds the survey question message
+// to be sent to the peer once the conversation is closed
+func SurveyMessage(survey *ChatSurvey, conversationID string, peer *chat.Account) (*chat.Message, error) {
+
+	scale := survey.GetScale()
+	if scale == "" {
+		scale = SurveyScaleCSAT
+	}
+	min, max, ok := SurveyScale(scale)
+	if !ok {
+		return nil, errors.BadRequest(
+			"chat.bot.survey.scale.invalid",
+			"chatbot: survey scale %q not supported",
+			scale,
+		)
+	}
+
+	text, err := surveyText("question", survey.GetQuestion(), peer)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: nps scale does not fit a single row of buttons
+	var rows []*chat.Buttons
+	row := &chat.Buttons{}
+	for score := min; score <= max; score++ {
+		if scale == SurveyScaleNPS && score == 6 {
+			rows = append(rows, row)
+			row = &chat.Buttons{}
+		}
+		caption := strconv.Itoa(int(score))
+		if scale == SurveyScaleThumbs {
+			caption = "👎"
+			if score == 1 {
+				caption = "👍"
+			}
+		}
+		row.Button = append(row.Button, &chat.Button{
+			Type: "postback",
+			Text: caption,
+			Code: surveyPostback + strconv.Itoa(int(score)),
+		})
+	}
+	rows = append(rows, row)
+
+	return &chat.Message{
+		Type:    "text",
+		Text:    text,
+		Buttons: rows,
+		Variables: map[string]string{
+			SurveyVariable: conversationID,
+			"scale":        scale,
+		},
+	}, nil
+}
+
+// SurveyScore parses the answer score from the button's
+// postback code or from the typed message text.
+func SurveyScore(scale string, message *chat.Message) (score int32, ok bool) {
+
+	text := message.GetPostback().GetCode()
+	if text == "" {
+		// NOTE: Some providers pass the button's code as a message text
+		text = message.GetText()
+	}
+	text = strings.TrimPrefix(strings.TrimSpace(text), surveyPostback)
+
+	n, err := strconv.ParseInt(text, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	min, max, ok := SurveyScale(scale)
+	if !ok || int32(n) < min || max < int32(n) {
+		return 0, false
+	}
+	return int32(n), true
+}
+
+// surveyPending is the client channel variable that holds the pending
+// post-chat survey state, so the answer is accepted after the service restart
+// or when received by the other node
+const surveyPending = "survey.pending"
+
+// chatSurvey is the pending post-chat survey state
+type chatSurvey struct {
+	ConversationID string `json:"conversation_id"`
+	Scale          string `json:"scale,omitempty"`
+	Rated          bool   `json:"rated,omitempty"`  // awaiting comment
+	Rating         int32  `json:"rating,omitempty"` // rated score
+	Expires        int64  `json:"expires"`          // unix ms
+	// chat.channel.id holding the state
+	channelID string
+}
+
+// expired reports whether the survey answer is no longer accepted
+func (e *chatSurvey) expired(now time.Time) bool {
+	return now.UnixMilli() > e.Expires
+}
+
+// saveSurvey state to the client channel variables; nil - clear
+func (c *Gateway) saveSurvey(ctx context.Context, channelID string, pending *chatSurvey) error {
+	if channelID == "" {
+		return nil
+	}
+	var value string
+	if pending != nil {
+		data, err := json.Marshal(pending)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	_, err := c.Internal.Client.SetVariables(ctx, &chat.SetVariablesRequest{
+		ChannelId: channelID,
+		Variables: map[string]string{
+			surveyPending: value, // empty - remove
+		},
+	})
+	return err
+}
+
+// loadSurvey state of the peer's latest channel, if any
+func (c *Gateway) loadSurvey(ctx context.Context, channel *Channel) (*chatSurvey, error) {
+	last, err := c.Internal.Client.GetChannelByPeer(ctx, &chat.GetChannelByPeerRequest{
+		PeerId: channel.Account.Contact,
+		FromId: c.Bot.GetId(),
+	})
+	if err != nil {
+		if errors.FromError(err).Code == http.StatusNotFound {
+			err = nil // NO channel(s) yet
+		}
+		return nil, err
+	}
+	var props map[string]string
+	if last.GetProps() != "" {
+		err = json.Unmarshal([]byte(last.GetProps()), &props)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data := props[surveyPending]
+	if data == "" {
+		return nil, nil // NO survey pending
+	}
+	var pending chatSurvey
+	err = json.Unmarshal([]byte(data), &pending)
+	if err != nil {
+		return nil, err
+	}
+	pending.channelID = last.GetId()
+	return &pending, nil
+}
+
+// awaitSurvey registers the survey sent to the channel,
+// so the next message from the peer will be treated as an answer
+func (c *Gateway) awaitSurvey(ctx context.Context, channel *Channel, message *chat.Message) {
+
+	conversationID := message.GetVariables()[SurveyVariable]
+	if conversationID == "" {
+		return
+	}
+
+	timeout := SurveyTimeout
+	if sec := c.Bot.GetSurvey().GetTimeout(); sec > 0 {
+		timeout = time.Duration(sec) * time.Second
+	}
+	now := time.Now()
+	pending := &chatSurvey{
+		ConversationID: conversationID,
+		Scale:          message.GetVariables()["scale"],
+		Expires:        now.Add(timeout).UnixMilli(),
+		channelID:      channel.ChannelID,
+	}
+
+	c.Lock() // +RW
+	for id, e := range c.surveys {
+		if e.expired(now) {
+			delete(c.surveys, id)
+		}
+	}
+	c.surveys[channel.ChatID] = pending
+	c.Unlock() // -RW
+
+	if err := c.saveSurvey(ctx, pending.channelID, pending); err != nil {
+		channel.Log.Error("[ CHAT::SURVEY ] save",
+			slog.String("conversation_id", conversationID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// readSurvey handles the pending survey answer from the channel, if any.
+// Returns (false, nil) if given message must be received as a regular one.
+func (c *Gateway) readSurvey(ctx context.Context, channel *Channel, message *chat.Message) (ok bool, err error) {
+
+	c.RLock() // +R
+	pending := c.surveys[channel.ChatID]
+	c.RUnlock() // -R
+
+	if pending == nil && channel.IsNew() && c.Bot.GetSurvey() != nil {
+		// Sent before restart or by the other node ?
+		pending, err = c.loadSurvey(ctx, channel)
+		if err != nil {
+			channel.Log.Error("[ CHAT::SURVEY ] load",
+				slog.Any("error", err),
+			)
+			return false, nil
+		}
+	}
+
+	if pending == nil {
+		return false, nil
+	}
+
+	var (
+		done = func() {
+			c.Lock() // +RW
+			delete(c.surveys, channel.ChatID)
+			c.Unlock() // -RW
+			if re := c.saveSurvey(ctx, pending.channelID, nil); re != nil {
+				channel.Log.Error("[ CHAT::SURVEY ] clear",
+					slog.String("conversation_id", pending.ConversationID),
+					slog.Any("error", re),
+				)
+			}
+		}
+		rate = chat.RateConversationRequest{
+			ConversationId: pending.ConversationID,
+			ProfileId:      c.Bot.GetId(),
+			Scale:          pending.Scale,
+		}
+	)
+
+	if pending.expired(time.Now()) || message.GetType() != "text" {
+		done()
+		return false, nil
+	}
+
+	if !pending.Rated {
+		score, is := SurveyScore(pending.Scale, message)
+		if !is {
+			// Not an answer; start a new chat
+			done()
+			return false, nil
+		}
+		rate.Rating = score
+	} else {
+		rate.Rating = pending.Rating
+		rate.Comment = strings.TrimSpace(message.GetText())
+	}
+
+	_, err = c.Internal.Client.RateConversation(ctx, &rate)
+	if err != nil {
+		done()
+		channel.Log.Error("[ CHAT::SURVEY ]",
+			slog.String("conversation_id", rate.ConversationId),
+			slog.Any("error", err),
+		)
+		return true, nil
+	}
+
+	prompt := c.Bot.GetSurvey().GetComment()
+	if pending.Rated || prompt == "" {
+		done()
+		return true, nil
+	}
+
+	// Request an optional comment
+	peer := channel.Account
+	prompt, err = surveyText("comment", prompt, &chat.Account{
+		Id:        peer.ID,
+		Channel:   peer.Channel,
+		Contact:   peer.Contact,
+		FirstName: peer.FirstName,
+		LastName:  peer.LastName,
+		Username:  peer.Username,
+	})
+	if err != nil || prompt == "" {
+		done()
+		return true, err
+	}
+
+	c.Lock() // +RW
+	pending.Rated = true
+	pending.Rating = rate.Rating
+	c.surveys[channel.ChatID] = pending
+	c.Unlock() // -RW
+
+	if re := c.saveSurvey(ctx, pending.channelID, pending); re != nil {
+		channel.Log.Error("[ CHAT::SURVEY ] save",
+			slog.String("conversation_id", pending.ConversationID),
+			slog.Any("error", re),
+		)
+	}
+
+	err = channel.Gateway.External.SendNotify(ctx, &Update{
+		Chat:  channel,
+		User:  &peer,
+		Title: channel.Title,
+		Message: &chat.Message{
+			Type: "text",
+			Text: prompt,
+		},
+	})
+
+	return true, err
+}
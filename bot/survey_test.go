@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/webitel/chat_manager/api/proto/chat"
+)
+
+func TestSurveyScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		scale   string
+		message *chat.Message
+		want    int32
+		wantOk  bool
+	}{
+		{
+			name:    "postback",
+			scale:   SurveyScaleCSAT,
+			message: &chat.Message{Type: "text", Postback: &chat.Postback{Code: "survey:4"}},
+			want:    4,
+			wantOk:  true,
+		},
+		{
+			name:    "callback as text",
+			scale:   SurveyScaleNPS,
+			message: &chat.Message{Type: "text", Text: "survey:10"},
+			want:    10,
+			wantOk:  true,
+		},
+		{
+			name:    "typed",
+			scale:   SurveyScaleThumbs,
+			message: &chat.Message{Type: "text", Text: " 0 "},
+			want:    0,
+			wantOk:  true,
+		},
+		{
+			name:    "out of range",
+			scale:   SurveyScaleCSAT,
+			message: &chat.Message{Type: "text", Text: "0"},
+			wantOk:  false,
+		},
+		{
+			name:    "not an answer",
+			scale:   SurveyScaleCSAT,
+			message: &chat.Message{Type: "text", Text: "Hello"},
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SurveyScore(tt.scale, tt.message)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("SurveyScore() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestValidateSurvey(t *testing.T) {
+	tests := []struct {
+		name    string
+		survey  *ChatSurvey
+		wantErr bool
+	}{
+		{"disabled", nil, false},
+		{"default", &ChatSurvey{Question: "Rate us, $(.FirstName)!"}, false},
+		{"scale", &ChatSurvey{Scale: "stars", Question: "Rate us"}, true},
+		{"question", &ChatSurvey{Scale: "nps"}, true},
+		{"template", &ChatSurvey{Question: "Rate us, $(.Unknown)"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSurvey(tt.survey); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSurvey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type surveyChatClient struct {
+	chat.ChatService
+	props string
+	rated *chat.RateConversationRequest
+}
+
+func (c *surveyChatClient) GetChannelByPeer(_ context.Context, req *chat.GetChannelByPeerRequest, _ ...client.CallOption) (*chat.Channel, error) {
+	return &chat.Channel{Id: "channel-1", Props: c.props}, nil
+}
+
+func (c *surveyChatClient) SetVariables(_ context.Context, req *chat.SetVariablesRequest, _ ...client.CallOption) (*chat.ChatVariablesResponse, error) {
+	props := map[string]string{}
+	_ = json.Unmarshal([]byte(c.props), &props)
+	for key, val := range req.Variables {
+		if val == "" {
+			delete(props, key)
+		} else {
+			props[key] = val
+		}
+	}
+	data, _ := json.Marshal(props)
+	c.props = string(data)
+	return &chat.ChatVariablesResponse{ChannelId: req.ChannelId, Variables: props}, nil
+}
+
+func (c *surveyChatClient) RateConversation(_ context.Context, req *chat.RateConversationRequest, _ ...client.CallOption) (*chat.RateConversationResponse, error) {
+	c.rated = req
+	return &chat.RateConversationResponse{}, nil
+}
+
+func TestSurveyPending(t *testing.T) {
+
+	remote := &surveyChatClient{}
+	srv := NewService(nil, slog.Default(), remote, nil, nil)
+	newGateway := func() *Gateway {
+		return &Gateway{
+			Bot:      &Bot{Id: 1, Survey: &ChatSurvey{Question: "Rate us"}},
+			Log:      slog.Default(),
+			Internal: srv,
+			RWMutex:  new(sync.RWMutex),
+			surveys:  make(map[string]*chatSurvey),
+		}
+	}
+
+	// Survey sent; conversation closed
+	gate := newGateway()
+	sent, err := SurveyMessage(gate.Bot.Survey, "conversation-1", &chat.Account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gate.awaitSurvey(context.Background(), &Channel{
+		ChatID: "peer-1", ChannelID: "channel-1", Log: slog.Default(),
+	}, sent)
+
+	// Answer received after restart -or- by the other node
+	gate = newGateway()
+	peer := &Channel{
+		ChatID: "peer-1", Account: Account{Contact: "peer-1"}, Log: slog.Default(), Gateway: gate,
+	}
+	ok, err := gate.readSurvey(context.Background(), peer, &chat.Message{
+		Type: "text", Postback: &chat.Postback{Code: "survey:5"},
+	})
+	if !ok || err != nil {
+		t.Fatalf("answer: %t, %v; survey pending expected", ok, err)
+	}
+	if remote.rated.GetConversationId() != "conversation-1" || remote.rated.GetRating() != 5 {
+		t.Errorf("rated: %v", remote.rated)
+	}
+	if strings.Contains(remote.props, surveyPending) {
+		t.Errorf("props: %s; survey pending cleared expected", remote.props)
+	}
+
+	// Regular message; no survey pending
+	if ok, _ = gate.readSurvey(context.Background(), peer, &chat.Message{Type: "text", Text: "5"}); ok {
+		t.Error("message received as a survey answer")
+	}
+}
@@ -179,6 +179,25 @@ func (c *TelegramBot) Register(ctx context.Context, callbackURL string) error {
 	return nil
 }
 
+// HealthCheck of the bot token (getMe) and webhook (getWebhookInfo)
+func (c *TelegramBot) HealthCheck(ctx context.Context, uri string) (*bot.Health, error) {
+
+	me, err := c.BotAPI.GetMe()
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := c.BotAPI.GetWebhookInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bot.Health{
+		Account:     "@" + me.UserName,
+		WebhookLost: hook.URL != uri,
+	}, nil
+}
+
 // Deregister Telegram Bot Webhook endpoint URI
 func (c *TelegramBot) Deregister(ctx context.Context) error {
 	// POST /deleteWebhook
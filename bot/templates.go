@@ -39,6 +39,7 @@ var (
 		UpdateChatTitle:  &templatePeer,
 		UpdateChatMember: &templatePeer,
 		UpdateLeftMember: &templatePeer,
+		OutOfHoursType:   &templatePeer,
 	}
 )
 
@@ -92,6 +93,7 @@ func (m *Template) FromProto(on *bot.ChatUpdates) error {
 		{UpdateChatMember, on.GetJoin()},
 		{UpdateLeftMember, on.GetLeft()},
 		{FilePolicyFailType, on.GetFilePolicyFail()},
+		{OutOfHoursType, on.GetOutOfHours()},
 	} {
 		e.text = strings.TrimSpace(e.text)
 		// addTemplate
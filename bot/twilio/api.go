@@ -0,0 +1,138 @@
+package twilio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Default REST API base URL
+const defaultBaseURL = "https://api.twilio.com"
+
+// Error of the REST API
+// https://www.twilio.com/docs/usage/twilios-response#response-formats-exceptions
+type Error struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("twilio: (%d) %s [%d]", e.Status, e.Message, e.Code)
+}
+
+// Client of the Messaging REST API
+type Client struct {
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	HTTP       *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// Message resource
+// https://www.twilio.com/docs/messaging/api/message-resource
+type Message struct {
+	SID          string `json:"sid"`
+	Status       string `json:"status"`
+	ErrorCode    *int   `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// SendMessage creates the outbound message;
+// form: To, From | MessagingServiceSid, Body, MediaUrl, StatusCallback
+func (c *Client) SendMessage(ctx context.Context, form url.Values) (*Message, error) {
+
+	link := strings.TrimRight(c.BaseURL, "/") +
+		"/2010-04-01/Accounts/" + url.PathEscape(c.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, link, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.AccountSID, c.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode/100 != 2 {
+		re := &Error{Status: rsp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(rsp.Body, 4096)).Decode(re)
+		if re.Message == "" {
+			re.Message = http.StatusText(rsp.StatusCode)
+		}
+		return nil, re
+	}
+	var res Message
+	if err = json.NewDecoder(rsp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Download the inbound media; HTTP Basic auth is sent
+// to the media URL only, NOT to the storage it redirects to
+func (c *Client) Download(ctx context.Context, link string, limit int64) (data []byte, mtype string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.SetBasicAuth(c.AccountSID, c.AuthToken)
+	rsp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", &Error{Status: rsp.StatusCode, Message: http.StatusText(rsp.StatusCode)}
+	}
+	data, err = io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("twilio: media size exceeds %d bytes", limit)
+	}
+	mtype, _, _ = strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	return data, mtype, nil
+}
+
+// calculateSignature of the webhook request: HMAC-SHA1 of the full URL
+// followed by the POST parameters sorted by name (and value), name and value concatenated
+// https://www.twilio.com/docs/usage/security#validating-requests
+func calculateSignature(authToken, link string, form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := hmac.New(sha1.New, []byte(authToken))
+	_, _ = io.WriteString(h, link)
+	for _, key := range keys {
+		values := append([]string(nil), form[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			_, _ = io.WriteString(h, key)
+			_, _ = io.WriteString(h, value)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,494 @@
+package twilio
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "twilio"
+	// Request signature header
+	signatureHeader = "X-Twilio-Signature"
+	// WhatsApp sender / recipient address prefix
+	whatsappPrefix = "whatsapp:"
+	// Max size of the inbound request body
+	maxRequestSize = 1 << 20
+	// Max size of the media to download
+	maxFileSize = 32 << 20
+	// Max length of the message body
+	maxBodyLength = 1600
+	// Max media per message
+	maxMedia = 10
+)
+
+// Message status of the status callback
+// https://www.twilio.com/docs/messaging/api/message-resource#message-status-values
+const (
+	statusUndelivered = "undelivered"
+	statusFailed      = "failed"
+)
+
+// Status callback query parameter(s)
+const (
+	queryStatus = "status"
+	queryChat   = "chat"
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the Twilio-compatible SMS/MMS or WhatsApp sender provider.
+// Chats are keyed by the sender's phone number
+type Bot struct {
+	Gateway *bot.Gateway
+	client  *Client
+	// Sender: phone number, "whatsapp:" prefixed for WhatsApp;
+	// or Messaging Service SID
+	from       string
+	serviceSID string
+	whatsapp   bool
+	// Webhook URL as configured at the vendor; signed
+	callbackURL string
+}
+
+// New initialize new agent.profile service Twilio provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+	accountSID := profile["account_sid"]
+	authToken := profile["auth_token"]
+	if accountSID == "" || authToken == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.twilio.credentials.required",
+			"twilio: account_sid and auth_token required",
+		)
+	}
+	from := strings.TrimSpace(profile["from"])
+	serviceSID := strings.TrimSpace(profile["messaging_service_sid"])
+	if from == "" && serviceSID == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.twilio.from.required",
+			"twilio: sender number or messaging_service_sid required",
+		)
+	}
+	baseURL := defaultBaseURL
+	if link := profile["api_url"]; link != "" {
+		endpoint, err := url.ParseRequestURI(link)
+		if err != nil || endpoint.Host == "" {
+			return nil, errors.BadRequest(
+				"chat.bot.twilio.api_url.invalid",
+				"twilio: invalid API base URL %q", link,
+			)
+		}
+		baseURL = link
+	}
+
+	// Parse and validate message templates
+	var err error
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.twilio.updates.invalid",
+			"twilio: %v", err,
+		)
+	}
+
+	client := &Client{
+		BaseURL:    baseURL,
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		HTTP:       &http.Client{Timeout: time.Minute},
+	}
+	if on, _ := strconv.ParseBool(profile["trace"]); on {
+		client.HTTP.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  true,
+		}
+	}
+
+	return &Bot{
+		Gateway:     agent,
+		client:      client,
+		from:        from,
+		serviceSID:  serviceSID,
+		whatsapp:    strings.HasPrefix(from, whatsappPrefix),
+		callbackURL: agent.CallbackURL(),
+	}, nil
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register does nothing; the number's incoming message
+// webhook URL must be set in the vendor's console
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	c.Gateway.Log.Info("twilio/bot.register",
+		slog.String("from", c.from),
+		slog.String("webhook_url", c.callbackURL),
+	)
+	return nil
+}
+
+// Deregister does nothing; see Register
+func (c *Bot) Deregister(ctx context.Context) error {
+	return nil
+}
+
+func (c *Bot) Close() error {
+	return nil
+}
+
+// channelName of the messages for the service notices
+func (c *Bot) channelName() string {
+	if c.whatsapp {
+		return "WhatsApp"
+	}
+	return "SMS"
+}
+
+// WebHook implements provider.Receiver interface for Twilio;
+// incoming messages and the status callbacks
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+
+	if notice.Method != http.MethodPost {
+		http.Error(reply, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	notice.Body = http.MaxBytesReader(reply, notice.Body, maxRequestSize)
+	if err := notice.ParseForm(); err != nil {
+		http.Error(reply, err.Error(), http.StatusBadRequest)
+		return
+	}
+	form := notice.PostForm
+
+	link := c.callbackURL
+	if notice.URL.RawQuery != "" {
+		link += "?" + notice.URL.RawQuery
+	}
+	sign := calculateSignature(c.client.AuthToken, link, form)
+	if !hmac.Equal([]byte(sign), []byte(notice.Header.Get(signatureHeader))) ||
+		form.Get("AccountSid") != c.client.AccountSID {
+		c.Gateway.Log.Warn("twilio/bot.webhook",
+			slog.String("error", "invalid request signature"),
+		)
+		http.Error(reply, "(403) Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if notice.URL.Query().Has(queryStatus) {
+		c.onStatus(notice.Context(), notice.URL.Query().Get(queryChat), form)
+		reply.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := c.onMessage(notice.Context(), form); err != nil {
+		c.Gateway.Log.Error("twilio/bot.onMessage",
+			slog.Any("error", err),
+			slog.String("sid", form.Get("MessageSid")),
+		)
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = http.StatusBadGateway
+		}
+		http.Error(reply, re.Detail, int(re.Code))
+		return
+	}
+
+	// Empty TwiML; NO auto-reply
+	reply.Header().Set("Content-Type", "text/xml")
+	reply.WriteHeader(http.StatusOK)
+	_, _ = reply.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Response></Response>`))
+}
+
+// messageText of the incoming message;
+// WhatsApp location and quick reply button
+func messageText(form url.Values) string {
+	if lat, lon := form.Get("Latitude"), form.Get("Longitude"); lat != "" && lon != "" {
+		latitude, _ := strconv.ParseFloat(lat, 64)
+		longitude, _ := strconv.ParseFloat(lon, 64)
+		text := fmt.Sprintf("https://www.google.com/maps/place/%f,%f", latitude, longitude)
+		if place := strings.TrimSpace(form.Get("Label") + "\n" + form.Get("Address")); place != "" {
+			text = place + "\n" + text
+		}
+		return text
+	}
+	if payload := form.Get("ButtonPayload"); payload != "" {
+		return payload
+	}
+	return strings.TrimSpace(form.Get("Body"))
+}
+
+// on: incoming message
+func (c *Bot) onMessage(ctx context.Context, form url.Values) error {
+
+	sender := strings.TrimPrefix(form.Get("From"), whatsappPrefix)
+	if sender == "" {
+		return nil // IGNORE
+	}
+	contact := &bot.Account{
+		ID:      0, // LOOKUP
+		Channel: provider,
+		Contact: sender,
+	}
+	if name := form.Get("ProfileName"); name != "" {
+		contact.FirstName, contact.LastName = util.ParseFullName(name)
+	}
+
+	channel, err := c.Gateway.GetChannel(ctx, sender, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return re // 502 Bad Gateway
+	}
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	if text := messageText(form); text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: text,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	media, _ := strconv.Atoi(form.Get("NumMedia"))
+	for i := 0; i < media && i < maxMedia; i++ {
+		n := strconv.Itoa(i)
+		file, err := c.getFile(ctx, form.Get("MediaUrl"+n), form.Get("MediaContentType"+n))
+		if err != nil {
+			return err
+		}
+		update.Message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getFile of the media into the storage
+func (c *Bot) getFile(ctx context.Context, link, mtype string) (*chat.File, error) {
+
+	data, ctype, err := c.client.Download(ctx, link, maxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	if mtype == "" {
+		mtype = ctype
+	}
+	// .../Media/{MediaSid}
+	name := link[strings.LastIndexByte(link, '/')+1:]
+	if ext, _ := mime.ExtensionsByType(mtype); len(ext) != 0 {
+		name += ext[0]
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// on: status callback of the message sent to the chat;
+// delivery failure is noticed to the conversation
+func (c *Bot) onStatus(ctx context.Context, chatID string, form url.Values) {
+
+	state := form.Get("MessageStatus")
+	code := form.Get("ErrorCode")
+	log := c.Gateway.Log.With(
+		slog.String("sid", form.Get("MessageSid")),
+		slog.String("status", state),
+		slog.String("err", code),
+		slog.String("to", form.Get("To")),
+		slog.String("chat.id", chatID),
+	)
+
+	switch state {
+	case statusUndelivered, statusFailed:
+		log.Warn("twilio/status")
+		if chatID == "" {
+			return
+		}
+		err := c.Gateway.SendServiceMessage(ctx,
+			fmt.Sprintf("%s delivery failed: %s (err:%s)", c.channelName(), state, code),
+			chatID,
+		)
+		if err != nil {
+			log.Error("twilio/status",
+				slog.Any("error", err),
+			)
+		}
+	default:
+		// queued | sending | sent | delivered | read
+		log.Debug("twilio/status")
+	}
+}
+
+// truncate s to n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		text    string
+		media   string
+	)
+
+	switch message.Type {
+	case "text":
+		text = message.GetText()
+
+	case "file":
+		text = message.GetText()
+		media = message.GetFile().GetUrl()
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("twilio/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel != nil && channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("twilio/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("twilio/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" && media == "" {
+		return nil // IGNORE: empty message text !
+	}
+
+	form := url.Values{}
+	to := channel.ChatID
+	if c.whatsapp {
+		to = whatsappPrefix + to
+	}
+	form.Set("To", to)
+	if c.serviceSID != "" {
+		form.Set("MessagingServiceSid", c.serviceSID)
+	} else {
+		form.Set("From", c.from)
+	}
+	if text != "" {
+		form.Set("Body", truncate(text, maxBodyLength))
+	}
+	if media != "" {
+		form.Set("MediaUrl", media)
+	}
+	if channel.ChannelID != "" {
+		form.Set("StatusCallback", c.callbackURL+"?"+url.Values{
+			queryStatus: {"1"},
+			queryChat:   {channel.ChannelID},
+		}.Encode())
+	}
+
+	sent, err := c.client.SendMessage(ctx, form)
+	if err != nil {
+		c.Gateway.Log.Error("twilio/message.send",
+			slog.Any("error", err),
+			slog.String("to", to),
+		)
+		return err
+	}
+	c.Gateway.Log.Debug("twilio/message.send",
+		slog.String("sid", sent.SID),
+		slog.String("status", sent.Status),
+		slog.String("to", to),
+	)
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package twilio
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+const (
+	testAccountSID = "AC00000000000000000000000000000001"
+	testAuthToken  = "secret"
+	testWebhookURL = "https://chat.example.com/chat/twilio"
+)
+
+// messagingAPI is the local stand-in of the vendor's REST API
+type messagingAPI struct {
+	*httptest.Server
+	mx   sync.Mutex
+	sent []url.Values
+}
+
+func newMessagingAPI(t *testing.T) *messagingAPI {
+	api := &messagingAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != testAccountSID || pass != testAuthToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":20003,"message":"Authenticate","status":401}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/2010-04-01/Accounts/" + testAccountSID + "/Messages.json":
+			_ = r.ParseForm()
+			if strings.TrimPrefix(r.PostForm.Get("To"), whatsappPrefix) == "+15005550001" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":21211,"message":"The 'To' number +15005550001 is not a valid phone number.","status":400}`))
+				return
+			}
+			api.mx.Lock()
+			api.sent = append(api.sent, r.PostForm)
+			api.mx.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"sid":"SM1","status":"queued","error_code":null}`))
+		case "/Media/ME1":
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write([]byte("jpeg"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func newTestBot(api *messagingAPI, from string) *Bot {
+	return &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		client: &Client{
+			BaseURL:    api.URL,
+			AccountSID: testAccountSID,
+			AuthToken:  testAuthToken,
+		},
+		from:        from,
+		whatsapp:    strings.HasPrefix(from, whatsappPrefix),
+		callbackURL: testWebhookURL,
+	}
+}
+
+func TestCalculateSignature(t *testing.T) {
+	form := url.Values{"To": {"+18005551212"}, "From": {"+12349013030"}, "Body": {"Hi"}}
+	sign := calculateSignature(testAuthToken, testWebhookURL, form)
+	if sign != calculateSignature(testAuthToken, testWebhookURL, url.Values{"Body": {"Hi"}, "From": {"+12349013030"}, "To": {"+18005551212"}}) {
+		t.Error("signature depends on the parameters order")
+	}
+	for _, tc := range []struct {
+		name string
+		link string
+		form url.Values
+	}{
+		{"url", testWebhookURL + "?status=1", form},
+		{"body", testWebhookURL, url.Values{"To": {"+18005551212"}, "From": {"+12349013030"}, "Body": {"Hi!"}}},
+	} {
+		if calculateSignature(testAuthToken, tc.link, tc.form) == sign {
+			t.Errorf("signature of the tampered %s matches", tc.name)
+		}
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	for _, tc := range []struct {
+		form url.Values
+		text string
+	}{
+		{url.Values{"Body": {" Hello "}}, "Hello"},
+		{url.Values{"Body": {"Yes"}, "ButtonText": {"Yes"}, "ButtonPayload": {"confirm"}}, "confirm"},
+		{url.Values{"Latitude": {"50.45"}, "Longitude": {"30.52"}, "Label": {"Office"}}, "Office\nhttps://www.google.com/maps/place/50.450000,30.520000"},
+	} {
+		if text := messageText(tc.form); text != tc.text {
+			t.Errorf("messageText(%v) = %q; want %q", tc.form, text, tc.text)
+		}
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	api := newMessagingAPI(t)
+	app := newTestBot(api, "whatsapp:+14155238886")
+	channel := &bot.Channel{ChatID: "+380501234567", ChannelID: "chat-1"}
+
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: "Hello"},
+		{Type: "file", Text: "Invoice", File: &chat.File{Name: "invoice.pdf", Url: "https://example.com/f/1"}},
+	} {
+		err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: message})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(api.sent) != 2 {
+		t.Fatalf("sent %d messages; want 2", len(api.sent))
+	}
+	sent := api.sent[0]
+	if sent.Get("To") != "whatsapp:+380501234567" || sent.Get("From") != "whatsapp:+14155238886" || sent.Get("Body") != "Hello" {
+		t.Errorf("message[0] = %v", sent)
+	}
+	if callback := sent.Get("StatusCallback"); callback != testWebhookURL+"?chat=chat-1&status=1" {
+		t.Errorf("status callback = %s", callback)
+	}
+	if sent = api.sent[1]; sent.Get("MediaUrl") != "https://example.com/f/1" || sent.Get("Body") != "Invoice" {
+		t.Errorf("message[1] = %v", sent)
+	}
+
+	channel.ChatID = "+15005550001"
+	err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: &chat.Message{Type: "text", Text: "Hello"}})
+	if re, ok := err.(*Error); !ok || re.Code != 21211 {
+		t.Errorf("SendNotify(invalid) = %v; want 21211", err)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	api := newMessagingAPI(t)
+	app := newTestBot(api, "+14155238886")
+	data, mtype, err := app.client.Download(context.Background(), api.URL+"/Media/ME1", 1024)
+	if err != nil || string(data) != "jpeg" || mtype != "image/jpeg" {
+		t.Errorf("Download() = (%q, %q, %v)", data, mtype, err)
+	}
+	if _, _, err = app.client.Download(context.Background(), api.URL+"/Media/ME1", 2); err == nil {
+		t.Error("Download(limit) = <nil>; want size exceeded")
+	}
+}
+
+func TestWebHook(t *testing.T) {
+
+	api := newMessagingAPI(t)
+	app := newTestBot(api, "+14155238886")
+
+	form := url.Values{
+		"AccountSid":    {testAccountSID},
+		"MessageSid":    {"SM1"},
+		"MessageStatus": {"delivered"},
+		"To":            {"+380501234567"},
+	}
+	post := func(query, sign string) int {
+		req := httptest.NewRequest(http.MethodPost, "/chat/twilio"+query, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(signatureHeader, sign)
+		rsp := httptest.NewRecorder()
+		app.WebHook(rsp, req)
+		return rsp.Code
+	}
+
+	query := "?chat=chat-1&status=1"
+	if code := post(query, "invalid"); code != http.StatusForbidden {
+		t.Errorf("unsigned status callback = (%d); want 403", code)
+	}
+	// signed for another URL
+	if code := post(query, calculateSignature(testAuthToken, testWebhookURL, form)); code != http.StatusForbidden {
+		t.Errorf("status callback signed w/o query = (%d); want 403", code)
+	}
+	if code := post(query, calculateSignature(testAuthToken, testWebhookURL+query, form)); code != http.StatusNoContent {
+		t.Errorf("status callback = (%d); want 204", code)
+	}
+}
@@ -59,14 +59,31 @@ type Account struct {
 func (c *Bot) getMe(refresh bool) (*Account, error) {
 
 	if !refresh {
-		if me := c.Account; me != nil && me.Ok() {
+		if me := c.account(); me != nil && me.Ok() {
 			return me, nil
 		}
-	} else {
-		// clear
-		c.Account = nil
 	}
 
+	me, err := c.fetchMe()
+	if err == nil {
+		c.accountMx.Lock()
+		c.Account = me
+		c.accountMx.Unlock()
+	}
+
+	return me, err
+}
+
+// account info cached
+func (c *Bot) account() *Account {
+	c.accountMx.RLock()
+	defer c.accountMx.RUnlock()
+	return c.Account
+}
+
+// fetchMe remote account info; cached one is NOT affected
+func (c *Bot) fetchMe() (*Account, error) {
+
 	var (
 		res Account
 		req getAccount
@@ -77,9 +94,5 @@ func (c *Bot) getMe(refresh bool) (*Account, error) {
 		err = res.Err()
 	}
 
-	if err == nil {
-		c.Account = &res
-	}
-
 	return &res, err
 }
@@ -10,6 +10,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/micro/micro/v3/service/errors"
@@ -28,6 +29,8 @@ type Bot struct {
 	Account *Account
 	Buttons ButtonOptions
 	Gateway *bot.Gateway
+	// guards the Account refresh
+	accountMx sync.RWMutex
 }
 
 // constants
@@ -186,7 +189,7 @@ func (c *Bot) Register(ctx context.Context, linkURL string) error {
 	}
 
 	// Refresh Account Info
-	that := c.Account
+	that := c.account()
 	this, _ := c.getMe(true)
 	if that != nil && this != nil {
 		if c.Sender.Name == that.Name {
@@ -197,6 +200,20 @@ func (c *Bot) Register(ctx context.Context, linkURL string) error {
 	return nil
 }
 
+// HealthCheck of the bot token and webhook (get_account_info)
+func (c *Bot) HealthCheck(ctx context.Context, uri string) (*bot.Health, error) {
+
+	me, err := c.fetchMe()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bot.Health{
+		Account:     me.Name,
+		WebhookLost: me.Webhook != uri,
+	}, nil
+}
+
 // Deregister viber Bot Webhook endpoint URI
 func (c *Bot) Deregister(ctx context.Context) error {
 
@@ -216,10 +233,14 @@ func (c *Bot) Deregister(ctx context.Context) error {
 		return err
 	}
 
+	c.accountMx.Lock()
 	if me := c.Account; me != nil {
-		me.Webhook = ""
-		me.Events = nil
+		that := *(me) // shallowcopy
+		that.Webhook = ""
+		that.Events = nil
+		c.Account = &that
 	}
+	c.accountMx.Unlock()
 
 	return nil
 }
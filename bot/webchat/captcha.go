@@ -0,0 +1,135 @@
+package webchat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	HCaptchaURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// NewCaptchaHandler of the provider, selected by the settings:
+//
+//	{"provider": "recaptcha|hcaptcha|turnstile|pow", "enabled": true, "secret": "..", "verify_url": ".."}
+//
+// Provider is "recaptcha" by default.
+func NewCaptchaHandler(settings string) (CaptchaHandler, error) {
+	var setting struct {
+		Provider string `json:"provider,omitempty"`
+	}
+	err := json.Unmarshal([]byte(settings), &setting)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(setting.Provider) {
+	case "", "recaptcha":
+		return NewRecaptchaHandler(settings)
+	case "hcaptcha":
+		return newSiteVerifyHandler("hcaptcha", HCaptchaURL, settings)
+	case "turnstile":
+		return newSiteVerifyHandler("turnstile", TurnstileURL, settings)
+	case "pow":
+		return NewPowCaptchaHandler(settings)
+	}
+	return nil, fmt.Errorf("captcha provider %q not supported", setting.Provider)
+}
+
+// SiteVerifySettings of the hCaptcha or Cloudflare Turnstile
+type SiteVerifySettings struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Secret  string `json:"secret,omitempty"`
+	// VerifyURL of the provider's siteverify endpoint; custom or stub
+	VerifyURL string `json:"verify_url,omitempty"`
+}
+
+// siteVerifyHandler of the hCaptcha or Turnstile response token.
+// Both implement the same siteverify protocol.
+type siteVerifyHandler struct {
+	name    string
+	setting *SiteVerifySettings
+}
+
+func newSiteVerifyHandler(name, verifyURL, settings string) (CaptchaHandler, error) {
+	var setting SiteVerifySettings
+	err := json.Unmarshal([]byte(settings), &setting)
+	if err != nil {
+		return nil, err
+	}
+	if setting.Secret == "" {
+		return nil, fmt.Errorf("%s secret should not be empty", name)
+	}
+	if setting.VerifyURL == "" {
+		setting.VerifyURL = verifyURL
+	} else if _, err = url.ParseRequestURI(setting.VerifyURL); err != nil {
+		return nil, fmt.Errorf("%s verify_url: %v", name, err)
+	}
+	return &siteVerifyHandler{name: name, setting: &setting}, nil
+}
+
+func (h *siteVerifyHandler) Enabled() bool {
+	return h.setting.Enabled
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Hostname   string   `json:"hostname,omitempty"`
+	ErrorCodes []string `json:"error-codes,omitempty"`
+}
+
+func (h *siteVerifyHandler) HandleCaptcha(rsp http.ResponseWriter, req *http.Request) {
+
+	defer req.Body.Close()
+
+	query := req.URL.Query()
+	remoteip := query.Get("remoteip")
+	if remoteip == "" {
+		remoteip = webChatRemoteIP(req)
+	}
+	form := url.Values{
+		"secret":   {h.setting.Secret},
+		"response": {query.Get("response")},
+		"remoteip": {remoteip},
+	}
+	verifyReq, err := http.NewRequestWithContext(req.Context(),
+		http.MethodPost, h.setting.VerifyURL, strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("can't create %s request (%v)", h.name, err))
+		return
+	}
+	verifyReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	verifyRsp, err := http.DefaultClient.Do(verifyReq)
+	if err != nil {
+		returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("request to %s failed (%v)", h.name, err))
+		return
+	}
+	defer verifyRsp.Body.Close()
+
+	var output siteVerifyResponse
+	err = json.NewDecoder(verifyRsp.Body).Decode(&output)
+	if err != nil {
+		returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("can't unmarshal %s response (%v)", h.name, err))
+		return
+	}
+
+	if !output.Success {
+		codes := strings.Join(output.ErrorCodes, ";")
+		if codes == "" {
+			codes = "verification failed"
+		}
+		returnErrorToResp(rsp, http.StatusBadRequest, errors.New(codes))
+		return
+	}
+
+	err = json.NewEncoder(rsp).Encode(RecaptchaResponse{Success: true})
+	if err != nil {
+		returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("can't marshal final results (%v)", err))
+	}
+}
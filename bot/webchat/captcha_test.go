@@ -0,0 +1,107 @@
+package webchat
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func captchaCheck(t *testing.T, h CaptchaHandler, response string) (int, RecaptchaResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/webchat/captcha?"+url.Values{"response": {response}}.Encode(), nil)
+	h.HandleCaptcha(rec, req)
+	var res RecaptchaResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec.Code, res
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+
+	stub := httptest.NewServer(http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		_ = req.ParseForm()
+		// NOTE: recaptcha posts the query parameters
+		res := map[string]any{"success": true, "score": 0.9}
+		if req.Form.Get("secret") != "s3cr3t" || req.Form.Get("response") != "passed" {
+			res = map[string]any{"success": false, "error-codes": []string{"invalid-input-response"}}
+		}
+		_ = json.NewEncoder(rsp).Encode(res)
+	}))
+	defer stub.Close()
+
+	for _, name := range []string{"hcaptcha", "turnstile", "recaptcha"} {
+		settings := `{"provider":"` + name + `","enabled":true,"secret":"s3cr3t","verify_url":"` + stub.URL + `"}`
+		h, err := NewCaptchaHandler(settings)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !h.Enabled() {
+			t.Fatalf("%s: disabled", name)
+		}
+		if code, res := captchaCheck(t, h, "passed"); code != http.StatusOK || !res.Success {
+			t.Errorf("%s: passed: %d %+v", name, code, res)
+		}
+		if code, res := captchaCheck(t, h, "failed"); code != http.StatusBadRequest || res.Success || res.Error == "" {
+			t.Errorf("%s: failed: %d %+v", name, code, res)
+		}
+	}
+
+	for _, settings := range []string{
+		`{"provider":"unknown","secret":"x"}`,
+		`{"provider":"turnstile"}`,
+		`{"provider":"hcaptcha","secret":"x","verify_url":"not a url"}`,
+		`{"provider":"pow","difficulty":64}`,
+	} {
+		if _, err := NewCaptchaHandler(settings); err == nil {
+			t.Errorf("%s: error expected", settings)
+		}
+	}
+}
+
+func TestPowCaptcha(t *testing.T) {
+
+	h, err := NewCaptchaHandler(`{"provider":"pow","enabled":true,"secret":"s3cr3t","difficulty":8}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pow := h.(*PowCaptchaHandler)
+	now := time.Unix(1700000000, 0)
+	pow.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	h.HandleCaptcha(rec, httptest.NewRequest("GET", "/webchat/captcha", nil))
+	var issued PowChallenge
+	if err = json.Unmarshal(rec.Body.Bytes(), &issued); err != nil || issued.Difficulty != 8 {
+		t.Fatalf("challenge: %s", rec.Body.String())
+	}
+
+	var solution string
+	for n := 0; ; n++ {
+		solution = strconv.Itoa(n)
+		hash := sha256.Sum256([]byte(issued.Challenge + ":" + solution))
+		if powLeadingZeros(hash[:]) >= issued.Difficulty {
+			break
+		}
+	}
+
+	if code, res := captchaCheck(t, h, issued.Challenge+"0:"+solution); code != http.StatusBadRequest {
+		t.Errorf("forged challenge: %d %+v", code, res)
+	}
+	if code, res := captchaCheck(t, h, issued.Challenge+":"+solution); code != http.StatusOK || !res.Success {
+		t.Fatalf("solution: %d %+v", code, res)
+	}
+	if code, _ := captchaCheck(t, h, issued.Challenge+":"+solution); code != http.StatusBadRequest {
+		t.Errorf("replay accepted: %d", code)
+	}
+
+	issued = *pow.challenge()
+	now = now.Add(powDefaultTTL + time.Second)
+	if code, _ := captchaCheck(t, h, issued.Challenge+":0"); code != http.StatusBadRequest {
+		t.Errorf("expired accepted: %d", code)
+	}
+}
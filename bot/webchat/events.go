@@ -0,0 +1,261 @@
+package webchat
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	errs "github.com/micro/micro/v3/service/errors"
+
+	"github.com/gorilla/websocket"
+)
+
+// peerConn of the chat room; websocket or event stream.
+// Room writes are [sync] within the writePump routine.
+type peerConn interface {
+	SetWriteDeadline(t time.Time) error
+	NextWriter(messageType int) (io.WriteCloser, error)
+	WriteMessage(messageType int, data []byte) error
+	RemoteAddr() net.Addr
+	Close() error
+}
+
+var _ peerConn = (*websocket.Conn)(nil)
+
+const (
+	// Query parameter of the POST request,
+	// identifying the event stream to respond to
+	streamParam = "stream"
+)
+
+// isEventStream reports whether req is the Server-Sent Events subscription.
+// This is the fallback transport for the networks blocking websockets.
+func isEventStream(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		if mtype, _, _ := mime.ParseMediaType(accept); mtype == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr of the HTTP request
+type remoteAddr string
+
+func (remoteAddr) Network() string  { return "tcp" }
+func (a remoteAddr) String() string { return string(a) }
+
+// eventStream connection of the room.
+// Downstream: Server-Sent Events; each frame is the "message" event.
+// Upstream: POST ?stream=id requests of the same envelope.
+type eventStream struct {
+	id   string
+	room *webChat
+	addr remoteAddr
+
+	mx     sync.Mutex
+	rsp    http.ResponseWriter
+	ctrl   *http.ResponseController
+	closed bool
+	done   chan struct{}
+}
+
+var _ peerConn = (*eventStream)(nil)
+
+func (e *eventStream) RemoteAddr() net.Addr {
+	return e.addr
+}
+
+func (e *eventStream) SetWriteDeadline(t time.Time) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.closed {
+		return net.ErrClosed
+	}
+	err := e.ctrl.SetWriteDeadline(t)
+	if errors.Is(err, http.ErrNotSupported) {
+		err = nil
+	}
+	return err
+}
+
+// write the event; [data] lines are prefixed
+func (e *eventStream) write(event string, data []byte) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if e.closed {
+		return net.ErrClosed
+	}
+	var frame bytes.Buffer
+	if event != "" {
+		frame.WriteString("event: " + event + "\n")
+	}
+	// JSON-encoded frame is terminated by the newline
+	data = bytes.TrimRight(data, "\n")
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		frame.WriteString("data: ")
+		frame.Write(line)
+		frame.WriteByte('\n')
+	}
+	frame.WriteByte('\n')
+	_, err := e.rsp.Write(frame.Bytes())
+	if err == nil {
+		err = e.ctrl.Flush()
+	}
+	return err
+}
+
+// eventWriter buffers the single frame
+type eventWriter struct {
+	bytes.Buffer
+	stream *eventStream
+}
+
+func (w *eventWriter) Close() error {
+	return w.stream.write("", w.Bytes())
+}
+
+func (e *eventStream) NextWriter(messageType int) (io.WriteCloser, error) {
+	if messageType != websocket.TextMessage {
+		return nil, fmt.Errorf("sse: message type %d not supported", messageType)
+	}
+	return &eventWriter{stream: e}, nil
+}
+
+func (e *eventStream) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case websocket.TextMessage:
+		return e.write("", data)
+	case websocket.PingMessage:
+		// keep-alive comment; intermediate proxies
+		e.mx.Lock()
+		defer e.mx.Unlock()
+		if e.closed {
+			return net.ErrClosed
+		}
+		_, err := io.WriteString(e.rsp, ": ping\n\n")
+		if err == nil {
+			err = e.ctrl.Flush()
+		}
+		return err
+	case websocket.CloseMessage:
+		// the widget shall not reconnect
+		return e.write("close", []byte("BYE"))
+	}
+	return fmt.Errorf("sse: message type %d not supported", messageType)
+}
+
+// Close the stream; the serving request completes
+func (e *eventStream) Close() error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+	if !e.closed {
+		e.closed = true
+		close(e.done)
+	}
+	return nil
+}
+
+// serveEvents of the room to the visitor until disconnected
+func (c *WebChatBot) serveEvents(rsp http.ResponseWriter, req *http.Request, room *webChat) {
+
+	stream := &eventStream{
+		id:   generateRandomString(32),
+		room: room,
+		addr: remoteAddr(req.RemoteAddr),
+		rsp:  rsp,
+		ctrl: http.NewResponseController(rsp),
+		done: make(chan struct{}),
+	}
+
+	header := rsp.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // NGINX
+	rsp.WriteHeader(http.StatusOK)
+
+	// The stream ID to POST requests with
+	if err := stream.write("stream", []byte(stream.id)); err != nil {
+		return
+	}
+
+	c.RWMutex.Lock() // +RW
+	c.streams[stream.id] = stream
+	c.RWMutex.Unlock() // -RW
+
+	defer func() {
+		c.RWMutex.Lock() // +RW
+		delete(c.streams, stream.id)
+		c.RWMutex.Unlock() // -RW
+	}()
+
+	c.join(room, stream)
+	room.Log.Info("[SSE] >>> Listen <<<",
+		slog.String("sse", req.RemoteAddr),
+	)
+
+	select {
+	case <-stream.done:
+	case <-req.Context().Done():
+		room.leave(stream)
+	}
+}
+
+// postRequest of the event stream; same envelope as for the websocket frame.
+// The response is sent to the originating event stream.
+func (c *WebChatBot) postRequest(rsp http.ResponseWriter, req *http.Request) {
+
+	defer req.Body.Close()
+
+	c.RWMutex.RLock() // +R
+	stream := c.streams[req.URL.Query().Get(streamParam)]
+	c.RWMutex.RUnlock() // -R
+
+	if stream == nil {
+		respondError(rsp, errs.Unauthorized(
+			"chat.web.client.unauthorized",
+			"webchat: unauthorized; event stream not found",
+		))
+		return // 401 Unauthorized (!)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, c.MessageMaxSize+1))
+	if err != nil {
+		respondError(rsp, errs.BadRequest(
+			"chat.web.request.invalid",
+			"webchat: request is invalid; %v", err,
+		))
+		return // (400) Bad Request
+	}
+	if int64(len(data)) > c.MessageMaxSize {
+		respondError(rsp, errs.New(
+			"chat.web.request.too_large",
+			"webchat: request exceeds message_max_size",
+			http.StatusRequestEntityTooLarge,
+		))
+		return // (413) Request Entity Too Large
+	}
+
+	if !stream.room.handle(stream, data) {
+		_ = stream.Close()
+		respondError(rsp, errs.New(
+			"chat.web.client.closed",
+			"webchat: chat room is closed",
+			http.StatusGone,
+		))
+		return // (410) Gone
+	}
+
+	rsp.WriteHeader(http.StatusAccepted)
+}
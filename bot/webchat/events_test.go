@@ -0,0 +1,72 @@
+package webchat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEventStream(t *testing.T) {
+
+	req := httptest.NewRequest("GET", "/webchat", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if !isEventStream(req) {
+		t.Fatal("event stream not detected")
+	}
+	req.Header.Set("Accept", "application/json")
+	if isEventStream(req) {
+		t.Fatal("event stream detected")
+	}
+
+	rec := httptest.NewRecorder()
+	stream := &eventStream{
+		rsp:  rec,
+		ctrl: http.NewResponseController(rec),
+		done: make(chan struct{}),
+	}
+	// frame as written by the room
+	w, err := stream.NextWriter(websocket.TextMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("{\"seq\":1,\"message\":{\"text\":\"hi\"}}\n"))
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = stream.WriteMessage(websocket.PingMessage, nil)
+	_ = stream.WriteMessage(websocket.CloseMessage, nil)
+
+	expect := "data: {\"seq\":1,\"message\":{\"text\":\"hi\"}}\n\n" +
+		": ping\n\n" +
+		"event: close\ndata: BYE\n\n"
+	if body := rec.Body.String(); body != expect {
+		t.Fatalf("stream:\n%s\nexpected:\n%s", body, expect)
+	}
+
+	_ = stream.Close()
+	_ = stream.Close()
+	select {
+	case <-stream.done:
+	default:
+		t.Fatal("stream not done")
+	}
+	if err = stream.WriteMessage(websocket.TextMessage, []byte("{}")); err == nil {
+		t.Fatal("write to closed stream")
+	}
+}
+
+func TestPostRequestUnknownStream(t *testing.T) {
+	c := &WebChatBot{
+		RWMutex: new(sync.RWMutex),
+		streams: make(map[string]*eventStream),
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/webchat?stream=none", nil)
+	c.postRequest(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status %d; expected 401", rec.Code)
+	}
+}
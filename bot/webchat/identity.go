@@ -0,0 +1,145 @@
+package webchat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// identityPrefix of the chat ID bound to the verified visitor identity.
+	// Device IDs (cookie) are never accepted within this namespace,
+	// so the identified conversation cannot be joined by the cookie copy.
+	identityPrefix = "uid:"
+	// identityParam of the request query, containing signed identity token.
+	// Browsers are not able to set headers for the websocket handshake.
+	identityParam = "token"
+	// clock skew allowed for the token time claims
+	identitySkew = time.Minute
+)
+
+// identity of the web visitor, signed by the site's backend.
+// Token is the JWT (HS256, HS384, HS512) using bot's identity_secret.
+type identity struct {
+	// External (site) user unique ID; required
+	Subject string `json:"sub"`
+	// Display name of the user
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone_number,omitempty"`
+	// Expiration time; required
+	Expires   json.Number `json:"exp"`
+	NotBefore json.Number `json:"nbf,omitempty"`
+}
+
+// chatID of the conversation bound to the identity
+func (id *identity) chatID() string {
+	return identityPrefix + id.Subject
+}
+
+// variables of the identity to populate the /start message with
+func (id *identity) variables() map[string]string {
+	if id == nil {
+		return nil
+	}
+	vars := map[string]string{
+		"user_id": id.Subject,
+	}
+	if id.Email != "" {
+		vars["email"] = id.Email
+	}
+	if id.Phone != "" {
+		vars["phone"] = id.Phone
+	}
+	return vars
+}
+
+var identityAlgs = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// verifyIdentity token signed with the secret key
+func verifyIdentity(token string, secret []byte, now time.Time) (*identity, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("jwt: malformed token")
+	}
+
+	var head struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return nil, err
+	}
+	alg := identityAlgs[head.Alg]
+	if alg == nil {
+		return nil, fmt.Errorf("jwt: algorithm %q not allowed", head.Alg)
+	}
+
+	sign, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("jwt: malformed signature")
+	}
+	mac := hmac.New(alg, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sign, mac.Sum(nil)) {
+		return nil, fmt.Errorf("jwt: invalid signature")
+	}
+
+	var claim identity
+	if err = decodeSegment(parts[1], &claim); err != nil {
+		return nil, err
+	}
+	exp, err := claim.Expires.Int64()
+	if err != nil || now.After(time.Unix(exp, 0).Add(identitySkew)) {
+		return nil, fmt.Errorf("jwt: token expired")
+	}
+	if nbf, err := claim.NotBefore.Int64(); err == nil && now.Add(identitySkew).Before(time.Unix(nbf, 0)) {
+		return nil, fmt.Errorf("jwt: token not valid yet")
+	}
+	claim.Subject = strings.TrimSpace(claim.Subject)
+	if claim.Subject == "" {
+		return nil, fmt.Errorf("jwt: subject required")
+	}
+	if len(claim.Subject) > 128 {
+		return nil, fmt.Errorf("jwt: subject too long")
+	}
+	return &claim, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("jwt: malformed token")
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("jwt: malformed token")
+	}
+	return nil
+}
+
+// identify the visitor of the request.
+// Returns nil if no token provided or identity is not configured.
+func (c *WebChatBot) identify(req *http.Request) (*identity, error) {
+	if len(c.IdentitySecret) == 0 {
+		return nil, nil // disabled
+	}
+	token := req.URL.Query().Get(identityParam)
+	if token == "" {
+		if c.IdentityRequired {
+			return nil, fmt.Errorf("webchat: identity token required")
+		}
+		return nil, nil // anonymous
+	}
+	return verifyIdentity(token, c.IdentitySecret, time.Now())
+}
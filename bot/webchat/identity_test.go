@@ -0,0 +1,71 @@
+package webchat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signIdentity(alg, claims, secret string) string {
+	enc := base64.RawURLEncoding
+	token := enc.EncodeToString([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) +
+		"." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return token + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyIdentity(t *testing.T) {
+
+	const secret = "s3cr3t"
+	now := time.Unix(1700000000, 0)
+
+	valid := signIdentity("HS256", `{"sub":"42","name":"John Doe","email":"john@example.com","phone_number":"+380441234567","exp":1700000600}`, secret)
+	user, err := verifyIdentity(valid, []byte(secret), now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if user.chatID() != "uid:42" || user.Name != "John Doe" {
+		t.Fatalf("identity: %+v", user)
+	}
+	vars := user.variables()
+	if vars["user_id"] != "42" || vars["email"] != "john@example.com" || vars["phone"] != "+380441234567" {
+		t.Fatalf("variables: %v", vars)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{"malformed", "abc.def", "malformed token"},
+		{"secret", signIdentity("HS256", `{"sub":"42","exp":1700000600}`, "other"), "invalid signature"},
+		{"alg", signIdentity("none", `{"sub":"42","exp":1700000600}`, secret), "not allowed"},
+		{"expired", signIdentity("HS256", `{"sub":"42","exp":1699990000}`, secret), "expired"},
+		{"no exp", signIdentity("HS256", `{"sub":"42"}`, secret), "expired"},
+		{"nbf", signIdentity("HS256", `{"sub":"42","exp":1700000600,"nbf":1700000300}`, secret), "not valid yet"},
+		{"no sub", signIdentity("HS256", `{"exp":1700000600}`, secret), "subject required"},
+	}
+	for _, tc := range tests {
+		_, err := verifyIdentity(tc.token, []byte(secret), now)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: error %v; expected %q", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestDeviceIdentityPrefix(t *testing.T) {
+	req := httptest.NewRequest("GET", "/webchat", nil)
+	req.Header.Set("Cookie", "cid=uid:42")
+	if id, ok := webChatDeviceID(req); ok {
+		t.Fatalf("device ID %q within identity namespace accepted", id)
+	}
+	req.Header.Set("Cookie", "cid=0123456789abcdef")
+	if id, ok := webChatDeviceID(req); !ok || id != "0123456789abcdef" {
+		t.Fatalf("device ID %q rejected", id)
+	}
+}
@@ -0,0 +1,282 @@
+package webchat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	errs "github.com/micro/micro/v3/service/errors"
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	// Query parameter of the leave-a-message request: POST ?offline
+	offlineParam = "offline"
+	// Conversation variable marks the offline conversation.
+	// The flow may send a message with this variable set to "true"
+	// to ask the widget to switch to the leave-a-message form.
+	offlineVariable = "offline"
+)
+
+var offlinePhone = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{4,31}$`)
+
+// offlineMessage left by the visitor
+type offlineMessage struct {
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Message string `json:"message"`
+}
+
+// validate the message submitted; the visitor must leave the contact to reply to
+func (m *offlineMessage) validate() error {
+	m.Name = strings.TrimSpace(m.Name)
+	m.Email = strings.TrimSpace(m.Email)
+	m.Phone = strings.TrimSpace(m.Phone)
+	m.Message = strings.TrimSpace(m.Message)
+	if m.Message == "" {
+		return fmt.Errorf("message: value required")
+	}
+	if m.Email == "" && m.Phone == "" {
+		return fmt.Errorf("email or phone: value required")
+	}
+	if len(m.Name) > prechatValueMax {
+		return fmt.Errorf("name: value too long")
+	}
+	if m.Email != "" {
+		addr, err := mail.ParseAddress(m.Email)
+		if err != nil || addr.Name != "" {
+			return fmt.Errorf("email: value is invalid")
+		}
+	}
+	if m.Phone != "" && !offlinePhone.MatchString(m.Phone) {
+		return fmt.Errorf("phone: value is invalid")
+	}
+	return nil
+}
+
+// variables of the offline conversation
+func (m *offlineMessage) variables() map[string]string {
+	vars := map[string]string{
+		offlineVariable: "true",
+	}
+	for name, value := range map[string]string{
+		"name": m.Name, "email": m.Email, "phone": m.Phone,
+	} {
+		if value != "" {
+			vars[name] = value
+		}
+	}
+	return vars
+}
+
+// isOffline reports whether the bot takes messages only at the moment
+func (c *WebChatBot) isOffline(now time.Time) bool {
+	if c.Offline {
+		return true
+	}
+	if c.OnlineHours != nil {
+		return !c.OnlineHours.IsOpen(now)
+	}
+	// the bot's business hours
+	return !c.Gateway.IsOpen(now)
+}
+
+// isOfflineChannel reports whether the conversation was left as a message
+func isOfflineChannel(channel *bot.Channel) bool {
+	props, _ := channel.Properties.(map[string]string)
+	return props[offlineVariable] == "true"
+}
+
+// leaveMessage of the visitor: POST ?offline
+//
+// Starts the conversation marked "offline" with the visitor's contact as variables.
+// A running conversation (the flow switched the widget to offline) is marked so.
+func (c *WebChatBot) leaveMessage(rsp http.ResponseWriter, req *http.Request) {
+
+	var form offlineMessage
+	data, err := io.ReadAll(io.LimitReader(req.Body, c.MessageMaxSize+1))
+	if err == nil && int64(len(data)) > c.MessageMaxSize {
+		respondError(rsp, errs.New(
+			"chat.bot.webchat.offline.too_large",
+			"webchat: offline: message too large",
+			http.StatusRequestEntityTooLarge,
+		))
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &form)
+	}
+	if err == nil {
+		err = form.validate()
+	}
+	if err != nil {
+		respondError(rsp, errs.BadRequest(
+			"chat.bot.webchat.offline.invalid",
+			"webchat: offline: %v", err,
+		))
+		return
+	}
+
+	user, err := c.identify(req)
+	if err != nil {
+		respondError(rsp, errs.Unauthorized(
+			"chat.bot.webchat.identity.invalid",
+			"webchat: %v", err,
+		))
+		return
+	}
+	deviceID, ok := webChatDeviceID(req)
+	if user != nil {
+		deviceID, ok = user.chatID(), true
+	}
+	if !ok || deviceID == "" {
+		deviceID = generateRandomString(32)
+		ok = false
+	}
+
+	endUser := c.endUser(deviceID, user)
+	if user == nil && form.Name != "" {
+		endUser.FirstName, endUser.LastName = util.ParseFullName(form.Name)
+	}
+
+	ctx := req.Context()
+	channel, err := c.Gateway.GetChannel(ctx, deviceID, endUser)
+	if err != nil {
+		c.Gateway.Log.Error("Failed to .GetChannel()",
+			slog.Any("error", err),
+		)
+		respondError(rsp, err)
+		return
+	}
+
+	// Visitor context; the pre-chat form is NOT required
+	vars, _ := c.visitorContext(req)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	for name, value := range form.variables() {
+		vars[name] = value
+	}
+
+	if !channel.IsNew() {
+		// Conversation is running; mark it offline
+		_, err = c.Gateway.Internal.Client.SetVariables(ctx,
+			&chat.SetVariablesRequest{
+				ChannelId: channel.ChannelID,
+				Variables: vars,
+			},
+		)
+		if err != nil {
+			c.Gateway.Log.Error("webchat.leaveMessage",
+				slog.Any("error", err),
+				slog.String("channel-id", channel.ChannelID),
+			)
+			respondError(rsp, err)
+			return
+		}
+	}
+	bindVariables(channel, vars)
+
+	err = c.Gateway.Read(ctx, &bot.Update{
+		Chat:  channel,
+		User:  &channel.Account,
+		Title: channel.Title,
+		Message: &chat.Message{
+			Type: "text",
+			Text: form.Message,
+		},
+	})
+	if err != nil {
+		c.Gateway.Log.Error("webchat.leaveMessage",
+			slog.Any("error", err),
+		)
+		respondError(rsp, err)
+		return
+	}
+
+	if !ok {
+		c.setDeviceCookie(rsp, req, deviceID)
+	}
+	rsp.WriteHeader(http.StatusAccepted)
+}
+
+// replyOffline relays the agent's message of the offline conversation
+// through the alternate (email or SMS) gateway: the contact the visitor left
+// is resolved to the visitor's own channel of that gateway, so the reply
+// goes on within the latest conversation of the same contact, if any.
+func (c *WebChatBot) replyOffline(ctx context.Context, channel *bot.Channel, message *chat.Message) error {
+
+	if c.OfflineReplyVia == 0 {
+		return errs.BadRequest(
+			"chat.bot.webchat.offline.reply_via.required",
+			"webchat: visitor is offline; offline_reply_via gateway not configured",
+		)
+	}
+	via, err := c.Gateway.Internal.Gateway(ctx, c.OfflineReplyVia, "")
+	if err != nil {
+		return err
+	}
+	if via == nil || via.External == nil {
+		return errs.BadGateway(
+			"chat.bot.webchat.offline.reply_via.not_found",
+			"webchat: visitor is offline; gateway id=%d not running", c.OfflineReplyVia,
+		)
+	}
+	var (
+		props, _ = channel.Properties.(map[string]string)
+		peerId   = props["phone"]
+	)
+	if via.External.String() == "email" {
+		peerId = props["email"]
+	}
+	if peerId == "" {
+		return errs.BadRequest(
+			"chat.bot.webchat.offline.contact.required",
+			"webchat: visitor is offline; no contact to reply via gateway id=%d", c.OfflineReplyVia,
+		)
+	}
+
+	contact := &bot.Account{
+		Channel: via.GetProvider(),
+		Contact: peerId,
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(props["name"])
+	// Resolve the visitor's channel of the gateway by the contact
+	replyTo, err := via.GetChannel(ctx, peerId, contact)
+	if err == nil {
+		err = via.External.SendNotify(ctx, &bot.Update{
+			Chat:  replyTo,
+			User:  &replyTo.Account,
+			Title: replyTo.Title,
+			Message: &chat.Message{
+				Type: message.Type,
+				Text: message.Text,
+				File: message.File,
+			},
+		})
+	}
+	if err != nil {
+		c.Log.Error("webchat.replyOffline",
+			slog.Any("error", err),
+			slog.String("chat-id", channel.ChatID),
+			slog.Int64("via", c.OfflineReplyVia),
+		)
+		return err
+	}
+	c.Log.Info("webchat.replyOffline",
+		slog.String("chat-id", channel.ChatID),
+		slog.Int64("via", c.OfflineReplyVia),
+		slog.Int64("contact-id", replyTo.Account.ID),
+	)
+	return nil
+}
@@ -0,0 +1,95 @@
+package webchat
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+func TestOfflineMessage(t *testing.T) {
+
+	for _, form := range []offlineMessage{
+		{Email: "john@example.com"},
+		{Message: "Call me back"},
+		{Message: "Hi", Email: "John <john@example.com>"},
+		{Message: "Hi", Phone: "call me"},
+	} {
+		if err := form.validate(); err == nil {
+			t.Errorf("%+v: error expected", form)
+		}
+	}
+
+	form := offlineMessage{Name: " John ", Phone: "+380 (44) 123-45-67", Message: " Call me back "}
+	if err := form.validate(); err != nil {
+		t.Fatal(err)
+	}
+	vars := form.variables()
+	if len(vars) != 3 || vars[offlineVariable] != "true" || vars["name"] != "John" || vars["phone"] != "+380 (44) 123-45-67" {
+		t.Errorf("variables: %v", vars)
+	}
+	if form.Message != "Call me back" {
+		t.Errorf("message: %q", form.Message)
+	}
+}
+
+func TestOfflineMode(t *testing.T) {
+
+	agent := &bot.Gateway{
+		Log: slog.Default(),
+		Bot: &bot.Bot{
+			Id:       1,
+			Provider: provider,
+			Metadata: map[string]string{
+				"online_hours": `{"timezone":"UTC","weekly":[{"days":["mon"],"open":"09:00","close":"18:00"}]}`,
+			},
+		},
+	}
+	srv, err := New(agent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := srv.(*WebChatBot)
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	if c.isOffline(monday) || !c.isOffline(monday.Add(24*time.Hour)) {
+		t.Error("online_hours not applied")
+	}
+
+	rec := httptest.NewRecorder()
+	c.servePrechat(rec, httptest.NewRequest("GET", "/webchat/prechat", nil))
+	var res struct {
+		Fields  []*formField `json:"fields"`
+		Offline *bool        `json:"offline"`
+	}
+	if err = json.Unmarshal(rec.Body.Bytes(), &res); err != nil || res.Fields == nil || res.Offline == nil {
+		t.Fatalf("prechat: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c.leaveMessage(rec, httptest.NewRequest("POST", "/webchat?offline", strings.NewReader(`{"message":"Hi"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("leave message: %d; expected 400", rec.Code)
+	}
+
+	// Agent's reply to the offline conversation; no gateway to reply via
+	channel := &bot.Channel{ChatID: "visitor", Properties: map[string]string{offlineVariable: "true", "email": "john@example.com"}}
+	err = c.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: &chat.Message{Type: "text", Text: "Hello"}})
+	if err == nil || !strings.Contains(err.Error(), "offline_reply_via") {
+		t.Errorf("reply: %v", err)
+	}
+	if channel.Closed != 0 {
+		t.Error("offline conversation closed")
+	}
+
+	agent.Bot.Metadata["offline_reply_via"] = "email"
+	if _, err = New(agent, nil); err == nil {
+		t.Error("offline_reply_via: error expected")
+	}
+}
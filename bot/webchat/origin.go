@@ -0,0 +1,63 @@
+package webchat
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// originRejected requests counter
+var originRejected, _ = otel.Meter("github.com/webitel/chat_manager/bot/webchat").
+	Int64Counter("webchat.origin.rejected",
+		metric.WithDescription("WebChat requests rejected due to the Origin not allowed"),
+		metric.WithUnit("{request}"),
+	)
+
+// CheckOrigin of the request against allow_origin list.
+// Applies to the websocket upgrade, event stream, media upload
+// as well as to the CORS preflight request of the bot URI.
+func (c *WebChatBot) CheckOrigin(req *http.Request) bool {
+	if c.Websocket.CheckOrigin(req) {
+		return true
+	}
+	origin := req.Header.Get(hdrOrigin)
+	c.Gateway.Log.Warn("Origin: Not Allowed",
+		slog.String("origin", origin),
+		slog.String("method", req.Method),
+		slog.String("peer", webChatRemoteIP(req)),
+	)
+	if originRejected != nil {
+		originRejected.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.Int64("bot.id", c.Gateway.Bot.GetId()),
+			attribute.String("http.request.method", req.Method),
+		))
+	}
+	return false
+}
+
+// sameOrigin reports whether the request Origin host is the same
+// as the request [X-Forwarded-]Host. Requests without Origin are allowed.
+// Default policy when no allow_origin list is configured for the bot.
+func sameOrigin(req *http.Request) bool {
+	origin := req.Header.Get(hdrOrigin)
+	if origin == "" {
+		return true // NOT a cross-origin browser request
+	}
+	src, err := url.Parse(origin)
+	if err != nil || src.Host == "" {
+		return false
+	}
+	host := req.Header.Get("X-Forwarded-Host")
+	if host == "" {
+		host = req.Host
+	}
+	// X-Forwarded-Host: client, proxy1
+	host, _, _ = strings.Cut(host, ",")
+	return strings.EqualFold(src.Host, strings.TrimSpace(host))
+}
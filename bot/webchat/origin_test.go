@@ -0,0 +1,81 @@
+package webchat
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/webitel/chat_manager/bot"
+)
+
+func TestCheckOrigin(t *testing.T) {
+
+	agent := &bot.Gateway{
+		Log: slog.Default(),
+		Bot: &bot.Bot{
+			Id:       1,
+			Provider: provider,
+			Metadata: map[string]string{
+				"allow_origin": " https://example.com/, https://*.example.org ",
+			},
+		},
+	}
+	srv, err := New(agent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := srv.(*WebChatBot)
+
+	for origin, allowed := range map[string]bool{
+		"https://example.com":             true,
+		"https://EXAMPLE.com":             true,
+		"https://www.example.com":         false,
+		"https://chat.example.org":        true,
+		"https://a.b.example.org":         true,
+		"https://example.org":             false,
+		"https://.example.org":            false,
+		"https://evil.com?.example.org":   false,
+		"https://evil.com/.example.org":   false,
+		"https://u@evil.com#.example.org": false,
+		"http://chat.example.org":         false,
+		"":                                false,
+	} {
+		req := httptest.NewRequest("GET", "/webchat", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if c.CheckOrigin(req) != allowed {
+			t.Errorf("origin %q: allowed=%t expected", origin, allowed)
+		}
+	}
+
+	// Default: same origin only
+	delete(agent.Bot.Metadata, "allow_origin")
+	srv, err = New(agent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = srv.(*WebChatBot)
+
+	for origin, allowed := range map[string]bool{
+		"https://chat.example.com": true,
+		"https://CHAT.example.com": true,
+		"https://example.com":      false,
+		"https://evil.com":         false,
+		"null":                     false,
+		"":                         true,
+	} {
+		req := httptest.NewRequest("GET", "https://chat.example.com/webchat", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if c.CheckOrigin(req) != allowed {
+			t.Errorf("origin %q: allowed=%t expected; same origin", origin, allowed)
+		}
+	}
+
+	agent.Bot.Metadata["allow_origin"] = "https://*.*.example.com"
+	if _, err = New(agent, nil); err == nil {
+		t.Fatal("multiple wildcards accepted")
+	}
+}
@@ -0,0 +1,199 @@
+package webchat
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	powDefaultDifficulty = 18 // bits
+	powDefaultTTL        = 5 * time.Minute
+)
+
+// PowSettings of the self-hosted proof-of-work captcha
+type PowSettings struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Secret to sign the challenges with.
+	// Random if empty; all the nodes serving the bot MUST share the same secret !
+	Secret string `json:"secret,omitempty"`
+	// Difficulty as the number of the leading zero bits required
+	Difficulty int `json:"difficulty,omitempty"`
+	// TTL of the challenge issued, e.g.: "5m"
+	TTL string `json:"ttl,omitempty"`
+}
+
+// PowCaptchaHandler is the proof-of-work captcha; needs no third party.
+//
+// GET /captcha responds with the signed challenge to be solved.
+// The visitor (widget) finds the solution string, so that
+// SHA-256(challenge + ":" + solution) has at least difficulty leading zero bits.
+// GET /captcha?response={challenge}:{solution} verifies the solution.
+// The challenge is stateless, expires and can be used once.
+type PowCaptchaHandler struct {
+	setting *PowSettings
+	secret  []byte
+	ttl     time.Duration
+	now     func() time.Time
+
+	mx   sync.Mutex
+	used map[string]int64 // challenges solved: expires
+}
+
+func NewPowCaptchaHandler(settings string) (CaptchaHandler, error) {
+	var setting PowSettings
+	err := json.Unmarshal([]byte(settings), &setting)
+	if err != nil {
+		return nil, err
+	}
+	h := &PowCaptchaHandler{
+		setting: &setting,
+		secret:  []byte(setting.Secret),
+		ttl:     powDefaultTTL,
+		now:     time.Now,
+		used:    make(map[string]int64),
+	}
+	if len(h.secret) == 0 {
+		h.secret = make([]byte, 32)
+		if _, err = rand.Read(h.secret); err != nil {
+			return nil, err
+		}
+	}
+	switch d := setting.Difficulty; {
+	case d == 0:
+		setting.Difficulty = powDefaultDifficulty
+	case d < 8 || d > 28:
+		return nil, fmt.Errorf("pow difficulty %d out of range [8..28]", d)
+	}
+	if setting.TTL != "" {
+		h.ttl, err = time.ParseDuration(setting.TTL)
+		if err != nil || h.ttl < 10*time.Second {
+			return nil, fmt.Errorf("pow ttl %q invalid", setting.TTL)
+		}
+	}
+	return h, nil
+}
+
+func (h *PowCaptchaHandler) Enabled() bool {
+	return h.setting.Enabled
+}
+
+// PowChallenge to be solved
+type PowChallenge struct {
+	Challenge  string `json:"challenge"`
+	Difficulty int    `json:"difficulty"`
+	Algorithm  string `json:"algorithm"`
+	Expires    int64  `json:"expires"` // unix seconds
+}
+
+func (h *PowCaptchaHandler) sign(data string) string {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// challenge issues the new one: {expires}.{difficulty}.{nonce}.{signature}
+func (h *PowCaptchaHandler) challenge() *PowChallenge {
+	expires := h.now().Add(h.ttl).Unix()
+	data := strconv.FormatInt(expires, 10) + "." +
+		strconv.Itoa(h.setting.Difficulty) + "." +
+		generateRandomString(16)
+	return &PowChallenge{
+		Challenge:  data + "." + h.sign(data),
+		Difficulty: h.setting.Difficulty,
+		Algorithm:  "SHA-256",
+		Expires:    expires,
+	}
+}
+
+// verify the response: {challenge}:{solution}
+func (h *PowCaptchaHandler) verify(response string) error {
+
+	challenge, solution, ok := strings.Cut(response, ":")
+	if !ok || solution == "" || len(solution) > 64 {
+		return errors.New("invalid-input-response")
+	}
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 4 {
+		return errors.New("invalid-input-response")
+	}
+	data := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(h.sign(data))) {
+		return errors.New("invalid-input-response")
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("invalid-input-response")
+	}
+	now := h.now().Unix()
+	if now > expires {
+		return errors.New("timeout-or-duplicate")
+	}
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return errors.New("invalid-input-response")
+	}
+	hash := sha256.Sum256([]byte(challenge + ":" + solution))
+	if powLeadingZeros(hash[:]) < difficulty {
+		return errors.New("invalid-solution")
+	}
+
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	if _, dup := h.used[challenge]; dup {
+		return errors.New("timeout-or-duplicate")
+	}
+	for used, exp := range h.used {
+		if now > exp {
+			delete(h.used, used)
+		}
+	}
+	h.used[challenge] = expires
+	return nil
+}
+
+// powLeadingZeros bits count of the hash
+func powLeadingZeros(hash []byte) (n int) {
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+func (h *PowCaptchaHandler) HandleCaptcha(rsp http.ResponseWriter, req *http.Request) {
+
+	defer req.Body.Close()
+
+	response := req.URL.Query().Get("response")
+	if response == "" {
+		rsp.Header().Set("Cache-Control", "no-store")
+		err := json.NewEncoder(rsp).Encode(h.challenge())
+		if err != nil {
+			returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("can't marshal challenge (%v)", err))
+		}
+		return
+	}
+
+	if err := h.verify(response); err != nil {
+		returnErrorToResp(rsp, http.StatusBadRequest, err)
+		return
+	}
+
+	err := json.NewEncoder(rsp).Encode(RecaptchaResponse{Success: true})
+	if err != nil {
+		returnErrorToResp(rsp, http.StatusInternalServerError, fmt.Errorf("can't marshal final results (%v)", err))
+	}
+}
@@ -0,0 +1,187 @@
+package webchat
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/webitel/chat_manager/bot"
+)
+
+// formField of the pre-chat form, filled by the visitor
+// before the conversation starts
+type formField struct {
+	// Name of the conversation variable to store the value as
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	// Input type hint for the widget: text, email, tel, textarea ..
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Pattern (regexp) the value must match
+	Pattern string `json:"pattern,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// prechatForm definition; served to the widget as is
+type prechatForm struct {
+	Fields []*formField `json:"fields"`
+}
+
+const (
+	// Max length of the single value captured
+	prechatValueMax = 1024
+	// Query parameter of the handshake with the JSON-encoded form values
+	prechatParam = "form"
+)
+
+var (
+	// name of the form field (variable)
+	prechatFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+	// variables reserved by the conversation or visitor context
+	prechatReserved = map[string]bool{
+		"cid": true, "chat": true, "user": true, "from": true, "flow": true,
+		"page_url": true, "referrer": true, "user_agent": true, "locale": true,
+	}
+)
+
+// newPrechatForm parses the JSON form definition
+func newPrechatForm(spec string) (*prechatForm, error) {
+	var form prechatForm
+	if err := json.Unmarshal([]byte(spec), &form.Fields); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(form.Fields))
+	for i, field := range form.Fields {
+		if field == nil || !prechatFieldName.MatchString(field.Name) {
+			return nil, fmt.Errorf("fields[%d]: invalid name", i)
+		}
+		if prechatReserved[field.Name] || names[field.Name] {
+			return nil, fmt.Errorf("fields[%d]: name %q is reserved or duplicate", i, field.Name)
+		}
+		names[field.Name] = true
+		if field.Pattern != "" {
+			re, err := regexp.Compile(field.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("fields[%d]: pattern: %v", i, err)
+			}
+			field.re = re
+		}
+	}
+	return &form, nil
+}
+
+// validate the values submitted; returns the variables of the fields filled
+func (f *prechatForm) validate(values map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(f.Fields))
+	for _, field := range f.Fields {
+		value := strings.TrimSpace(values[field.Name])
+		if value == "" {
+			if field.Required {
+				return nil, fmt.Errorf("%s: value required", field.Name)
+			}
+			continue
+		}
+		if len(value) > prechatValueMax {
+			return nil, fmt.Errorf("%s: value too long", field.Name)
+		}
+		if field.re != nil && !field.re.MatchString(value) {
+			return nil, fmt.Errorf("%s: value is invalid", field.Name)
+		}
+		vars[field.Name] = value
+	}
+	return vars, nil
+}
+
+// visitorContext of the websocket handshake request:
+// pre-chat form values and the visitor's page, referrer, user agent and locale.
+// Returns the variables to bind the conversation with.
+func (c *WebChatBot) visitorContext(req *http.Request) (map[string]string, error) {
+
+	query := req.URL.Query()
+	vars := make(map[string]string)
+	if c.Prechat != nil {
+		var values map[string]string
+		if s := query.Get(prechatParam); s != "" {
+			if err := json.Unmarshal([]byte(s), &values); err != nil {
+				return nil, fmt.Errorf("webchat: form: invalid values; %v", err)
+			}
+		}
+		form, err := c.Prechat.validate(values)
+		if err != nil {
+			return nil, fmt.Errorf("webchat: form: %v", err)
+		}
+		vars = form
+	}
+
+	set := func(name, value string) {
+		if value = strings.TrimSpace(value); value == "" {
+			return
+		}
+		if len(value) > prechatValueMax {
+			value = value[:prechatValueMax]
+			for !utf8.ValidString(value) {
+				value = value[:len(value)-1]
+			}
+		}
+		vars[name] = value
+	}
+	// NOTE: browsers do not send Referer on the websocket handshake,
+	// so the widget shall provide the page it is running on
+	page := query.Get("page")
+	if page == "" {
+		page = req.Referer()
+	}
+	set("page_url", page)
+	set("referrer", query.Get("referrer"))
+	set("user_agent", req.UserAgent())
+	locale := query.Get("locale")
+	if locale == "" {
+		// Accept-Language: uk-UA,uk;q=0.9,en;q=0.8
+		locale, _, _ = strings.Cut(req.Header.Get("Accept-Language"), ",")
+		locale, _, _ = strings.Cut(locale, ";")
+	}
+	set("locale", locale)
+
+	return vars, nil
+}
+
+// bindVariables of the visitor context to the channel;
+// the conversation (re)started is populated with
+func bindVariables(channel *bot.Channel, vars map[string]string) {
+	if len(vars) == 0 {
+		return
+	}
+	props, _ := channel.Properties.(map[string]string)
+	if props == nil {
+		props = make(map[string]string, len(vars))
+		channel.Properties = props
+	}
+	for name, value := range vars {
+		props[name] = value
+	}
+}
+
+// servePrechat form definition and the offline state to the widget
+func (c *WebChatBot) servePrechat(rsp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rsp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	form := c.Prechat
+	if form == nil {
+		form = &prechatForm{Fields: []*formField{}}
+	}
+	respondJson(rsp, struct {
+		*prechatForm
+		// Offline; the widget shall show the leave-a-message form
+		Offline bool `json:"offline"`
+	}{
+		prechatForm: form,
+		Offline:     c.isOffline(time.Now()),
+	}, http.StatusOK)
+}
@@ -0,0 +1,78 @@
+package webchat
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPrechatForm(t *testing.T) {
+
+	for _, spec := range []string{
+		`{"name":"email"}`,
+		`[{"name":"1st"}]`,
+		`[{"name":"email"},{"name":"email"}]`,
+		`[{"name":"locale"}]`,
+		`[{"name":"email","pattern":"("}]`,
+	} {
+		if _, err := newPrechatForm(spec); err == nil {
+			t.Errorf("form %s: error expected", spec)
+		}
+	}
+
+	form, err := newPrechatForm(`[
+		{"name":"name","label":"Your name","required":true},
+		{"name":"email","type":"email","pattern":"^[^@\\s]+@[^@\\s]+$"}
+	]`)
+	if err != nil {
+		t.Fatalf("form: %v", err)
+	}
+	c := &WebChatBot{Prechat: form}
+
+	query := url.Values{
+		"form":   {`{"name":" John ","email":"john@example.com","extra":"x"}`},
+		"page":   {"https://example.com/pricing"},
+		"locale": {"uk-UA"},
+	}
+	req := httptest.NewRequest("GET", "/webchat?"+query.Encode(), nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	vars, err := c.visitorContext(req)
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	expect := map[string]string{
+		"name":       "John",
+		"email":      "john@example.com",
+		"page_url":   "https://example.com/pricing",
+		"user_agent": "Mozilla/5.0",
+		"locale":     "uk-UA",
+	}
+	if len(vars) != len(expect) {
+		t.Fatalf("variables: %v", vars)
+	}
+	for name, value := range expect {
+		if vars[name] != value {
+			t.Errorf("%s: %q; expected %q", name, vars[name], value)
+		}
+	}
+
+	for _, values := range []string{
+		``,
+		`{"email":"john@example.com"}`,
+		`{"name":"John","email":"john"}`,
+		`not json`,
+	} {
+		req := httptest.NewRequest("GET", "/webchat?"+url.Values{"form": {values}}.Encode(), nil)
+		if _, err := c.visitorContext(req); err == nil {
+			t.Errorf("form %s: error expected", values)
+		}
+	}
+
+	// locale from Accept-Language; no form configured
+	c.Prechat = nil
+	req = httptest.NewRequest("GET", "/webchat", nil)
+	req.Header.Set("Accept-Language", "en-US;q=0.9,en;q=0.8")
+	if vars, err = c.visitorContext(req); err != nil || vars["locale"] != "en-US" {
+		t.Fatalf("locale: %v; %v", vars, err)
+	}
+}
@@ -31,6 +31,8 @@ type RecaptchaSettings struct {
 	Enabled   bool     `json:"enabled,omitempty"`
 	Secret    string   `json:"secret,omitempty"`
 	Threshold *float64 `json:"threshold,omitempty"`
+	// VerifyURL of the siteverify endpoint; RecaptchaURL by default
+	VerifyURL string `json:"verify_url,omitempty"`
 }
 
 type RecaptchaResponse struct {
@@ -56,6 +58,11 @@ func (r *RecaptchaSettings) Normalize() error {
 		def := DefaultThreshold
 		r.Threshold = &def
 	}
+	if r.VerifyURL == "" {
+		r.VerifyURL = RecaptchaURL
+	} else if _, err := url.ParseRequestURI(r.VerifyURL); err != nil {
+		return fmt.Errorf("recaptcha verify_url: %v", err)
+	}
 	return nil
 }
 
@@ -86,7 +93,7 @@ func (h *RecaptchaHandler) HandleCaptcha(rsp http.ResponseWriter, req *http.Requ
 	response := req.URL.Query().Get("response")
 	remoteip := req.URL.Query().Get("remoteip")
 
-	rawUrl, _ := url.Parse(RecaptchaURL)
+	rawUrl, _ := url.Parse(h.setting.VerifyURL)
 	params := rawUrl.Query()
 	defer req.Body.Close()
 
@@ -15,6 +15,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,6 +27,7 @@ import (
 	"github.com/gorilla/websocket"
 	chat "github.com/webitel/chat_manager/api/proto/chat"
 	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
 )
 
 // webChat room with external client
@@ -41,8 +43,9 @@ type webChat struct {
 	Bot *WebChatBot
 	// Chat Channel (external) definition
 	*bot.Channel
-	// This chat opened connections (different tabs)
-	conn   []*websocket.Conn
+	// This chat opened connections (different tabs);
+	// websocket or event stream
+	conn   []peerConn
 	closed bool
 	// Buffered channel for sync write operations.
 	send chan func()  // [sync] write(!)
@@ -50,7 +53,8 @@ type webChat struct {
 	// Chat history: messages
 	msgi map[int64]int   // index[msg.id]
 	msgs []*chat.Message // ordinal
-
+	// Verified visitor identity; nil if anonymous
+	user *identity
 }
 
 // save given *chat.Message m to this *webChat c local history store
@@ -89,9 +93,14 @@ type originWildcard [2]string
 
 func (pttn originWildcard) match(origin string) bool {
 	prefix, suffix := pttn[0], pttn[1]
-	return len(origin) >= len(prefix)+len(suffix) &&
+	if !(len(origin) > len(prefix)+len(suffix) &&
 		strings.HasPrefix(origin, prefix) &&
-		strings.HasSuffix(origin, suffix)
+		strings.HasSuffix(origin, suffix)) {
+		return false
+	}
+	// Wildcard stands for the host (sub)domain label(s) only
+	// e.g.: https://*.example.com does NOT match https://evil.com?.example.com
+	return !strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:@?#\\ ")
 }
 
 // WebChatBot gateway provider
@@ -115,9 +124,26 @@ type WebChatBot struct {
 	MessageMaxSize int64
 	// MediaMaxSize allows the maximum file size to upload.
 	MediaMaxSize int64
+	// IdentitySecret to verify the visitor identity token with.
+	// The verified conversation follows the user across devices.
+	IdentitySecret []byte
+	// IdentityRequired rejects anonymous visitors
+	IdentityRequired bool
+	// Prechat form to be filled by the visitor before the conversation starts
+	Prechat *prechatForm
+	// Offline mode; visitors may leave a message only
+	Offline bool
+	// OnlineHours schedule; offline outside the hours.
+	// The bot's business hours by default
+	OnlineHours *bot.Schedule
+	// OfflineReplyVia gateway (email or SMS) ID
+	// to relay agent replies of the offline conversation
+	OfflineReplyVia int64
 	// Unexported: runtime chat(s) store
 	*sync.RWMutex
 	chat map[string]*webChat
+	// event streams by ID
+	streams map[string]*eventStream
 }
 
 // New initialize new agent.Profile service provider
@@ -137,23 +163,23 @@ func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
 			},
 			Subprotocols:      nil,
 			EnableCompression: false,
-			CheckOrigin: func(req *http.Request) bool {
-				return true // Default: NO check at all (!)
-			},
+			CheckOrigin:       sameOrigin, // Default: NO allow_origin specified
 			Error: func(rsp http.ResponseWriter, req *http.Request, code int, err error) {
 				// panic("not implemented")
 				if err == nil {
-					err = fmt.Errorf(http.StatusText(code))
+					err = errors.New(http.StatusText(code))
 				}
 				rsp.Header().Set("Sec-Websocket-Version", "13")
 				http.Error(rsp, err.Error() /*http.StatusText(code)*/, code) // err.Error(), code)
 
 				if err == nil {
-					agent.Log.Error("%d %s", code, http.StatusText(code),
+					agent.Log.Error(http.StatusText(code),
+						slog.Int("code", code),
 						slog.String("peer", webChatRemoteIP(req)),
 					)
 				} else {
-					agent.Log.Error("%d %s", code, http.StatusText(code),
+					agent.Log.Error(http.StatusText(code),
+						slog.Int("code", code),
 						slog.Any("error", err),
 						slog.String("peer", webChatRemoteIP(req)),
 					)
@@ -190,7 +216,7 @@ func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
 	if err != nil {
 		return nil, errs.BadRequest(
 			"chat.bot.webchat.updates.invalid",
-			err.Error(),
+			"%s", err.Error(),
 		)
 	}
 
@@ -323,12 +349,69 @@ func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
 	}
 
 	if s, ok := profile["captcha"]; ok && s != "" {
-		svhost.Captcha, err = NewRecaptchaHandler(s)
+		svhost.Captcha, err = NewCaptchaHandler(s)
 		if err != nil {
 			return nil, errors.Wrap(err, "[captcha_creation]: %s")
 		}
 	}
 
+	if s := profile["identity_secret"]; s != "" {
+		svhost.IdentitySecret = []byte(s)
+	}
+	if s := profile["identity_required"]; s != "" {
+		svhost.IdentityRequired, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.identity_required.invalid",
+				"webchat: identity_required: %v", err,
+			)
+		}
+		if svhost.IdentityRequired && len(svhost.IdentitySecret) == 0 {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.identity_secret.required",
+				"webchat: identity_secret required to verify visitors",
+			)
+		}
+	}
+
+	if s := profile["prechat_form"]; s != "" {
+		svhost.Prechat, err = newPrechatForm(s)
+		if err != nil {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.prechat_form.invalid",
+				"webchat: prechat_form: %v", err,
+			)
+		}
+	}
+
+	if s := profile["offline"]; s != "" {
+		svhost.Offline, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.offline.invalid",
+				"webchat: offline: %v", err,
+			)
+		}
+	}
+	if s := profile["online_hours"]; s != "" {
+		svhost.OnlineHours, err = bot.ParseSchedule(s)
+		if err != nil {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.online_hours.invalid",
+				"webchat: online_hours: %v", err,
+			)
+		}
+	}
+	if s := profile["offline_reply_via"]; s != "" {
+		svhost.OfflineReplyVia, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || svhost.OfflineReplyVia <= 0 {
+			return nil, errs.BadRequest(
+				"chat.bot.webchat.offline_reply_via.invalid",
+				"webchat: offline_reply_via: gateway id expected; %q", s,
+			)
+		}
+	}
+
 	// AllowOrigins is a list of origins a cross-domain request can be executed from.
 	// If the special "*" value is present in the list, all origins will be allowed.
 	// An origin may contain a wildcard (*) to replace 0 or more characters
@@ -339,12 +422,19 @@ func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
 	allowedOrigins := make([]originPattern, 0, len(allowOrigins))
 	for _, origin := range allowOrigins {
 		// Normalize
-		origin = strings.ToLower(origin)
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		origin = strings.TrimRight(origin, "/")
 		if origin == "*" {
 			// If "*" is present in the list, turn the whole list into a match all
 			allowedOrigins = append(allowedOrigins[:0], originAny(true))
 			break
 		} else if i := strings.IndexByte(origin, '*'); i >= 0 {
+			if strings.Count(origin, "*") > 1 {
+				return nil, errs.BadRequest(
+					"chat.bot.webchat.allow_origin.invalid",
+					"webchat: allow_origin: %q; only one wildcard allowed", origin,
+				)
+			}
 			// Split the origin in two: start and end string without the *
 			allowedOrigins = append(allowedOrigins, originWildcard{origin[0:i], origin[i+1:]})
 		} else if origin != "" {
@@ -373,9 +463,11 @@ func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
 	if state, ok := state.(*WebChatBot); ok && state != nil {
 		svhost.RWMutex = state.RWMutex
 		svhost.chat = state.chat
+		svhost.streams = state.streams
 	} else {
 		svhost.RWMutex = new(sync.RWMutex)
 		svhost.chat = make(map[string]*webChat, 4096)
+		svhost.streams = make(map[string]*eventStream)
 	}
 
 	// go bot.runtime(context.Background())
@@ -423,6 +515,14 @@ func (c *WebChatBot) SendNotify(ctx context.Context, notify *bot.Update) error {
 		if closed {
 			return nil
 		}
+		if isOfflineChannel(channel) {
+			// Visitor left a message; reply via alternate channel
+			switch message.Type {
+			case "text", "file":
+				return c.replyOffline(ctx, channel, message)
+			}
+			return nil // IGNORE: updates
+		}
 		defer channel.Close()
 		c.Log.Error("CHAT: Channel NOT connected; Force .Close(!)",
 			slog.String("chat-id", channel.ChatID),
@@ -622,7 +722,7 @@ func webChatDeviceID(req *http.Request) (id string, ok bool) {
 		deviceID = cookie.Value
 	}
 
-	if deviceID != "" {
+	if deviceID != "" && !strings.HasPrefix(deviceID, identityPrefix) {
 		// DETECTED
 		return deviceID, true
 	}
@@ -746,8 +846,20 @@ func (c *WebChatBot) uploadMultiMedia(rsp http.ResponseWriter, req *http.Request
 
 	defer req.Body.Close()
 
-	cookie, err := req.Cookie("cid")
-	if err != nil || cookie.Value == "" {
+	user, err := c.identify(req)
+	if err != nil {
+		respondError(rsp, errs.Unauthorized(
+			"chat.web.client.unauthorized",
+			"webchat: unauthorized; %v", err,
+		))
+		return // 401 Unauthorized (!)
+	}
+
+	deviceID, ok := webChatDeviceID(req)
+	if user != nil {
+		deviceID, ok = user.chatID(), true
+	}
+	if !ok {
 		// http.Error(rsp, "(401) Unauthorized", http.StatusUnauthorized)
 		respondError(rsp, errs.Unauthorized(
 			"chat.web.client.unauthorized",
@@ -756,7 +868,6 @@ func (c *WebChatBot) uploadMultiMedia(rsp http.ResponseWriter, req *http.Request
 		return // 401 Unauthorized (!)
 	}
 
-	deviceID := cookie.Value
 	// TODO: Find active room with User's (Device) cID
 	c.RWMutex.RLock() // +R
 	room, _ := c.chat[deviceID]
@@ -933,7 +1044,7 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 	// CORS: Origin
 	origin := req.Header.Get(hdrOrigin)
 	responseHeader := rsp.Header()
-	if !c.Websocket.CheckOrigin(req) {
+	if !c.CheckOrigin(req) {
 		// Sanitize from HTTP Gateway Main Handler
 		responseHeader.Del("Access-Control-Allow-Credentials")
 		responseHeader.Del("Access-Control-Allow-Methods")
@@ -952,6 +1063,9 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 		"X-XSRF-Token", // Axios frontend
 	)
 	responseHeader.Set("Access-Control-Allow-Origin", origin)
+	if values := responseHeader.Values("Vary"); !slices.Contains(values, "Origin") {
+		responseHeader.Add("Vary", "Origin")
+	}
 
 	if strings.HasSuffix(req.URL.Path, bot.CaptchaSuffix) {
 		if c.Captcha != nil && c.Captcha.Enabled() {
@@ -970,6 +1084,21 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 			return
 		}
 	}
+	// GET /prechat
+	if strings.HasSuffix(req.URL.Path, bot.PrechatSuffix) {
+		c.servePrechat(rsp, req)
+		return
+	}
+	// POST ?offline ; leave-a-message
+	if req.Method == http.MethodPost && req.URL.Query().Has(offlineParam) {
+		c.leaveMessage(rsp, req)
+		return
+	}
+	// POST ?stream= ; event stream request
+	if req.Method == http.MethodPost && req.URL.Query().Has(streamParam) {
+		c.postRequest(rsp, req)
+		return
+	}
 	// POST /media?filename=
 	if req.Method == http.MethodPost {
 		c.uploadMultiMedia(rsp, req)
@@ -977,7 +1106,8 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 	}
 
 	// GET /websocket
-	if !websocket.IsWebSocketUpgrade(req) {
+	events := isEventStream(req)
+	if !events && !websocket.IsWebSocketUpgrade(req) {
 		// TODO: handle other supported options here
 		// http.ServeFile(rsp, req, "~/webitel/chat/bot/webchat/webchat.html")
 		c.Websocket.Error(rsp, req, http.StatusBadRequest, nil)
@@ -985,9 +1115,49 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	room := c.openRoom(rsp, req)
+	if room == nil {
+		return // responded
+	}
+	// Server-Sent Events; fallback transport
+	if events {
+		c.serveEvents(rsp, req, room)
+		return
+	}
+
+	// UPGRADE: connection protocol !
+	conn, err := c.Websocket.Upgrade(rsp, req, responseHeader)
+
+	// NOTE: req released !
+	if err != nil {
+		c.Log.Error(err.Error(),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	c.join(room, conn)
+	go room.readPump(conn)
+}
+
+// openRoom authorizes the visitor of the request
+// and returns the chat room to join.
+// Returns nil if error has been responded.
+func (c *WebChatBot) openRoom(rsp http.ResponseWriter, req *http.Request) *webChat {
+
 	// Authorization
 	var room *webChat
+	user, err := c.identify(req)
+	if err != nil {
+		c.Websocket.Error(rsp, req, http.StatusUnauthorized, err)
+		return nil // (401) Unauthorized
+	}
 	deviceID, ok := webChatDeviceID(req)
+	if user != nil {
+		// Bind the conversation to the verified identity;
+		// NO device cookie required
+		deviceID, ok = user.chatID(), true
+	}
 	if !ok || deviceID == "" {
 		// // Definitely: creating NEW client !
 		// if !httpIsSecure(req) {
@@ -1013,17 +1183,9 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 
 	if room == nil {
 
-		endUser := &bot.Account{
-			ID:        0,
-			FirstName: "Web",
-			LastName:  "Chat",
-			Username:  "",
-			Channel:   c.String(),
-			Contact:   deviceID,
-		}
 		// Find -or- Create chat User (client) !
 		channel, err := c.Gateway.GetChannel(
-			context.TODO(), deviceID, endUser,
+			context.TODO(), deviceID, c.endUser(deviceID, user),
 		)
 
 		if err != nil {
@@ -1038,7 +1200,18 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 			}
 			// conn.Write(!)
 			http.Error(rsp, re.Detail, (int)(re.Code))
-			return // 503 Bad Gateway
+			return nil // 503 Bad Gateway
+		}
+		// Pre-chat form and visitor context
+		vars, err := c.visitorContext(req)
+		if err != nil {
+			if channel.IsNew() {
+				c.Websocket.Error(rsp, req, http.StatusBadRequest, err)
+				return nil // (400) Bad Request
+			}
+			// Conversation is running; form was submitted before
+		} else {
+			bindVariables(channel, vars)
 		}
 
 		room = &webChat{
@@ -1050,6 +1223,8 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 
 			msgi: make(map[int64]int, 32),
 			msgs: make([]*chat.Message, 0, 32),
+
+			user: user,
 		}
 
 		size := c.Websocket.WriteBufferSize
@@ -1060,47 +1235,61 @@ func (c *WebChatBot) WebHook(rsp http.ResponseWriter, req *http.Request) {
 	}
 	// Set-Cookie: cid=; IF not provided
 	if !ok {
-		// Proxy-Path:
-		cookiePath := "/"
-		if siteURL, err := url.Parse(c.Gateway.Internal.URL); err == nil {
-			cookiePath = siteURL.Path // Resolve path prefix from public URL
-		}
-		cookiePath = strings.TrimRight(cookiePath, "/") + req.URL.Path
-		// Set-Cookie:
-		cookie := &http.Cookie{
-			Name:  "cid",
-			Value: deviceID,   // unique client + device identifier
-			Path:  cookiePath, // req.URL.Path, // "/"+ c.Profile.UrlId, // TODO: prefix from NGINX proxy location
-			// Domain:     domain, // req.Header.Get("Host"),
-			Expires: cookieNeverExp, // 2147483648 (2^31)
-			// RawExpires: "",
-			MaxAge:   0,
-			Secure:   httpIsSecure(req), // req.URL.Schema == "https"
-			HttpOnly: true,
-			// Cross-origin ([site]: example.com <-> [chat]: webitel.com) Set-Cookie
-			// NOTE: https://developer.mozilla.org/de/docs/Web/HTTP/Headers/Set-Cookie/SameSite#none
-			SameSite: http.SameSiteNoneMode, // http.SameSiteLaxMode,
-			// Raw:        "",
-			// Unparsed:   nil,
-		}
-		if !cookie.Secure {
-			cookie.SameSite = http.SameSiteLaxMode
-		}
-		responseHeader.Add(hdrSetCookie, cookie.String())
+		c.setDeviceCookie(rsp, req, deviceID)
 	}
 
-	// UPGRADE: connection protocol !
-	conn, err := c.Websocket.Upgrade(rsp, req, responseHeader)
+	return room
+}
 
-	// NOTE: req released !
-	if err != nil {
-		c.Log.Error(err.Error(),
-			slog.Any("error", err),
-		)
-		return
+// endUser account of the visitor device;
+// verified identity (if any) names the account
+func (c *WebChatBot) endUser(deviceID string, user *identity) *bot.Account {
+	endUser := &bot.Account{
+		ID:        0,
+		FirstName: "Web",
+		LastName:  "Chat",
+		Username:  "",
+		Channel:   c.String(),
+		Contact:   deviceID,
+	}
+	if user != nil {
+		endUser.Contact = user.Subject
+		if user.Name != "" {
+			endUser.FirstName, endUser.LastName = util.ParseFullName(user.Name)
+		}
 	}
+	return endUser
+}
 
-	c.join(room, conn)
+// setDeviceCookie of the new visitor device ID
+func (c *WebChatBot) setDeviceCookie(rsp http.ResponseWriter, req *http.Request, deviceID string) {
+	// Proxy-Path:
+	cookiePath := "/"
+	if siteURL, err := url.Parse(c.Gateway.Internal.URL); err == nil {
+		cookiePath = siteURL.Path // Resolve path prefix from public URL
+	}
+	cookiePath = strings.TrimRight(cookiePath, "/") + req.URL.Path
+	// Set-Cookie:
+	cookie := &http.Cookie{
+		Name:  "cid",
+		Value: deviceID,   // unique client + device identifier
+		Path:  cookiePath, // req.URL.Path, // "/"+ c.Profile.UrlId, // TODO: prefix from NGINX proxy location
+		// Domain:     domain, // req.Header.Get("Host"),
+		Expires: cookieNeverExp, // 2147483648 (2^31)
+		// RawExpires: "",
+		MaxAge:   0,
+		Secure:   httpIsSecure(req), // req.URL.Schema == "https"
+		HttpOnly: true,
+		// Cross-origin ([site]: example.com <-> [chat]: webitel.com) Set-Cookie
+		// NOTE: https://developer.mozilla.org/de/docs/Web/HTTP/Headers/Set-Cookie/SameSite#none
+		SameSite: http.SameSiteNoneMode, // http.SameSiteLaxMode,
+		// Raw:        "",
+		// Unparsed:   nil,
+	}
+	if !cookie.Secure {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	rsp.Header().Add(hdrSetCookie, cookie.String())
 }
 
 // // routine opened c.chat channel(s); read messages ...
@@ -1125,7 +1314,7 @@ type webChatInfo struct {
 	Msgs []*chat.Message `json:"msgs,omitempty"`
 }
 
-func (c *WebChatBot) join(client *webChat, conn *websocket.Conn) {
+func (c *WebChatBot) join(client *webChat, conn peerConn) {
 
 	chatID := client.ChatID
 	primary := len(client.conn) == 0
@@ -1197,6 +1386,8 @@ func (c *WebChatBot) join(client *webChat, conn *websocket.Conn) {
 				Message: &chat.Message{
 					Type: "text",
 					Text: "/start",
+					// Verified identity (if any)
+					Variables: client.user.variables(),
 				},
 			}
 
@@ -1220,8 +1411,6 @@ func (c *WebChatBot) join(client *webChat, conn *websocket.Conn) {
 	} else { // secondary ...
 
 	}
-
-	go client.readPump(conn)
 }
 
 // WebChatRequest message envelope
@@ -1241,41 +1430,47 @@ type webChatResponse struct {
 }
 
 // single websocket [conn]ection READer routine
-func (c *webChat) readPump(conn *websocket.Conn) {
-	defer func() {
-		// // c.RWMutex.Lock() //   +RW
-		select { // sync remove operation
-		case c.send <- func() {
-			// [sync] remove this conn
-			var ok bool
-			for i, this := range c.conn {
-				if ok = (this == conn); ok {
-					c.conn = append(c.conn[:i], c.conn[i+1:]...)
-					break
-				}
+// leave the room; [sync] remove given conn
+func (c *webChat) leave(conn peerConn) {
+	// // c.RWMutex.Lock() //   +RW
+	select { // sync remove operation
+	case c.send <- func() {
+		// [sync] remove this conn
+		var ok bool
+		for i, this := range c.conn {
+			if ok = (this == conn); ok {
+				c.conn = append(c.conn[:i], c.conn[i+1:]...)
+				break
 			}
-			// if ok {
-			// 	c.Log.Info().
-			// 		Str("ws", conn.RemoteAddr().String()).
-			// 		Msg("[WS] >>> READ.Close(!) <<< OK")
-			// } else {
-			// 	c.Log.Warn().
-			// 		Str("ws", conn.RemoteAddr().String()).
-			// 		Msg("[WS] >>> READ.Close(!) <<< NOT FOUND")
-			// }
-			// // NOTE: DO NOT c.closed = true due to
-			// // page reloaded conn may return !
-		}:
-		default:
-			c.Log.Error("[WS] >>> READ.Close(!) <<< OMITTED",
-				slog.String("ws", conn.RemoteAddr().String()),
-			)
-			// FIXME: Expect to be closed !
-			// How to check it's NOT but full ?
 		}
+		// if ok {
+		// 	c.Log.Info().
+		// 		Str("ws", conn.RemoteAddr().String()).
+		// 		Msg("[WS] >>> READ.Close(!) <<< OK")
+		// } else {
+		// 	c.Log.Warn().
+		// 		Str("ws", conn.RemoteAddr().String()).
+		// 		Msg("[WS] >>> READ.Close(!) <<< NOT FOUND")
+		// }
+		// // NOTE: DO NOT c.closed = true due to
+		// // page reloaded conn may return !
+	}:
+	default:
+		c.Log.Error("[WS] >>> READ.Close(!) <<< OMITTED",
+			slog.String("ws", conn.RemoteAddr().String()),
+		)
+		// FIXME: Expect to be closed !
+		// How to check it's NOT but full ?
+	}
 
-		// c.RWMutex.Unlock() // -RW
-		_ = conn.Close() // Undelaying TCP
+	// c.RWMutex.Unlock() // -RW
+	_ = conn.Close() // Undelaying TCP
+}
+
+// single websocket [conn]ection READer routine
+func (c *webChat) readPump(conn *websocket.Conn) {
+	defer func() {
+		c.leave(conn)
 		// if err := conn.Close(); err != nil {
 		// 	c.Log.Err(err).
 		// 		Str("ws", conn.RemoteAddr().String()).
@@ -1331,112 +1526,121 @@ func (c *webChat) readPump(conn *websocket.Conn) {
 			}
 			return // runtime
 		}
-		// validate request
-		var (
-			msg *chat.Message
-			req webChatRequest
-			res webChatResponse
-		)
+		if !c.handle(conn, data) {
+			return
+		}
+	}
+}
 
-		err = json.Unmarshal(data, &req)
+// handle single request frame data received from conn;
+// false if the room is closed
+func (c *webChat) handle(conn peerConn, data []byte) bool {
+	// validate request
+	var (
+		msg *chat.Message
+		req webChatRequest
+		res webChatResponse
+	)
 
-		// if err == nil && request.ID == nil || len(*request.ID) == 0 {
-		// 	// SEND: {"error": "request.id required but missing"}
-		// 	err = fmt.Errorf("request.id required but missing")
-		// 	break // loop: readPump
-		// }
+	err := json.Unmarshal(data, &req)
 
-		// Respond TO Request ...
-		res.Id = req.Id
+	// if err == nil && request.ID == nil || len(*request.ID) == 0 {
+	// 	// SEND: {"error": "request.id required but missing"}
+	// 	err = fmt.Errorf("request.id required but missing")
+	// 	break // loop: readPump
+	// }
 
-		switch strings.ToLower(req.Method) {
-		case "send", "": // default: "send"
-			if msg = req.Message; msg == nil {
-				err = fmt.Errorf("send: message is missing")
-			}
-		default:
-			// SEND: {"error": "method not allowed"}
-			err = fmt.Errorf("method=%q not allowed", req.Method)
-		}
+	// Respond TO Request ...
+	res.Id = req.Id
 
-		// message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
-		// // c.hub.broadcast <- message
-		if err == nil {
-			err = c.Bot.Read(
-				context.TODO(),
-				&bot.Update{
-					ID:    0,
-					Chat:  c.Channel,
-					User:  &c.Account,
-					Title: "",
-					// Event:   msg.GetType(), // "text"
-					Message: msg,
-				},
-			)
+	switch strings.ToLower(req.Method) {
+	case "send", "": // default: "send"
+		if msg = req.Message; msg == nil {
+			err = fmt.Errorf("send: message is missing")
 		}
+	default:
+		// SEND: {"error": "method not allowed"}
+		err = fmt.Errorf("method=%q not allowed", req.Method)
+	}
 
-		// if err != nil {
-		// 	// c.Log.Err(err).Msg("Failed to deliver message")
-		// }
-		if err != nil {
-			res.Error = err.Error()
-			c.Log.Error("Request Error",
-				slog.Any("error", err),
-				slog.String("ws", conn.RemoteAddr().String()),
-			)
-			// panic(err)
-			// TODO: send reply to originator channel only !
-		} else {
-			res.Message = msg
-		}
-		// encoded result message
-		respData, _ := c.encodeJSON(res)
-		// respData, err := json.Marshal(res)
-		// if err != nil {
-		// 	res.Error = err.Error()
-		// 	res.Message = nil
-		// 	respData, _ = json.Marshal(res)
-		// }
+	// message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
+	// // c.hub.broadcast <- message
+	if err == nil {
+		err = c.Bot.Read(
+			context.TODO(),
+			&bot.Update{
+				ID:    0,
+				Chat:  c.Channel,
+				User:  &c.Account,
+				Title: "",
+				// Event:   msg.GetType(), // "text"
+				Message: msg,
+			},
+		)
+	}
 
-		// broadcast to sibling connection(s)
-		broadcast := func() {
+	// if err != nil {
+	// 	// c.Log.Err(err).Msg("Failed to deliver message")
+	// }
+	if err != nil {
+		res.Error = err.Error()
+		c.Log.Error("Request Error",
+			slog.Any("error", err),
+			slog.String("ws", conn.RemoteAddr().String()),
+		)
+		// panic(err)
+		// TODO: send reply to originator channel only !
+	} else {
+		res.Message = msg
+	}
+	// encoded result message
+	respData, _ := c.encodeJSON(res)
+	// respData, err := json.Marshal(res)
+	// if err != nil {
+	// 	res.Error = err.Error()
+	// 	res.Message = nil
+	// 	respData, _ = json.Marshal(res)
+	// }
 
-			if res.Error != "" {
-				// Just respond with NO broadcast
-				_ = c.sendFrame(conn, websocket.TextMessage, respData)
-				return
-			}
-			// Push history ...
-			c.pushMessage(msg)
-			// Send response ...
-			_ = c.sendFrame(conn, websocket.TextMessage, respData)
+	// broadcast to sibling connection(s)
+	broadcast := func() {
 
-			// encoded notify message
-			var noteData []byte
-			for i := len(c.conn) - 1; i >= 0; i-- {
-				peer := c.conn[i]
-				if peer == conn {
-					// c.sendFrame(conn, websocket.TextMessage, resultData)
-					continue // self
-				}
-				if len(noteData) == 0 {
-					update := webChatResponse{
-						Message: msg,
-					}
-					// noteData, _ = json.Marshal(update)
-					noteData, _ = c.encodeJSON(update)
+		if res.Error != "" {
+			// Just respond with NO broadcast
+			_ = c.sendFrame(conn, websocket.TextMessage, respData)
+			return
+		}
+		// Push history ...
+		c.pushMessage(msg)
+		// Send response ...
+		_ = c.sendFrame(conn, websocket.TextMessage, respData)
+
+		// encoded notify message
+		var noteData []byte
+		for i := len(c.conn) - 1; i >= 0; i-- {
+			peer := c.conn[i]
+			if peer == conn {
+				// c.sendFrame(conn, websocket.TextMessage, resultData)
+				continue // self
+			}
+			if len(noteData) == 0 {
+				update := webChatResponse{
+					Message: msg,
 				}
-				_ = c.sendFrame(peer, websocket.TextMessage, noteData)
+				// noteData, _ = json.Marshal(update)
+				noteData, _ = c.encodeJSON(update)
 			}
+			_ = c.sendFrame(peer, websocket.TextMessage, noteData)
 		}
+	}
 
-		select {
-		case c.send <- broadcast:
-		default:
-			c.Log.Warn("Broadcast to closed(c.send) channel")
-			return
-		}
+	select {
+	case c.send <- broadcast:
+	default:
+		c.Log.Warn("Broadcast to closed(c.send) channel")
+		return false
 	}
+	return true
 }
 
 // webChat room WRITEr routine (multiplexor)
@@ -1460,7 +1664,8 @@ func (c *webChat) writePump() {
 		}
 		c.Bot.RWMutex.Unlock() // -RW
 		// Ensure service closed this chat !
-		if c.Channel.Closed == 0 {
+		// NOTE: offline conversation awaits the agent's reply
+		if c.Channel.Closed == 0 && !isOfflineChannel(c.Channel) {
 			_ = c.Channel.Close()
 		}
 		if found {
@@ -1545,7 +1750,7 @@ func (c *webChat) writePump() {
 }
 
 // sendFrame writes given frame message data to single conn
-func (c *webChat) sendFrame(conn *websocket.Conn, typeof int, data []byte) (err error) {
+func (c *webChat) sendFrame(conn peerConn, typeof int, data []byte) (err error) {
 
 	defer func() {
 		if err != nil {
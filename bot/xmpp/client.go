@@ -0,0 +1,365 @@
+package xmpp
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errNotConnected = errors.New("xmpp: not connected")
+	errClosed       = errors.New("xmpp: session closed")
+)
+
+// Client of the XMPP server; client or component session.
+// Keeps the session alive with XEP-0199 pings
+// and reconnects on failure.
+type Client struct {
+	Config
+	// Ping interval; zero disables
+	Ping time.Duration
+	Log  *slog.Logger
+	// Trace the raw stanzas sent and received
+	Trace bool
+	// OnStanza handles message or presence stanza received;
+	// stanzas are handled in order, apart from the read loop
+	OnStanza func(s *Stanza)
+
+	mx   sync.Mutex
+	sess *session
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start connecting the server in background
+func (c *Client) Start() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.stop != nil {
+		return // running
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(c.stop, c.done)
+}
+
+// Close the session and stops reconnecting
+func (c *Client) Close() {
+	c.mx.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mx.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (c *Client) session() *session {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.sess
+}
+
+func (c *Client) setSession(sess *session) {
+	c.mx.Lock()
+	c.sess = sess
+	c.mx.Unlock()
+}
+
+// JID of the session bound; configured if not connected
+func (c *Client) JID() JID {
+	if sess := c.session(); sess != nil {
+		return sess.jid
+	}
+	return c.Config.JID
+}
+
+func (c *Client) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	backoff := time.Second
+	for {
+		sess, err := c.connect()
+		if err != nil {
+			c.Log.Error("xmpp/connect",
+				slog.String("host", c.Host),
+				slog.String("jid", c.Config.JID.String()),
+				slog.Any("error", err),
+			)
+			select {
+			case <-stop:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > time.Minute {
+				backoff = time.Minute
+			}
+			continue
+		}
+		backoff = time.Second
+		c.setSession(sess)
+		c.Log.Info("xmpp/connect",
+			slog.String("host", c.Host),
+			slog.String("jid", sess.jid.String()),
+		)
+		select {
+		case <-stop:
+			c.setSession(nil)
+			sess.close(errClosed)
+			return
+		case <-sess.closed:
+			c.setSession(nil)
+			c.Log.Warn("xmpp/session",
+				slog.String("host", c.Host),
+				slog.Any("error", sess.err),
+			)
+		}
+	}
+}
+
+func (c *Client) connect() (*session, error) {
+	var trace func(dir, data string)
+	if c.Trace {
+		trace = func(dir, data string) {
+			c.Log.Debug("xmpp/"+dir, slog.String("xml", data))
+		}
+	}
+	conn, jid, err := dial(&c.Config, trace)
+	if err != nil {
+		return nil, err
+	}
+	sess := &session{
+		stream:  conn,
+		jid:     jid,
+		timeout: c.Timeout,
+		handle:  c.OnStanza,
+		pending: make(map[string]chan *Stanza),
+		inbox:   make(chan *Stanza, 64),
+		closed:  make(chan struct{}),
+	}
+	go sess.readLoop()
+	go sess.handleLoop()
+	if c.Component {
+		// stanzas sent on behalf of
+		sess.from = jid.String()
+	} else {
+		// Initial presence; available
+		if err = sess.stream.writeRaw("<presence/>"); err != nil {
+			sess.close(err)
+			return nil, err
+		}
+	}
+	if c.Ping > 0 {
+		go sess.pingLoop(c.Ping)
+	}
+	return sess, nil
+}
+
+// Send the stanza; message or presence
+func (c *Client) Send(s *Stanza) error {
+	sess := c.session()
+	if sess == nil {
+		return errNotConnected
+	}
+	if s.From == "" {
+		s.From = sess.from
+	}
+	return sess.send(s)
+}
+
+// Request the iq of the payload; returns the result stanza
+func (c *Client) Request(ctx context.Context, typ, to string, payload any) (*Stanza, error) {
+	sess := c.session()
+	if sess == nil {
+		return nil, errNotConnected
+	}
+	return sess.request(ctx, typ, to, payload)
+}
+
+// session of the negotiated stream
+type session struct {
+	stream  *stream
+	jid     JID
+	from    string // component: the stanzas sender
+	seq     uint32
+	timeout time.Duration
+	handle  func(s *Stanza)
+
+	mx sync.Mutex
+	// iq results awaiting by ID
+	pending map[string]chan *Stanza
+	// message and presence stanzas to be handled in order
+	inbox chan *Stanza
+
+	once   sync.Once
+	err    error
+	closed chan struct{}
+}
+
+func (s *session) close(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.closed)
+		s.stream.close()
+	})
+}
+
+func (s *session) send(v *Stanza) error {
+	if err := s.stream.write(v); err != nil {
+		s.close(err)
+		return err
+	}
+	return nil
+}
+
+// iq stanza of the request
+type iq struct {
+	XMLName xml.Name `xml:"iq"`
+	ID      string   `xml:"id,attr"`
+	Type    string   `xml:"type,attr"`
+	From    string   `xml:"from,attr,omitempty"`
+	To      string   `xml:"to,attr,omitempty"`
+	Payload any
+}
+
+// request sends iq and waits for the result
+func (s *session) request(ctx context.Context, typ, to string, payload any) (*Stanza, error) {
+	id := "q" + strconv.FormatUint(uint64(atomic.AddUint32(&s.seq, 1)), 10)
+	wait := make(chan *Stanza, 1)
+	s.mx.Lock()
+	s.pending[id] = wait
+	s.mx.Unlock()
+	defer func() {
+		s.mx.Lock()
+		delete(s.pending, id)
+		s.mx.Unlock()
+	}()
+
+	err := s.stream.write(&iq{ID: id, Type: typ, From: s.from, To: to, Payload: payload})
+	if err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	select {
+	case res := <-wait:
+		if res.Type == "error" {
+			if res.Error != nil {
+				return res, res.Error
+			}
+			return res, &StanzaError{Type: "cancel"}
+		}
+		return res, nil
+	case <-s.closed:
+		return nil, errClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(timeout):
+		return nil, errors.New("xmpp: iq " + id + " timeout")
+	}
+}
+
+func (s *session) readLoop() {
+	for {
+		var v Stanza
+		_, err := s.stream.next(&v)
+		if err != nil {
+			s.close(err)
+			return
+		}
+		switch v.XMLName.Local {
+		case "iq":
+			switch v.Type {
+			case "result", "error":
+				s.mx.Lock()
+				wait := s.pending[v.ID]
+				s.mx.Unlock()
+				if wait != nil {
+					wait <- &v
+				}
+			case "get", "set":
+				err = s.reply(&v)
+			}
+		case "message", "presence":
+			select {
+			case s.inbox <- &v:
+			case <-s.closed:
+				return
+			}
+		}
+		if err != nil {
+			s.close(err)
+			return
+		}
+	}
+}
+
+// reply to the iq request; ping supported only
+func (s *session) reply(req *Stanza) error {
+	res := &Stanza{
+		XMLName: xml.Name{Local: "iq"},
+		ID:      req.ID,
+		Type:    "result",
+		From:    req.To,
+		To:      req.From,
+	}
+	if req.payload(nsPing) == nil {
+		res.Type = "error"
+		res.Error = &StanzaError{
+			Type:      "cancel",
+			Condition: []Element{{XMLName: xml.Name{Space: nsStanzas, Local: "service-unavailable"}}},
+		}
+	}
+	return s.stream.write(res)
+}
+
+// handleLoop handles message and presence stanzas
+// apart from the readLoop so the handler is free
+// to send requests within the session
+func (s *session) handleLoop() {
+	for {
+		var v *Stanza
+		select {
+		case <-s.closed:
+			return
+		case v = <-s.inbox:
+		}
+		if s.handle != nil {
+			s.handle(v)
+		}
+	}
+}
+
+// ping request payload; XEP-0199
+type ping struct {
+	XMLName xml.Name `xml:"urn:xmpp:ping ping"`
+}
+
+func (s *session) pingLoop(every time.Duration) {
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-tick.C:
+		}
+		_, err := s.request(context.Background(), "get", s.jid.Domain, &ping{})
+		var re *StanzaError
+		if err != nil && !errors.As(err, &re) {
+			// NOTE: stanza error is the response
+			s.close(err)
+			return
+		}
+	}
+}
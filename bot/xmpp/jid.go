@@ -0,0 +1,51 @@
+package xmpp
+
+import (
+	"errors"
+	"strings"
+)
+
+// JID is the XMPP address: [local@]domain[/resource]
+type JID struct {
+	Local    string
+	Domain   string
+	Resource string
+}
+
+// ParseJID address; localpart and domain are lowercased
+func ParseJID(s string) (JID, error) {
+	var jid JID
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		s, jid.Resource = s[:i], s[i+1:]
+		if jid.Resource == "" {
+			return jid, errors.New("jid: empty resource")
+		}
+	}
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		jid.Local, s = strings.ToLower(s[:i]), s[i+1:]
+		if jid.Local == "" {
+			return jid, errors.New("jid: empty localpart")
+		}
+	}
+	jid.Domain = strings.TrimSuffix(strings.ToLower(s), ".")
+	if jid.Domain == "" {
+		return jid, errors.New("jid: empty domain")
+	}
+	return jid, nil
+}
+
+// Bare address: [local@]domain
+func (j JID) Bare() string {
+	if j.Local == "" {
+		return j.Domain
+	}
+	return j.Local + "@" + j.Domain
+}
+
+func (j JID) String() string {
+	if j.Resource == "" {
+		return j.Bare()
+	}
+	return j.Bare() + "/" + j.Resource
+}
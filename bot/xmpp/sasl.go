@@ -0,0 +1,104 @@
+package xmpp
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// scramSHA1 client of the SASL SCRAM-SHA-1 mechanism; RFC 5802
+// No channel binding; username and password are NOT SASLprep-ed
+type scramSHA1 struct {
+	user, password string
+	nonce          string
+	firstBare      string
+	serverSign     []byte
+	verified       bool
+}
+
+// newScram client; random nonce if empty
+func newScram(user, password, nonce string) *scramSHA1 {
+	if nonce == "" {
+		data := make([]byte, 18)
+		_, _ = rand.Read(data)
+		nonce = base64.RawStdEncoding.EncodeToString(data)
+	}
+	return &scramSHA1{user: user, password: password, nonce: nonce}
+}
+
+// First client message
+func (c *scramSHA1) First() string {
+	user := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(c.user)
+	c.firstBare = "n=" + user + ",r=" + c.nonce
+	return "n,," + c.firstBare
+}
+
+// Next client final message of the server first message (challenge)
+func (c *scramSHA1) Next(challenge string) (string, error) {
+	var (
+		nonce, salt string
+		iter        int
+	)
+	for _, attr := range strings.Split(challenge, ",") {
+		key, value, _ := strings.Cut(attr, "=")
+		switch key {
+		case "r":
+			nonce = value
+		case "s":
+			salt = value
+		case "i":
+			iter, _ = strconv.Atoi(value)
+		case "e":
+			return "", errors.New("xmpp: SCRAM: " + value)
+		}
+	}
+	if !strings.HasPrefix(nonce, c.nonce) || len(nonce) == len(c.nonce) {
+		return "", errors.New("xmpp: SCRAM: invalid server nonce")
+	}
+	saltData, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil || iter < 1 {
+		return "", errors.New("xmpp: SCRAM: invalid server challenge")
+	}
+
+	salted, err := pbkdf2.Key(sha1.New, c.password, saltData, iter, sha1.Size)
+	if err != nil {
+		return "", err
+	}
+	clientKey := hmacSHA1(salted, "Client Key")
+	storedKey := sha1.Sum(clientKey)
+	serverKey := hmacSHA1(salted, "Server Key")
+
+	final := "c=biws,r=" + nonce // biws: base64("n,,")
+	authMessage := c.firstBare + "," + challenge + "," + final
+	clientSign := hmacSHA1(storedKey[:], authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSign[i]
+	}
+	c.serverSign = hmacSHA1(serverKey, authMessage)
+	return final + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// Verify the server final message
+func (c *scramSHA1) Verify(data string) error {
+	sign, ok := strings.CutPrefix(data, "v=")
+	if !ok || c.serverSign == nil {
+		return errors.New("xmpp: SCRAM: server signature missing")
+	}
+	if !hmac.Equal([]byte(sign), []byte(base64.StdEncoding.EncodeToString(c.serverSign))) {
+		return errors.New("xmpp: SCRAM: invalid server signature")
+	}
+	c.verified = true
+	return nil
+}
+
+func hmacSHA1(key []byte, data string) []byte {
+	h := hmac.New(sha1.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,514 @@
+package xmpp
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// XML namespaces
+const (
+	nsStream     = "http://etherx.jabber.org/streams"
+	nsClient     = "jabber:client"
+	nsComponent  = "jabber:component:accept"
+	nsTLS        = "urn:ietf:params:xml:ns:xmpp-tls"
+	nsSASL       = "urn:ietf:params:xml:ns:xmpp-sasl"
+	nsBind       = "urn:ietf:params:xml:ns:xmpp-bind"
+	nsSession    = "urn:ietf:params:xml:ns:xmpp-session"
+	nsStanzas    = "urn:ietf:params:xml:ns:xmpp-stanzas"
+	nsPing       = "urn:xmpp:ping"
+	nsChatStates = "http://jabber.org/protocol/chatstates"
+	nsNick       = "http://jabber.org/protocol/nick"
+	nsOOB        = "jabber:x:oob"
+	nsDiscoInfo  = "http://jabber.org/protocol/disco#info"
+	nsDiscoItems = "http://jabber.org/protocol/disco#items"
+	nsUpload     = "urn:xmpp:http:upload:0"
+)
+
+// Connection security
+const (
+	tlsStartTLS = "starttls" // upgrade; required
+	tlsDirect   = "direct"   // implicit TLS, e.g.: :5223
+	tlsNone     = "none"     // plain; trusted (local) networks only
+)
+
+// Stanza of the stream; message, presence or iq
+type Stanza struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	From    string `xml:"from,attr,omitempty"`
+	To      string `xml:"to,attr,omitempty"`
+
+	Body  string       `xml:"body,omitempty"`
+	Nick  string       `xml:"http://jabber.org/protocol/nick nick,omitempty"`
+	OOB   []OOB        `xml:"jabber:x:oob x,omitempty"`
+	Error *StanzaError `xml:"error,omitempty"`
+	// Child elements (payload) not listed above, e.g.: chat state
+	Any []Element `xml:",any"`
+}
+
+// Element of the stanza's payload
+type Element struct {
+	XMLName xml.Name
+	Inner   []byte `xml:",innerxml"`
+}
+
+// Decode child elements of the element into v
+func (e *Element) Decode(v any) error {
+	data := make([]byte, 0, len(e.Inner)+7)
+	data = append(data, "<e>"...)
+	data = append(data, e.Inner...)
+	data = append(data, "</e>"...)
+	return xml.Unmarshal(data, v)
+}
+
+// payload element of the namespace; nil if none
+func (s *Stanza) payload(space string) *Element {
+	for i := range s.Any {
+		if s.Any[i].XMLName.Space == space {
+			return &s.Any[i]
+		}
+	}
+	return nil
+}
+
+// OOB is the out of band data (XEP-0066); file URL
+type OOB struct {
+	URL  string `xml:"url"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+// StanzaError of the stanza type="error"
+type StanzaError struct {
+	Type      string    `xml:"type,attr"`
+	Condition []Element `xml:",any"`
+	Text      string    `xml:"urn:ietf:params:xml:ns:xmpp-stanzas text,omitempty"`
+}
+
+func (e *StanzaError) Error() string {
+	cond := "undefined-condition"
+	if len(e.Condition) != 0 {
+		cond = e.Condition[0].XMLName.Local
+	}
+	if e.Text != "" {
+		return fmt.Sprintf("xmpp: %s (%s): %s", cond, e.Type, e.Text)
+	}
+	return fmt.Sprintf("xmpp: %s (%s)", cond, e.Type)
+}
+
+// StreamError of the stream; fatal
+type StreamError struct {
+	Condition string
+	Text      string
+}
+
+func (e *StreamError) Error() string {
+	if e.Text != "" {
+		return "xmpp: stream error: " + e.Condition + ": " + e.Text
+	}
+	return "xmpp: stream error: " + e.Condition
+}
+
+var errStreamClosed = errors.New("xmpp: stream closed")
+
+// features of the stream
+type features struct {
+	StartTLS *struct {
+		Required *struct{} `xml:"required"`
+	} `xml:"urn:ietf:params:xml:ns:xmpp-tls starttls"`
+	Mechanisms []string  `xml:"urn:ietf:params:xml:ns:xmpp-sasl mechanisms>mechanism"`
+	Bind       *struct{} `xml:"urn:ietf:params:xml:ns:xmpp-bind bind"`
+	Session    *struct {
+		Optional *struct{} `xml:"optional"`
+	} `xml:"urn:ietf:params:xml:ns:xmpp-session session"`
+}
+
+// saslResponse of the server; challenge, success or failure
+type saslResponse struct {
+	XMLName   xml.Name
+	Data      string     `xml:",chardata"`
+	Condition []xml.Name `xml:",any"`
+}
+
+// Config of the stream
+type Config struct {
+	JID       JID    // bot's address; client: user@domain[/resource]; component: domain
+	Password  string // client password or component secret
+	Host      string // host:port to connect to
+	TLS       string // starttls | direct | none
+	TLSConfig *tls.Config
+	Component bool // XEP-0114 external component
+	Timeout   time.Duration
+}
+
+// stream is the XML stream of the connection
+type stream struct {
+	conn net.Conn
+	dec  *xml.Decoder
+	wmx  sync.Mutex
+	// stream header ID
+	id string
+	// trace the raw stanzas, if not nil
+	trace func(dir, data string)
+}
+
+// dial the server and negotiate the stream
+func dial(conf *Config, trace func(dir, data string)) (*stream, JID, error) {
+
+	var (
+		err  error
+		conn net.Conn
+		jid  = conf.JID
+	)
+	dialer := &net.Dialer{Timeout: conf.Timeout}
+	tlsConfig := conf.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = jid.Domain
+	}
+	if conf.TLS == tlsDirect {
+		conn, err = tls.DialWithDialer(dialer, "tcp", conf.Host, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", conf.Host)
+	}
+	if err != nil {
+		return nil, jid, err
+	}
+
+	s := &stream{conn: conn, trace: trace}
+	if conf.Timeout > 0 {
+		// negotiation deadline
+		_ = conn.SetDeadline(time.Now().Add(conf.Timeout))
+	}
+	if conf.Component {
+		err = s.handshake(conf)
+	} else {
+		jid, err = s.login(conf, tlsConfig)
+	}
+	if err != nil {
+		s.conn.Close()
+		return nil, jid, err
+	}
+	_ = s.conn.SetDeadline(time.Time{})
+	return s, jid, nil
+}
+
+// open the stream and read the server's stream header
+func (s *stream) open(ns, to string) error {
+	s.dec = xml.NewDecoder(bufio.NewReader(s.conn))
+	err := s.writeRaw(fmt.Sprintf(
+		"<?xml version='1.0'?><stream:stream xmlns='%s' xmlns:stream='%s' to='%s' version='1.0'>",
+		ns, nsStream, escape(to),
+	))
+	if err != nil {
+		return err
+	}
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Space != nsStream || start.Name.Local != "stream" {
+				return fmt.Errorf("xmpp: unexpected <%s> stream header", start.Name.Local)
+			}
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "id" {
+					s.id = attr.Value
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// next element of the stream into v; stream errors are returned
+func (s *stream) next(v any) (xml.StartElement, error) {
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		switch e := token.(type) {
+		case xml.EndElement:
+			// </stream:stream>
+			return xml.StartElement{}, errStreamClosed
+		case xml.StartElement:
+			if e.Name.Space == nsStream && e.Name.Local == "error" {
+				var re struct {
+					Any  []xml.Name `xml:",any"`
+					Text string     `xml:"urn:ietf:params:xml:ns:xmpp-streams text"`
+				}
+				_ = s.dec.DecodeElement(&re, &e)
+				se := &StreamError{Condition: "undefined-condition", Text: re.Text}
+				for _, name := range re.Any {
+					if name.Local != "text" {
+						se.Condition = name.Local
+						break
+					}
+				}
+				return e, se
+			}
+			if v == nil {
+				return e, s.dec.Skip()
+			}
+			if err = s.dec.DecodeElement(v, &e); err == nil && s.trace != nil {
+				data, _ := xml.Marshal(v)
+				s.trace("recv", string(data))
+			}
+			return e, err
+		}
+	}
+}
+
+// login as the client: STARTTLS, SASL, bind
+func (s *stream) login(conf *Config, tlsConfig *tls.Config) (JID, error) {
+
+	jid := conf.JID
+	if err := s.open(nsClient, jid.Domain); err != nil {
+		return jid, err
+	}
+	var feat features
+	if _, err := s.next(&feat); err != nil {
+		return jid, err
+	}
+
+	if conf.TLS == tlsStartTLS {
+		if feat.StartTLS == nil {
+			return jid, errors.New("xmpp: server does not offer STARTTLS")
+		}
+		if err := s.writeRaw("<starttls xmlns='" + nsTLS + "'/>"); err != nil {
+			return jid, err
+		}
+		start, err := s.next(nil)
+		if err != nil {
+			return jid, err
+		}
+		if start.Name.Local != "proceed" {
+			return jid, errors.New("xmpp: STARTTLS failure")
+		}
+		conn := tls.Client(s.conn, tlsConfig)
+		if err = conn.Handshake(); err != nil {
+			return jid, err
+		}
+		s.conn = conn
+		if err = s.open(nsClient, jid.Domain); err != nil {
+			return jid, err
+		}
+		feat = features{}
+		if _, err = s.next(&feat); err != nil {
+			return jid, err
+		}
+	}
+
+	if err := s.authenticate(jid.Local, conf.Password, feat.Mechanisms); err != nil {
+		return jid, err
+	}
+	if err := s.open(nsClient, jid.Domain); err != nil {
+		return jid, err
+	}
+	feat = features{}
+	if _, err := s.next(&feat); err != nil {
+		return jid, err
+	}
+	if feat.Bind == nil {
+		return jid, errors.New("xmpp: server does not offer resource binding")
+	}
+
+	// Bind resource
+	var bind struct {
+		JID string `xml:"jid"`
+	}
+	payload := "<bind xmlns='" + nsBind + "'>"
+	if jid.Resource != "" {
+		payload += "<resource>" + escape(jid.Resource) + "</resource>"
+	}
+	payload += "</bind>"
+	if err := s.iq("bind", "set", "", payload, &bind); err != nil {
+		return jid, err
+	}
+	bound, err := ParseJID(bind.JID)
+	if err != nil {
+		return jid, fmt.Errorf("xmpp: bind: %v", err)
+	}
+	// Legacy session establishment; RFC 3921
+	if feat.Session != nil && feat.Session.Optional == nil {
+		if err = s.iq("session", "set", "", "<session xmlns='"+nsSession+"'/>", nil); err != nil {
+			return bound, err
+		}
+	}
+	return bound, nil
+}
+
+// iq request of the negotiation phase; no other stanzas expected
+func (s *stream) iq(id, typ, to, payload string, res any) error {
+	head := "<iq id='" + id + "' type='" + typ + "'"
+	if to != "" {
+		head += " to='" + escape(to) + "'"
+	}
+	if err := s.writeRaw(head + ">" + payload + "</iq>"); err != nil {
+		return err
+	}
+	var iq Stanza
+	if _, err := s.next(&iq); err != nil {
+		return err
+	}
+	if iq.Type == "error" {
+		if iq.Error != nil {
+			return iq.Error
+		}
+		return &StanzaError{Type: "cancel"}
+	}
+	if res != nil && len(iq.Any) != 0 {
+		return iq.Any[0].Decode(res)
+	}
+	return nil
+}
+
+// authenticate with the SASL mechanism offered: SCRAM-SHA-1 or PLAIN
+func (s *stream) authenticate(user, password string, offered []string) error {
+
+	mechanism := ""
+	for _, prefer := range []string{"SCRAM-SHA-1", "PLAIN"} {
+		for _, name := range offered {
+			if strings.EqualFold(name, prefer) {
+				mechanism = prefer
+				break
+			}
+		}
+		if mechanism != "" {
+			break
+		}
+	}
+
+	var scram *scramSHA1
+	initial := ""
+	switch mechanism {
+	case "SCRAM-SHA-1":
+		scram = newScram(user, password, "")
+		initial = scram.First()
+	case "PLAIN":
+		initial = "\x00" + user + "\x00" + password
+	default:
+		return fmt.Errorf("xmpp: no supported SASL mechanism of %v", offered)
+	}
+
+	err := s.writeRaw("<auth xmlns='" + nsSASL + "' mechanism='" + mechanism + "'>" +
+		base64.StdEncoding.EncodeToString([]byte(initial)) + "</auth>")
+	if err != nil {
+		return err
+	}
+	for {
+		var res saslResponse
+		if _, err = s.next(&res); err != nil {
+			return err
+		}
+		data, _ := base64.StdEncoding.DecodeString(strings.TrimSpace(res.Data))
+		switch res.XMLName.Local {
+		case "challenge":
+			if scram == nil {
+				return errors.New("xmpp: unexpected SASL challenge")
+			}
+			var reply string
+			if scram.serverSign == nil {
+				reply, err = scram.Next(string(data))
+			} else {
+				// server final message as the challenge; empty response
+				err = scram.Verify(string(data))
+			}
+			if err != nil {
+				return err
+			}
+			err = s.writeRaw("<response xmlns='" + nsSASL + "'>" +
+				base64.StdEncoding.EncodeToString([]byte(reply)) + "</response>")
+			if err != nil {
+				return err
+			}
+		case "success":
+			if scram != nil && !(scram.verified && len(data) == 0) {
+				if err = scram.Verify(string(data)); err != nil {
+					return err
+				}
+			}
+			return nil
+		case "failure":
+			cond := "not-authorized"
+			if len(res.Condition) != 0 {
+				cond = res.Condition[0].Local
+			}
+			return fmt.Errorf("xmpp: SASL %s authentication failure: %s", mechanism, cond)
+		default:
+			return fmt.Errorf("xmpp: unexpected SASL <%s>", res.XMLName.Local)
+		}
+	}
+}
+
+// handshake as the external component; XEP-0114
+func (s *stream) handshake(conf *Config) error {
+	if err := s.open(nsComponent, conf.JID.Domain); err != nil {
+		return err
+	}
+	if s.id == "" {
+		return errors.New("xmpp: component stream id missing")
+	}
+	hash := sha1.Sum([]byte(s.id + conf.Password))
+	if err := s.writeRaw("<handshake>" + hex.EncodeToString(hash[:]) + "</handshake>"); err != nil {
+		return err
+	}
+	start, err := s.next(nil)
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != "handshake" {
+		return fmt.Errorf("xmpp: unexpected <%s> handshake response", start.Name.Local)
+	}
+	return nil
+}
+
+// writeRaw XML data
+func (s *stream) writeRaw(data string) error {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	if s.trace != nil {
+		s.trace("send", data)
+	}
+	_, err := io.WriteString(s.conn, data)
+	return err
+}
+
+// write the stanza
+func (s *stream) write(v any) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.writeRaw(string(data))
+}
+
+// close the stream
+func (s *stream) close() {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = s.writeRaw("</stream:stream>")
+	s.conn.Close()
+}
+
+// escape XML attribute or text value
+func escape(s string) string {
+	var buf strings.Builder
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
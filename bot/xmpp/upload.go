@@ -0,0 +1,146 @@
+package xmpp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// discoInfo request payload
+type discoInfo struct {
+	XMLName xml.Name `xml:"http://jabber.org/protocol/disco#info query"`
+}
+
+// discoItems request payload
+type discoItems struct {
+	XMLName xml.Name `xml:"http://jabber.org/protocol/disco#items query"`
+}
+
+// DiscoverUpload service JID of the server; XEP-0363.
+// Returns empty string if the server has no such service
+func (c *Client) DiscoverUpload(ctx context.Context) (string, error) {
+
+	domain := c.JID().Domain
+	supports := func(jid string) (bool, error) {
+		res, err := c.Request(ctx, "get", jid, &discoInfo{})
+		if err != nil {
+			return false, err
+		}
+		var info struct {
+			Features []struct {
+				Var string `xml:"var,attr"`
+			} `xml:"feature"`
+		}
+		if query := res.payload(nsDiscoInfo); query != nil {
+			_ = query.Decode(&info)
+		}
+		for _, feature := range info.Features {
+			if feature.Var == nsUpload {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if ok, err := supports(domain); err != nil {
+		return "", err
+	} else if ok {
+		return domain, nil
+	}
+	res, err := c.Request(ctx, "get", domain, &discoItems{})
+	if err != nil {
+		return "", err
+	}
+	var items struct {
+		Items []struct {
+			JID string `xml:"jid,attr"`
+		} `xml:"item"`
+	}
+	if query := res.payload(nsDiscoItems); query != nil {
+		_ = query.Decode(&items)
+	}
+	for _, item := range items.Items {
+		if ok, _ := supports(item.JID); ok {
+			return item.JID, nil
+		}
+	}
+	return "", nil
+}
+
+// uploadRequest payload; XEP-0363
+type uploadRequest struct {
+	XMLName  xml.Name `xml:"urn:xmpp:http:upload:0 request"`
+	Filename string   `xml:"filename,attr"`
+	Size     int64    `xml:"size,attr"`
+	Type     string   `xml:"content-type,attr,omitempty"`
+}
+
+// Slot of the upload
+type Slot struct {
+	Put struct {
+		URL    string `xml:"url,attr"`
+		Header []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"header"`
+	} `xml:"put"`
+	Get struct {
+		URL string `xml:"url,attr"`
+	} `xml:"get"`
+}
+
+// Upload the file using the service; returns the file's GET URL
+func (c *Client) Upload(ctx context.Context, service, name, mtype string, data []byte, client *http.Client) (string, error) {
+
+	res, err := c.Request(ctx, "get", service, &uploadRequest{
+		Filename: name,
+		Size:     int64(len(data)),
+		Type:     mtype,
+	})
+	if err != nil {
+		return "", err
+	}
+	var slot Slot
+	payload := res.payload(nsUpload)
+	if payload == nil {
+		return "", fmt.Errorf("xmpp: upload slot missing")
+	}
+	if err = payload.Decode(&slot); err != nil {
+		return "", err
+	}
+	if slot.Put.URL == "" || slot.Get.URL == "" {
+		return "", fmt.Errorf("xmpp: upload slot URL missing")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, slot.Put.URL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = int64(len(data))
+	if mtype != "" {
+		req.Header.Set("Content-Type", mtype)
+	}
+	for _, h := range slot.Put.Header {
+		switch http.CanonicalHeaderKey(h.Name) {
+		case "Authorization", "Cookie", "Expires":
+			// allowed; see XEP-0363 §5
+			req.Header.Set(h.Name, h.Value)
+		}
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(rsp.Body, 4096))
+	if rsp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("xmpp: upload PUT %s", rsp.Status)
+	}
+	return slot.Get.URL, nil
+}
@@ -0,0 +1,676 @@
+package xmpp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/util"
+)
+
+const (
+	provider = "xmpp"
+	// Max size of the file to download
+	maxFileSize = 32 << 20
+	// Timeout of the incoming stanza processing
+	handleTimeout = time.Minute
+)
+
+func init() {
+	bot.Register(provider, New)
+}
+
+// Bot is the XMPP channel provider,
+// logged in as the client or the external component (XEP-0114).
+// Chats are keyed by the sender's bare JID
+type Bot struct {
+	Gateway *bot.Gateway
+	Client  *Client
+	// HTTP client to download and upload (XEP-0363) files
+	http *http.Client
+
+	// HTTP File Upload service JID; discovered if empty
+	umx        sync.Mutex
+	upload     string
+	discovered bool
+
+	mx sync.Mutex
+	// options sent to the chat: [chat][text]code
+	menus map[string]map[string]string
+}
+
+// New initialize new agent.profile service XMPP provider
+func New(agent *bot.Gateway, state bot.Provider) (bot.Provider, error) {
+
+	profile := agent.Bot.GetMetadata()
+
+	jid, err := ParseJID(profile["jid"])
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.jid.invalid",
+			"xmpp: %v", err,
+		)
+	}
+	if profile["password"] == "" {
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.password.required",
+			"xmpp: password required",
+		)
+	}
+
+	conf := Config{
+		JID:      jid,
+		Password: profile["password"],
+		Host:     profile["host"],
+		TLS:      strings.ToLower(profile["tls"]),
+		Timeout:  30 * time.Second,
+	}
+	switch mode := strings.ToLower(profile["mode"]); mode {
+	case "", "client":
+		if jid.Local == "" {
+			return nil, errors.BadRequest(
+				"chat.bot.xmpp.jid.invalid",
+				"xmpp: client jid user@domain required",
+			)
+		}
+		if conf.Host == "" {
+			conf.Host = net.JoinHostPort(jid.Domain, "5222")
+		}
+		if conf.TLS == "" {
+			conf.TLS = tlsStartTLS
+		}
+	case "component":
+		conf.Component = true
+		if conf.Host == "" {
+			return nil, errors.BadRequest(
+				"chat.bot.xmpp.host.required",
+				"xmpp: component host:port required",
+			)
+		}
+		if conf.TLS == "" {
+			conf.TLS = tlsNone
+		}
+	default:
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.mode.invalid",
+			"xmpp: mode %q is invalid; want client or component", mode,
+		)
+	}
+	if _, _, err = net.SplitHostPort(conf.Host); err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.host.invalid",
+			"xmpp: host %q is invalid; want host:port", conf.Host,
+		)
+	}
+	switch conf.TLS {
+	case tlsStartTLS, tlsDirect, tlsNone:
+	default:
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.tls.invalid",
+			"xmpp: tls %q is invalid; want starttls, direct or none", conf.TLS,
+		)
+	}
+	if skip, _ := strconv.ParseBool(profile["tls_skip_verify"]); skip {
+		conf.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	// Parse and validate message templates
+	agent.Template = bot.NewTemplate(provider)
+	if err = agent.Template.FromProto(
+		agent.Bot.GetUpdates(),
+	); err == nil {
+		// Quick tests ! <nil> means default (well-known) test cases
+		err = agent.Template.Test(nil)
+	}
+	if err != nil {
+		return nil, errors.BadRequest(
+			"chat.bot.xmpp.updates.invalid",
+			"xmpp: %v", err,
+		)
+	}
+
+	trace, _ := strconv.ParseBool(profile["trace"])
+	httpClient := &http.Client{Timeout: time.Minute}
+	if trace {
+		httpClient.Transport = &bot.TransportDump{
+			Transport: http.DefaultTransport,
+			WithBody:  false, // files
+		}
+	}
+
+	app := &Bot{
+		Gateway: agent,
+		http:    httpClient,
+		upload:  profile["upload"],
+		menus:   make(map[string]map[string]string),
+	}
+	app.discovered = app.upload != "" || conf.Component
+	app.Client = &Client{
+		Config:   conf,
+		Ping:     time.Minute,
+		Log:      agent.Log,
+		Trace:    trace,
+		OnStanza: app.onStanza,
+	}
+
+	// Latest (current) state
+	if last, _ := state.(*Bot); last != nil {
+		last.Client.Close()
+		last.mx.Lock()
+		for id, menu := range last.menus {
+			app.menus[id] = menu
+		}
+		last.mx.Unlock()
+	}
+
+	if agent.Bot.GetEnabled() && app.ownsSession() {
+		app.Client.Start()
+	}
+
+	return app, nil
+}
+
+// ownsSession reports whether this node runs the XMPP session
+func (c *Bot) ownsSession() bool {
+	return bot.OwnsSession(c.Gateway.Bot.GetMetadata()["session"])
+}
+
+func (*Bot) String() string {
+	return provider
+}
+
+// Register starts the XMPP session, if this node owns it
+func (c *Bot) Register(ctx context.Context, uri string) error {
+	if !c.ownsSession() {
+		c.Gateway.Log.Warn("xmpp/bot.register",
+			slog.String("jid", c.Client.Config.JID.String()),
+			slog.String("error", "session is owned by another node; see metadata.session"),
+		)
+		return nil
+	}
+	c.Client.Start()
+	return nil
+}
+
+// Deregister closes the XMPP session
+func (c *Bot) Deregister(ctx context.Context) error {
+	c.Client.Close()
+	return nil
+}
+
+func (c *Bot) Close() error {
+	c.Client.Close()
+	return nil
+}
+
+// WebHook is not supported; messages are received over XMPP session
+func (c *Bot) WebHook(reply http.ResponseWriter, notice *http.Request) {
+	http.Error(reply, "xmpp: webhook not supported", http.StatusNotFound)
+}
+
+// on: message or presence stanza received
+func (c *Bot) onStanza(s *Stanza) {
+
+	from, err := ParseJID(s.From)
+	if err != nil || from.Bare() == c.Client.JID().Bare() {
+		return // IGNORE
+	}
+
+	if s.XMLName.Local == "presence" {
+		c.onPresence(s, from)
+		return
+	}
+
+	switch s.Type {
+	case "", "normal", "chat":
+	default:
+		// error | groupchat | headline
+		return // IGNORE
+	}
+	if strings.TrimSpace(s.Body) == "" && len(s.OOB) == 0 {
+		// chat state notification (XEP-0085), receipt etc
+		return // IGNORE
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handleTimeout)
+	defer cancel()
+	if err = c.onMessage(ctx, s, from); err != nil {
+		c.Gateway.Log.Error("xmpp/bot.onMessage",
+			slog.Any("error", err),
+			slog.String("from", s.From),
+			slog.String("id", s.ID),
+		)
+	}
+}
+
+// on: presence; subscription requests are approved
+func (c *Bot) onPresence(s *Stanza, from JID) {
+	var reply []string
+	switch s.Type {
+	case "subscribe":
+		reply = []string{"subscribed"}
+		if c.Client.Component {
+			reply = append(reply, "")
+		}
+	case "probe":
+		// client: server answers on behalf of
+		if c.Client.Component {
+			reply = []string{""}
+		}
+	}
+	for _, typ := range reply {
+		err := c.Client.Send(&Stanza{
+			XMLName: xml.Name{Local: "presence"},
+			Type:    typ,
+			To:      from.Bare(),
+		})
+		if err != nil {
+			c.Gateway.Log.Error("xmpp/bot.onPresence",
+				slog.Any("error", err),
+				slog.String("from", s.From),
+				slog.String("type", s.Type),
+			)
+			return
+		}
+	}
+}
+
+// on: message
+func (c *Bot) onMessage(ctx context.Context, s *Stanza, from JID) error {
+
+	chatID := from.Bare()
+	contact := &bot.Account{
+		ID:       0, // LOOKUP
+		Channel:  provider,
+		Contact:  chatID,
+		Username: from.Local,
+	}
+	name := strings.TrimSpace(s.Nick)
+	if name == "" {
+		name = from.Local
+	}
+	contact.FirstName, contact.LastName = util.ParseFullName(name)
+
+	channel, err := c.Gateway.GetChannel(ctx, chatID, contact)
+	if err != nil {
+		// Failed locate chat channel !
+		re := errors.FromError(err)
+		if re.Code == 0 {
+			re.Code = (int32)(http.StatusBadGateway)
+		}
+		return re // 502 Bad Gateway
+	}
+	update := bot.Update{
+		Title: channel.Title,
+		Chat:  channel,
+		User:  contact,
+	}
+
+	text := strings.TrimSpace(s.Body)
+	var files []*chat.File
+	for _, oob := range s.OOB {
+		link := strings.TrimSpace(oob.URL)
+		if link == "" {
+			continue
+		}
+		file, err := c.getFile(ctx, link)
+		if err != nil {
+			// deliver the link instead
+			c.Gateway.Log.Warn("xmpp/bot.getFile",
+				slog.Any("error", err),
+				slog.String("url", link),
+			)
+			continue
+		}
+		files = append(files, file)
+		if text == link {
+			// XEP-0363: body is the file URL
+			text = ""
+		}
+	}
+
+	if text != "" {
+		update.Message = &chat.Message{
+			Type: "text",
+			Text: c.takeOption(chatID, text),
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		update.Message = &chat.Message{
+			Type: "file",
+			File: file,
+		}
+		if err = c.Gateway.Read(ctx, &update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeOption code of the options sent to the chat, if text selects one
+func (c *Bot) takeOption(chatID, text string) string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	code, ok := c.menus[chatID][strings.ToLower(text)]
+	if !ok {
+		return text
+	}
+	delete(c.menus, chatID)
+	return code
+}
+
+// download the file of the link
+func (c *Bot) download(ctx context.Context, link string) (data []byte, mtype string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("xmpp: GET %s: %s", link, rsp.Status)
+	}
+	data, err = io.ReadAll(io.LimitReader(rsp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxFileSize {
+		return nil, "", fmt.Errorf("xmpp: file size exceeds %d bytes", maxFileSize)
+	}
+	mtype, _, _ = strings.Cut(rsp.Header.Get("Content-Type"), ";")
+	return data, mtype, nil
+}
+
+// getFile of the link into the storage
+func (c *Bot) getFile(ctx context.Context, link string) (*chat.File, error) {
+
+	data, mtype, err := c.download(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	// XEP-0363: .../{slot}/{filename}
+	name := link
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	name = path.Base(name)
+	if mtype == "" || mtype == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+			mtype, _, _ = strings.Cut(byExt, ";")
+		}
+	}
+	media, err := c.Gateway.UploadFile(
+		ctx, 4096, mtype, name, uuid.NewString(), bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chat.File{
+		Id:      media.Id,
+		Url:     media.Url,
+		Mime:    mtype,
+		Name:    name,
+		Size:    media.Size,
+		Malware: media.Malware,
+	}, nil
+}
+
+// uploadService JID; discovered once
+func (c *Bot) uploadService(ctx context.Context) string {
+	c.umx.Lock()
+	defer c.umx.Unlock()
+	if !c.discovered {
+		service, err := c.Client.DiscoverUpload(ctx)
+		if err != nil {
+			c.Gateway.Log.Warn("xmpp/upload.discover",
+				slog.Any("error", err),
+			)
+			return "" // try again later
+		}
+		c.upload, c.discovered = service, true
+	}
+	return c.upload
+}
+
+// shareFile of the agent; re-uploaded to the XMPP server
+// if it supports HTTP File Upload (XEP-0363), otherwise the link is shared
+func (c *Bot) shareFile(ctx context.Context, doc *chat.File) (string, error) {
+	service := c.uploadService(ctx)
+	if service == "" {
+		return doc.GetUrl(), nil
+	}
+	data, mtype, err := c.download(ctx, doc.GetUrl())
+	if err != nil {
+		return "", err
+	}
+	if doc.GetMime() != "" {
+		mtype = doc.GetMime()
+	}
+	name := doc.GetName()
+	if name == "" {
+		name = "file"
+	}
+	return c.Client.Upload(ctx, service, name, mtype, data, c.http)
+}
+
+// chatState element; XEP-0085
+func chatState(state string) Element {
+	return Element{XMLName: xml.Name{Space: nsChatStates, Local: state}}
+}
+
+// newMessage of the text to the chat
+func newMessage(to, text string) *Stanza {
+	return &Stanza{
+		XMLName: xml.Name{Local: "message"},
+		ID:      uuid.NewString(),
+		Type:    "chat",
+		To:      to,
+		Body:    text,
+		Any:     []Element{chatState("active")},
+	}
+}
+
+// newTextMessage of the text and options to choose from;
+// returns the options mapping of the reply text to the code
+func newTextMessage(to, text string, rows []*chat.Buttons) (*Stanza, map[string]string) {
+
+	var (
+		lines []string
+		menu  map[string]string
+		items int
+	)
+	for _, row := range rows {
+		for _, button := range row.GetButton() {
+			label := strings.TrimSpace(button.GetText())
+			if label == "" {
+				continue
+			}
+			switch strings.ToLower(button.GetType()) {
+			case "url":
+				if button.GetUrl() != "" {
+					lines = append(lines, label+": "+button.GetUrl())
+				}
+			case "reply", "postback":
+				code := button.GetCode()
+				if code == "" {
+					code = label
+				}
+				if menu == nil {
+					menu = make(map[string]string)
+				}
+				items++
+				n := strconv.Itoa(items)
+				menu[n] = code
+				menu[strings.ToLower(label)] = code
+				lines = append(lines, n+". "+label)
+			}
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if len(lines) != 0 {
+		text = strings.TrimSpace(text + "\n\n" + strings.Join(lines, "\n"))
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return newMessage(to, text), menu
+}
+
+func (c *Bot) SendNotify(ctx context.Context, notify *bot.Update) error {
+
+	var (
+		channel = notify.Chat
+		message = notify.Message
+		updates = c.Gateway.Template
+		chatID  = channel.ChatID
+		text    string
+		buttons []*chat.Buttons
+		file    *chat.File
+	)
+
+	switch message.Type {
+	case "text":
+		text = message.GetText()
+		buttons = message.GetButtons()
+		if buttons == nil {
+			buttons = message.GetInline()
+		}
+
+	case "file":
+		text = message.GetText()
+		file = message.GetFile()
+
+	case "joined":
+		peer := message.NewChatMembers[0]
+		messageText, err := updates.MessageText("join", peer)
+		if err != nil {
+			c.Gateway.Log.Error("xmpp/bot.updateChatMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		messageText = strings.TrimSpace(messageText)
+		if messageText == "" {
+			return nil
+		}
+		// format new message to the engine for saving it in the DB as operator message [WTEL-4695]
+		messageToSave := &chat.Message{
+			Type:      "text",
+			Text:      messageText,
+			CreatedAt: time.Now().UnixMilli(),
+			From:      peer,
+		}
+		if channel.ChannelID != "" {
+			_, err = c.Gateway.Internal.Client.SendServiceMessage(ctx, &chat.SendServiceMessageRequest{Message: messageToSave, ChatId: channel.ChannelID})
+			return err
+		}
+		text = messageText
+
+	case "left":
+		peer := message.LeftChatMember
+		messageText, err := updates.MessageText("left", peer)
+		if err != nil {
+			c.Gateway.Log.Error("xmpp/bot.updateLeftMember",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	case "closed":
+		messageText, err := updates.MessageText("close", nil)
+		if err != nil {
+			c.Gateway.Log.Error("xmpp/bot.updateChatClose",
+				slog.Any("error", err),
+				slog.String("update", message.Type),
+			)
+		}
+		text = messageText
+
+	default:
+		// UNKNOWN Internal Message Update
+		return nil // IGNORE
+	}
+
+	sendMessage, menu := newTextMessage(chatID, text, buttons)
+	if sendMessage != nil {
+		if err := c.Client.Send(sendMessage); err != nil {
+			c.Gateway.Log.Error("xmpp/message.send",
+				slog.Any("error", err),
+				slog.String("to", chatID),
+			)
+			return err
+		}
+		if len(menu) != 0 {
+			c.mx.Lock()
+			c.menus[chatID] = menu
+			c.mx.Unlock()
+		}
+	}
+
+	if file != nil {
+		link, err := c.shareFile(ctx, file)
+		if err != nil {
+			c.Gateway.Log.Error("xmpp/upload",
+				slog.Any("error", err),
+				slog.String("to", chatID),
+				slog.String("file", file.GetName()),
+			)
+			return err
+		}
+		sendMessage = newMessage(chatID, link)
+		sendMessage.OOB = []OOB{{URL: link}}
+		if err = c.Client.Send(sendMessage); err != nil {
+			c.Gateway.Log.Error("xmpp/message.send",
+				slog.Any("error", err),
+				slog.String("to", chatID),
+			)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendUserAction sends the chat state (XEP-0085) of the agent
+func (c *Bot) SendUserAction(ctx context.Context, chatID string, action chat.UserAction) (bool, error) {
+	state := "composing"
+	if action == chat.UserAction_Cancel {
+		state = "paused"
+	}
+	err := c.Client.Send(&Stanza{
+		XMLName: xml.Name{Local: "message"},
+		Type:    "chat",
+		To:      chatID,
+		Any:     []Element{chatState(state)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,377 @@
+package xmpp
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/bot"
+)
+
+// xmppServer is the local stand-in of the XMPP server; single connection
+type xmppServer struct {
+	t    *testing.T
+	ln   net.Listener
+	conn net.Conn
+	dec  *xml.Decoder
+	// XEP-0363 slots PUT / GET base URL
+	files string
+	// stanzas to push once the session established
+	push []string
+	// messages received
+	recv chan *Stanza
+}
+
+func newServer(t *testing.T, files string, component bool, push ...string) *xmppServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &xmppServer{t: t, ln: ln, files: files, push: push, recv: make(chan *Stanza, 16)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		srv.conn = conn
+		if component {
+			srv.handshake()
+		} else {
+			srv.login()
+		}
+		srv.serve()
+	}()
+	return srv
+}
+
+func (srv *xmppServer) write(data string) {
+	_, _ = io.WriteString(srv.conn, data)
+}
+
+// header of the client's stream
+func (srv *xmppServer) header() {
+	srv.dec = xml.NewDecoder(srv.conn)
+	for {
+		token, err := srv.dec.Token()
+		if err != nil {
+			return
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "stream" {
+			return
+		}
+	}
+}
+
+func (srv *xmppServer) read(v any) bool {
+	for {
+		token, err := srv.dec.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return srv.dec.DecodeElement(v, &start) == nil
+		}
+	}
+}
+
+const streamHeader = `<?xml version='1.0'?><stream:stream xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' id='s1' from='example.org' version='1.0'>`
+
+func (srv *xmppServer) login() {
+	srv.header()
+	srv.write(streamHeader + `<stream:features><mechanisms xmlns='urn:ietf:params:xml:ns:xmpp-sasl'><mechanism>PLAIN</mechanism></mechanisms></stream:features>`)
+	var auth struct {
+		Mechanism string `xml:"mechanism,attr"`
+		Data      string `xml:",chardata"`
+	}
+	srv.read(&auth)
+	if data, _ := base64.StdEncoding.DecodeString(auth.Data); auth.Mechanism != "PLAIN" || string(data) != "\x00bot\x00secret" {
+		srv.write(`<failure xmlns='urn:ietf:params:xml:ns:xmpp-sasl'><not-authorized/></failure>`)
+		return
+	}
+	srv.write(`<success xmlns='urn:ietf:params:xml:ns:xmpp-sasl'/>`)
+	srv.header()
+	srv.write(streamHeader + `<stream:features><bind xmlns='urn:ietf:params:xml:ns:xmpp-bind'/></stream:features>`)
+	var bind Stanza
+	srv.read(&bind)
+	srv.write(`<iq type='result' id='` + bind.ID + `'><bind xmlns='urn:ietf:params:xml:ns:xmpp-bind'><jid>bot@example.org/r1</jid></bind></iq>`)
+}
+
+func (srv *xmppServer) handshake() {
+	srv.header()
+	srv.write(`<?xml version='1.0'?><stream:stream xmlns='jabber:component:accept' xmlns:stream='http://etherx.jabber.org/streams' id='c1' from='bot.example.org'>`)
+	var hash string
+	srv.read(&hash)
+	want := sha1.Sum([]byte("c1secret"))
+	if hash != hex.EncodeToString(want[:]) {
+		srv.write(`<stream:error><not-authorized xmlns='urn:ietf:params:xml:ns:xmpp-streams'/></stream:error>`)
+		return
+	}
+	srv.write(`<handshake/>`)
+}
+
+func (srv *xmppServer) serve() {
+	for _, data := range srv.push {
+		srv.write(data)
+	}
+	for {
+		var s Stanza
+		if !srv.read(&s) {
+			return
+		}
+		switch s.XMLName.Local {
+		case "message":
+			srv.recv <- &s
+		case "iq":
+			switch {
+			case s.payload(nsDiscoInfo) != nil && s.To == "upload.example.org":
+				srv.write(`<iq type='result' id='` + s.ID + `' from='upload.example.org'><query xmlns='http://jabber.org/protocol/disco#info'><feature var='urn:xmpp:http:upload:0'/></query></iq>`)
+			case s.payload(nsDiscoInfo) != nil:
+				srv.write(`<iq type='result' id='` + s.ID + `' from='` + s.To + `'><query xmlns='http://jabber.org/protocol/disco#info'><feature var='urn:xmpp:ping'/></query></iq>`)
+			case s.payload(nsDiscoItems) != nil:
+				srv.write(`<iq type='result' id='` + s.ID + `' from='example.org'><query xmlns='http://jabber.org/protocol/disco#items'><item jid='conference.example.org'/><item jid='upload.example.org'/></query></iq>`)
+			case s.payload(nsUpload) != nil:
+				srv.write(`<iq type='result' id='` + s.ID + `' from='upload.example.org'><slot xmlns='urn:xmpp:http:upload:0'>` +
+					`<put url='` + srv.files + `/upload/a.txt'><header name='Authorization'>Bearer slot</header><header name='Host'>evil</header></put>` +
+					`<get url='` + srv.files + `/share/a.txt'/></slot></iq>`)
+			default:
+				srv.write(`<iq type='result' id='` + s.ID + `'/>`)
+			}
+		}
+	}
+}
+
+// fileServer of the agent's files and XEP-0363 uploads
+type fileServer struct {
+	*httptest.Server
+	mx       sync.Mutex
+	uploaded map[string]string
+}
+
+func newFileServer(t *testing.T) *fileServer {
+	fs := &fileServer{uploaded: make(map[string]string)}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/files/a.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("hello"))
+		case r.Method == http.MethodPut && r.Header.Get("Authorization") == "Bearer slot":
+			data, _ := io.ReadAll(r.Body)
+			fs.mx.Lock()
+			fs.uploaded[r.URL.Path] = string(data)
+			fs.mx.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func newTestClient(srv *xmppServer, jid string, component bool) *Client {
+	addr, _ := ParseJID(jid)
+	return &Client{
+		Config: Config{
+			JID:       addr,
+			Password:  "secret",
+			Host:      srv.ln.Addr().String(),
+			TLS:       tlsNone,
+			Component: component,
+			Timeout:   5 * time.Second,
+		},
+		Log: slog.Default(),
+	}
+}
+
+// connected waits for the session established
+func connected(t *testing.T, c *Client) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if c.session() != nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("session not established")
+}
+
+func receive(t *testing.T, srv *xmppServer) *Stanza {
+	t.Helper()
+	select {
+	case s := <-srv.recv:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+		return nil
+	}
+}
+
+func TestParseJID(t *testing.T) {
+	jid, err := ParseJID("Alice@Example.ORG/Phone")
+	if err != nil || jid.Bare() != "alice@example.org" || jid.String() != "alice@example.org/Phone" {
+		t.Errorf("ParseJID() = (%v, %v)", jid, err)
+	}
+	for _, s := range []string{"", "@example.org", "alice@example.org/"} {
+		if _, err = ParseJID(s); err == nil {
+			t.Errorf("ParseJID(%q) = <nil>; want error", s)
+		}
+	}
+}
+
+func TestScram(t *testing.T) {
+	// RFC 5802 §5
+	scram := newScram("user", "pencil", "fyko+d2lbbFgONRv9qkxdawL")
+	if first := scram.First(); first != "n,,n=user,r=fyko+d2lbbFgONRv9qkxdawL" {
+		t.Errorf("first = %s", first)
+	}
+	final, err := scram.Next("r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096")
+	if err != nil || final != "c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=" {
+		t.Errorf("final = (%s, %v)", final, err)
+	}
+	if err = scram.Verify("v=rmF9pqV8S7suAoZWja4dJRkFsKQ="); err != nil {
+		t.Error(err)
+	}
+	if err = scram.Verify("v=AAAAAAAAAAAAAAAAAAAAAAAAAAA="); err == nil {
+		t.Error("Verify(invalid) = <nil>; want error")
+	}
+	if _, err = newScram("user", "pencil", "abc").Next("r=xyz,s=QSXCR+Q6sek8bf92,i=4096"); err == nil {
+		t.Error("Next(server nonce) = <nil>; want error")
+	}
+}
+
+func TestClient(t *testing.T) {
+
+	srv := newServer(t, "", false,
+		`<message from='alice@example.org/phone' to='bot@example.org' type='chat' id='m1'><body>Hi</body><nick xmlns='http://jabber.org/protocol/nick'>Alice Liddell</nick><active xmlns='http://jabber.org/protocol/chatstates'/></message>`,
+	)
+	received := make(chan *Stanza, 1)
+	client := newTestClient(srv, "bot@example.org", false)
+	client.OnStanza = func(s *Stanza) {
+		received <- s
+	}
+	client.Start()
+	defer client.Close()
+	connected(t, client)
+
+	if jid := client.JID().String(); jid != "bot@example.org/r1" {
+		t.Errorf("bound JID = %s", jid)
+	}
+	select {
+	case s := <-received:
+		if s.From != "alice@example.org/phone" || s.Body != "Hi" || s.Nick != "Alice Liddell" || s.payload(nsChatStates) == nil {
+			t.Errorf("message = %+v", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+
+	service, err := client.DiscoverUpload(context.Background())
+	if err != nil || service != "upload.example.org" {
+		t.Errorf("DiscoverUpload() = (%q, %v)", service, err)
+	}
+}
+
+func TestComponent(t *testing.T) {
+	srv := newServer(t, "", true)
+	client := newTestClient(srv, "bot.example.org", true)
+	client.Start()
+	defer client.Close()
+	connected(t, client)
+
+	if err := client.Send(newMessage("alice@example.org", "Hello")); err != nil {
+		t.Fatal(err)
+	}
+	if s := receive(t, srv); s.From != "bot.example.org" || s.Body != "Hello" {
+		t.Errorf("message = %+v", s)
+	}
+}
+
+func TestNewTextMessage(t *testing.T) {
+	s, menu := newTextMessage("alice@example.org", "Rate us", []*chat.Buttons{{Button: []*chat.Button{
+		{Type: "reply", Text: "Good", Code: "5"},
+		{Type: "postback", Text: "Bad", Code: "1"},
+		{Type: "url", Text: "Site", Url: "https://example.com"},
+	}}})
+	if want := "Rate us\n\n1. Good\n2. Bad\nSite: https://example.com"; s.Body != want {
+		t.Errorf("body = %q; want %q", s.Body, want)
+	}
+	if menu["2"] != "1" || menu["good"] != "5" || len(menu) != 4 {
+		t.Errorf("menu = %v", menu)
+	}
+}
+
+func TestSendNotify(t *testing.T) {
+
+	fs := newFileServer(t)
+	srv := newServer(t, fs.URL, false)
+	app := &Bot{
+		Gateway: &bot.Gateway{Log: slog.Default()},
+		Client:  newTestClient(srv, "bot@example.org", false),
+		http:    fs.Client(),
+		menus:   make(map[string]map[string]string),
+	}
+	app.Client.Start()
+	defer app.Client.Close()
+	connected(t, app.Client)
+
+	channel := &bot.Channel{ChatID: "alice@example.org"}
+	for _, message := range []*chat.Message{
+		{Type: "text", Text: "Rate us", Buttons: []*chat.Buttons{{Button: []*chat.Button{{Type: "reply", Text: "Good", Code: "5"}}}}},
+		{Type: "file", Text: "Notes", File: &chat.File{Name: "a.txt", Mime: "text/plain", Url: fs.URL + "/files/a.txt"}},
+	} {
+		if err := app.SendNotify(context.Background(), &bot.Update{Chat: channel, Message: message}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s := receive(t, srv); s.To != "alice@example.org" || s.Type != "chat" || s.Body != "Rate us\n\n1. Good" {
+		t.Errorf("text = %+v", s)
+	}
+	if code := app.takeOption("alice@example.org", "1"); code != "5" {
+		t.Errorf("takeOption(1) = %q; want 5", code)
+	}
+	if s := receive(t, srv); s.Body != "Notes" {
+		t.Errorf("caption = %+v", s)
+	}
+	share := fs.URL + "/share/a.txt"
+	if s := receive(t, srv); s.Body != share || len(s.OOB) != 1 || s.OOB[0].URL != share {
+		t.Errorf("file = %+v", s)
+	}
+	fs.mx.Lock()
+	defer fs.mx.Unlock()
+	if data := fs.uploaded["/upload/a.txt"]; data != "hello" {
+		t.Errorf("uploaded = %q", data)
+	}
+
+	if ok, err := app.SendUserAction(context.Background(), "alice@example.org", chat.UserAction_Typing); !ok || err != nil {
+		t.Errorf("SendUserAction() = (%v, %v)", ok, err)
+	}
+	if s := receive(t, srv); s.Body != "" || s.payload(nsChatStates) == nil || s.payload(nsChatStates).XMLName.Local != "composing" {
+		t.Errorf("chat state = %+v", s)
+	}
+}
+
+func TestStanzaError(t *testing.T) {
+	var s Stanza
+	err := xml.Unmarshal([]byte(`<iq type='error' id='q1'><error type='cancel'><item-not-found xmlns='urn:ietf:params:xml:ns:xmpp-stanzas'/><text xmlns='urn:ietf:params:xml:ns:xmpp-stanzas'>No such item</text></error></iq>`), &s)
+	if err != nil || s.Error == nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	if msg := s.Error.Error(); !strings.Contains(msg, "item-not-found") || !strings.Contains(msg, "No such item") {
+		t.Errorf("Error() = %s", msg)
+	}
+}
@@ -8,11 +8,21 @@ import (
 	// Register Chat Bot Provider(s) ...
 	_ "github.com/webitel/chat_manager/bot/corezoid"
 	_ "github.com/webitel/chat_manager/bot/custom"
+	_ "github.com/webitel/chat_manager/bot/discord"       // gateway, interactions
+	_ "github.com/webitel/chat_manager/bot/email"         // imap/smtp
 	_ "github.com/webitel/chat_manager/bot/facebook"      // messenger
+	_ "github.com/webitel/chat_manager/bot/generic"       // json over http
+	_ "github.com/webitel/chat_manager/bot/line"          // messaging api
+	_ "github.com/webitel/chat_manager/bot/matrix"        // client-server api
+	_ "github.com/webitel/chat_manager/bot/msteams"       // bot framework
+	_ "github.com/webitel/chat_manager/bot/slack"         // events api
+	_ "github.com/webitel/chat_manager/bot/smpp"          // sms
 	_ "github.com/webitel/chat_manager/bot/telegram/gotd" // telegram-app [gotd]
 	_ "github.com/webitel/chat_manager/bot/telegram/http" // telegram-bot [telegram]
+	_ "github.com/webitel/chat_manager/bot/twilio"        // sms, mms, whatsapp
 	_ "github.com/webitel/chat_manager/bot/viber"
 	_ "github.com/webitel/chat_manager/bot/vk"
 	_ "github.com/webitel/chat_manager/bot/webchat" // websocket
 	_ "github.com/webitel/chat_manager/bot/whatsapp/infobip"
+	_ "github.com/webitel/chat_manager/bot/xmpp" // client, component
 )
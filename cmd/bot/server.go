@@ -12,6 +12,7 @@ import (
 	micro "github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/broker"
 	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
 	"github.com/micro/micro/v3/service/server"
 	microgrpcsrv "github.com/micro/micro/v3/service/server/grpc"
 	"github.com/pkg/errors"
@@ -32,11 +33,14 @@ import (
 	"github.com/webitel/chat_manager/auth"
 	"github.com/webitel/chat_manager/bot"
 	"github.com/webitel/chat_manager/cmd"
+	"github.com/webitel/chat_manager/internal/health"
 	sqlxrepo "github.com/webitel/chat_manager/internal/repo/sqlx"
+	"github.com/webitel/chat_manager/internal/secret"
 	"github.com/webitel/chat_manager/internal/wrapper"
 	"github.com/webitel/chat_manager/log"
 	aud "github.com/webitel/chat_manager/logger"
 	"github.com/webitel/chat_manager/otel"
+	"github.com/webitel/chat_manager/otel/prometheus"
 	"github.com/webitel/chat_manager/store/postgres"
 )
 
@@ -46,6 +50,19 @@ const (
 	usage = "Run a chat gateways service"
 )
 
+var (
+	secretKeysFlag = &cli.StringFlag{
+		Name:    "secret-keys",
+		EnvVars: []string{"WEBITEL_BOT_SECRET_KEYS"},
+		Usage:   "Comma-separated kid:base64(key) list of the AES keys to encrypt bot credentials at rest. The first key is primary; the rest are kept to decrypt values of the rotated keys.",
+	}
+	dbDSNFlag = &cli.StringFlag{
+		Name:    "db-dsn",
+		EnvVars: []string{"WEBITEL_DBO_ADDRESS"},
+		Usage:   "Persistent database driver name and a driver-specific data source name.",
+	}
+)
+
 var (
 	srv *bot.Service
 
@@ -81,10 +98,19 @@ var (
 			Value:   "127.0.0.1:10030",
 		},
 		&cli.StringFlag{
-			Name:    "db-dsn",
-			EnvVars: []string{"WEBITEL_DBO_ADDRESS"},
-			Usage:   "Persistent database driver name and a driver-specific data source name.",
+			Name:    "internal-address",
+			EnvVars: []string{"WEBITEL_BOT_INTERNAL_ADDRESS"},
+			Usage:   "Bind address for the internal HTTP server: health, readiness and metrics endpoints. Empty disables.",
+			Value:   ":10031",
+		},
+		&cli.DurationFlag{
+			Name:    "health-check-interval",
+			EnvVars: []string{"WEBITEL_BOT_HEALTH_CHECK_INTERVAL"},
+			Usage:   "Interval of the running bots health checks: credentials, webhook registration. Zero disables the checks.",
+			Value:   bot.DefaultHealthCheckInterval,
 		},
+		secretKeysFlag,
+		dbDSNFlag,
 		&cli.IntFlag{
 			Name:    "db-max-open-conns",
 			EnvVars: []string{"WEBITEL_DBO_MAX_OPEN_CONNS"},
@@ -139,10 +165,16 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
-	err := otel.Configure(ctx.Context,
+	// Prometheus [/metrics] exposition
+	metrics, err := prometheus.NewExporter()
+	if err != nil {
+		return err // log(FATAL) & exit(1)
+	}
+	err = otel.Configure(ctx.Context,
 		otelsdk.WithResource(resource.NewSchemaless(
 			resourceAttrs...,
 		)),
+		metrics.Option(),
 	)
 	if err != nil {
 		// fatal(fmt.Errorf("[O]pen[Tel]emetry configuration failed"))
@@ -199,6 +231,11 @@ func Run(ctx *cli.Context) error {
 	webRoot := ctx.String("web_root")
 	srvAddr := ctx.String("address")
 
+	secrets, err := secret.ParseKeyring(ctx.String("secret-keys"))
+	if err != nil {
+		return errors.Wrap(err, "--secret-keys")
+	}
+
 	// CHECK: valid [host]:port address specified
 	if _, _, err := net.SplitHostPort(srvAddr); err != nil {
 		return errors.Wrap(err, "Invalid address")
@@ -262,6 +299,13 @@ func Run(ctx *cli.Context) error {
 	fileService := pbstorage.NewFileService("storage", sender)
 	srv = bot.NewService(store, stdlog, agent, auditor, fileService)
 	srv.WebRoot = webRoot // Static assets base folder
+	srv.Metrics = metrics
+	srv.Secrets = secrets
+	srv.HealthCheckInterval = ctx.Duration("health-check-interval")
+	srv.Health = health.NewChecker().
+		Add("postgres", health.Postgres(dbo.DB)).
+		Add("broker", health.Broker(broker.DefaultBroker)).
+		Add("registry", health.Registry(registry.DefaultRegistry, name))
 
 	// AUTH: go.webitel.app
 	srv.Auth = auth.NewClient(
@@ -284,6 +328,7 @@ func Run(ctx *cli.Context) error {
 
 	srv.URL = baseURL
 	srv.Addr = srvAddr
+	srv.InternalAddr = ctx.String("internal-address")
 
 	// r.PathPrefix("/").Methods("GET", "POST").Handler(srv)
 
@@ -299,12 +344,50 @@ func Run(ctx *cli.Context) error {
 	return nil
 }
 
+// RotateSecrets re-encrypts the bot credentials with the primary --secret-keys key
+func RotateSecrets(ctx *cli.Context) error {
+
+	secrets, err := secret.ParseKeyring(ctx.String("secret-keys"))
+	if err != nil {
+		return errors.Wrap(err, "--secret-keys")
+	}
+	if secrets == nil {
+		return errors.New("--secret-keys: required")
+	}
+
+	stdlog := slog.Default()
+	dbo, err := postgres.OpenDB(stdlog, ctx.String("db-dsn"))
+	if err != nil {
+		return errors.Wrap(err, "Invalid DSN String")
+	}
+	defer dbo.Close()
+
+	store := sqlxrepo.NewBotStore(stdlog, dbo.DB)
+	rotate := bot.NewService(store, stdlog, nil, nil, nil)
+	rotate.Secrets = secrets
+
+	n, err := rotate.RotateSecrets(ctx.Context)
+	stdlog.Info("bot secrets rotated",
+		slog.String("kid", secrets.KeyID()),
+		slog.Int("bots", n),
+	)
+	return err
+}
+
 func init() {
 	app := &cli.Command{
 		Name:   "bot",
 		Usage:  usage,
 		Flags:  Flags,
 		Action: Run,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "rotate-secrets",
+				Usage:  "Re-encrypt bot credentials with the primary secret key",
+				Flags:  []cli.Flag{secretKeysFlag, dbDSNFlag},
+				Action: RotateSecrets,
+			},
+		},
 	}
 	cmd.Register(app)
 }
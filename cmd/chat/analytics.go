@@ -0,0 +1,155 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	oauth "github.com/webitel/chat_manager/api/proto/auth"
+	pb "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+	store "github.com/webitel/chat_manager/internal/repo/sqlx"
+)
+
+type AnalyticsService struct {
+	logs  *slog.Logger
+	authN *auth.Client
+	store store.AnalyticsStore
+}
+
+type AnalyticsServiceOption func(srv *AnalyticsService) error
+
+func AnalyticsServiceLogs(logs *slog.Logger) AnalyticsServiceOption {
+	return func(srv *AnalyticsService) error {
+		srv.logs = logs
+		return nil
+	}
+}
+
+func AnalyticsServiceAuthN(client *auth.Client) AnalyticsServiceOption {
+	return func(srv *AnalyticsService) error {
+		srv.authN = client
+		return nil
+	}
+}
+
+func AnalyticsServiceStore(store store.AnalyticsStore) AnalyticsServiceOption {
+	return func(srv *AnalyticsService) error {
+		srv.store = store
+		return nil
+	}
+}
+
+func NewAnalyticsService(opts ...AnalyticsServiceOption) *AnalyticsService {
+	srv := &AnalyticsService{}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+var _ pb.AnalyticsHandler = (*AnalyticsService)(nil)
+
+func (srv *AnalyticsService) bindNativeClient(ctx *app.Context) error {
+	authZ := &ctx.Authorization
+	if authZ.Creds == nil && authZ.Native != nil {
+		md, _ := metadata.FromContext(
+			ctx.Context,
+		)
+		dc, _ := strconv.ParseInt(
+			md["X-Webitel-Domain"], 10, 64,
+		)
+		authZ.Creds = &oauth.Userinfo{
+			Dc: dc,
+			Permissions: []*oauth.Permission{
+				&auth.PermissionSelectAny,
+			},
+			Scope: []*oauth.Objclass{{
+				Class:  scopeChats,
+				Access: "r",
+			}},
+		}
+	}
+	return nil
+}
+
+// Query of the conversation metrics aggregates
+func (srv *AnalyticsService) GetChatMetrics(ctx context.Context, req *pb.ChatMetricsRequest, res *pb.ChatMetrics) error {
+
+	// region: ----- Authentication -----
+	authN, err := app.GetContext(
+		ctx, app.AuthorizationRequire(
+			srv.authN.GetAuthorization,
+		),
+		srv.bindNativeClient,
+	)
+
+	if err != nil {
+		return err // 401
+	}
+	// endregion: ----- Authentication -----
+
+	// region: ----- Authorization -----
+	scope := authN.Authorization.HasObjclass(scopeChats)
+	if scope == nil || !authN.Authorization.CanAccess(scope, auth.READ) {
+		return errors.Forbidden(
+			"chat.objclass.access.denied",
+			"denied: require r:chats access but not granted",
+		) // (403) Forbidden
+	}
+	// Prepare SELECT request
+	search := app.SearchOptions{
+		Context: *(authN),
+		Access:  auth.READ,
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+	}
+	// Can SELECT ANY object(s) ?
+	super := &auth.PermissionSelectAny
+	if !authN.HasPermission(super.Id) {
+		// SELF related ONLY (!)
+		search.FilterAND("self", authN.Creds.GetUserId())
+	}
+	// endregion: ----- Authorization -----
+
+	// ------- Filter(s) ------- //
+	if vs := req.Group; vs != "" {
+		search.FilterAND("group", vs)
+	}
+	if vs := req.Via; vs != nil {
+		search.FilterAND("via", vs)
+	}
+	zone := time.UTC
+	if vs := req.GetTimeZone(); vs != "" {
+		zone, err = time.LoadLocation(vs)
+		if err != nil {
+			return errors.BadRequest(
+				"chat.metrics.time_zone.input",
+				"metrics( time_zone: %s ) input: invalid time zone",
+				vs,
+			)
+		}
+		search.FilterAND("tz", zone.String())
+	}
+	date := req.GetDate()
+	if date.GetSince() <= 0 && date.GetUntil() <= 0 {
+		// Current day, by default
+		currentTime := authN.Localtime().In(zone)
+		year, month, day := currentTime.Date()
+		startOfTheDay := time.Date(year, month, day, 0, 0, 0, 0, currentTime.Location())
+		date = &pb.Timerange{Since: startOfTheDay.UnixMilli(), Until: currentTime.UnixMilli()}
+	}
+	search.FilterAND("date", date)
+	// PERFORM
+	err = srv.store.GetChatMetrics(&search, res)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -349,6 +349,7 @@ func (c *chatService) executeBroadcastSocials(ctx context.Context, authUser *aut
 		conversation.CreatedAt = to.CreatedAt.Add(2 * time.Millisecond)
 	}
 
+	var fromApp *app.Channel
 	err = c.repo.WithTransaction(func(tx *sqlx.Tx) error {
 		if err := c.repo.CreateConversationTx(ctx, tx, &conversation); err != nil {
 			return err
@@ -366,13 +367,17 @@ func (c *chatService) executeBroadcastSocials(ctx context.Context, authUser *aut
 			return err
 		}
 
-		fromApp := mapChannelToAppChannel(&from)
+		fromApp = mapChannelToAppChannel(&from)
 		if _, err := c.saveMessage(ctx, tx, fromApp, message); err != nil {
 			return err
 		}
 
 		return nil
 	})
+	if err == nil {
+		// Committed
+		c.publishMessage(fromApp, message)
+	}
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.Error:
@@ -595,6 +600,7 @@ func (c *chatService) executeBroadcastPortal(ctx context.Context, authUser *auth
 		conversation.CreatedAt = to.CreatedAt.Add(2 * time.Millisecond)
 	}
 
+	var fromApp *app.Channel
 	err = c.repo.WithTransaction(func(tx *sqlx.Tx) error {
 		if err := c.repo.CreateConversationTx(ctx, tx, &conversation); err != nil {
 			return err
@@ -612,13 +618,17 @@ func (c *chatService) executeBroadcastPortal(ctx context.Context, authUser *auth
 			return err
 		}
 
-		fromApp := mapChannelToAppChannel(&from)
+		fromApp = mapChannelToAppChannel(&from)
 		if _, err := c.saveMessage(ctx, tx, fromApp, message); err != nil {
 			return err
 		}
 
 		return nil
 	})
+	if err == nil {
+		// Committed
+		c.publishMessage(fromApp, message)
+	}
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.Error:
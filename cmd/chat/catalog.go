@@ -219,6 +219,8 @@ func (srv *Catalog) GetDialogs(ctx context.Context, req *pb.ChatDialogsRequest,
 				[]string{
 					"members",
 					"context",
+					"tags",
+					"disposition",
 				},
 			),
 		),
@@ -257,6 +259,9 @@ func (srv *Catalog) GetDialogs(ctx context.Context, req *pb.ChatDialogsRequest,
 		rated := vs.GetValue()
 		search.FilterAND("rated", &rated)
 	}
+	if vs := req.Tag; len(vs) > 0 {
+		search.FilterAND("tag", vs)
+	}
 	if vs := req.Group; len(vs) > 0 {
 		if delete(vs, ""); len(vs) > 0 {
 			search.FilterAND("group", vs)
@@ -742,3 +747,65 @@ func (srv *Catalog) GetUpdates(ctx context.Context, req *pb.ChatMessagesRequest,
 
 	return nil
 }
+
+// Query of the post-chat survey rating aggregates
+func (srv *Catalog) GetRatings(ctx context.Context, req *pb.ChatRatingsRequest, res *pb.ChatRatings) error {
+
+	// region: ----- Authentication -----
+	authN, err := app.GetContext(
+		ctx, app.AuthorizationRequire(
+			srv.authN.GetAuthorization,
+		),
+		srv.bindNativeClient,
+	)
+
+	if err != nil {
+		return err // 401
+	}
+	// endregion: ----- Authentication -----
+
+	// region: ----- Authorization -----
+	scope := authN.Authorization.HasObjclass(scopeChats)
+	if scope == nil {
+		return errors.Forbidden(
+			"chat.objclass.access.denied",
+			"denied: require r:chats access but not granted",
+		) // (403) Forbidden
+	}
+	// Prepare SELECT request
+	search := app.SearchOptions{
+		Context: *(authN),
+		Access:  auth.READ,
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+	}
+	// Can SELECT ANY object(s) ?
+	super := &auth.PermissionSelectAny
+	if !authN.HasPermission(super.Id) {
+		// SELF related ONLY (!)
+		search.FilterAND("self", authN.Creds.GetUserId())
+	}
+	// endregion: ----- Authorization -----
+
+	// ------- Filter(s) ------- //
+	if vs := req.Group; vs != "" {
+		search.FilterAND("group", vs)
+	}
+	if vs := req.Via; vs != nil {
+		search.FilterAND("via", vs)
+	}
+	if vs := req.Date; vs != nil {
+		search.FilterAND("date", vs)
+	}
+	if vs := req.Scale; vs != "" {
+		search.FilterAND("scale", vs)
+	}
+	// PERFORM
+	err = srv.store.GetRatings(&search, res)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,398 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	oauth "github.com/webitel/chat_manager/api/proto/auth"
+	pb "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+	store "github.com/webitel/chat_manager/internal/repo/sqlx"
+)
+
+// Conversation variables exported to the flow
+const (
+	// Comma-separated list of the conversation tags name
+	chatTagsVariable = "tags"
+	// Wrap-up disposition code name of the conversation
+	chatDispositionVariable = "disposition"
+)
+
+type DispositionsService struct {
+	logs  *slog.Logger
+	authN *auth.Client
+	store store.DispositionStore
+	chats store.ChatStore
+}
+
+type DispositionsServiceOption func(srv *DispositionsService) error
+
+func DispositionsServiceLogs(logs *slog.Logger) DispositionsServiceOption {
+	return func(srv *DispositionsService) error {
+		srv.logs = logs
+		return nil
+	}
+}
+
+func DispositionsServiceAuthN(client *auth.Client) DispositionsServiceOption {
+	return func(srv *DispositionsService) error {
+		srv.authN = client
+		return nil
+	}
+}
+
+func DispositionsServiceStore(store store.DispositionStore) DispositionsServiceOption {
+	return func(srv *DispositionsService) error {
+		srv.store = store
+		return nil
+	}
+}
+
+func DispositionsServiceChatStore(store store.ChatStore) DispositionsServiceOption {
+	return func(srv *DispositionsService) error {
+		srv.chats = store
+		return nil
+	}
+}
+
+func NewDispositionsService(opts ...DispositionsServiceOption) *DispositionsService {
+	srv := &DispositionsService{}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+var _ pb.DispositionsHandler = (*DispositionsService)(nil)
+
+func (srv *DispositionsService) bindNativeClient(ctx *app.Context) error {
+	authZ := &ctx.Authorization
+	if authZ.Creds == nil && authZ.Native != nil {
+		md, _ := metadata.FromContext(
+			ctx.Context,
+		)
+		dc, _ := strconv.ParseInt(
+			md["X-Webitel-Domain"], 10, 64,
+		)
+		authZ.Creds = &oauth.Userinfo{
+			Dc: dc,
+			Permissions: []*oauth.Permission{
+				&auth.PermissionSelectAny,
+				&auth.PermissionUpdateAny,
+			},
+			Scope: []*oauth.Objclass{{
+				Class:  scopeChats,
+				Access: "rw",
+			}},
+		}
+	}
+	return nil
+}
+
+// authorize the request to perform given access mode on chats objclass
+func (srv *DispositionsService) authorize(ctx context.Context, mode auth.AccessMode, perm string) (*app.Context, error) {
+
+	// region: ----- Authentication -----
+	authN, err := app.GetContext(
+		ctx, app.AuthorizationRequire(
+			srv.authN.GetAuthorization,
+		),
+		srv.bindNativeClient,
+	)
+
+	if err != nil {
+		return nil, err // 401
+	}
+	// endregion: ----- Authentication -----
+
+	// region: ----- Authorization -----
+	scope := authN.Authorization.HasObjclass(scopeChats)
+	if scope == nil || !authN.Authorization.CanAccess(scope, mode) {
+		return nil, errors.Forbidden(
+			"chat.objclass.access.denied",
+			"denied: require %s:chats access but not granted",
+			perm,
+		) // (403) Forbidden
+	}
+	// endregion: ----- Authorization -----
+
+	return authN, nil
+}
+
+func (srv *DispositionsService) searchLabels(ctx context.Context, dict string, req *pb.SearchLabelRequest, res *pb.LabelList) error {
+
+	authN, err := srv.authorize(ctx, auth.READ, "r")
+	if err != nil {
+		return err
+	}
+
+	search := app.SearchOptions{
+		Context: *(authN),
+		ID:      req.GetId(),
+		Term:    req.GetQ(),
+		Access:  auth.READ,
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+	}
+
+	return srv.store.SearchLabels(&search, dict, res)
+}
+
+func (srv *DispositionsService) createLabel(ctx context.Context, dict string, req, res *pb.Label) error {
+
+	req.Name = strings.TrimSpace(req.GetName())
+	if req.Name == "" {
+		return errors.BadRequest(
+			"chat."+dict+".name.required",
+			"chat: %s name required",
+			dict,
+		)
+	}
+
+	authN, err := srv.authorize(ctx, auth.ADD, "x")
+	if err != nil {
+		return err
+	}
+
+	create := app.CreateOptions{
+		Context: *(authN),
+	}
+
+	res.Name = req.GetName()
+	res.Description = strings.TrimSpace(req.GetDescription())
+
+	return srv.store.CreateLabel(&create, dict, res)
+}
+
+func (srv *DispositionsService) updateLabel(ctx context.Context, dict string, req, res *pb.Label) error {
+
+	if req.GetId() <= 0 {
+		return errors.BadRequest(
+			"chat."+dict+".id.required",
+			"chat: %s id required",
+			dict,
+		)
+	}
+
+	req.Name = strings.TrimSpace(req.GetName())
+	if req.Name == "" {
+		return errors.BadRequest(
+			"chat."+dict+".name.required",
+			"chat: %s name required",
+			dict,
+		)
+	}
+
+	authN, err := srv.authorize(ctx, auth.WRITE, "w")
+	if err != nil {
+		return err
+	}
+
+	update := app.UpdateOptions{
+		Context: *(authN),
+	}
+
+	res.Id = req.GetId()
+	res.Name = req.GetName()
+	res.Description = strings.TrimSpace(req.GetDescription())
+
+	return srv.store.UpdateLabel(&update, dict, res)
+}
+
+func (srv *DispositionsService) deleteLabel(ctx context.Context, dict string, req *pb.DeleteLabelRequest, res *pb.Label) error {
+
+	if req.GetId() <= 0 {
+		return errors.BadRequest(
+			"chat."+dict+".id.required",
+			"chat: %s id required",
+			dict,
+		)
+	}
+
+	authN, err := srv.authorize(ctx, auth.DELETE, "d")
+	if err != nil {
+		return err
+	}
+
+	del := app.DeleteOptions{
+		Context: *(authN),
+		ID:      []int64{req.GetId()},
+	}
+
+	obj, err := srv.store.DeleteLabel(&del, dict)
+	if err != nil {
+		return err
+	}
+
+	res.Id = obj.GetId()
+	res.Name = obj.GetName()
+	res.Description = obj.GetDescription()
+	res.CreatedAt = obj.GetCreatedAt()
+	res.UpdatedAt = obj.GetUpdatedAt()
+	return nil
+}
+
+// SearchTags query of the domain's conversation tags dictionary
+func (srv *DispositionsService) SearchTags(ctx context.Context, req *pb.SearchLabelRequest, res *pb.LabelList) error {
+	return srv.searchLabels(ctx, store.LabelTag, req, res)
+}
+
+// CreateTag new conversation tag
+func (srv *DispositionsService) CreateTag(ctx context.Context, req *pb.Label, res *pb.Label) error {
+	return srv.createLabel(ctx, store.LabelTag, req, res)
+}
+
+// UpdateTag conversation tag
+func (srv *DispositionsService) UpdateTag(ctx context.Context, req *pb.Label, res *pb.Label) error {
+	return srv.updateLabel(ctx, store.LabelTag, req, res)
+}
+
+// DeleteTag conversation tag
+func (srv *DispositionsService) DeleteTag(ctx context.Context, req *pb.DeleteLabelRequest, res *pb.Label) error {
+	return srv.deleteLabel(ctx, store.LabelTag, req, res)
+}
+
+// SearchDispositions query of the domain's wrap-up disposition codes dictionary
+func (srv *DispositionsService) SearchDispositions(ctx context.Context, req *pb.SearchLabelRequest, res *pb.LabelList) error {
+	return srv.searchLabels(ctx, store.LabelDisposition, req, res)
+}
+
+// CreateDisposition new disposition code
+func (srv *DispositionsService) CreateDisposition(ctx context.Context, req *pb.Label, res *pb.Label) error {
+	return srv.createLabel(ctx, store.LabelDisposition, req, res)
+}
+
+// UpdateDisposition code
+func (srv *DispositionsService) UpdateDisposition(ctx context.Context, req *pb.Label, res *pb.Label) error {
+	return srv.updateLabel(ctx, store.LabelDisposition, req, res)
+}
+
+// DeleteDisposition code
+func (srv *DispositionsService) DeleteDisposition(ctx context.Context, req *pb.DeleteLabelRequest, res *pb.Label) error {
+	return srv.deleteLabel(ctx, store.LabelDisposition, req, res)
+}
+
+// labelConversation authorizes the agent to label the conversation.
+// Conversation may be closed already; wrap-up.
+func (srv *DispositionsService) labelConversation(ctx context.Context, chatID string) (*app.UpdateOptions, error) {
+
+	if chatID == "" {
+		return nil, errors.BadRequest(
+			"chat.conversation.id.required",
+			"chat: conversation id required",
+		)
+	}
+
+	authN, err := srv.authorize(ctx, auth.WRITE, "w")
+	if err != nil {
+		return nil, err
+	}
+
+	// Can UPDATE ANY object(s) ?
+	super := &auth.PermissionUpdateAny
+	if !authN.HasPermission(super.Id) {
+		// Conversation member ONLY (!)
+		ok, err := srv.store.IsConversationMember(
+			authN.Context, chatID, authN.Creds.GetUserId(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.Forbidden(
+				"chat.conversation.access.denied",
+				"denied: not a member of the conversation id=%s",
+				chatID,
+			) // (403) Forbidden
+		}
+	}
+
+	return &app.UpdateOptions{
+		Context: *(authN),
+	}, nil
+}
+
+// exportVariables binds the conversation labels to be available for the flow
+func (srv *DispositionsService) exportVariables(ctx context.Context, chatID string, vars map[string]string) {
+	_, err := srv.chats.BindChannel(ctx, chatID, vars)
+	if err != nil {
+		srv.logs.Warn("[ CHAT::LABEL ] export variables",
+			slog.String("conversation_id", chatID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// SetConversationTags replaces tags of the conversation
+func (srv *DispositionsService) SetConversationTags(ctx context.Context, req *pb.SetConversationTagsRequest, res *pb.ConversationLabels) error {
+
+	chatID := req.GetChatId()
+	update, err := srv.labelConversation(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	tags, err := srv.store.SetConversationTags(update, chatID, req.GetTags())
+	if err != nil {
+		return err
+	}
+
+	if tags == nil {
+		return errors.NotFound(
+			"chat.conversation.id.not_found",
+			"chat: conversation id=%s not found",
+			chatID,
+		)
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.GetName())
+	}
+
+	srv.exportVariables(update.Context.Context, chatID, map[string]string{
+		chatTagsVariable: strings.Join(names, ","),
+	})
+
+	res.ChatId = chatID
+	res.Tags = tags
+	return nil
+}
+
+// SetDisposition sets wrap-up disposition of the conversation
+func (srv *DispositionsService) SetDisposition(ctx context.Context, req *pb.SetDispositionRequest, res *pb.ConversationLabels) error {
+
+	chatID := req.GetChatId()
+	update, err := srv.labelConversation(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	disposition, ok, err := srv.store.SetConversationDisposition(
+		update, chatID, req.GetDisposition(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.NotFound(
+			"chat.conversation.id.not_found",
+			"chat: conversation id=%s not found",
+			chatID,
+		)
+	}
+
+	srv.exportVariables(update.Context.Context, chatID, map[string]string{
+		chatDispositionVariable: disposition.GetName(),
+	})
+
+	res.ChatId = chatID
+	res.Disposition = disposition
+	return nil
+}
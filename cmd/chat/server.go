@@ -8,6 +8,7 @@ import (
 
 	micro "github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/registry"
 	"github.com/micro/micro/v3/service/server"
 	microgrpcsrv "github.com/micro/micro/v3/service/server/grpc"
 	"github.com/urfave/cli/v2"
@@ -31,10 +32,13 @@ import (
 	"github.com/webitel/chat_manager/internal/auth"
 	event "github.com/webitel/chat_manager/internal/event_router"
 	"github.com/webitel/chat_manager/internal/flow"
+	"github.com/webitel/chat_manager/internal/health"
 	pg "github.com/webitel/chat_manager/internal/repo/sqlx"
+	"github.com/webitel/chat_manager/internal/webhook"
 	"github.com/webitel/chat_manager/internal/wrapper"
 	"github.com/webitel/chat_manager/log"
 	"github.com/webitel/chat_manager/otel"
+	"github.com/webitel/chat_manager/otel/prometheus"
 	"github.com/webitel/chat_manager/store/postgres"
 )
 
@@ -60,6 +64,34 @@ var (
 			EnvVars: []string{"WBTL_LOG_LEVEL"},
 			Usage:   "Log Level",
 		},
+		&cli.StringFlag{
+			Name:    "address",
+			EnvVars: []string{"WEBITEL_CHAT_ADDRESS"},
+			Usage:   "Bind address for the debug HTTP server: pprof, expvar and traces. Keep it local.",
+			Value:   "127.0.0.1:6060",
+		},
+		&cli.StringFlag{
+			Name:    "internal-address",
+			EnvVars: []string{"WEBITEL_CHAT_INTERNAL_ADDRESS"},
+			Usage:   "Bind address for the internal HTTP server: health, readiness and metrics endpoints. Empty disables.",
+			Value:   ":6061",
+		},
+		&cli.BoolFlag{
+			Name:    "user-action-events",
+			EnvVars: []string{"WEBITEL_CHAT_USER_ACTION_EVENTS"},
+			Usage:   "Publish user_action (typing) events of the chat partners to the agents.",
+			Value:   true,
+		},
+		&cli.BoolFlag{
+			Name:    "webhooks-allow-http",
+			EnvVars: []string{"WEBITEL_CHAT_WEBHOOKS_ALLOW_HTTP"},
+			Usage:   "Allow plaintext http:// webhook URLs; https:// only otherwise.",
+		},
+		&cli.BoolFlag{
+			Name:    "webhooks-allow-private",
+			EnvVars: []string{"WEBITEL_CHAT_WEBHOOKS_ALLOW_PRIVATE"},
+			Usage:   "Allow webhook targets within loopback, private and link-local networks.",
+		},
 		&cli.StringFlag{
 			Name:    "db-dsn",
 			EnvVars: []string{"WEBITEL_DBO_ADDRESS"},
@@ -119,10 +151,16 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
-	err := otel.Configure(ctx.Context,
+	// Prometheus [/metrics] exposition
+	metrics, err := prometheus.NewExporter()
+	if err != nil {
+		return err // log(FATAL) & exit(1)
+	}
+	err = otel.Configure(ctx.Context,
 		otelsdk.WithResource(resource.NewSchemaless(
 			resourceAttrs...,
 		)),
+		metrics.Option(),
 	)
 	if err != nil {
 		// fatal(fmt.Errorf("[O]pen[Tel]emetry configuration failed"))
@@ -194,7 +232,17 @@ func Run(ctx *cli.Context) error {
 	auth := auth.NewClient(stdlog, authClient)
 	eventRouter := event.NewRouter(botClient /*flow,*/, broker.DefaultBroker, store, stdlog)
 
-	serv := NewChatService(store, stdlog, flow, auth, botClient, storageClient, eventRouter)
+	webhooks := webhook.NewDispatcher(store, stdlog, 4)
+	webhooks.Policy = webhook.Policy{
+		AllowHTTP:    ctx.Bool("webhooks-allow-http"),
+		AllowPrivate: ctx.Bool("webhooks-allow-private"),
+	}
+	defer webhooks.Close()
+
+	serv := NewChatService(
+		store, stdlog, flow, auth, botClient, storageClient, eventRouter, webhooks,
+		ctx.Bool("user-action-events"),
+	)
 
 	for _, regErr := range []error{
 		pb.RegisterChatServiceHandler(service.Server(), serv),
@@ -309,10 +357,109 @@ func Run(ctx *cli.Context) error {
 		return err
 	}
 
+	dispositions := NewDispositionsService(
+		DispositionsServiceLogs(stdlog),
+		DispositionsServiceAuthN(authN.NewClient(
+			authN.ClientService(service),
+			authN.ClientCache(authN.NewLru(4096)),
+		)),
+		DispositionsServiceStore(store),
+		DispositionsServiceChatStore(store),
+	)
+
+	if err := pb2.RegisterDispositionsHandler(
+		service.Server(), dispositions,
+	); err != nil {
+		log.FataLog(stdlog,
+			"failed to register service",
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	analytics := NewAnalyticsService(
+		AnalyticsServiceLogs(stdlog),
+		AnalyticsServiceAuthN(authN.NewClient(
+			authN.ClientService(service),
+			authN.ClientCache(authN.NewLru(4096)),
+		)),
+		AnalyticsServiceStore(store),
+	)
+
+	if err := pb2.RegisterAnalyticsHandler(
+		service.Server(), analytics,
+	); err != nil {
+		log.FataLog(stdlog,
+			"failed to register service",
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	updates := NewUpdatesService(
+		UpdatesServiceLogs(stdlog),
+		UpdatesServiceAuthN(authN.NewClient(
+			authN.ClientService(service),
+			authN.ClientCache(authN.NewLru(4096)),
+		)),
+		UpdatesServiceStore(store),
+		UpdatesServiceBroker(broker.DefaultBroker),
+	)
+
+	if err := pb2.RegisterUpdatesHandler(
+		service.Server(), updates,
+	); err != nil {
+		log.FataLog(stdlog,
+			"failed to register service",
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	webhooksService := NewWebhooksService(
+		WebhooksServiceLogs(stdlog),
+		WebhooksServiceAuthN(authN.NewClient(
+			authN.ClientService(service),
+			authN.ClientCache(authN.NewLru(4096)),
+		)),
+		WebhooksServiceStore(store),
+		WebhooksServiceDispatcher(webhooks),
+	)
+
+	if err := pb2.RegisterWebhooksHandler(
+		service.Server(), webhooksService,
+	); err != nil {
+		log.FataLog(stdlog,
+			"failed to register service",
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	probes := health.NewChecker().
+		Add("postgres", health.Postgres(dbo.DB)).
+		Add("broker", health.Broker(broker.DefaultBroker)).
+		Add("registry", health.Registry(registry.DefaultRegistry, name))
+
+	// Probes and metrics; NEVER the http.DefaultServeMux (/debug/)
+	if addr := ctx.String("internal-address"); addr != "" {
+		rmux := http.NewServeMux()
+		probes.Register(rmux)
+		rmux.Handle("/metrics", metrics)
+		internal := http.Server{
+			Addr:    addr,
+			Handler: rmux,
+		}
+		go func() {
+			_ = internal.ListenAndServe()
+		}()
+		defer internal.Close()
+	}
+
 	///debug/events
 	///debug/requests
 	httpsrv := http.Server{
-		Addr: "127.0.0.1:6060",
+		Addr: ctx.String("address"),
 	}
 	go func() {
 		_ = httpsrv.ListenAndServe()
@@ -32,6 +32,7 @@ import (
 	"github.com/webitel/chat_manager/internal/keyboard"
 	pg "github.com/webitel/chat_manager/internal/repo/sqlx"
 	"github.com/webitel/chat_manager/internal/util"
+	"github.com/webitel/chat_manager/internal/webhook"
 	wlog "github.com/webitel/chat_manager/log"
 	"github.com/webitel/chat_manager/pkg/events"
 )
@@ -53,6 +54,7 @@ type Service interface {
 	DeleteMessage(ctx context.Context, req *pbchat.DeleteMessageRequest, res *pbchat.HistoryMessage) error
 	StartConversation(ctx context.Context, req *pbchat.StartConversationRequest, res *pbchat.StartConversationResponse) error
 	CloseConversation(ctx context.Context, req *pbchat.CloseConversationRequest, res *pbchat.CloseConversationResponse) error
+	RateConversation(ctx context.Context, req *pbchat.RateConversationRequest, res *pbchat.RateConversationResponse) error
 	JoinConversation(ctx context.Context, req *pbchat.JoinConversationRequest, res *pbchat.JoinConversationResponse) error
 	LeaveConversation(ctx context.Context, req *pbchat.LeaveConversationRequest, res *pbchat.LeaveConversationResponse) error
 	InviteToConversation(ctx context.Context, req *pbchat.InviteToConversationRequest, res *pbchat.InviteToConversationResponse) error
@@ -78,6 +80,10 @@ type chatService struct {
 	botClient     pbbot.BotsService
 	storageClient pbstorage.FileService
 	eventRouter   event.Router
+	webhooks      *webhook.Dispatcher
+	// Publish [user_action] events, e.g. typing,
+	// to the internal members (agents). Optional
+	userActions bool
 }
 
 var _ pbchat.ChatServiceHandler = (*chatService)(nil)
@@ -90,6 +96,8 @@ func NewChatService(
 	botClient pbbot.BotsService,
 	storageClient pbstorage.FileService,
 	eventRouter event.Router,
+	webhooks *webhook.Dispatcher,
+	userActions bool,
 ) Service {
 	return &chatService{
 		repo,
@@ -99,6 +107,8 @@ func NewChatService(
 		botClient,
 		storageClient,
 		eventRouter,
+		webhooks,
+		userActions,
 	}
 }
 
@@ -683,6 +693,7 @@ func (s *chatService) StartConversation(
 		}
 
 		// Save historical START conversation message ...
+		// NOTE: published with the conversation_start webhook event below
 		if _, err := s.saveMessage(ctx, tx, &sender, startMessage); err != nil {
 			return err
 		}
@@ -702,6 +713,14 @@ func (s *chatService) StartConversation(
 		return err
 	}
 
+	s.webhooks.Publish(conversation.DomainID, webhook.ConversationStart, map[string]any{
+		"conversation_id": conversation.ID,
+		"channel_id":      channel.ID,
+		"user":            req.GetUser(),
+		"title":           title,
+		"message":         startMessage,
+	})
+
 	if !req.GetUser().GetInternal() {
 		// // // profileID, providerNode, err :=
 		// // profileID, _, err := event.ContactProfileNode(req.GetUser().GetConnection())
@@ -932,6 +951,17 @@ func (s *chatService) CloseConversation(
 		return err
 	}
 
+	s.webhooks.Publish(chat.DomainID, webhook.ConversationClose, map[string]any{
+		"conversation_id":   targetChatID,
+		"closer_channel_id": senderChatID,
+		"cause":             cause,
+	})
+
+	// Post-chat survey; if configured
+	if req.GetCause() != pbchat.CloseConversationCause_broadcast_end {
+		s.sendChatSurvey(ctx, chat)
+	}
+
 	// +OK
 	return nil
 }
@@ -2862,9 +2892,28 @@ func (c *chatService) saveMessage(ctx context.Context, dcx sqlx.ExtContext, send
 	}
 	// endregion
 
+	// NOTE: within transaction the event is published by the caller on commit
+	if _, ok := dcx.(*sqlx.Tx); !ok {
+		c.publishMessage(sender, sendMessage)
+	}
+
 	return saveMessage, nil
 }
 
+// publishMessage webhook event of the message saved by the sender channel
+func (c *chatService) publishMessage(sender *app.Channel, message *pbchat.Message) {
+	targetChatID := sender.Chat.Invite
+	senderChatID := sender.Chat.ID
+	if senderChatID == "" {
+		senderChatID = targetChatID
+	}
+	c.webhooks.Publish(sender.DomainID, webhook.Message, map[string]any{
+		"conversation_id": targetChatID,
+		"channel_id":      senderChatID,
+		"message":         message,
+	})
+}
+
 // SendMessage publishes given message to all related recepients
 // Override: event_router.RouteMessage()
 func (c *chatService) sendMessage(ctx context.Context, chatRoom *app.Session, notify *pbchat.Message) (sent int, err error) {
@@ -3775,6 +3824,17 @@ func (c *chatService) SendUserAction(ctx context.Context, req *pbchat.SendUserAc
 
 		switch member.Channel {
 		case "websocket": // TO: engine (internal)
+			if c.userActions {
+				err := c.eventRouter.SendUserActionToWebitelUser(member, req)
+				if err != nil {
+					c.log.Warn("ACTION [TO]",
+						slog.Any("error", err),
+						slog.String("channel_id", req.ChannelId),
+					)
+					continue
+				}
+				res.Ok = true
+			}
 		case "chatflow": // TO: workflow (internal)
 		default: // TO: webitel.chat.bot (external)
 			{
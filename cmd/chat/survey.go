@@ -0,0 +1,176 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/micro/micro/v3/service/errors"
+	pbbot "github.com/webitel/chat_manager/api/proto/bot"
+	pbchat "github.com/webitel/chat_manager/api/proto/chat"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/bot"
+	"github.com/webitel/chat_manager/internal/contact"
+	pg "github.com/webitel/chat_manager/internal/repo/sqlx"
+)
+
+// sendChatSurvey sends the post-chat survey, if configured,
+// to the external member(s) of the closed chat session
+func (c *chatService) sendChatSurvey(ctx context.Context, chat *app.Session) {
+
+	members := make([]*app.Channel, 1+len(chat.Members))
+	members[0] = chat.Channel
+	copy(members[1:], chat.Members)
+
+	for _, member := range members {
+
+		if member.IsClosed() {
+			continue // left before
+		}
+
+		switch member.Chat.Channel {
+		case "websocket", "chatflow":
+			continue // internal
+		}
+
+		if member.User.Channel == "portal" {
+			continue // no gateway
+		}
+
+		log := c.log.With(
+			slog.String("conversation_id", member.Chat.Invite),
+			slog.String("channel_id", member.Chat.ID),
+			slog.String("contact", member.Chat.Contact),
+		)
+
+		profileID, _, err := contact.ContactObjectNode(member.Chat.Contact)
+		if err != nil || profileID == 0 {
+			log.Warn("[ CHAT::SURVEY ] invalid gateway contact")
+			continue
+		}
+
+		gate, err := c.repo.GetChatBotByID(ctx, profileID)
+		if err != nil {
+			log.Error("[ CHAT::SURVEY ] lookup gateway",
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		if gate == nil || !gate.Survey.Valid {
+			continue // disabled
+		}
+
+		var survey pbbot.ChatSurvey
+		err = json.Unmarshal(gate.Survey.JSONText, &survey)
+		if err != nil {
+			log.Error("[ CHAT::SURVEY ] decode settings",
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		if survey.GetQuestion() == "" {
+			continue // disabled
+		}
+
+		peer := member.User
+		notice, err := bot.SurveyMessage(
+			&survey, member.Chat.Invite, &pbchat.Account{
+				Id:        peer.ID,
+				Channel:   peer.Channel,
+				Contact:   peer.Contact,
+				FirstName: peer.FirstName,
+				LastName:  peer.LastName,
+				Username:  peer.UserName,
+			},
+		)
+		if err != nil {
+			log.Error("[ CHAT::SURVEY ] build message",
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		notice.CreatedAt = app.DateTimestamp(app.CurrentTime())
+		err = c.eventRouter.SendMessageToGateway(chat.Channel, member, notice)
+		if err != nil {
+			log.Error("[ CHAT::SURVEY ] send message",
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		log.Debug("[ CHAT::SURVEY ] sent")
+	}
+}
+
+// RateConversation records the post-chat survey answer
+// from the text gateway; even if the conversation is closed
+func (c *chatService) RateConversation(ctx context.Context, req *pbchat.RateConversationRequest, res *pbchat.RateConversationResponse) error {
+
+	conversationID := req.GetConversationId()
+	if conversationID == "" {
+		return errors.BadRequest(
+			"chat.rate.conversation.required",
+			"rate: conversation ID required",
+		)
+	}
+
+	profileID := req.GetProfileId()
+	if profileID == 0 {
+		return errors.BadRequest(
+			"chat.rate.profile.required",
+			"rate: gateway profile ID required",
+		)
+	}
+
+	scale := strings.ToLower(req.GetScale())
+	if scale == "" {
+		scale = bot.SurveyScaleCSAT
+	}
+	min, max, ok := bot.SurveyScale(scale)
+	if !ok {
+		return errors.BadRequest(
+			"chat.rate.scale.invalid",
+			"rate: scale %q not supported",
+			scale,
+		)
+	}
+
+	rating := req.GetRating()
+	if rating < min || max < rating {
+		return errors.BadRequest(
+			"chat.rate.rating.invalid",
+			"rate: %s rating %d out of range [%d..%d]",
+			scale, rating, min, max,
+		)
+	}
+
+	ok, err := c.repo.RateConversation(ctx, &pg.ConversationRating{
+		ConversationID: conversationID,
+		GatewayID:      profileID,
+		Scale:          scale,
+		Rating:         rating,
+		Comment:        strings.TrimSpace(req.GetComment()),
+	})
+
+	if err != nil {
+		c.log.Error("[ CHAT::SURVEY ] store rating",
+			slog.String("conversation_id", conversationID),
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	if !ok {
+		return errors.NotFound(
+			"chat.rate.conversation.not_found",
+			"rate: conversation ID=%s not found",
+			conversationID,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/micro/micro/v3/service/errors"
+	pbchat "github.com/webitel/chat_manager/api/proto/chat"
+	pg "github.com/webitel/chat_manager/internal/repo/sqlx"
+)
+
+type ratingRepo struct {
+	pg.Repository
+	rated *pg.ConversationRating
+	found bool
+	via   int64 // client's gateway
+}
+
+func (r *ratingRepo) RateConversation(_ context.Context, rate *pg.ConversationRating) (bool, error) {
+	r.rated = rate
+	return r.found && rate.GatewayID == r.via, nil
+}
+
+func TestRateConversation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *pbchat.RateConversationRequest
+		found   bool
+		wantErr int32 // code
+	}{
+		{
+			name:  "csat",
+			req:   &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 1, Rating: 5, Comment: " Thanks "},
+			found: true,
+		},
+		{
+			name:  "nps",
+			req:   &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 1, Scale: "NPS", Rating: 0},
+			found: true,
+		},
+		{
+			name:    "out of range",
+			req:     &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 1, Scale: "thumbs", Rating: 2},
+			wantErr: 400,
+		},
+		{
+			name:    "scale",
+			req:     &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 1, Scale: "stars", Rating: 1},
+			wantErr: 400,
+		},
+		{
+			name:    "conversation",
+			req:     &pbchat.RateConversationRequest{ProfileId: 1, Rating: 1},
+			wantErr: 400,
+		},
+		{
+			name:    "foreign gateway",
+			req:     &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 2, Rating: 5},
+			found:   true,
+			wantErr: 404,
+		},
+		{
+			name:    "not found",
+			req:     &pbchat.RateConversationRequest{ConversationId: "c1", ProfileId: 1, Rating: 1},
+			wantErr: 404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ratingRepo{found: tt.found, via: 1}
+			srv := &chatService{repo: repo, log: slog.Default()}
+			err := srv.RateConversation(context.Background(), tt.req, &pbchat.RateConversationResponse{})
+			var code int32
+			if err != nil {
+				code = errors.FromError(err).Code
+			}
+			if code != tt.wantErr {
+				t.Fatalf("RateConversation() error = %v, want %d", err, tt.wantErr)
+			}
+			if tt.wantErr == 400 {
+				if repo.rated != nil {
+					t.Error("RateConversation() stored invalid rating")
+				}
+				return
+			}
+			if tt.req.Comment != "" && repo.rated.Comment != "Thanks" || repo.rated.Scale != strings.ToLower(tt.req.Scale) && tt.req.Scale != "" {
+				t.Errorf("RateConversation() stored %+v", repo.rated)
+			}
+		})
+	}
+}
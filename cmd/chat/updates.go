@@ -0,0 +1,285 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+	store "github.com/webitel/chat_manager/internal/repo/sqlx"
+	"github.com/webitel/chat_manager/pkg/events"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Missed messages replay page size
+const updatesReplaySize = 100
+
+type UpdatesService struct {
+	logs   *slog.Logger
+	authN  *auth.Client
+	store  store.UpdatesStore
+	broker broker.Broker
+}
+
+type UpdatesServiceOption func(srv *UpdatesService) error
+
+func UpdatesServiceLogs(logs *slog.Logger) UpdatesServiceOption {
+	return func(srv *UpdatesService) error {
+		srv.logs = logs
+		return nil
+	}
+}
+
+func UpdatesServiceAuthN(client *auth.Client) UpdatesServiceOption {
+	return func(srv *UpdatesService) error {
+		srv.authN = client
+		return nil
+	}
+}
+
+func UpdatesServiceStore(store store.UpdatesStore) UpdatesServiceOption {
+	return func(srv *UpdatesService) error {
+		srv.store = store
+		return nil
+	}
+}
+
+func UpdatesServiceBroker(broker broker.Broker) UpdatesServiceOption {
+	return func(srv *UpdatesService) error {
+		srv.broker = broker
+		return nil
+	}
+}
+
+func NewUpdatesService(opts ...UpdatesServiceOption) *UpdatesService {
+	srv := &UpdatesService{}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+var _ pb.UpdatesHandler = (*UpdatesService)(nil)
+
+// Subscribe streams updates of the authenticated user's conversations
+func (srv *UpdatesService) Subscribe(ctx context.Context, req *pb.SubscribeRequest, stream pb.Updates_SubscribeStream) error {
+
+	defer stream.Close()
+
+	// region: ----- Authentication -----
+	authN, err := app.GetContext(
+		ctx, app.AuthorizationRequire(
+			srv.authN.GetAuthorization,
+		),
+	)
+
+	if err != nil {
+		return err // 401
+	}
+	// endregion: ----- Authentication -----
+
+	// region: ----- Authorization -----
+	scope := authN.Authorization.HasObjclass(scopeChats)
+	if scope == nil || !authN.Authorization.CanAccess(scope, auth.READ) {
+		return errors.Forbidden(
+			"chat.objclass.access.denied",
+			"denied: require r:chats access but not granted",
+		) // (403) Forbidden
+	}
+	var (
+		dc     = authN.Creds.GetDc()
+		userID = authN.Creds.GetUserId()
+	)
+	if dc < 1 || userID < 1 {
+		return errors.Forbidden(
+			"chat.updates.user.required",
+			"updates: end-user authorization required",
+		) // (403) Forbidden
+	}
+	// endregion: ----- Authorization -----
+
+	types := make(map[string]bool, len(req.GetTypes()))
+	for _, typeOf := range req.GetTypes() {
+		if typeOf = strings.TrimSpace(typeOf); typeOf != "" {
+			types[typeOf] = true
+		}
+	}
+	accept := func(typeOf string) bool {
+		return len(types) == 0 || types[typeOf]
+	}
+
+	log := srv.logs.With(
+		slog.Int64("domain_id", dc),
+		slog.Int64("user_id", userID),
+	)
+
+	// Subscribe first, so the events published during replay will not be missed
+	queue := make(chan *broker.Message, 64)
+	sub, err := srv.broker.Subscribe(
+		fmt.Sprintf("event.*.%d.%d", dc, userID),
+		func(msg *broker.Message) error {
+			select {
+			case queue <- msg:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+		broker.Queue(fmt.Sprintf(
+			"chat.updates.%d.%d.%s", dc, userID, uuid.NewString(),
+		)),
+	)
+	if err != nil {
+		log.Error("[ CHAT::UPDATES ] subscribe",
+			slog.Any("error", err),
+		)
+		return errors.InternalServerError(
+			"chat.updates.subscribe.error",
+			"updates: subscribe failed",
+		)
+	}
+	defer sub.Unsubscribe()
+
+	// Replay messages missed since given offset.
+	// NOTE: Other update types are not persisted, so cannot be replayed
+	offset := req.GetOffset()
+	if offset > 0 && accept(events.MessageEventType) {
+		for {
+			list, err := srv.store.GetMessageUpdates(
+				ctx, dc, userID, offset, updatesReplaySize,
+			)
+			if err != nil {
+				log.Error("[ CHAT::UPDATES ] replay",
+					slog.Int64("offset", offset),
+					slog.Any("error", err),
+				)
+				return err
+			}
+			for _, node := range list {
+				update, err := messageUpdate(node)
+				if err != nil {
+					return err
+				}
+				if err = stream.Send(update); err != nil {
+					return err
+				}
+				offset = update.Id
+			}
+			if len(list) < updatesReplaySize {
+				break
+			}
+		}
+	}
+
+	log.Debug("[ CHAT::UPDATES ] subscribed")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("[ CHAT::UPDATES ] unsubscribed")
+			return nil
+		case msg := <-queue:
+			update, err := eventUpdate(msg)
+			if err != nil {
+				log.Warn("[ CHAT::UPDATES ] decode event",
+					slog.Any("error", err),
+				)
+				continue
+			}
+			if !accept(update.Type) {
+				continue
+			}
+			if update.Type == events.MessageEventType && 0 < update.Id && update.Id <= offset {
+				continue // replayed
+			}
+			if err = stream.Send(update); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventUpdate decodes the broker event delivery.
+// Routing key: event.<type>.<domain>.<user>
+func eventUpdate(msg *broker.Message) (*pb.Update, error) {
+
+	topic := strings.Split(msg.Header["Micro-Topic"], ".")
+	if len(topic) != 4 || topic[0] != "event" {
+		return nil, fmt.Errorf("updates: invalid event topic %q", msg.Header["Micro-Topic"])
+	}
+
+	return newUpdate(topic[1], msg.Body)
+}
+
+// messageUpdate converts the missed message into the "message" update
+func messageUpdate(node *store.MessageUpdate) (*pb.Update, error) {
+
+	msg := node.Message
+	notify := events.MessageEvent{
+		BaseEvent: events.BaseEvent{
+			ConversationID: node.ChannelID,
+			Timestamp:      app.DateTimestamp(msg.CreatedAt),
+		},
+		Message: events.Message{
+			ID:        msg.ID,
+			ChannelID: msg.ChannelID,
+			Type:      msg.Type,
+			Text:      msg.Text,
+			CreatedAt: app.DateTimestamp(msg.CreatedAt),
+		},
+	}
+	if !msg.UpdatedAt.IsZero() {
+		notify.UpdatedAt = app.DateTimestamp(msg.UpdatedAt)
+	}
+	if doc := msg.File; doc != nil {
+		notify.File = &events.File{
+			ID:   doc.ID,
+			URL:  doc.URL,
+			Size: doc.Size,
+			Type: doc.Type,
+			Name: doc.Name,
+		}
+	}
+
+	data, err := json.Marshal(notify)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpdate(events.MessageEventType, data)
+}
+
+func newUpdate(typeOf string, body []byte) (*pb.Update, error) {
+
+	var event map[string]any
+	err := json.Unmarshal(body, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := structpb.NewStruct(event)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &pb.Update{
+		Type: typeOf,
+		Data: data,
+	}
+	if date, ok := event["timestamp"].(float64); ok {
+		update.Date = int64(date)
+	}
+	if typeOf == events.MessageEventType {
+		if id, ok := event["id"].(float64); ok {
+			update.Id = int64(id)
+		}
+	}
+
+	return update, nil
+}
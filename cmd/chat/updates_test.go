@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/micro/micro/v3/service/broker"
+)
+
+func TestEventUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		topic    string
+		body     string
+		wantType string
+		wantId   int64
+		wantDate int64
+		wantErr  bool
+	}{
+		{
+			name:     "message",
+			topic:    "event.message.1.10",
+			body:     `{"conversation_id":"c1","timestamp":1700000000000,"id":42,"type":"text","text":"Hi"}`,
+			wantType: "message",
+			wantId:   42,
+			wantDate: 1700000000000,
+		},
+		{
+			name:     "typing",
+			topic:    "event.user_action.1.10",
+			body:     `{"conversation_id":"c1","timestamp":1700000000001,"channel_id":"c2","action":"typing"}`,
+			wantType: "user_action",
+			wantDate: 1700000000001,
+		},
+		{
+			name:    "topic",
+			topic:   "chat.message",
+			body:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "body",
+			topic:   "event.message.1.10",
+			body:    `[`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := eventUpdate(&broker.Message{
+				Header: map[string]string{"Micro-Topic": tt.topic},
+				Body:   []byte(tt.body),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("eventUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Type != tt.wantType || got.Id != tt.wantId || got.Date != tt.wantDate {
+				t.Errorf("eventUpdate() = %s#%d@%d; want %s#%d@%d",
+					got.Type, got.Id, got.Date, tt.wantType, tt.wantId, tt.wantDate,
+				)
+			}
+		})
+	}
+}
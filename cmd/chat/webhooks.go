@@ -0,0 +1,302 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+	store "github.com/webitel/chat_manager/internal/repo/sqlx"
+	"github.com/webitel/chat_manager/internal/webhook"
+)
+
+type WebhooksService struct {
+	logs     *slog.Logger
+	authN    *auth.Client
+	store    store.WebhookStore
+	dispatch *webhook.Dispatcher
+}
+
+type WebhooksServiceOption func(srv *WebhooksService) error
+
+func WebhooksServiceLogs(logs *slog.Logger) WebhooksServiceOption {
+	return func(srv *WebhooksService) error {
+		srv.logs = logs
+		return nil
+	}
+}
+
+func WebhooksServiceAuthN(client *auth.Client) WebhooksServiceOption {
+	return func(srv *WebhooksService) error {
+		srv.authN = client
+		return nil
+	}
+}
+
+func WebhooksServiceStore(store store.WebhookStore) WebhooksServiceOption {
+	return func(srv *WebhooksService) error {
+		srv.store = store
+		return nil
+	}
+}
+
+// WebhooksServiceDispatcher to invalidate cached subscriptions on changes
+func WebhooksServiceDispatcher(dispatch *webhook.Dispatcher) WebhooksServiceOption {
+	return func(srv *WebhooksService) error {
+		srv.dispatch = dispatch
+		return nil
+	}
+}
+
+func NewWebhooksService(opts ...WebhooksServiceOption) *WebhooksService {
+	srv := &WebhooksService{}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+var _ pb.WebhooksHandler = (*WebhooksService)(nil)
+
+// authorize the request to perform given access mode on chats objclass
+func (srv *WebhooksService) authorize(ctx context.Context, mode auth.AccessMode, perm string) (*app.Context, error) {
+
+	// region: ----- Authentication -----
+	authN, err := app.GetContext(
+		ctx, app.AuthorizationRequire(
+			srv.authN.GetAuthorization,
+		),
+	)
+
+	if err != nil {
+		return nil, err // 401
+	}
+	// endregion: ----- Authentication -----
+
+	// region: ----- Authorization -----
+	scope := authN.Authorization.HasObjclass(scopeChats)
+	if scope == nil || !authN.Authorization.CanAccess(scope, mode) {
+		return nil, errors.Forbidden(
+			"chat.objclass.access.denied",
+			"denied: require %s:chats access but not granted",
+			perm,
+		) // (403) Forbidden
+	}
+	// endregion: ----- Authorization -----
+
+	return authN, nil
+}
+
+// validate webhook input; normalized
+func (srv *WebhooksService) validate(ctx context.Context, req *pb.Webhook) error {
+
+	req.Name = strings.TrimSpace(req.GetName())
+	if req.Name == "" {
+		return errors.BadRequest(
+			"chat.webhook.name.required",
+			"chat: webhook name required",
+		)
+	}
+
+	req.Url = strings.TrimSpace(req.GetUrl())
+	if req.Url == "" {
+		return errors.BadRequest(
+			"chat.webhook.url.required",
+			"chat: webhook url required",
+		)
+	}
+	// scheme and target address; NOT the internal network
+	err := srv.dispatch.CheckURL(ctx, req.Url)
+	if err != nil {
+		return errors.BadRequest(
+			"chat.webhook.url.invalid",
+			"chat: webhook url %q is invalid; %v",
+			req.Url, err,
+		)
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event == "" {
+			continue
+		}
+		supported := false
+		for _, typ := range webhook.EventTypes {
+			if event == typ {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.BadRequest(
+				"chat.webhook.events.invalid",
+				"chat: webhook event %q is not supported; expect any of: %s",
+				event, strings.Join(webhook.EventTypes, ", "),
+			)
+		}
+		events = append(events, event)
+	}
+	req.Events = events
+
+	return nil
+}
+
+// SearchWebhooks query of the domain's webhook subscriptions
+func (srv *WebhooksService) SearchWebhooks(ctx context.Context, req *pb.SearchWebhookRequest, res *pb.WebhookList) error {
+
+	authN, err := srv.authorize(ctx, auth.READ, "r")
+	if err != nil {
+		return err
+	}
+
+	search := app.SearchOptions{
+		Context: *(authN),
+		ID:      req.GetId(),
+		Term:    req.GetQ(),
+		Access:  auth.READ,
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+	}
+
+	return srv.store.SearchWebhooks(&search, res)
+}
+
+// CreateWebhook subscription
+func (srv *WebhooksService) CreateWebhook(ctx context.Context, req *pb.Webhook, res *pb.Webhook) error {
+
+	err := srv.validate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	authN, err := srv.authorize(ctx, auth.ADD, "x")
+	if err != nil {
+		return err
+	}
+
+	create := app.CreateOptions{
+		Context: *(authN),
+	}
+
+	res.Name = req.GetName()
+	res.Url = req.GetUrl()
+	res.Secret = req.GetSecret()
+	res.Events = req.GetEvents()
+	res.Enabled = req.GetEnabled()
+
+	err = srv.store.CreateWebhook(&create, res)
+	if err != nil {
+		return err
+	}
+
+	srv.dispatch.Invalidate(authN.Creds.GetDc())
+	return nil
+}
+
+// UpdateWebhook subscription
+func (srv *WebhooksService) UpdateWebhook(ctx context.Context, req *pb.Webhook, res *pb.Webhook) error {
+
+	if req.GetId() <= 0 {
+		return errors.BadRequest(
+			"chat.webhook.id.required",
+			"chat: webhook id required",
+		)
+	}
+
+	err := srv.validate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	authN, err := srv.authorize(ctx, auth.WRITE, "w")
+	if err != nil {
+		return err
+	}
+
+	update := app.UpdateOptions{
+		Context: *(authN),
+	}
+
+	res.Id = req.GetId()
+	res.Name = req.GetName()
+	res.Url = req.GetUrl()
+	res.Secret = req.GetSecret()
+	res.Events = req.GetEvents()
+	res.Enabled = req.GetEnabled()
+
+	err = srv.store.UpdateWebhook(&update, res)
+	if err != nil {
+		return err
+	}
+
+	srv.dispatch.Invalidate(authN.Creds.GetDc())
+	return nil
+}
+
+// DeleteWebhook subscription
+func (srv *WebhooksService) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest, res *pb.Webhook) error {
+
+	if req.GetId() <= 0 {
+		return errors.BadRequest(
+			"chat.webhook.id.required",
+			"chat: webhook id required",
+		)
+	}
+
+	authN, err := srv.authorize(ctx, auth.DELETE, "d")
+	if err != nil {
+		return err
+	}
+
+	remove := app.DeleteOptions{
+		Context: *(authN),
+		ID:      []int64{req.GetId()},
+	}
+
+	obj, err := srv.store.DeleteWebhook(&remove)
+	if err != nil {
+		return err
+	}
+
+	srv.dispatch.Invalidate(authN.Creds.GetDc())
+
+	res.Id = obj.GetId()
+	res.Name = obj.GetName()
+	res.Url = obj.GetUrl()
+	res.Events = obj.GetEvents()
+	res.Enabled = obj.GetEnabled()
+	res.CreatedAt = obj.GetCreatedAt()
+	res.UpdatedAt = obj.GetUpdatedAt()
+
+	return nil
+}
+
+// SearchWebhookFailures query of the failed deliveries; dead-letter log
+func (srv *WebhooksService) SearchWebhookFailures(ctx context.Context, req *pb.SearchWebhookFailureRequest, res *pb.WebhookFailureList) error {
+
+	authN, err := srv.authorize(ctx, auth.READ, "r")
+	if err != nil {
+		return err
+	}
+
+	search := app.SearchOptions{
+		Context: *(authN),
+		Filter:  make(map[string]any),
+		Access:  auth.READ,
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+	}
+
+	if oid := req.GetWebhookId(); oid > 0 {
+		search.Filter["webhook_id"] = oid
+	}
+	if date := req.GetDate(); date != nil {
+		search.Filter["date"] = date
+	}
+
+	return srv.store.SearchWebhookFailures(&search, res)
+}
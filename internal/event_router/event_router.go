@@ -56,6 +56,7 @@ type Router interface {
 	// Override
 	SendMessageToGateway(sender, target *app.Channel, message *chat.Message) error
 	SendUserActionToGateway(target *app.Channel, sender *chat.SendUserActionRequest) (bool, error)
+	SendUserActionToWebitelUser(target *app.Channel, sender *chat.SendUserActionRequest) error
 }
 
 func NewRouter(
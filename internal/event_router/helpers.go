@@ -2,8 +2,11 @@ package event_router
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	// "strings"
 	"database/sql"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/webitel/chat_manager/app"
 	"github.com/webitel/chat_manager/internal/contact"
+	"github.com/webitel/chat_manager/pkg/events"
 
 	gate "github.com/webitel/chat_manager/api/proto/bot"
 	chat "github.com/webitel/chat_manager/api/proto/chat"
@@ -442,3 +446,26 @@ func (c *eventRouter) SendUserActionToGateway(target *app.Channel, sender *chat.
 
 	return affected.GetOk(), nil
 }
+
+// SendUserActionToWebitelUser notifies internal chat member (agent)
+// about the partner's action, e.g.: typing
+func (c *eventRouter) SendUserActionToWebitelUser(target *app.Channel, sender *chat.SendUserActionRequest) error {
+
+	data, _ := json.Marshal(
+		events.UserActionEvent{
+			BaseEvent: events.BaseEvent{
+				ConversationID: target.Chat.Invite,
+				Timestamp:      time.Now().Unix() * 1000,
+			},
+			ChannelID: sender.GetChannelId(),
+			Action:    strings.ToLower(sender.GetAction().String()),
+		},
+	)
+
+	return c.sendEventToWebitelUser(nil, &store.Channel{
+		ID:             target.Chat.ID,
+		ConversationID: target.Chat.Invite,
+		UserID:         target.User.ID,
+		DomainID:       target.DomainID,
+	}, events.UserActionEventType, data)
+}
@@ -211,7 +211,9 @@ func (c *Channel) callWrap(next client.CallFunc) client.CallFunc {
 		c.ID = c.ChatID() // resolve channel's chat-id ! early binding
 
 		// doRequest
+		start := time.Now()
 		err := next(ctx, addr, req, rsp, opts)
+		observeCall(ctx, req.Method(), start, err)
 		//
 		if err != nil {
 
@@ -0,0 +1,35 @@
+package flow
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// flowCallDuration of the workflow service requests
+var flowCallDuration, _ = otel.Meter("github.com/webitel/chat_manager/internal/flow").
+	Float64Histogram("chat.flow.call.duration",
+		metric.WithDescription("Duration of the workflow (chat flow) service requests"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+		),
+	)
+
+// observeCall of the workflow service method since start
+func observeCall(ctx context.Context, method string, start time.Time, err error) {
+	if flowCallDuration == nil {
+		return
+	}
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	flowCallDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("status", status),
+	))
+}
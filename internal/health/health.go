@@ -0,0 +1,170 @@
+// Package health serves the [/healthz] liveness and [/readyz] readiness probes
+// reporting the connectivity of the service dependencies.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// Check the dependency; nil error means OK
+type Check func(ctx context.Context) error
+
+// DefaultTimeout of the dependency checks
+const DefaultTimeout = 3 * time.Second
+
+// Checker of the service dependencies
+type Checker struct {
+	// Timeout of the checks; default: 3s
+	Timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// NewChecker of the service dependencies
+func NewChecker() *Checker {
+	return &Checker{
+		Timeout: DefaultTimeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Add the named dependency Check
+func (c *Checker) Add(name string, check Check) *Checker {
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+	return c
+}
+
+// Status of the service dependencies
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Ready reports whether all the dependencies are OK
+func (s *Status) Ready() bool {
+	return s.Status == "ok"
+}
+
+// Run all the dependency checks concurrently
+func (c *Checker) Run(ctx context.Context) *Status {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	var (
+		wg  sync.WaitGroup
+		mx  sync.Mutex
+		res = &Status{
+			Status: "ok",
+			Checks: make(map[string]string, len(c.names)),
+		}
+	)
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			state := "ok"
+			if err := check(ctx); err != nil {
+				state = err.Error()
+			}
+			mx.Lock()
+			defer mx.Unlock()
+			res.Checks[name] = state
+			if state != "ok" {
+				res.Status = "unavailable"
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+	return res
+}
+
+// Healthz serves the liveness probe.
+// The process is alive, so it always responds 200 OK
+// reporting the dependencies status for information.
+func (c *Checker) Healthz(rsp http.ResponseWriter, req *http.Request) {
+	respond(rsp, http.StatusOK, c.Run(req.Context()))
+}
+
+// Readyz serves the readiness probe.
+// Responds 503 Service Unavailable unless all the dependencies are OK.
+func (c *Checker) Readyz(rsp http.ResponseWriter, req *http.Request) {
+	res := c.Run(req.Context())
+	code := http.StatusOK
+	if !res.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	respond(rsp, code, res)
+}
+
+// Register the [/healthz] and [/readyz] handlers
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", c.Healthz)
+	mux.HandleFunc("/readyz", c.Readyz)
+}
+
+func respond(rsp http.ResponseWriter, code int, res *Status) {
+	header := rsp.Header()
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Cache-Control", "no-store")
+	rsp.WriteHeader(code)
+	_ = json.NewEncoder(rsp).Encode(res)
+}
+
+// Postgres checks the database connection
+func Postgres(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Broker checks the message broker connection.
+// The broker implementation may report the connection state.
+func Broker(b broker.Broker) Check {
+	return func(context.Context) error {
+		if b == nil {
+			return errors.New("broker: not configured")
+		}
+		if conn, is := b.(interface{ Connected() bool }); is && !conn.Connected() {
+			return errors.New(b.String() + ": not connected")
+		}
+		return nil
+	}
+}
+
+// Registry checks the service discovery connection
+// and the service is registered with it.
+func Registry(r registry.Registry, service string) Check {
+	return func(ctx context.Context) error {
+		if r == nil {
+			return errors.New("registry: not configured")
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := r.GetService(service)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				return errors.New(r.String() + ": " + service + ": " + err.Error())
+			}
+			return nil
+		case <-ctx.Done():
+			return errors.New(r.String() + ": " + ctx.Err().Error())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+
+	var down error
+	checker := NewChecker().
+		Add("postgres", func(context.Context) error { return nil }).
+		Add("broker", func(context.Context) error { return down }).
+		Add("registry", func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() })
+	checker.Timeout = 10 * time.Millisecond
+
+	mux := http.NewServeMux()
+	checker.Register(mux)
+	probe := func(path string) (int, *Status) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		var res Status
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("GET %s: %s", path, rec.Body.String())
+		}
+		return rec.Code, &res
+	}
+
+	code, res := probe("/readyz")
+	if code != http.StatusServiceUnavailable || res.Ready() || res.Checks["registry"] == "ok" || res.Checks["postgres"] != "ok" {
+		t.Errorf("readyz: %d %+v", code, res)
+	}
+
+	checker.Add("registry", func(context.Context) error { return nil })
+	if code, res = probe("/readyz"); code != http.StatusOK || !res.Ready() {
+		t.Errorf("readyz: %d %+v", code, res)
+	}
+
+	down = errors.New("rabbitmq: not connected")
+	if code, res = probe("/healthz"); code != http.StatusOK || res.Checks["broker"] != down.Error() {
+		t.Errorf("healthz: %d %+v", code, res)
+	}
+}
@@ -370,11 +370,63 @@ func (s *pgsqlBotStore) UpdateContact(ctx context.Context, client *app.User) (ok
 	return // ok, nil
 }
 
+// SetStatus saves the last health check status of the bot
+func (s *pgsqlBotStore) SetStatus(ctx context.Context, status *bot.BotStatus) error {
+	_, err := s.primary().ExecContext(ctx,
+		"UPDATE chat.bot SET status = $2 WHERE id = $1",
+		status.GetId(), dbl.NullJSONBytes(status),
+	)
+	return err
+}
+
+// GetStatus returns the last health check status of the bot; nil if not checked yet
+func (s *pgsqlBotStore) GetStatus(ctx context.Context, pid int64) (*bot.BotStatus, error) {
+	var (
+		status bot.BotStatus
+		isNull = true
+	)
+	err := s.secondary().QueryRowContext(ctx,
+		"SELECT status FROM chat.bot WHERE id = $1", pid,
+	).Scan(ScanFunc(func(src interface{}) error {
+		if src == nil {
+			return nil
+		}
+		isNull = false
+		return ScanJSON(&status)(src)
+	}))
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	if err != nil || isNull {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ClaimHealthCheck of the bot at date unless checked since; atomic across the nodes
+func (s *pgsqlBotStore) ClaimHealthCheck(ctx context.Context, pid, date, since int64) (bool, error) {
+	var id int64
+	err := s.primary().QueryRowContext(ctx,
+		"UPDATE chat.bot SET status = jsonb_set(coalesce(status, '{}'), '{checked_at}', to_jsonb($2::int8))"+
+			" WHERE id = $1 AND coalesce((status->>'checked_at')::int8, 0) <= $3"+
+			" RETURNING id",
+		pid, date, since,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil // checked by other node
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func nullChatUpdates(src *bot.ChatUpdates) *bot.ChatUpdates {
 	if src != nil {
 		for _, s := range []string{
 			src.Title, src.Close,
 			src.Join, src.Left, src.FilePolicyFail,
+			src.OutOfHours,
 		} {
 			if s != "" {
 				return src
@@ -386,6 +438,22 @@ func nullChatUpdates(src *bot.ChatUpdates) *bot.ChatUpdates {
 	return src
 }
 
+func nullChatSurvey(src *bot.ChatSurvey) *bot.ChatSurvey {
+	if src != nil && src.Question == "" {
+		// Zero(!) Question is mandatory
+		src = nil
+	}
+	return src
+}
+
+func nullBusinessHours(src *bot.BusinessHours) *bot.BusinessHours {
+	if src != nil && len(src.Weekly) == 0 && len(src.Holidays) == 0 {
+		// Zero(!) open 24/7
+		src = nil
+	}
+	return src
+}
+
 func createBotRequest(req *app.CreateOptions, obj *bot.Bot) (stmtQ SelectStmt, params params, err error) {
 
 	deref := app.SearchOptions{
@@ -402,8 +470,8 @@ func createBotRequest(req *app.CreateOptions, obj *bot.Bot) (stmtQ SelectStmt, p
 
 	stmtQ = stmtQ.
 		Prefix("WITH created AS (" +
-			"INSERT INTO chat.bot (dc, uri, name, flow_id, enabled, updates, provider, metadata, created_at, created_by, updated_at, updated_by)" +
-			" VALUES (:dc, :uri, :name, :flow_id, :enabled, :updates, :provider, :metadata, :created_at, :created_by, :created_at, :created_by)" +
+			"INSERT INTO chat.bot (dc, uri, name, flow_id, enabled, updates, survey, business_hours, provider, metadata, created_at, created_by, updated_at, updated_by)" +
+			" VALUES (:dc, :uri, :name, :flow_id, :enabled, :updates, :survey, :business_hours, :provider, :metadata, :created_at, :created_by, :created_at, :created_by)" +
 			" RETURNING bot.*" +
 			")",
 		).
@@ -421,6 +489,12 @@ func createBotRequest(req *app.CreateOptions, obj *bot.Bot) (stmtQ SelectStmt, p
 	params.set("updates", dbl.NullJSONBytes(
 		nullChatUpdates(obj.GetUpdates()),
 	))
+	params.set("survey", dbl.NullJSONBytes(
+		nullChatSurvey(obj.GetSurvey()),
+	))
+	params.set("business_hours", dbl.NullJSONBytes(
+		nullBusinessHours(obj.GetBusinessHours()),
+	))
 	params.set("provider", obj.GetProvider())
 	params.set("metadata", dbl.NullJSONBytes(
 		obj.GetMetadata(),
@@ -495,7 +569,9 @@ func searchBotRequest(req *app.SearchOptions) (stmtQ SelectStmt, params params,
 			"uri", "name",
 			"flow", "enabled",
 			"provider", "metadata",
-			"updates", // template of updates
+			"updates",        // template of updates
+			"survey",         // post-chat survey
+			"business_hours", // business hours calendar
 			"created_at", "created_by",
 			"updated_at", "updated_by",
 		}
@@ -525,6 +601,10 @@ func searchBotRequest(req *app.SearchOptions) (stmtQ SelectStmt, params params,
 			stmtQ = stmtQ.Column("bot.enabled")
 		case "updates":
 			stmtQ = stmtQ.Column("bot.updates")
+		case "survey":
+			stmtQ = stmtQ.Column("bot.survey")
+		case "business_hours":
+			stmtQ = stmtQ.Column("bot.business_hours")
 		case "provider":
 			stmtQ = stmtQ.Column("bot.provider")
 		case "metadata":
@@ -795,6 +875,50 @@ func searchBotResults(rows *sql.Rows, limit int) ([]*bot.Bot, error) {
 					return nil
 				})
 			}
+		case "survey":
+			row[i] = func() interface{} { // *bot.ChatSurvey NULL
+				return ScanFunc(func(src interface{}) error {
+					if src == nil {
+						obj.Survey = nil
+						return nil
+					}
+
+					dst := obj.Survey
+					if dst == nil {
+						dst = new(bot.ChatSurvey)
+					}
+
+					err := ScanJSON(dst)(src)
+					if err != nil {
+						return err
+					}
+
+					obj.Survey = nullChatSurvey(dst)
+					return nil
+				})
+			}
+		case "business_hours":
+			row[i] = func() interface{} { // *bot.BusinessHours NULL
+				return ScanFunc(func(src interface{}) error {
+					if src == nil {
+						obj.BusinessHours = nil
+						return nil
+					}
+
+					dst := obj.BusinessHours
+					if dst == nil {
+						dst = new(bot.BusinessHours)
+					}
+
+					err := ScanJSON(dst)(src)
+					if err != nil {
+						return err
+					}
+
+					obj.BusinessHours = nullBusinessHours(dst)
+					return nil
+				})
+			}
 		case "provider":
 			row[i] = func() interface{} {
 				return &obj.Provider // *string NOTNULL
@@ -899,7 +1023,8 @@ func updateBotRequest(req *app.UpdateOptions, set *bot.Bot) (stmt SelectStmt, pa
 			// "id", "dc", "uri",
 			"name", "flow", "enabled",
 			// "provider",
-			"metadata", "updates",
+			"metadata", "updates", "survey",
+			"business_hours",
 			// "created_at", // "created_by",
 			// "updated_at", "updated_by",
 		}
@@ -940,6 +1065,16 @@ func updateBotRequest(req *app.UpdateOptions, set *bot.Bot) (stmt SelectStmt, pa
 				nullChatUpdates(set.GetUpdates())),
 			)
 			update = update.Set("updates", dbl.Expr(":updates"))
+		case "survey":
+			params.set("survey", dbl.NullJSONBytes(
+				nullChatSurvey(set.GetSurvey())),
+			)
+			update = update.Set("survey", dbl.Expr(":survey"))
+		case "business_hours":
+			params.set("business_hours", dbl.NullJSONBytes(
+				nullBusinessHours(set.GetBusinessHours())),
+			)
+			update = update.Set("business_hours", dbl.Expr(":business_hours"))
 		case "metadata":
 			params.set("metadata", dbl.NullJSONBytes(
 				set.GetMetadata(),
@@ -1098,7 +1233,8 @@ func (repo *sqlxRepository) GetChatBotByID(ctx context.Context, botId int64) (*C
 			b.enabled,
 			b.provider,
 			b.created_at,
-			b.updated_at
+			b.updated_at,
+			b.survey
 		FROM
 			chat.bot b
 		WHERE
@@ -46,6 +46,8 @@ type searchChatArgs struct {
 	// <true> -- HAS an audit rate
 	// <false> -- has NO audit rate
 	Rated *bool
+	// Tagged dialogs ONLY with ANY of the given tag IDs.
+	Tag []int64
 
 	// Chat (thread|member) IDs
 	// Combined
@@ -229,6 +231,22 @@ func searchChatRequest(req *app.SearchOptions) (args searchChatArgs, err error)
 					return // err
 				}
 			}
+		case "tag":
+			{
+				switch data := input.(type) {
+				case []int64:
+					if len(data) > 0 {
+						args.Tag = data
+					}
+				default:
+					err = errors.BadRequest(
+						"chat.query.tag.input",
+						"chat( tag: %v ) convert %[1]T into []int64",
+						input,
+					)
+					return // err
+				}
+			}
 		// ID: extra granular ...
 		case "thread.id": //, "chat.id":
 			{
@@ -583,6 +601,21 @@ func searchChatDialogsQuery(req *app.SearchOptions) (ctx *SELECT, plan dataFetch
 			)))
 			return alias
 		}
+		ratingAlias string
+		joinRating  = func() string {
+			alias := ratingAlias
+			if alias != "" {
+				return alias
+			}
+			// once
+			alias = "rt"
+			ratingAlias = alias
+			ctx.Query = ctx.Query.JoinClause(CompactSQL(fmt.Sprintf(
+				` LEFT JOIN chat.conversation_rating %s ON %s.conversation_id = %s.thread_id`,
+				alias, alias, left,
+			)))
+			return alias
+		}
 		needsProcessingAlias string
 		joinNeedsProcessing  = func() string {
 			alias := needsProcessingAlias
@@ -1040,6 +1073,67 @@ func searchChatDialogsQuery(req *app.SearchOptions) (ctx *SELECT, plan dataFetch
 					return postgres.Int8{Value: &node.RateId}
 				})
 			}
+		case "rating":
+			{
+				rt := joinRating()
+				ctx.Query = ctx.Query.Column(fmt.Sprintf(
+					"CASE WHEN %[1]s.conversation_id NOTNULL THEN"+
+						" json_build_object('scale', %[1]s.scale, 'value', %[1]s.rating, 'comment', %[1]s.comment"+
+						", 'date', (extract(epoch from %[1]s.updated_at) * 1000)::::int8) END",
+					rt,
+				))
+				plan = append(plan, func(node *api.Dialog) any {
+					return ScanFunc(func(src interface{}) error {
+						if src == nil {
+							node.Rating = nil
+							return nil
+						}
+						rate := new(api.Rating)
+						err := ScanJSON(rate)(src)
+						if err != nil {
+							return err
+						}
+						node.Rating = rate
+						return nil
+					})
+				})
+			}
+		case "tags":
+			{
+				ctx.Query = ctx.Query.Column(fmt.Sprintf(
+					"(SELECT json_agg(json_build_object('id', e.id, 'name', e.name) ORDER BY e.name)"+
+						" FROM chat.conversation_tag t JOIN chat.tag e ON e.id = t.tag_id"+
+						" WHERE t.conversation_id = %s.thread_id)",
+					left,
+				))
+				plan = append(plan, func(node *api.Dialog) any {
+					return ScanJSON(&node.Tags)
+				})
+			}
+		case "disposition":
+			{
+				ctx.Query = ctx.Query.Column(fmt.Sprintf(
+					"(SELECT json_build_object('id', e.id, 'name', e.name)"+
+						" FROM chat.conversation s JOIN chat.disposition e ON e.id = s.disposition_id"+
+						" WHERE s.id = %s.thread_id)",
+					left,
+				))
+				plan = append(plan, func(node *api.Dialog) any {
+					return ScanFunc(func(src interface{}) error {
+						if src == nil {
+							node.Disposition = nil
+							return nil
+						}
+						label := new(api.Label)
+						err := ScanJSON(label)(src)
+						if err != nil {
+							return err
+						}
+						node.Disposition = label
+						return nil
+					})
+				})
+			}
 
 		default:
 			err = errors.BadRequest(
@@ -917,13 +917,27 @@ func selectChatMember(req searchChatArgs, params params) (cte chatMemberQ, err e
 		if !(*(req.Rated)) {
 			exists = "NOT EXISTS"
 		}
+		// rated by the audit -OR- by the client survey
 		cond := fmt.Sprintf(
-			"%s (SELECT 1 FROM call_center.cc_audit_rate WHERE conversation_id = %s.conversation_id)",
+			"%[1]s (SELECT 1 FROM call_center.cc_audit_rate WHERE conversation_id = %[2]s.conversation_id"+
+				" UNION ALL SELECT 1 FROM chat.conversation_rating WHERE conversation_id = %[2]s.conversation_id)",
 			exists, left,
 		)
 		cte.member.query = cte.member.query.Where(cond)
 		cte.invite.query = cte.invite.query.Where(cond)
 	}
+	// chat( tag: [id] )
+	if vs := req.Tag; len(vs) > 0 {
+		var tags pgtype.Int8Array
+		_ = tags.Set(vs)
+		params.set("tag", &tags)
+		cond := fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM chat.conversation_tag WHERE conversation_id = %s.conversation_id AND tag_id = ANY(:tag))",
+			left,
+		)
+		cte.member.query = cte.member.query.Where(cond)
+		cte.invite.query = cte.invite.query.Where(cond)
+	}
 
 	return
 }
@@ -1007,12 +1021,22 @@ func selectChatThread(req searchChatArgs, params params) (cte sq.SelectBuilder,
 	}
 	// chat( rated: bool ) -- WTEL-9850
 	if vs := req.Rated; vs != nil {
+		// rated by the audit -OR- by the client survey
+		const rated = "(SELECT 1 FROM call_center.cc_audit_rate WHERE conversation_id = c.id" +
+			" UNION ALL SELECT 1 FROM chat.conversation_rating WHERE conversation_id = c.id)"
 		if *(vs) {
-			cte = cte.Where("EXISTS (SELECT 1 FROM call_center.cc_audit_rate WHERE conversation_id = c.id)")
+			cte = cte.Where("EXISTS " + rated)
 		} else {
-			cte = cte.Where("NOT EXISTS (SELECT 1 FROM call_center.cc_audit_rate WHERE conversation_id = c.id)")
+			cte = cte.Where("NOT EXISTS " + rated)
 		}
 	}
+	// chat( tag: [id] )
+	if vs := req.Tag; len(vs) > 0 {
+		var tags pgtype.Int8Array
+		_ = tags.Set(vs)
+		params.set("tag", &tags)
+		cte = cte.Where("EXISTS (SELECT 1 FROM chat.conversation_tag WHERE conversation_id = c.id AND tag_id = ANY(:tag))")
+	}
 
 	return // cte, nil
 }
@@ -0,0 +1,307 @@
+package sqlxrepo
+
+import (
+	"strconv"
+
+	"github.com/jackc/pgtype"
+	"github.com/micro/micro/v3/service/errors"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/store/postgres"
+)
+
+var _ AnalyticsStore = (*sqlxRepository)(nil)
+
+// Per conversation metrics source
+const chatMetricsJoinSQL = `LEFT JOIN LATERAL (
+    SELECT x."connection"::::int8 via
+      FROM chat.channel x
+     WHERE x.conversation_id = c.id AND NOT x.internal
+     ORDER BY x.created_at
+     LIMIT 1
+  ) g ON true
+  LEFT JOIN LATERAL (
+    SELECT min(x.joined_at) joined_at
+      FROM chat.channel x
+     WHERE x.conversation_id = c.id AND x.internal
+  ) a ON true
+  LEFT JOIN LATERAL (
+    SELECT count(*) messages
+         , count(*) FILTER (WHERE NOT x.internal) inbound
+         , count(*) FILTER (WHERE x.internal) outbound
+         , min(e.created_at) FILTER (WHERE x.internal) first_reply
+      FROM chat.message e
+      LEFT JOIN chat.channel x ON x.id = e.channel_id
+     WHERE e.conversation_id = c.id
+  ) m ON true
+  LEFT JOIN LATERAL (
+    SELECT avg(r.created_at - r.prev_at) response_time
+      FROM (
+        SELECT e.created_at, x.internal
+             , lag(e.created_at) OVER w prev_at
+             , lag(x.internal) OVER w prev_internal
+          FROM chat.message e
+          JOIN chat.channel x ON x.id = e.channel_id
+         WHERE e.conversation_id = c.id
+        WINDOW w AS (ORDER BY e.created_at)
+      ) r
+     WHERE r.internal AND NOT r.prev_internal
+  ) rt ON true`
+
+// Per agent's channel metrics source; the messages
+// of the agent itself and the client's ones while joined
+const chatAgentMetricsJoinSQL = `JOIN chat.conversation c ON c.id = x.conversation_id
+  LEFT JOIN LATERAL (
+    SELECT y."connection"::::int8 via
+      FROM chat.channel y
+     WHERE y.conversation_id = c.id AND NOT y.internal
+     ORDER BY y.created_at
+     LIMIT 1
+  ) g ON true
+  LEFT JOIN LATERAL (
+    SELECT count(*) messages
+         , count(*) FILTER (WHERE e.channel_id <> x.id) inbound
+         , count(*) FILTER (WHERE e.channel_id = x.id) outbound
+         , min(e.created_at) FILTER (WHERE e.channel_id = x.id) first_reply
+      FROM chat.message e
+      JOIN chat.channel y ON y.id = e.channel_id
+     WHERE e.conversation_id = c.id
+       AND (e.channel_id = x.id OR NOT y.internal)
+       AND e.created_at >= x.joined_at
+       AND (x.closed_at ISNULL OR e.created_at <= x.closed_at)
+  ) m ON true
+  LEFT JOIN LATERAL (
+    SELECT avg(r.created_at - r.prev_at) response_time
+      FROM (
+        SELECT e.created_at, e.channel_id = x.id own
+             , lag(e.created_at) OVER w prev_at
+             , lag(e.channel_id = x.id) OVER w prev_own
+          FROM chat.message e
+          JOIN chat.channel y ON y.id = e.channel_id
+         WHERE e.conversation_id = c.id
+           AND (e.channel_id = x.id OR NOT y.internal)
+           AND e.created_at >= x.joined_at
+           AND (x.closed_at ISNULL OR e.created_at <= x.closed_at)
+        WINDOW w AS (ORDER BY e.created_at)
+      ) r
+     WHERE r.own AND NOT r.prev_own
+  ) rt ON true`
+
+// milli(seconds) of the average interval expression
+func avgMillis(expr string) string {
+	return "coalesce((extract(epoch from avg(" + expr + ")) * 1000)::::int8, 0)"
+}
+
+func searchChatMetricsQuery(req *app.SearchOptions) (ctx *SELECT, err error) {
+
+	ctx = &SELECT{
+		Params: params{
+			"pdc": req.Authorization.Creds.GetDc(),
+		},
+	}
+
+	group, _ := req.Filter["group"].(string)
+
+	var source SelectStmt
+	if group == "agent" {
+		// Agent's own channel(s) metrics; NOT the whole conversation
+		source = postgres.PGSQL.
+			Select(
+				"c.id", "x.user_id", "x.created_at", "x.closed_at", "g.via", "x.joined_at",
+				"m.messages", "m.inbound", "m.outbound", "m.first_reply", "rt.response_time",
+			).
+			From("chat.channel x").
+			JoinClause(CompactSQL(chatAgentMetricsJoinSQL)).
+			Where("c.domain_id = :pdc").
+			Where("x.internal AND x.joined_at NOTNULL")
+	} else {
+		source = postgres.PGSQL.
+			Select(
+				"c.id", "c.created_at", "c.closed_at", "g.via", "a.joined_at",
+				"m.messages", "m.inbound", "m.outbound", "m.first_reply", "rt.response_time",
+			).
+			From("chat.conversation c").
+			JoinClause(CompactSQL(chatMetricsJoinSQL)).
+			Where("c.domain_id = :pdc")
+	}
+
+	for param, input := range req.Filter {
+		switch param {
+		case "group":
+		case "tz":
+			ctx.Params.set("tz", input)
+		case "self":
+			ctx.Params.set("self", input)
+			if group == "agent" {
+				source = source.Where("x.user_id = :self")
+				break
+			}
+			source = source.Where("EXISTS(SELECT 1 FROM chat.channel s" +
+				" WHERE s.conversation_id = c.id AND s.internal AND s.user_id = :self)")
+		case "via":
+			via, _ := input.(*api.Peer)
+			oid, _ := strconv.ParseInt(via.GetId(), 10, 64)
+			if oid < 1 {
+				err = errors.BadRequest(
+					"chat.metrics.via.input",
+					"metrics( via: %v ) input: invalid gateway id",
+					input,
+				)
+				return // nil, err
+			}
+			ctx.Params.set("via", oid)
+			source = source.Where("g.via = :via")
+		case "date":
+			date, _ := input.(*api.Timerange)
+			if 0 < date.GetSince() {
+				var since pgtype.Timestamp
+				_ = since.Set(app.EpochtimeDate(
+					date.Since, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("since", &since)
+				source = source.Where("c.created_at >= :since")
+			}
+			if 0 < date.GetUntil() {
+				var until pgtype.Timestamp
+				_ = until.Set(app.EpochtimeDate(
+					date.Until, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("until", &until)
+				source = source.Where("c.created_at < :until")
+			}
+		default:
+			err = errors.BadRequest(
+				"chat.metrics.query.input",
+				"metrics( %s ) input: no filter support",
+				param,
+			)
+			return // nil, err
+		}
+	}
+
+	ctx.Query = postgres.PGSQL.
+		Select().
+		FromSelect(source, "s")
+
+	conversations := "count(*)"
+	switch group {
+	case "", "gateway":
+		ctx.Query = ctx.Query.
+			Column("ROW(s.via::::text, b.provider, b.name) peer").
+			Column("NULL::::int8 \"time\"").
+			LeftJoin("chat.bot b ON b.id = s.via").
+			GroupBy("s.via", "b.provider", "b.name")
+	case "agent":
+		// The agent may (re)join the same conversation more than once
+		conversations = "count(DISTINCT s.id)"
+		ctx.Query = ctx.Query.
+			Column("ROW(s.user_id::::text, 'user', coalesce(u.name, u.username)) peer").
+			Column("NULL::::int8 \"time\"").
+			LeftJoin("directory.wbt_user u ON u.id = s.user_id").
+			GroupBy("s.user_id", "u.name", "u.username")
+	case "hour":
+		// [created_at] is UTC; truncate to the hour of the requested time zone
+		if _, ok := ctx.Params["tz"]; !ok {
+			ctx.Params.set("tz", "UTC")
+		}
+		ctx.Query = ctx.Query.
+			Column("NULL peer").
+			Column("(extract(epoch from date_trunc('hour', s.created_at AT TIME ZONE 'UTC' AT TIME ZONE :tz) AT TIME ZONE :tz) * 1000)::::int8 \"time\"").
+			GroupBy("2")
+	default:
+		err = errors.BadRequest(
+			"chat.metrics.group.input",
+			"metrics( group: %s ) input: no group support",
+			group,
+		)
+		return // nil, err
+	}
+
+	ctx.Query = ctx.Query.
+		Column(conversations + " conversations").
+		Column("count(*) FILTER (WHERE s.closed_at NOTNULL AND s.joined_at ISNULL) abandoned").
+		Column("coalesce(sum(s.messages), 0)::::int8 messages").
+		Column("coalesce(sum(s.inbound), 0)::::int8 inbound").
+		Column("coalesce(sum(s.outbound), 0)::::int8 outbound").
+		Column(avgMillis("s.first_reply - s.created_at") + " first_response_time").
+		Column(avgMillis("s.response_time") + " response_time").
+		Column(avgMillis("s.closed_at - s.joined_at") + " handle_time")
+
+	if group == "hour" {
+		ctx.Query = ctx.Query.OrderBy("2") // time
+	} else {
+		ctx.Query = ctx.Query.OrderBy("3 DESC") // conversations DESC
+	}
+
+	// [OFFSET|LIMIT]: paging
+	if size := req.GetSize(); size > 0 {
+		// OFFSET (page-1)*size -- omit same-sized previous page(s) from result
+		if page := req.GetPage(); page > 1 {
+			ctx.Query = ctx.Query.Offset((uint64)((page - 1) * size))
+		}
+		// LIMIT (size+1) -- to indicate whether there are more result entries
+		ctx.Query = ctx.Query.Limit((uint64)(size + 1))
+	}
+
+	return // ctx, nil
+}
+
+// Query of the conversation metrics aggregates
+func (c *sqlxRepository) GetChatMetrics(req *app.SearchOptions, res *api.ChatMetrics) error {
+
+	ctx := req.Context.Context
+	cte, err := searchChatMetricsQuery(req)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := cte.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.db.QueryContext(
+		ctx, query, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		size = req.GetSize()
+		data []*api.ChatMetric
+	)
+	for rows.Next() {
+		// LIMIT
+		if 0 < size && len(data) == size {
+			res.Next = true
+			break
+		}
+		node := new(api.ChatMetric)
+		err = rows.Scan(
+			fetchPeerRow(&node.Peer),
+			postgres.Int8{Value: &node.Time},
+			&node.Conversations,
+			&node.Abandoned,
+			&node.Messages,
+			&node.Inbound,
+			&node.Outbound,
+			&node.FirstResponseTime,
+			&node.ResponseTime,
+			&node.HandleTime,
+		)
+		if err != nil {
+			return err
+		}
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	res.Data = data
+	res.Page = int32(req.GetPage())
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package sqlxrepo
+
+import (
+	"strings"
+	"testing"
+
+	authN "github.com/webitel/chat_manager/api/proto/auth"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+)
+
+func TestSearchChatMetricsQuery(t *testing.T) {
+
+	search := func(filter map[string]any) *app.SearchOptions {
+		return &app.SearchOptions{
+			Context: app.Context{
+				Authorization: auth.Authorization{
+					Creds: &authN.Userinfo{Dc: 1},
+				},
+			},
+			Filter: filter,
+			Size:   10,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filter  map[string]any
+		want    []string
+		skip    []string
+		args    []any
+		wantErr bool
+	}{
+		{
+			name:   "gateway",
+			filter: map[string]any{"via": &api.Peer{Id: "7"}, "self": int64(5)},
+			want: []string{
+				"FROM chat.conversation c",
+				"g.via=$",
+				"s.user_id=$",
+				"count(*)conversations",
+				"GROUP BY s.via,b.provider,b.name",
+				"LIMIT 11",
+			},
+		},
+		{
+			// Per agent's own channel; NOT the whole conversation
+			name:   "agent",
+			filter: map[string]any{"group": "agent", "self": int64(5)},
+			want: []string{
+				"FROM chat.channel x JOIN chat.conversation c ON c.id=x.conversation_id",
+				"x.internal AND x.joined_at NOTNULL",
+				"x.user_id=$",
+				"e.channel_id=x.id OR NOT y.internal",
+				"e.created_at>=x.joined_at",
+				"count(DISTINCT s.id)conversations",
+				"GROUP BY s.user_id,u.name,u.username",
+			},
+			skip: []string{
+				"chat.conversation c LEFT JOIN",
+				"SELECT DISTINCT m.user_id",
+			},
+		},
+		{
+			name:   "hour",
+			filter: map[string]any{"group": "hour", "tz": "Europe/Kyiv"},
+			want: []string{
+				"date_trunc('hour',s.created_at AT TIME ZONE'UTC'AT TIME ZONE $1)AT TIME ZONE $1",
+				"ORDER BY 2",
+			},
+			args: []any{"Europe/Kyiv", int64(1)},
+		},
+		{
+			name:   "hour/utc",
+			filter: map[string]any{"group": "hour"},
+			args:   []any{"UTC", int64(1)},
+		},
+		{
+			name:    "group",
+			filter:  map[string]any{"group": "queue"},
+			wantErr: true,
+		},
+		{
+			name:    "via",
+			filter:  map[string]any{"via": &api.Peer{Id: "bot"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cte, err := searchChatMetricsQuery(search(tt.filter))
+			if err == nil {
+				var (
+					query string
+					args  []any
+				)
+				query, args, err = cte.ToSql()
+				for _, part := range tt.want {
+					if !strings.Contains(query, part) {
+						t.Errorf("query: %s\nmissing: %s", query, part)
+					}
+				}
+				for _, part := range tt.skip {
+					if strings.Contains(query, part) {
+						t.Errorf("query: %s\nunexpected: %s", query, part)
+					}
+				}
+				if tt.args != nil && len(args) != len(tt.args) {
+					t.Fatalf("args: %v; expect %v", args, tt.args)
+				}
+				for i, arg := range tt.args {
+					if args[i] != arg {
+						t.Errorf("args[%d]: %v; expect %v", i, args[i], arg)
+					}
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("searchChatMetricsQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
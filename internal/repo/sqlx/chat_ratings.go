@@ -0,0 +1,181 @@
+package sqlxrepo
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgtype"
+	"github.com/micro/micro/v3/service/errors"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/store/postgres"
+)
+
+// Post-chat survey answer considered as satisfied
+const ratingSatisfiedExpr = "CASE r.scale" +
+	" WHEN 'nps' THEN r.rating >= 9" +
+	" WHEN 'thumbs' THEN r.rating = 1" +
+	" ELSE r.rating >= 4 END"
+
+func searchChatRatingsQuery(req *app.SearchOptions) (ctx *SELECT, err error) {
+
+	ctx = &SELECT{
+		Params: params{
+			"pdc": req.Authorization.Creds.GetDc(),
+		},
+	}
+
+	ctx.Query = postgres.PGSQL.
+		Select().
+		From("chat.conversation_rating r").
+		Where("r.domain_id = :pdc")
+
+	group, _ := req.Filter["group"].(string)
+	switch group {
+	case "", "gateway":
+		ctx.Query = ctx.Query.
+			Column("ROW(r.gateway_id::::text, b.provider, b.name) peer").
+			LeftJoin("chat.bot b ON b.id = r.gateway_id").
+			GroupBy("r.gateway_id", "b.provider", "b.name")
+	case "agent":
+		ctx.Query = ctx.Query.
+			Column("ROW(a.user_id::::text, 'user', coalesce(u.name, u.username)) peer").
+			JoinClause("JOIN LATERAL (SELECT DISTINCT m.user_id FROM chat.channel m"+
+				" WHERE m.conversation_id = r.conversation_id AND m.internal AND m.joined_at NOTNULL) a ON true").
+			LeftJoin("directory.wbt_user u ON u.id = a.user_id").
+			GroupBy("a.user_id", "u.name", "u.username")
+	default:
+		err = errors.BadRequest(
+			"chat.ratings.group.input",
+			"ratings( group: %s ) input: no group support",
+			group,
+		)
+		return // nil, err
+	}
+
+	ctx.Query = ctx.Query.
+		Column("r.scale").
+		Column("count(*) count").
+		Column("avg(r.rating)::::float8 average").
+		Column(fmt.Sprintf("count(*) FILTER (WHERE %s) satisfied", ratingSatisfiedExpr)).
+		GroupBy("r.scale").
+		OrderBy("3 DESC", "2") // count DESC, scale
+
+	for param, input := range req.Filter {
+		switch param {
+		case "group":
+		case "self":
+			ctx.Params.set("self", input)
+			ctx.Query = ctx.Query.Where("EXISTS(SELECT 1 FROM chat.channel s" +
+				" WHERE s.conversation_id = r.conversation_id AND s.internal AND s.user_id = :self)")
+		case "via":
+			via, _ := input.(*api.Peer)
+			oid, _ := strconv.ParseInt(via.GetId(), 10, 64)
+			if oid < 1 {
+				err = errors.BadRequest(
+					"chat.ratings.via.input",
+					"ratings( via: %v ) input: invalid gateway id",
+					input,
+				)
+				return // nil, err
+			}
+			ctx.Params.set("via", oid)
+			ctx.Query = ctx.Query.Where("r.gateway_id = :via")
+		case "date":
+			date, _ := input.(*api.Timerange)
+			if 0 < date.GetSince() {
+				var since pgtype.Timestamp
+				_ = since.Set(app.EpochtimeDate(
+					date.Since, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("since", &since)
+				ctx.Query = ctx.Query.Where("r.created_at >= :since")
+			}
+			if 0 < date.GetUntil() {
+				var until pgtype.Timestamp
+				_ = until.Set(app.EpochtimeDate(
+					date.Until, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("until", &until)
+				ctx.Query = ctx.Query.Where("r.created_at < :until")
+			}
+		case "scale":
+			ctx.Params.set("scale", input)
+			ctx.Query = ctx.Query.Where("r.scale = :scale")
+		default:
+			err = errors.BadRequest(
+				"chat.ratings.query.input",
+				"ratings( %s ) input: no filter support",
+				param,
+			)
+			return // nil, err
+		}
+	}
+
+	// [OFFSET|LIMIT]: paging
+	if size := req.GetSize(); size > 0 {
+		// OFFSET (page-1)*size -- omit same-sized previous page(s) from result
+		if page := req.GetPage(); page > 1 {
+			ctx.Query = ctx.Query.Offset((uint64)((page - 1) * size))
+		}
+		// LIMIT (size+1) -- to indicate whether there are more result entries
+		ctx.Query = ctx.Query.Limit((uint64)(size + 1))
+	}
+
+	return // ctx, nil
+}
+
+// Query of the post-chat survey rating aggregates
+func (c *sqlxRepository) GetRatings(req *app.SearchOptions, res *api.ChatRatings) error {
+
+	ctx := req.Context.Context
+	cte, err := searchChatRatingsQuery(req)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := cte.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.db.QueryContext(
+		ctx, query, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		size = req.GetSize()
+		data []*api.ChatRating
+	)
+	for rows.Next() {
+		// LIMIT
+		if 0 < size && len(data) == size {
+			res.Next = true
+			break
+		}
+		node := new(api.ChatRating)
+		err = rows.Scan(
+			fetchPeerRow(&node.Peer),
+			&node.Scale,
+			&node.Count,
+			&node.Average,
+			&node.Satisfied,
+		)
+		if err != nil {
+			return err
+		}
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	res.Data = data
+	res.Page = int32(req.GetPage())
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package sqlxrepo
+
+import (
+	"strings"
+	"testing"
+
+	authN "github.com/webitel/chat_manager/api/proto/auth"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/auth"
+)
+
+func TestSearchChatRatingsQuery(t *testing.T) {
+
+	search := func(filter map[string]any) *app.SearchOptions {
+		return &app.SearchOptions{
+			Context: app.Context{
+				Authorization: auth.Authorization{
+					Creds: &authN.Userinfo{Dc: 1},
+				},
+			},
+			Filter: filter,
+			Size:   10,
+			Page:   2,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filter  map[string]any
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "gateway",
+			filter: map[string]any{"scale": "csat", "via": &api.Peer{Id: "7"}},
+			want: []string{
+				"FROM chat.conversation_rating r",
+				"LEFT JOIN chat.bot b ON b.id=r.gateway_id",
+				"r.domain_id=$",
+				"r.gateway_id=$",
+				"r.scale=$",
+				"GROUP BY r.gateway_id,b.provider,b.name,r.scale",
+				"LIMIT 11 OFFSET 10",
+			},
+		},
+		{
+			name:   "agent",
+			filter: map[string]any{"group": "agent", "self": int64(5)},
+			want: []string{
+				"JOIN LATERAL(SELECT DISTINCT m.user_id FROM chat.channel m",
+				"s.user_id=$",
+				"GROUP BY a.user_id,u.name,u.username,r.scale",
+			},
+		},
+		{
+			name:    "group",
+			filter:  map[string]any{"group": "queue"},
+			wantErr: true,
+		},
+		{
+			name:    "via",
+			filter:  map[string]any{"via": &api.Peer{Id: "bot"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cte, err := searchChatRatingsQuery(search(tt.filter))
+			if err == nil {
+				var query string
+				query, _, err = cte.ToSql()
+				for _, part := range tt.want {
+					if !strings.Contains(query, part) {
+						t.Errorf("query: %s\nmissing: %s", query, part)
+					}
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("searchChatRatingsQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
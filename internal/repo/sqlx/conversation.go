@@ -116,6 +116,50 @@ func (repo *sqlxRepository) CloseConversation(ctx context.Context, id string, ca
 	return err
 }
 
+const psqlConversationRateQ = `INSERT INTO chat.conversation_rating AS r
+  (conversation_id, domain_id, gateway_id, scale, rating, comment, created_at, updated_at)
+SELECT c.id, c.domain_id, b.id, $3, $4, nullif($5, ''), $6, $6
+  FROM chat.conversation c
+  JOIN chat.bot b ON b.id = $2 AND b.dc = c.domain_id
+ WHERE c.id = $1
+   -- the client came via this gateway ONLY
+   AND EXISTS(SELECT 1 FROM chat.channel ch
+               WHERE ch.conversation_id = c.id
+                 AND NOT ch.internal
+                 AND ch.connection = b.id::text)
+ON CONFLICT (conversation_id) DO UPDATE
+   SET scale = EXCLUDED.scale
+     , rating = EXCLUDED.rating
+     , comment = coalesce(EXCLUDED.comment, r.comment)
+     , updated_at = EXCLUDED.updated_at`
+
+func (repo *sqlxRepository) RateConversation(ctx context.Context, rate *ConversationRating) (bool, error) {
+
+	if rate.UpdatedAt.IsZero() {
+		rate.UpdatedAt = time.Now()
+	}
+
+	res, err := repo.db.ExecContext(ctx,
+		// query statement
+		psqlConversationRateQ,
+		// query params ...
+		rate.ConversationID, rate.GatewayID,
+		rate.Scale, rate.Rating, rate.Comment,
+		rate.UpdatedAt.UTC(),
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
 /*func (repo *sqlxRepository) CloseConversation(ctx context.Context, id string) error {
 	_, err := repo.db.ExecContext(ctx, `update chat.conversation set closed_at=$1 where id=$2`, sql.NullTime{
 		Valid: true,
@@ -0,0 +1,375 @@
+package sqlxrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
+	"github.com/micro/micro/v3/service/errors"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/store/postgres"
+)
+
+// Dictionaries of the conversation labels
+const (
+	LabelTag         = "tag"         // chat.tag
+	LabelDisposition = "disposition" // chat.disposition
+)
+
+var _ DispositionStore = (*sqlxRepository)(nil)
+
+// labelTable returns the dictionary table name
+func labelTable(dict string) string {
+	switch dict {
+	case LabelTag, LabelDisposition:
+		return "chat." + dict
+	}
+	panic(fmt.Errorf("chat: label dictionary %q not supported", dict))
+}
+
+// labelError converts unique constraint violation into client error
+func labelError(dict string, obj *api.Label, err error) error {
+	if re, is := err.(*pgconn.PgError); is && re.Code == "23505" {
+		return errors.Conflict(
+			"chat."+dict+".name.conflict",
+			"chat: %s name %q already exists",
+			dict, obj.GetName(),
+		)
+	}
+	return err
+}
+
+func scanLabel(row interface{ Scan(...any) error }, obj *api.Label) error {
+	return row.Scan(
+		&obj.Id,
+		&obj.Name,
+		postgres.Text{Value: &obj.Description},
+		postgres.Epochtime{Precision: app.TimePrecision, Value: &obj.CreatedAt},
+		postgres.Epochtime{Precision: app.TimePrecision, Value: &obj.UpdatedAt},
+	)
+}
+
+const labelColumns = "e.id, e.name, e.description, e.created_at, e.updated_at"
+
+// SearchLabels query of the dictionary entries
+func (c *sqlxRepository) SearchLabels(req *app.SearchOptions, dict string, res *api.LabelList) error {
+
+	ctx := &SELECT{
+		Params: params{
+			"pdc": req.Authorization.Creds.GetDc(),
+		},
+	}
+
+	ctx.Query = postgres.PGSQL.
+		Select(labelColumns).
+		From(labelTable(dict) + " e").
+		Where("e.domain_id = :pdc").
+		OrderBy("e.name")
+
+	if vs := req.ID; len(vs) > 0 {
+		var oid pgtype.Int8Array
+		_ = oid.Set(vs)
+		ctx.Params.set("id", &oid)
+		ctx.Query = ctx.Query.Where("e.id = ANY(:id)")
+	}
+
+	if term := req.Term; term != "" {
+		ctx.Params.set("q", postgres.Substring(app.Substring(term)))
+		ctx.Query = ctx.Query.Where(`e.name ILIKE :q COLLATE "default"`)
+	}
+
+	// [OFFSET|LIMIT]: paging
+	if size := req.GetSize(); size > 0 {
+		if page := req.GetPage(); page > 1 {
+			ctx.Query = ctx.Query.Offset((uint64)((page - 1) * size))
+		}
+		// LIMIT (size+1) -- to indicate whether there are more result entries
+		ctx.Query = ctx.Query.Limit((uint64)(size + 1))
+	}
+
+	query, args, err := ctx.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.db.QueryContext(
+		req.Context.Context, query, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		size = req.GetSize()
+		data []*api.Label
+	)
+	for rows.Next() {
+		// LIMIT
+		if 0 < size && len(data) == size {
+			res.Next = true
+			break
+		}
+		node := new(api.Label)
+		err = scanLabel(rows, node)
+		if err != nil {
+			return err
+		}
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	res.Data = data
+	res.Page = int32(req.GetPage())
+
+	return nil
+}
+
+// CreateLabel adds new dictionary entry
+func (c *sqlxRepository) CreateLabel(req *app.CreateOptions, dict string, obj *api.Label) error {
+
+	var (
+		authN = req.Authorization.Creds
+		query = fmt.Sprintf(
+			"INSERT INTO %s AS e (domain_id, name, description, created_at, created_by, updated_at, updated_by)"+
+				" VALUES ($1, $2, nullif($3, ''), $4, $5, $4, $5)"+
+				" RETURNING %s",
+			labelTable(dict), labelColumns,
+		)
+	)
+
+	err := scanLabel(c.db.QueryRowContext(
+		req.Context.Context, query,
+		authN.GetDc(), obj.GetName(), obj.GetDescription(),
+		req.Localtime().UTC(), authN.GetUserId(),
+	), obj)
+
+	return labelError(dict, obj, err)
+}
+
+// UpdateLabel modifies dictionary entry
+func (c *sqlxRepository) UpdateLabel(req *app.UpdateOptions, dict string, obj *api.Label) error {
+
+	var (
+		authN = req.Authorization.Creds
+		query = fmt.Sprintf(
+			"UPDATE %s AS e SET name = $3, description = nullif($4, ''), updated_at = $5, updated_by = $6"+
+				" WHERE e.domain_id = $1 AND e.id = $2"+
+				" RETURNING %s",
+			labelTable(dict), labelColumns,
+		)
+	)
+
+	err := scanLabel(c.db.QueryRowContext(
+		req.Context.Context, query,
+		authN.GetDc(), obj.GetId(),
+		obj.GetName(), obj.GetDescription(),
+		req.Localtime().UTC(), authN.GetUserId(),
+	), obj)
+
+	if err == sql.ErrNoRows {
+		return errors.NotFound(
+			"chat."+dict+".id.not_found",
+			"chat: %s id=%d not found",
+			dict, obj.GetId(),
+		)
+	}
+
+	return labelError(dict, obj, err)
+}
+
+// DeleteLabel removes dictionary entry
+func (c *sqlxRepository) DeleteLabel(req *app.DeleteOptions, dict string) (*api.Label, error) {
+
+	if len(req.ID) != 1 {
+		return nil, errors.BadRequest(
+			"chat."+dict+".id.required",
+			"chat: %s id required",
+			dict,
+		)
+	}
+
+	var (
+		obj   api.Label
+		query = fmt.Sprintf(
+			"DELETE FROM %s AS e WHERE e.domain_id = $1 AND e.id = $2 RETURNING %s",
+			labelTable(dict), labelColumns,
+		)
+	)
+
+	err := scanLabel(c.db.QueryRowContext(
+		req.Context.Context, query,
+		req.Authorization.Creds.GetDc(), req.ID[0],
+	), &obj)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound(
+			"chat."+dict+".id.not_found",
+			"chat: %s id=%d not found",
+			dict, req.ID[0],
+		)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+const psqlConversationTagsQ = `WITH chat AS (
+  SELECT c.id FROM chat.conversation c WHERE c.id = $1 AND c.domain_id = $2
+)
+, del AS (
+  DELETE FROM chat.conversation_tag t USING chat
+   WHERE t.conversation_id = chat.id AND t.tag_id <> ALL($3::int8[])
+)
+, ins AS (
+  INSERT INTO chat.conversation_tag (conversation_id, tag_id, created_at, created_by)
+  SELECT chat.id, e.id, $4, $5
+    FROM chat, chat.tag e
+   WHERE e.domain_id = $2 AND e.id = ANY($3::int8[])
+  ON CONFLICT DO NOTHING
+)
+SELECT (SELECT count(*) FROM chat), ` + labelColumns + `
+  FROM chat.tag e
+ WHERE e.domain_id = $2 AND e.id = ANY($3::int8[])
+ ORDER BY e.name`
+
+// SetConversationTags replaces the set of the conversation tags.
+// Returns the tags set, or (nil, nil) if no such conversation found.
+func (c *sqlxRepository) SetConversationTags(req *app.UpdateOptions, chatID string, tags []int64) ([]*api.Label, error) {
+
+	var (
+		authN = req.Authorization.Creds
+		oid   pgtype.Int8Array
+	)
+
+	if tags == nil {
+		tags = []int64{}
+	}
+	_ = oid.Set(tags)
+
+	rows, err := c.db.QueryContext(
+		req.Context.Context, psqlConversationTagsQ,
+		chatID, authN.GetDc(), &oid,
+		req.Localtime().UTC(), authN.GetUserId(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		found bool
+		data  = []*api.Label{}
+	)
+	for rows.Next() {
+		var (
+			n    int
+			node = new(api.Label)
+		)
+		err = rows.Scan(
+			&n,
+			&node.Id,
+			&node.Name,
+			postgres.Text{Value: &node.Description},
+			postgres.Epochtime{Precision: app.TimePrecision, Value: &node.CreatedAt},
+			postgres.Epochtime{Precision: app.TimePrecision, Value: &node.UpdatedAt},
+		)
+		if err != nil {
+			return nil, err
+		}
+		found = found || n > 0
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found && len(data) > 0 {
+		return nil, nil // chat not found
+	}
+
+	if len(data) == 0 {
+		// untag: ensure conversation exists
+		err = c.db.QueryRowContext(req.Context.Context,
+			"SELECT true FROM chat.conversation WHERE id = $1 AND domain_id = $2",
+			chatID, authN.GetDc(),
+		).Scan(&found)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// SetConversationDisposition sets the wrap-up disposition of the conversation.
+// Zero disposition resets the value. Returns false if no such conversation found.
+func (c *sqlxRepository) SetConversationDisposition(req *app.UpdateOptions, chatID string, disposition int64) (*api.Label, bool, error) {
+
+	var (
+		dc  = req.Authorization.Creds.GetDc()
+		obj *api.Label
+	)
+
+	if disposition != 0 {
+		var (
+			node  api.Label
+			query = fmt.Sprintf(
+				"SELECT %s FROM chat.disposition e WHERE e.domain_id = $1 AND e.id = $2",
+				labelColumns,
+			)
+		)
+		err := scanLabel(c.db.QueryRowContext(
+			req.Context.Context, query, dc, disposition,
+		), &node)
+		if err == sql.ErrNoRows {
+			return nil, false, errors.BadRequest(
+				"chat.disposition.id.invalid",
+				"chat: disposition id=%d not found",
+				disposition,
+			)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		obj = &node
+	}
+
+	res, err := c.db.ExecContext(req.Context.Context,
+		"UPDATE chat.conversation SET disposition_id = nullif($3, 0) WHERE id = $1 AND domain_id = $2",
+		chatID, dc, disposition,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return obj, n == 1, nil
+}
+
+// IsConversationMember reports whether given user is (was) the internal member of the conversation
+func (c *sqlxRepository) IsConversationMember(ctx context.Context, chatID string, userID int64) (bool, error) {
+
+	var ok bool
+	err := c.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT id FROM chat.channel WHERE conversation_id = $1 AND internal AND user_id = $2)",
+		chatID, userID,
+	).Scan(&ok)
+
+	return ok, err
+}
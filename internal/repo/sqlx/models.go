@@ -357,4 +357,16 @@ type ChatBot struct {
 	Provider  string    `db:"provider" json:"provider"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// Post-chat survey settings; NULL means disabled
+	Survey types.NullJSONText `db:"survey" json:"survey"`
+}
+
+// ConversationRating is the post-chat survey answer
+type ConversationRating struct {
+	ConversationID string    `db:"conversation_id" json:"conversation_id"`
+	GatewayID      int64     `db:"gateway_id" json:"gateway_id"`
+	Scale          string    `db:"scale" json:"scale"`
+	Rating         int32     `db:"rating" json:"rating"`
+	Comment        string    `db:"comment" json:"comment,omitempty"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
 }
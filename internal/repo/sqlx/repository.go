@@ -74,6 +74,9 @@ type ConversationRepository interface {
 	) ([]*Conversation, error)
 	CreateConversation(ctx context.Context, c *Conversation) error
 	GetConversationByID(ctx context.Context, id string) (*Conversation, error)
+	// RateConversation stores the post-chat survey answer.
+	// Returns false if no such conversation found via the rate.GatewayID.
+	RateConversation(ctx context.Context, rate *ConversationRating) (bool, error)
 	//GetConversationByID(ctx context.Context, id string) (*Conversation, []*Channel, []*Message, error)
 }
 
@@ -0,0 +1,83 @@
+package sqlxrepo
+
+import (
+	"context"
+	"database/sql"
+)
+
+var _ UpdatesStore = (*sqlxRepository)(nil)
+
+// MessageUpdate is the message missed by the recipient channel
+type MessageUpdate struct {
+	// Recipient's channel unique ID
+	ChannelID string
+	// Message sent
+	Message *Message
+}
+
+const psqlMessageUpdatesQ = `SELECT c.id
+     , m.id, coalesce(m.channel_id, m.conversation_id), m.conversation_id
+     , m.created_at, m.updated_at, m.type, m.text
+     , m.file_id, m.file_url, m.file_size, m.file_type, m.file_name
+  FROM chat.channel c
+  JOIN chat.message m ON m.conversation_id = c.conversation_id
+   AND m.id > $3 AND m.created_at >= c.created_at
+   AND coalesce(m.channel_id, m.conversation_id) <> c.id
+ WHERE c.domain_id = $1 AND c.user_id = $2
+   AND c.internal AND c.closed_at ISNULL
+ ORDER BY m.id
+ LIMIT $4`
+
+// GetMessageUpdates returns messages with ID greater than given offset,
+// sent to the user's active conversations, in order
+func (c *sqlxRepository) GetMessageUpdates(ctx context.Context, dc, userID, offset int64, limit int) ([]*MessageUpdate, error) {
+
+	rows, err := c.db.QueryContext(
+		ctx, psqlMessageUpdatesQ, dc, userID, offset, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*MessageUpdate
+	for rows.Next() {
+		var (
+			msg     Message
+			updated sql.NullTime
+			text    sql.NullString
+			fileID  sql.NullInt64
+			fileURL sql.NullString
+			size    sql.NullInt64
+			mime    sql.NullString
+			name    sql.NullString
+			node    = &MessageUpdate{Message: &msg}
+		)
+		err = rows.Scan(
+			&node.ChannelID,
+			&msg.ID, &msg.ChannelID, &msg.ConversationID,
+			&msg.CreatedAt, &updated, &msg.Type, &text,
+			&fileID, &fileURL, &size, &mime, &name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		msg.UpdatedAt = updated.Time
+		msg.Text = text.String
+		if fileID.Valid {
+			msg.File = &Document{
+				ID:   fileID.Int64,
+				URL:  fileURL.String,
+				Size: size.Int64,
+				Type: mime.String,
+				Name: name.String,
+			}
+		}
+		list = append(list, node)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -19,4 +19,6 @@ type CatalogStore interface {
 	GetContactChatHistory(req *app.SearchOptions) (*api.GetContactChatHistoryResponse, error)
 	// Query of the chat updates ; offset: forward
 	GetUpdates(req *app.SearchOptions) (*api.ChatMessages, error)
+	// Query of the post-chat survey rating aggregates
+	GetRatings(req *app.SearchOptions, res *api.ChatRatings) error
 }
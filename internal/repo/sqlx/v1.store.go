@@ -36,8 +36,56 @@ type AgentChatStore interface {
 	GetAgentChatsCounter(req *app.SearchOptions) (int64, error)
 }
 
+type DispositionStore interface {
+	// SearchLabels query of the [tag|disposition] dictionary entries
+	SearchLabels(req *app.SearchOptions, dict string, res *messages.LabelList) error
+	// CreateLabel adds new [tag|disposition] dictionary entry
+	CreateLabel(req *app.CreateOptions, dict string, obj *messages.Label) error
+	// UpdateLabel modifies [tag|disposition] dictionary entry
+	UpdateLabel(req *app.UpdateOptions, dict string, obj *messages.Label) error
+	// DeleteLabel removes [tag|disposition] dictionary entry
+	DeleteLabel(req *app.DeleteOptions, dict string) (*messages.Label, error)
+	// SetConversationTags replaces the set of the conversation tags
+	SetConversationTags(req *app.UpdateOptions, chatID string, tags []int64) ([]*messages.Label, error)
+	// SetConversationDisposition sets the wrap-up disposition of the conversation
+	SetConversationDisposition(req *app.UpdateOptions, chatID string, disposition int64) (*messages.Label, bool, error)
+	// IsConversationMember reports whether given user is (was) the internal member of the conversation
+	IsConversationMember(ctx context.Context, chatID string, userID int64) (bool, error)
+}
+
+type AnalyticsStore interface {
+	// GetChatMetrics query of the conversation metrics aggregates
+	GetChatMetrics(req *app.SearchOptions, res *messages.ChatMetrics) error
+}
+
+type UpdatesStore interface {
+	// GetMessageUpdates returns messages missed by the user since given offset message ID
+	GetMessageUpdates(ctx context.Context, dc, userID, offset int64, limit int) ([]*MessageUpdate, error)
+}
+
+type WebhookStore interface {
+	// SearchWebhooks query of the domain's webhook subscriptions
+	SearchWebhooks(req *app.SearchOptions, res *messages.WebhookList) error
+	// CreateWebhook adds new webhook subscription
+	CreateWebhook(req *app.CreateOptions, obj *messages.Webhook) error
+	// UpdateWebhook modifies webhook subscription
+	UpdateWebhook(req *app.UpdateOptions, obj *messages.Webhook) error
+	// DeleteWebhook removes webhook subscription
+	DeleteWebhook(req *app.DeleteOptions) (*messages.Webhook, error)
+	// GetDomainWebhooks returns enabled webhook subscriptions of the domain, including secret
+	GetDomainWebhooks(ctx context.Context, dc int64) ([]*messages.Webhook, error)
+	// CreateWebhookFailure logs the event failed to be delivered; dead-letter
+	CreateWebhookFailure(ctx context.Context, dc int64, obj *messages.WebhookFailure) error
+	// SearchWebhookFailures query of the failed deliveries; dead-letter log
+	SearchWebhookFailures(req *app.SearchOptions, res *messages.WebhookFailureList) error
+}
+
 type Store interface {
 	CatalogStore
 	ChatStore
 	AgentChatStore
+	DispositionStore
+	AnalyticsStore
+	UpdatesStore
+	WebhookStore
 }
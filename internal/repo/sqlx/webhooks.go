@@ -0,0 +1,365 @@
+package sqlxrepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
+	"github.com/micro/micro/v3/service/errors"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+	"github.com/webitel/chat_manager/app"
+	"github.com/webitel/chat_manager/store/postgres"
+)
+
+var _ WebhookStore = (*sqlxRepository)(nil)
+
+const webhookColumns = "e.id, e.name, e.url, e.events, e.enabled, e.created_at, e.updated_at"
+
+func scanWebhook(row interface{ Scan(...any) error }, obj *api.Webhook, dst ...any) error {
+	var events pgtype.TextArray
+	err := row.Scan(append([]any{
+		&obj.Id,
+		&obj.Name,
+		&obj.Url,
+		&events,
+		&obj.Enabled,
+		postgres.Epochtime{Precision: app.TimePrecision, Value: &obj.CreatedAt},
+		postgres.Epochtime{Precision: app.TimePrecision, Value: &obj.UpdatedAt},
+	}, dst...)...)
+	if err != nil {
+		return err
+	}
+	obj.Events = nil
+	return events.AssignTo(&obj.Events)
+}
+
+// webhookError converts unique constraint violation into client error
+func webhookError(obj *api.Webhook, err error) error {
+	if re, is := err.(*pgconn.PgError); is && re.Code == "23505" {
+		return errors.Conflict(
+			"chat.webhook.name.conflict",
+			"chat: webhook name %q already exists",
+			obj.GetName(),
+		)
+	}
+	return err
+}
+
+// SearchWebhooks query of the domain's webhook subscriptions
+func (c *sqlxRepository) SearchWebhooks(req *app.SearchOptions, res *api.WebhookList) error {
+
+	ctx := &SELECT{
+		Params: params{
+			"pdc": req.Authorization.Creds.GetDc(),
+		},
+	}
+
+	ctx.Query = postgres.PGSQL.
+		Select(webhookColumns).
+		From("chat.webhook e").
+		Where("e.domain_id = :pdc").
+		OrderBy("e.name")
+
+	if vs := req.ID; len(vs) > 0 {
+		var oid pgtype.Int8Array
+		_ = oid.Set(vs)
+		ctx.Params.set("id", &oid)
+		ctx.Query = ctx.Query.Where("e.id = ANY(:id)")
+	}
+
+	if term := req.Term; term != "" {
+		ctx.Params.set("q", postgres.Substring(app.Substring(term)))
+		ctx.Query = ctx.Query.Where(`e.name ILIKE :q COLLATE "default"`)
+	}
+
+	// [OFFSET|LIMIT]: paging
+	if size := req.GetSize(); size > 0 {
+		if page := req.GetPage(); page > 1 {
+			ctx.Query = ctx.Query.Offset((uint64)((page - 1) * size))
+		}
+		// LIMIT (size+1) -- to indicate whether there are more result entries
+		ctx.Query = ctx.Query.Limit((uint64)(size + 1))
+	}
+
+	query, args, err := ctx.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.db.QueryContext(
+		req.Context.Context, query, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		size = req.GetSize()
+		data []*api.Webhook
+	)
+	for rows.Next() {
+		// LIMIT
+		if 0 < size && len(data) == size {
+			res.Next = true
+			break
+		}
+		node := new(api.Webhook)
+		err = scanWebhook(rows, node)
+		if err != nil {
+			return err
+		}
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	res.Data = data
+	res.Page = int32(req.GetPage())
+
+	return nil
+}
+
+// CreateWebhook adds new webhook subscription
+func (c *sqlxRepository) CreateWebhook(req *app.CreateOptions, obj *api.Webhook) error {
+
+	var (
+		authN  = req.Authorization.Creds
+		events pgtype.TextArray
+	)
+	if obj.Events == nil {
+		obj.Events = []string{}
+	}
+	_ = events.Set(obj.Events)
+
+	err := scanWebhook(c.db.QueryRowContext(
+		req.Context.Context,
+		"INSERT INTO chat.webhook AS e"+
+			" (domain_id, name, url, secret, events, enabled, created_at, created_by, updated_at, updated_by)"+
+			" VALUES ($1, $2, $3, nullif($4, ''), $5, $6, $7, $8, $7, $8)"+
+			" RETURNING "+webhookColumns,
+		authN.GetDc(), obj.GetName(), obj.GetUrl(), obj.GetSecret(),
+		&events, obj.GetEnabled(), req.Localtime().UTC(), authN.GetUserId(),
+	), obj)
+
+	obj.Secret = "" // writeonly
+	return webhookError(obj, err)
+}
+
+// UpdateWebhook modifies webhook subscription.
+// Empty secret keeps the previous one.
+func (c *sqlxRepository) UpdateWebhook(req *app.UpdateOptions, obj *api.Webhook) error {
+
+	var (
+		authN  = req.Authorization.Creds
+		events pgtype.TextArray
+	)
+	if obj.Events == nil {
+		obj.Events = []string{}
+	}
+	_ = events.Set(obj.Events)
+
+	err := scanWebhook(c.db.QueryRowContext(
+		req.Context.Context,
+		"UPDATE chat.webhook AS e SET name = $3, url = $4, secret = coalesce(nullif($5, ''), e.secret)"+
+			", events = $6, enabled = $7, updated_at = $8, updated_by = $9"+
+			" WHERE e.domain_id = $1 AND e.id = $2"+
+			" RETURNING "+webhookColumns,
+		authN.GetDc(), obj.GetId(), obj.GetName(), obj.GetUrl(), obj.GetSecret(),
+		&events, obj.GetEnabled(), req.Localtime().UTC(), authN.GetUserId(),
+	), obj)
+
+	obj.Secret = "" // writeonly
+	if err == sql.ErrNoRows {
+		return errors.NotFound(
+			"chat.webhook.id.not_found",
+			"chat: webhook id=%d not found",
+			obj.GetId(),
+		)
+	}
+
+	return webhookError(obj, err)
+}
+
+// DeleteWebhook removes webhook subscription
+func (c *sqlxRepository) DeleteWebhook(req *app.DeleteOptions) (*api.Webhook, error) {
+
+	if len(req.ID) != 1 {
+		return nil, errors.BadRequest(
+			"chat.webhook.id.required",
+			"chat: webhook id required",
+		)
+	}
+
+	var obj api.Webhook
+	err := scanWebhook(c.db.QueryRowContext(
+		req.Context.Context,
+		"DELETE FROM chat.webhook AS e WHERE e.domain_id = $1 AND e.id = $2 RETURNING "+webhookColumns,
+		req.Authorization.Creds.GetDc(), req.ID[0],
+	), &obj)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound(
+			"chat.webhook.id.not_found",
+			"chat: webhook id=%d not found",
+			req.ID[0],
+		)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+// GetDomainWebhooks returns enabled webhook subscriptions of the domain,
+// including secret; for delivery purpose only
+func (c *sqlxRepository) GetDomainWebhooks(ctx context.Context, dc int64) ([]*api.Webhook, error) {
+
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT "+webhookColumns+", coalesce(e.secret, '') FROM chat.webhook e"+
+			" WHERE e.domain_id = $1 AND e.enabled ORDER BY e.id",
+		dc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*api.Webhook
+	for rows.Next() {
+		node := new(api.Webhook)
+		err = scanWebhook(rows, node, &node.Secret)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, node)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// CreateWebhookFailure logs the event failed to be delivered; dead-letter
+func (c *sqlxRepository) CreateWebhookFailure(ctx context.Context, dc int64, obj *api.WebhookFailure) error {
+	return c.db.QueryRowContext(ctx,
+		"INSERT INTO chat.webhook_failure (domain_id, webhook_id, event, payload, attempts, error)"+
+			" VALUES ($1, $2, $3, $4::jsonb, $5, nullif($6, ''))"+
+			" RETURNING id, created_at",
+		dc, obj.GetWebhookId(), obj.GetEvent(), obj.GetPayload(), obj.GetAttempts(), obj.GetError(),
+	).Scan(
+		&obj.Id, postgres.Epochtime{Precision: app.TimePrecision, Value: &obj.CreatedAt},
+	)
+}
+
+// SearchWebhookFailures query of the failed deliveries; dead-letter log
+func (c *sqlxRepository) SearchWebhookFailures(req *app.SearchOptions, res *api.WebhookFailureList) error {
+
+	ctx := &SELECT{
+		Params: params{
+			"pdc": req.Authorization.Creds.GetDc(),
+		},
+	}
+
+	ctx.Query = postgres.PGSQL.
+		Select(
+			"e.id", "e.webhook_id", "e.event", "e.payload::::text",
+			"e.attempts", "e.error", "e.created_at",
+		).
+		From("chat.webhook_failure e").
+		Where("e.domain_id = :pdc").
+		OrderBy("e.created_at DESC")
+
+	for param, input := range req.Filter {
+		switch param {
+		case "webhook_id":
+			ctx.Params.set("webhook", input)
+			ctx.Query = ctx.Query.Where("e.webhook_id = :webhook")
+		case "date":
+			date, _ := input.(*api.Timerange)
+			if 0 < date.GetSince() {
+				var since pgtype.Timestamp
+				_ = since.Set(app.EpochtimeDate(
+					date.Since, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("since", &since)
+				ctx.Query = ctx.Query.Where("e.created_at >= :since")
+			}
+			if 0 < date.GetUntil() {
+				var until pgtype.Timestamp
+				_ = until.Set(app.EpochtimeDate(
+					date.Until, app.TimePrecision,
+				).UTC())
+				ctx.Params.set("until", &until)
+				ctx.Query = ctx.Query.Where("e.created_at < :until")
+			}
+		default:
+			return errors.BadRequest(
+				"chat.webhook.failures.query.input",
+				"failures( %s ) input: no filter support",
+				param,
+			)
+		}
+	}
+
+	// [OFFSET|LIMIT]: paging
+	if size := req.GetSize(); size > 0 {
+		if page := req.GetPage(); page > 1 {
+			ctx.Query = ctx.Query.Offset((uint64)((page - 1) * size))
+		}
+		// LIMIT (size+1) -- to indicate whether there are more result entries
+		ctx.Query = ctx.Query.Limit((uint64)(size + 1))
+	}
+
+	query, args, err := ctx.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := c.db.QueryContext(
+		req.Context.Context, query, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		size = req.GetSize()
+		data []*api.WebhookFailure
+	)
+	for rows.Next() {
+		// LIMIT
+		if 0 < size && len(data) == size {
+			res.Next = true
+			break
+		}
+		node := new(api.WebhookFailure)
+		err = rows.Scan(
+			&node.Id,
+			&node.WebhookId,
+			&node.Event,
+			&node.Payload,
+			&node.Attempts,
+			postgres.Text{Value: &node.Error},
+			postgres.Epochtime{Precision: app.TimePrecision, Value: &node.CreatedAt},
+		)
+		if err != nil {
+			return err
+		}
+		data = append(data, node)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	res.Data = data
+	res.Page = int32(req.GetPage())
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Package secret provides the envelope encryption of the credentials stored at rest.
+//
+// Each value is sealed with a random data encryption key (DEK),
+// which in turn is wrapped with the key encryption key (KEK) from config.
+// The KEK identifier is kept with the value, so the keys can be rotated:
+// new values are sealed with the primary key; old ones are still readable
+// while their key is present in the Keyring.
+//
+//	enc:v1:<kid>:<base64(wrapped DEK)>:<base64(nonce|ciphertext)>
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix of the encrypted value
+const Prefix = "enc:v1:"
+
+var (
+	// ErrNoKeys configured to decrypt the value
+	ErrNoKeys = errors.New("secret: no encryption keys configured")
+	// ErrMalformed value of the encrypted secret
+	ErrMalformed = errors.New("secret: malformed encrypted value")
+)
+
+var encoding = base64.RawStdEncoding
+
+// Keyring of the key encryption keys by key id
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeyring of the comma-separated `kid:base64(key)` list.
+// The first key is primary and is used to encrypt new values.
+// Key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// Empty spec returns <nil> Keyring; encryption disabled.
+func ParseKeyring(spec string) (*Keyring, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil // disabled
+	}
+	var (
+		primary string
+		keys    = make(map[string][]byte)
+	)
+	for _, entry := range strings.Split(spec, ",") {
+		kid, key, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("secret: key %q; expect kid:base64", entry)
+		}
+		if _, dup := keys[kid]; dup {
+			return nil, fmt.Errorf("secret: key %q; duplicate", kid)
+		}
+		data, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("secret: key %q; %v", kid, err)
+		}
+		if primary == "" {
+			primary = kid
+		}
+		keys[kid] = data
+	}
+	return NewKeyring(primary, keys)
+}
+
+// NewKeyring of the given keys by id; primary is used to encrypt new values
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("secret: primary key %q not found", primary)
+	}
+	ring := &Keyring{
+		primary: primary,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+	for kid, key := range keys {
+		if strings.ContainsAny(kid, ":,") {
+			return nil, fmt.Errorf("secret: key %q; invalid id", kid)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("secret: key %q; %v", kid, err)
+		}
+		ring.keys[kid] = aead
+	}
+	return ring, nil
+}
+
+// KeyID of the primary key; empty if disabled
+func (k *Keyring) KeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.primary
+}
+
+// Encrypt plaintext with the primary key.
+// Returns plaintext as is if the Keyring is <nil>; encryption disabled.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil {
+		return plaintext, nil // disabled
+	}
+	dek := make([]byte, 32) // AES-256
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	kid := k.primary
+	wrapped, err := seal(k.keys[kid], dek, []byte(kid))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(data, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + kid + ":" +
+		encoding.EncodeToString(wrapped) + ":" +
+		encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt the value encrypted with any of the Keyring keys.
+// Not encrypted value is returned as is, so the plaintext
+// stored before the encryption was enabled is still readable.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil // plaintext
+	}
+	if k == nil {
+		return "", ErrNoKeys
+	}
+	parts := strings.Split(value[len(Prefix):], ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kid := parts[0]
+	kek, ok := k.keys[kid]
+	if !ok {
+		return "", fmt.Errorf("secret: key %q not found", kid)
+	}
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	dek, err := open(kek, wrapped, []byte(kid))
+	if err != nil {
+		return "", fmt.Errorf("secret: key %q; %v", kid, err)
+	}
+	data, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether the value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// KeyID of the encrypted value; empty if not encrypted
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	kid, _, _ := strings.Cut(value[len(Prefix):], ":")
+	return kid
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce|ciphertext
+func seal(aead cipher.AEAD, plaintext, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, data), nil
+}
+
+// open nonce|ciphertext
+func open(aead cipher.AEAD, sealed, data []byte) ([]byte, error) {
+	size := aead.NonceSize()
+	if len(sealed) < size {
+		return nil, ErrMalformed
+	}
+	return aead.Open(nil, sealed[:size], sealed[size:], data)
+}
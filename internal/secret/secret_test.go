@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestKeyring(t *testing.T) {
+
+	old, err := ParseKeyring("k1:" + testKey('1'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := old.Encrypt("123:ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(value) || KeyID(value) != "k1" || strings.Contains(value, "ABC") {
+		t.Fatalf("encrypt: %q", value)
+	}
+
+	// Rotate: k2 is primary; k1 still readable
+	ring, err := ParseKeyring("k2:" + testKey('2') + ", k1:" + testKey('1'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := ring.Decrypt(value); err != nil || plain != "123:ABC" {
+		t.Errorf("decrypt: %q, %v", plain, err)
+	}
+	if value, _ = ring.Encrypt("123:ABC"); KeyID(value) != "k2" {
+		t.Errorf("encrypt: %q; expect primary k2", value)
+	}
+
+	// Plaintext stored before encryption enabled
+	if plain, err := ring.Decrypt("123:ABC"); err != nil || plain != "123:ABC" {
+		t.Errorf("decrypt: %q, %v; plaintext", plain, err)
+	}
+	// Key removed
+	if _, err := old.Decrypt(value); err == nil {
+		t.Error("decrypt: k2 not found expected")
+	}
+	// Disabled
+	var none *Keyring
+	if _, err := none.Decrypt(value); err != ErrNoKeys {
+		t.Errorf("decrypt: %v; expect %v", err, ErrNoKeys)
+	}
+	// Tampered
+	if _, err := ring.Decrypt(value[:len(value)-2] + "AA"); err == nil {
+		t.Error("decrypt: tampered value accepted")
+	}
+	// Invalid key size
+	if _, err := ParseKeyring("k1:" + base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("parse: invalid key size accepted")
+	}
+}
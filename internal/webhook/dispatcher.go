@@ -0,0 +1,373 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+)
+
+// Event types available for subscription
+const (
+	ConversationStart = "conversation_start"
+	ConversationClose = "conversation_close"
+	Message           = "message"
+)
+
+// EventTypes supported
+var EventTypes = []string{
+	ConversationStart,
+	Message,
+	ConversationClose,
+}
+
+// SignHeader is the HTTP request header
+// with HMAC-SHA256 hex digest of the body
+const SignHeader = "X-Webitel-Sign"
+
+// Store of the webhook subscriptions
+type Store interface {
+	// GetDomainWebhooks returns enabled webhook subscriptions of the domain, including secret
+	GetDomainWebhooks(ctx context.Context, dc int64) ([]*api.Webhook, error)
+	// CreateWebhookFailure logs the event failed to be delivered; dead-letter
+	CreateWebhookFailure(ctx context.Context, dc int64, obj *api.WebhookFailure) error
+}
+
+// Event envelope to be delivered
+type Event struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Domain int64  `json:"domain_id"`
+	Date   int64  `json:"date"`
+	Data   any    `json:"data,omitempty"`
+}
+
+type delivery struct {
+	hook    *api.Webhook
+	event   *Event
+	body    []byte
+	attempt int
+}
+
+type domainHooks struct {
+	list    []*api.Webhook
+	expires time.Time
+}
+
+// Dispatcher delivers domain events
+// to the subscribed webhook(s) in background.
+// Failed deliveries are retried with exponential backoff
+// and logged as failure(s) after the last attempt.
+type Dispatcher struct {
+	logs   *slog.Logger
+	store  Store
+	client *http.Client
+
+	// MaxAttempts per delivery
+	MaxAttempts int
+	// Backoff is the base delay between attempts: backoff << attempt
+	Backoff time.Duration
+	// CacheTTL of the domain's subscriptions
+	CacheTTL time.Duration
+	// Policy of the target URL(s); checked on dial
+	Policy Policy
+
+	mx    sync.Mutex
+	cache map[int64]*domainHooks
+
+	queue chan *delivery
+	wg    sync.WaitGroup
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// NewDispatcher starts new webhooks dispatcher
+// with given number of delivery workers
+func NewDispatcher(store Store, logs *slog.Logger, workers int) *Dispatcher {
+	if logs == nil {
+		logs = slog.Default()
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	d := &Dispatcher{
+		logs:  logs,
+		store: store,
+
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		CacheTTL:    30 * time.Second,
+
+		cache: make(map[int64]*domainHooks),
+		queue: make(chan *delivery, 1024),
+		stop:  make(chan struct{}),
+	}
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		// Resolved address; NOT the internal network, unless allowed
+		Control: func(network, address string, conn syscall.RawConn) error {
+			return d.Policy.control(network, address, conn)
+		},
+	}
+	d.client = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// NO proxy; dial the target itself
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: workers,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("webhook: stopped after %d redirects", len(via))
+			}
+			return d.Policy.checkScheme(req.URL)
+		},
+	}
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+	return d
+}
+
+// Close stops dispatcher background workers.
+// Pending retries are discarded.
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.once.Do(func() {
+		close(d.stop)
+		d.wg.Wait()
+	})
+}
+
+// Invalidate cached subscriptions of the domain
+func (d *Dispatcher) Invalidate(dc int64) {
+	if d == nil {
+		return
+	}
+	d.mx.Lock()
+	delete(d.cache, dc)
+	d.mx.Unlock()
+}
+
+func (d *Dispatcher) webhooks(dc int64) ([]*api.Webhook, error) {
+	d.mx.Lock()
+	hooks, ok := d.cache[dc]
+	d.mx.Unlock()
+	if ok && time.Now().Before(hooks.expires) {
+		return hooks.list, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	list, err := d.store.GetDomainWebhooks(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+	d.mx.Lock()
+	d.cache[dc] = &domainHooks{
+		list:    list,
+		expires: time.Now().Add(d.CacheTTL),
+	}
+	d.mx.Unlock()
+	return list, nil
+}
+
+// subscribed reports whether hook accepts given event type
+func subscribed(hook *api.Webhook, typ string) bool {
+	if len(hook.Events) == 0 {
+		return true // all
+	}
+	for _, event := range hook.Events {
+		if strings.EqualFold(event, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish domain event of given type to the subscribed webhook(s).
+// Delivery is performed asynchronously; nil Dispatcher is a no-op.
+func (d *Dispatcher) Publish(dc int64, typ string, data any) {
+	if d == nil || dc == 0 {
+		return
+	}
+	hooks, err := d.webhooks(dc)
+	if err != nil {
+		d.logs.Error("webhooks: failed to lookup subscriptions",
+			slog.Int64("domain.id", dc),
+			slog.Any("error", err),
+		)
+		return
+	}
+	var event *Event
+	for _, hook := range hooks {
+		if !subscribed(hook, typ) {
+			continue
+		}
+		if event == nil {
+			event = &Event{
+				ID:     uuid.NewString(),
+				Type:   typ,
+				Domain: dc,
+				Date:   time.Now().UnixMilli(),
+				Data:   data,
+			}
+		}
+		body, err := json.Marshal(event)
+		if err != nil {
+			d.logs.Error("webhooks: failed to encode event",
+				slog.String("event", typ),
+				slog.Any("error", err),
+			)
+			return
+		}
+		d.enqueue(&delivery{
+			hook:  hook,
+			event: event,
+			body:  body,
+		})
+	}
+}
+
+// CheckURL of the webhook target against the Policy
+func (d *Dispatcher) CheckURL(ctx context.Context, rawURL string) error {
+	var policy Policy
+	if d != nil {
+		policy = d.Policy
+	}
+	return policy.CheckURL(ctx, rawURL)
+}
+
+func (d *Dispatcher) enqueue(job *delivery) {
+	select {
+	case <-d.stop:
+	case d.queue <- job:
+	default:
+		d.logs.Warn("webhooks: delivery queue is full; event dropped",
+			slog.Int64("webhook.id", job.hook.GetId()),
+			slog.String("event", job.event.Type),
+		)
+	}
+}
+
+// retry failed delivery; waits for the queue,
+// discarded only when the dispatcher is closed
+func (d *Dispatcher) retry(job *delivery) {
+	select {
+	case <-d.stop:
+	case d.queue <- job:
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case job := <-d.queue:
+			d.deliver(job)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(job *delivery) {
+	job.attempt++
+	err := d.send(job)
+	if err == nil {
+		return
+	}
+	log := d.logs.With(
+		slog.Int64("webhook.id", job.hook.GetId()),
+		slog.String("event", job.event.Type),
+		slog.Int("attempt", job.attempt),
+	)
+	if job.attempt < d.MaxAttempts {
+		delay := d.Backoff << (job.attempt - 1)
+		log.Warn("webhooks: delivery failed; retry",
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+		time.AfterFunc(delay, func() {
+			d.retry(job)
+		})
+		return
+	}
+	log.Error("webhooks: delivery failed",
+		slog.Any("error", err),
+	)
+	// dead-letter
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = d.store.CreateWebhookFailure(ctx, job.event.Domain, &api.WebhookFailure{
+		WebhookId: job.hook.GetId(),
+		Event:     job.event.Type,
+		Payload:   string(job.body),
+		Attempts:  int32(job.attempt),
+		Error:     err.Error(),
+	})
+	if err != nil {
+		log.Error("webhooks: failed to log delivery failure",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (d *Dispatcher) send(job *delivery) error {
+	href, err := url.Parse(job.hook.GetUrl())
+	if err != nil {
+		return err
+	}
+	// saved before the Policy(?)
+	err = d.Policy.checkScheme(href)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, href.String(), bytes.NewReader(job.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if secret := job.hook.GetSecret(); secret != "" {
+		req.Header.Set(SignHeader, calculateHash(job.body, secret))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return fmt.Errorf("webhook responded HTTP %s: %s",
+		resp.Status, strings.TrimSpace(string(snippet)),
+	)
+}
+
+func calculateHash(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
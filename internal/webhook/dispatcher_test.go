@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/webitel/chat_manager/api/proto/chat/messages"
+)
+
+type testStore struct {
+	hooks    []*api.Webhook
+	failures chan *api.WebhookFailure
+}
+
+func (s *testStore) GetDomainWebhooks(_ context.Context, _ int64) ([]*api.Webhook, error) {
+	return s.hooks, nil
+}
+
+func (s *testStore) CreateWebhookFailure(_ context.Context, _ int64, obj *api.WebhookFailure) error {
+	s.failures <- obj
+	return nil
+}
+
+func TestDispatcherSign(t *testing.T) {
+	const secret = "s3cr3t"
+	signed := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signed <- r.Header.Get(SignHeader) == calculateHash(body, secret)
+	}))
+	defer srv.Close()
+
+	store := &testStore{
+		hooks: []*api.Webhook{
+			{Id: 1, Url: srv.URL, Secret: secret, Events: []string{Message}},
+		},
+	}
+	d := NewDispatcher(store, nil, 1)
+	d.Policy = Policy{AllowHTTP: true, AllowPrivate: true} // httptest
+	defer d.Close()
+
+	d.Publish(1, ConversationStart, nil) // not subscribed
+	d.Publish(1, Message, map[string]any{"text": "Hi"})
+
+	select {
+	case ok := <-signed:
+		if !ok {
+			t.Fatalf("%s header mismatch", SignHeader)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+	select {
+	case <-signed:
+		t.Fatal("unsubscribed event delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	store := &testStore{
+		hooks:    []*api.Webhook{{Id: 7, Url: srv.URL}},
+		failures: make(chan *api.WebhookFailure, 1),
+	}
+	d := NewDispatcher(store, nil, 1)
+	d.Policy = Policy{AllowHTTP: true, AllowPrivate: true} // httptest
+	d.MaxAttempts = 3
+	d.Backoff = time.Millisecond
+	defer d.Close()
+
+	d.Publish(1, ConversationClose, nil)
+
+	select {
+	case fail := <-store.failures:
+		if fail.WebhookId != 7 || fail.Attempts != 3 || fail.Event != ConversationClose {
+			t.Fatalf("failure = %+v", fail)
+		}
+		if n := atomic.LoadInt32(&attempts); n != 3 {
+			t.Fatalf("attempts = %d; want 3", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure not logged")
+	}
+}
+
+func TestDispatcherPolicy(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+	}))
+	defer srv.Close()
+
+	store := &testStore{
+		hooks:    []*api.Webhook{{Id: 9, Url: srv.URL}},
+		failures: make(chan *api.WebhookFailure, 1),
+	}
+	d := NewDispatcher(store, nil, 1)
+	d.Policy = Policy{AllowHTTP: true} // NOT the loopback
+	d.MaxAttempts = 1
+	defer d.Close()
+
+	d.Publish(1, Message, nil)
+
+	select {
+	case fail := <-store.failures:
+		if !strings.Contains(fail.Error, ErrAddressNotAllowed.Error()) {
+			t.Fatalf("failure = %+v", fail)
+		}
+		if n := atomic.LoadInt32(&attempts); n != 0 {
+			t.Fatalf("attempts = %d; want 0", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure not logged")
+	}
+}
+
+func TestPolicyCheckURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		policy Policy
+		err    error
+		ok     bool
+	}{
+		{url: "https://93.184.215.14/hook", ok: true},
+		{url: "http://93.184.215.14/hook"},
+		{url: "http://93.184.215.14/hook", policy: Policy{AllowHTTP: true}, ok: true},
+		{url: "ftp://93.184.215.14/hook"},
+		{url: "https:///hook"},
+		{url: "https://localhost/hook", err: ErrAddressNotAllowed},
+		{url: "https://127.0.0.1:8080/hook", err: ErrAddressNotAllowed},
+		{url: "https://10.0.0.1/hook", err: ErrAddressNotAllowed},
+		{url: "https://192.168.1.1/hook", err: ErrAddressNotAllowed},
+		{url: "https://169.254.169.254/latest/meta-data", err: ErrAddressNotAllowed},
+		{url: "https://[::1]/hook", err: ErrAddressNotAllowed},
+		{url: "https://[fe80::1]/hook", err: ErrAddressNotAllowed},
+		{url: "https://[::ffff:127.0.0.1]/hook", err: ErrAddressNotAllowed},
+		{url: "https://0.0.0.0/hook", err: ErrAddressNotAllowed},
+		{url: "https://10.0.0.1/hook", policy: Policy{AllowPrivate: true}, ok: true},
+	}
+	for _, tt := range tests {
+		err := tt.policy.CheckURL(context.Background(), tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckURL(%q, %+v) = %v; ok %v", tt.url, tt.policy, err, tt.ok)
+		}
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("CheckURL(%q) = %v; want %v", tt.url, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrAddressNotAllowed of the webhook target; internal network
+var ErrAddressNotAllowed = errors.New("webhook: target address is not allowed")
+
+// Policy of the webhook target URL(s).
+// Zero value allows public https:// targets only.
+type Policy struct {
+	// AllowHTTP plaintext http:// target URL(s)
+	AllowHTTP bool
+	// AllowPrivate network target(s):
+	// loopback, private, link-local, unspecified ..
+	AllowPrivate bool
+}
+
+// checkScheme of the target URL
+func (p Policy) checkScheme(href *url.URL) error {
+	switch href.Scheme {
+	case "https":
+	case "http":
+		if !p.AllowHTTP {
+			return fmt.Errorf("webhook: insecure %s:// scheme is not allowed; expect https://", href.Scheme)
+		}
+	default:
+		return fmt.Errorf("webhook: %s:// scheme is not supported; expect https://", href.Scheme)
+	}
+	if href.Host == "" {
+		return fmt.Errorf("webhook: absolute URL expected")
+	}
+	return nil
+}
+
+// allowAddr reports whether target IP address is allowed
+func (p Policy) allowAddr(ip netip.Addr) bool {
+	if p.AllowPrivate {
+		return true
+	}
+	ip = ip.Unmap()
+	return ip.IsValid() && !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast())
+}
+
+// CheckURL of the webhook target, before save.
+// Resolves the host name to reject internal network targets;
+// the dial-time control is performed on delivery anyway.
+func (p Policy) CheckURL(ctx context.Context, rawURL string) error {
+	href, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	err = p.checkScheme(href)
+	if err != nil || p.AllowPrivate {
+		return err
+	}
+	host := strings.ToLower(strings.TrimSuffix(href.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrAddressNotAllowed
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !p.allowAddr(ip) {
+			return ErrAddressNotAllowed
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		// NOT resolvable now; dial-time control
+		return nil
+	}
+	for _, ip := range addrs {
+		if !p.allowAddr(ip) {
+			return ErrAddressNotAllowed
+		}
+	}
+	return nil
+}
+
+// control of the resolved address to dial; net.Dialer.Control
+func (p Policy) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !p.allowAddr(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package prometheus exposes the OpenTelemetry SDK metrics
+// on the Prometheus [/metrics] endpoint, using the upstream
+// go.opentelemetry.io/otel/exporters/prometheus exporter.
+//
+//	metrics, err := prometheus.NewExporter()
+//	err = otel.Configure(ctx, metrics.Option())
+//	// ...
+//	http.Handle("/metrics", metrics)
+package prometheus
+
+import (
+	"net/http"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelsdk "github.com/webitel/webitel-go-kit/otel/sdk"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Exporter collects the metrics on demand, e.g. on scrape.
+type Exporter struct {
+	reader  *otelprom.Exporter
+	handler http.Handler
+}
+
+// NewExporter of the Prometheus [/metrics] endpoint.
+// The Option() MUST be registered with the otel.Configure(!)
+func NewExporter() (*Exporter, error) {
+	// Own registry; do not expose the default Go collectors twice
+	registry := prom.NewRegistry()
+	reader, err := otelprom.New(
+		otelprom.WithRegisterer(registry),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{
+		reader: reader,
+		handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+			ErrorHandling: promhttp.ContinueOnError,
+		}),
+	}, nil
+}
+
+// Reader to register with the sdkmetric.MeterProvider
+func (e *Exporter) Reader() sdkmetric.Reader {
+	return e.reader
+}
+
+// Option of the otel.Configure(!) to register the Reader
+func (e *Exporter) Option() otelsdk.Option {
+	return otelsdk.WithMetricOptions(
+		sdkmetric.WithReader(e.reader),
+	)
+}
+
+// ServeHTTP the [GET /metrics] scrape request
+func (e *Exporter) ServeHTTP(rsp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(rsp, "(405) Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e.handler.ServeHTTP(rsp, req)
+}
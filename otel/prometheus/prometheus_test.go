@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestExporter(t *testing.T) {
+
+	exporter, err := NewExporter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter.Reader()),
+	)
+	defer provider.Shutdown(context.Background())
+
+	ctx := context.Background()
+	meter := provider.Meter("test")
+	counter, _ := meter.Int64Counter("chat.messages",
+		metric.WithDescription("Messages count"),
+	)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("provider", `we"b`)))
+	latency, _ := meter.Float64Histogram("flow.call.duration",
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.1, 1),
+	)
+	latency.Record(ctx, 0.5)
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("GET /metrics: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, part := range []string{
+		"# HELP chat_messages_total Messages count\n",
+		"# TYPE chat_messages_total counter\n",
+		`provider="we\"b"} 2` + "\n",
+		"# TYPE flow_call_duration_seconds histogram\n",
+		`le="0.1"} 0` + "\n",
+		`le="1"} 1` + "\n",
+		`le="+Inf"} 1` + "\n",
+		"flow_call_duration_seconds_count{",
+	} {
+		if !strings.Contains(body, part) {
+			t.Errorf("missing %q in:\n%s", part, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("POST", "/metrics", nil))
+	if rec.Code != 405 {
+		t.Errorf("POST /metrics: %d; expect 405", rec.Code)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	UserInvitationEventType     = "user_invite"
 	DeclineInvitationEventType  = "decline_invite"
 	UpdateChannelEventType      = "update_channel"
+	UserActionEventType         = "user_action"
 )
 
 type BaseEvent struct {
@@ -76,6 +77,12 @@ type UpdateChannelEvent struct {
 	UpdatedAt int64  `json:"updated_at"`
 }
 
+type UserActionEvent struct {
+	BaseEvent
+	ChannelID string `json:"channel_id"` // FROM: channel.ID ! sender !
+	Action    string `json:"action"`     // typing, cancel
+}
+
 type Conversation struct {
 	ID        string `json:"id"`
 	Title     string `json:"title,omitempty"`
@@ -321,6 +321,16 @@ func (r *rbroker) Address() string {
 	return ""
 }
 
+// Connected reports whether the broker is connected to the server at the moment
+func (r *rbroker) Connected() bool {
+	if r.conn == nil {
+		return false
+	}
+	r.conn.Lock()
+	defer r.conn.Unlock()
+	return r.conn.connected
+}
+
 func (r *rbroker) Init(opts ...broker.Option) error {
 	for _, o := range opts {
 		o(&r.opts)
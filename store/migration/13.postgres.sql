@@ -0,0 +1,21 @@
+-- Post-chat survey settings per bot (gateway) profile.
+ALTER TABLE chat.bot ADD COLUMN IF NOT EXISTS survey jsonb NULL;
+
+-- Post-chat survey answers; one per conversation.
+CREATE TABLE IF NOT EXISTS chat.conversation_rating
+(
+    conversation_id uuid NOT NULL
+        CONSTRAINT conversation_rating_pk PRIMARY KEY
+        CONSTRAINT conversation_rating_conversation_fk
+            REFERENCES chat.conversation (id) ON DELETE CASCADE,
+    domain_id bigint NOT NULL,
+    gateway_id bigint NULL,
+    scale text NOT NULL,
+    rating smallint NOT NULL,
+    comment text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS conversation_rating_domain_id_created_at_index
+ON chat.conversation_rating (domain_id, created_at);
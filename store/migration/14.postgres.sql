@@ -0,0 +1,52 @@
+-- Conversation tags dictionary; per domain.
+CREATE TABLE IF NOT EXISTS chat.tag
+(
+    id bigserial NOT NULL
+        CONSTRAINT tag_pk PRIMARY KEY,
+    domain_id bigint NOT NULL,
+    name text NOT NULL,
+    description text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    created_by bigint NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_by bigint NULL,
+    CONSTRAINT tag_domain_id_name_uindex UNIQUE (domain_id, name)
+);
+
+-- Conversation wrap-up disposition codes dictionary; per domain.
+CREATE TABLE IF NOT EXISTS chat.disposition
+(
+    id bigserial NOT NULL
+        CONSTRAINT disposition_pk PRIMARY KEY,
+    domain_id bigint NOT NULL,
+    name text NOT NULL,
+    description text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    created_by bigint NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_by bigint NULL,
+    CONSTRAINT disposition_domain_id_name_uindex UNIQUE (domain_id, name)
+);
+
+-- Tags of the conversation.
+CREATE TABLE IF NOT EXISTS chat.conversation_tag
+(
+    conversation_id uuid NOT NULL
+        CONSTRAINT conversation_tag_conversation_fk
+            REFERENCES chat.conversation (id) ON DELETE CASCADE,
+    tag_id bigint NOT NULL
+        CONSTRAINT conversation_tag_tag_fk
+            REFERENCES chat.tag (id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    created_by bigint NULL,
+    CONSTRAINT conversation_tag_pk PRIMARY KEY (conversation_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_tag_tag_id_index
+ON chat.conversation_tag (tag_id);
+
+-- Wrap-up disposition of the conversation.
+ALTER TABLE chat.conversation
+    ADD COLUMN IF NOT EXISTS disposition_id bigint NULL
+        CONSTRAINT conversation_disposition_fk
+            REFERENCES chat.disposition (id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- Chat analytics: conversations started within domain's timerange.
+CREATE INDEX IF NOT EXISTS conversation_domain_id_created_at_index
+ON chat.conversation (domain_id, created_at DESC);
@@ -0,0 +1,36 @@
+-- Outbound domain event webhooks subscriptions.
+CREATE TABLE IF NOT EXISTS chat.webhook
+(
+    id bigserial NOT NULL
+        CONSTRAINT webhook_pk PRIMARY KEY,
+    domain_id bigint NOT NULL,
+    name text NOT NULL,
+    url text NOT NULL,
+    secret text NULL,
+    events text[] NOT NULL DEFAULT '{}',
+    enabled boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    created_by bigint NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_by bigint NULL,
+    CONSTRAINT webhook_domain_id_name_uindex UNIQUE (domain_id, name)
+);
+
+-- Webhook deliveries failed after all the retries; dead-letter log.
+CREATE TABLE IF NOT EXISTS chat.webhook_failure
+(
+    id bigserial NOT NULL
+        CONSTRAINT webhook_failure_pk PRIMARY KEY,
+    domain_id bigint NOT NULL,
+    webhook_id bigint NOT NULL
+        CONSTRAINT webhook_failure_webhook_fk
+            REFERENCES chat.webhook (id) ON DELETE CASCADE,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer NOT NULL,
+    error text NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_failure_domain_id_created_at_index
+ON chat.webhook_failure (domain_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Business hours calendar per bot (gateway) profile.
+ALTER TABLE chat.bot ADD COLUMN IF NOT EXISTS business_hours jsonb NULL;
@@ -0,0 +1,2 @@
+-- The last health check status of the bot (gateway) profile.
+ALTER TABLE chat.bot ADD COLUMN IF NOT EXISTS status jsonb NULL;
@@ -0,0 +1,14 @@
+-- chat.message --
+
+-- Chat analytics: per conversation (agent) messages within timerange.
+-- Replaces the (conversation_id, created_at DESC) index of the 6th migration
+-- with the one covering the sender [channel_id], so the metrics scans are index-only.
+-- NO extra index on the hottest table to maintain on write.
+
+CREATE INDEX IF NOT EXISTS message_conversation_id_created_at_channel_id_index ON chat.message
+  USING btree (conversation_id, created_at DESC) INCLUDE (channel_id)
+;
+
+DROP INDEX IF EXISTS
+  chat.message_conversation_id_created_at_index -- btree (conversation_id, created_at DESC)
+;